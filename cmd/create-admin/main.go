@@ -0,0 +1,75 @@
+// Command create-admin gives admin role to web user with email from -email flag.
+// Web user is created with password from ADMIN_PASSWORD environment variable when it doesn't exist.
+// The grant is recorded in audit trail with os user and host which ran the command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/pkg/config"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+func main() {
+	email := flag.String("email", "", "email of web user who becomes admin")
+	flag.Parse()
+
+	if *email == "" {
+		fmt.Fprintln(os.Stderr, "usage: create-admin -email <email>")
+		os.Exit(2)
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		panic(err)
+	}
+
+	log := logger.Get(cfg.LogLevel)
+
+	store, err := store.New(cfg, log)
+	if err != nil {
+		log.Fatal("failed to create store", zap.Error(err))
+	}
+
+	passwords, err := service.NewPasswordPolicy(cfg)
+	if err != nil {
+		log.Fatal("failed to create password policy", zap.Error(err))
+	}
+
+	created, err := service.NewWebUserService(store, passwords).BootstrapAdmin(*email, os.Getenv("ADMIN_PASSWORD"), operatorOrigin())
+	if err != nil {
+		log.Fatal("failed to create admin", zap.String("email", *email), zap.Error(err))
+	}
+
+	if created {
+		log.Info("admin created", zap.String("email", *email))
+
+		return
+	}
+
+	log.Info("web user became admin", zap.String("email", *email))
+}
+
+// operatorOrigin returns origin of the grant for audit trail, command has no client ip,
+// so its name with os user and host is recorded as user agent.
+func operatorOrigin() model.Origin {
+	operator := "unknown"
+	if current, err := user.Current(); err == nil {
+		operator = current.Username
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return model.Origin{UserAgent: fmt.Sprintf("create-admin (%s@%s)", operator, host)}
+}
@@ -1,9 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"go.uber.org/zap"
 
 	"github.com/VladPetriv/scanner_backend_api/internal/handler"
+	"github.com/VladPetriv/scanner_backend_api/internal/outbox"
 	"github.com/VladPetriv/scanner_backend_api/internal/server"
 	"github.com/VladPetriv/scanner_backend_api/internal/service"
 	"github.com/VladPetriv/scanner_backend_api/internal/store"
@@ -33,24 +41,49 @@ func main() {
 
 	store, err := store.New(cfg, log)
 	if err != nil {
-		log.Error("failed to create store", zap.Error(err))
+		log.Fatal("failed to create store", zap.Error(err))
 	}
 
-	service, err := service.New(store, cfg.JwtSecretKey)
+	service, err := service.New(store, cfg)
+	if err != nil {
+		log.Fatal("failed to create service", zap.Error(err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runner, err := kafka.NewRunner(service, cfg, log)
 	if err != nil {
-		log.Error("failed to create service", zap.Error(err))
+		log.Error("failed to create kafka runner", zap.Error(err))
+	} else {
+		go runner.Run(ctx)
 	}
 
-	go kafka.GetChannelFromQueue(service, cfg, log)
-	go kafka.GetDataFromQueue(service, cfg, log)
+	go outbox.NewRunner(service, log).Run(ctx)
 
 	server := new(server.Server)
 
 	handler := handler.New(service, log)
 
+	go func() {
+		<-ctx.Done()
+
+		log.Info("shutting down")
+
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.Error("failed to shutdown server", zap.Error(err))
+		}
+	}()
+
 	log.Info("start server", zap.String("PORT", cfg.Port))
 
-	if err := server.Start(handler.InitRoutes()); err != nil {
+	if err := server.Start(handler.InitRoutes()); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Error("failed to start server", zap.Error(err))
 	}
+
+	if runner != nil {
+		if err := runner.Close(); err != nil {
+			log.Error("failed to close kafka runner", zap.Error(err))
+		}
+	}
 }
@@ -0,0 +1,12 @@
+CREATE TABLE ingest_failure (
+  id SERIAL PRIMARY KEY,
+  topic VARCHAR(255) NOT NULL,
+  kafka_partition INT NOT NULL,
+  kafka_offset BIGINT NOT NULL,
+  payload BYTEA NOT NULL,
+  error TEXT NOT NULL,
+  attempts INT NOT NULL DEFAULT 1,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT uq_ingest_failure_position UNIQUE (topic, kafka_partition, kafka_offset)
+);
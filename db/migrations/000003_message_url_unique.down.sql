@@ -0,0 +1 @@
+ALTER TABLE message DROP CONSTRAINT uq_message_url;
@@ -0,0 +1,42 @@
+CREATE TEMP TABLE message_duplicate AS
+SELECT id, MIN(id) OVER (PARTITION BY message_url) AS original_id
+FROM message
+WHERE message_url IS NOT NULL;
+
+DELETE FROM message_duplicate WHERE id = original_id;
+
+-- saved.message_id is unique, so only one saved row per original message can survive
+DELETE FROM saved WHERE id IN (
+  SELECT id FROM (
+    SELECT s.id, ROW_NUMBER() OVER (PARTITION BY COALESCE(d.original_id, s.message_id) ORDER BY s.id) AS rn
+    FROM saved s
+    LEFT JOIN message_duplicate d ON d.id = s.message_id
+  ) ranked
+  WHERE rn > 1
+);
+
+UPDATE saved s SET message_id = d.original_id
+FROM message_duplicate d
+WHERE s.message_id = d.id;
+
+UPDATE replie r SET message_id = d.original_id
+FROM message_duplicate d
+WHERE r.message_id = d.id;
+
+-- every copy of the message carried the whole reply thread, keep one reply of each
+DELETE FROM replie WHERE id IN (
+  SELECT id FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY message_id, user_id, title, imageurl ORDER BY id) AS rn
+    FROM replie
+    WHERE message_id IN (SELECT original_id FROM message_duplicate)
+  ) ranked
+  WHERE rn > 1
+);
+
+DELETE FROM message m
+USING message_duplicate d
+WHERE m.id = d.id;
+
+DROP TABLE message_duplicate;
+
+ALTER TABLE message ADD CONSTRAINT uq_message_url UNIQUE (message_url);
@@ -0,0 +1,5 @@
+UPDATE tg_user SET username = 'tg' || COALESCE(tg_id::TEXT, id::TEXT) WHERE username IS NULL;
+ALTER TABLE tg_user ALTER COLUMN username SET NOT NULL;
+
+ALTER TABLE tg_user DROP CONSTRAINT uq_tg_user_tg_id;
+ALTER TABLE tg_user DROP COLUMN tg_id;
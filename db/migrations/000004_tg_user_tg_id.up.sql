@@ -0,0 +1,5 @@
+ALTER TABLE tg_user ADD COLUMN tg_id BIGINT;
+ALTER TABLE tg_user ADD CONSTRAINT uq_tg_user_tg_id UNIQUE (tg_id);
+
+-- telegram users are not required to have a username
+ALTER TABLE tg_user ALTER COLUMN username DROP NOT NULL;
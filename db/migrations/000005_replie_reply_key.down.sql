@@ -0,0 +1,2 @@
+ALTER TABLE replie DROP CONSTRAINT uq_replie_message_reply_key;
+ALTER TABLE replie DROP COLUMN reply_key;
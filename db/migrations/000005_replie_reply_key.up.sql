@@ -0,0 +1,15 @@
+ALTER TABLE replie ADD COLUMN reply_key VARCHAR(64);
+
+-- replies stored before keys existed are keyed by their content like replies without telegram id,
+-- missing title or image is keyed as empty string the same way ingest does it
+UPDATE replie SET reply_key = 'md5:' || md5(user_id || '|' || COALESCE(title, '') || '|' || COALESCE(imageurl, ''));
+
+UPDATE replie r SET reply_key = r.reply_key || ':' || r.id
+FROM (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY message_id, reply_key ORDER BY id) AS rn
+  FROM replie
+) ranked
+WHERE ranked.id = r.id AND ranked.rn > 1;
+
+ALTER TABLE replie ALTER COLUMN reply_key SET NOT NULL;
+ALTER TABLE replie ADD CONSTRAINT uq_replie_message_reply_key UNIQUE (message_id, reply_key);
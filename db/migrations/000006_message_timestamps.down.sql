@@ -0,0 +1,10 @@
+DROP INDEX idx_message_channel_posted_at;
+DROP INDEX idx_message_posted_at;
+
+ALTER TABLE replie DROP COLUMN ingested_at;
+ALTER TABLE replie DROP COLUMN edited_at;
+ALTER TABLE replie DROP COLUMN posted_at;
+
+ALTER TABLE message DROP COLUMN ingested_at;
+ALTER TABLE message DROP COLUMN edited_at;
+ALTER TABLE message DROP COLUMN posted_at;
@@ -0,0 +1,10 @@
+ALTER TABLE message ADD COLUMN posted_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE message ADD COLUMN edited_at TIMESTAMPTZ;
+ALTER TABLE message ADD COLUMN ingested_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE replie ADD COLUMN posted_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE replie ADD COLUMN edited_at TIMESTAMPTZ;
+ALTER TABLE replie ADD COLUMN ingested_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX idx_message_posted_at ON message (posted_at DESC, id DESC);
+CREATE INDEX idx_message_channel_posted_at ON message (channel_id, posted_at DESC, id DESC);
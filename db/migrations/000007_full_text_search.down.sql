@@ -0,0 +1,5 @@
+DROP INDEX idx_replie_tsv;
+DROP INDEX idx_message_tsv;
+
+ALTER TABLE replie DROP COLUMN tsv;
+ALTER TABLE message DROP COLUMN tsv;
@@ -0,0 +1,16 @@
+-- titles are indexed with english and russian stemming, simple config keeps words
+-- of every other language (ukrainian included) as they are
+ALTER TABLE message ADD COLUMN tsv TSVECTOR GENERATED ALWAYS AS (
+  to_tsvector('english', COALESCE(title, '')) ||
+  to_tsvector('russian', COALESCE(title, '')) ||
+  to_tsvector('simple', COALESCE(title, ''))
+) STORED;
+
+ALTER TABLE replie ADD COLUMN tsv TSVECTOR GENERATED ALWAYS AS (
+  to_tsvector('english', COALESCE(title, '')) ||
+  to_tsvector('russian', COALESCE(title, '')) ||
+  to_tsvector('simple', COALESCE(title, ''))
+) STORED;
+
+CREATE INDEX idx_message_tsv ON message USING GIN (tsv);
+CREATE INDEX idx_replie_tsv ON replie USING GIN (tsv);
@@ -0,0 +1,15 @@
+DROP INDEX idx_saved_user_saved_at;
+
+ALTER TABLE saved DROP COLUMN saved_at;
+
+-- message_id becomes globally unique again, only the first save of each message survives
+DELETE FROM saved WHERE id IN (
+  SELECT id FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY message_id ORDER BY id) AS rn
+    FROM saved
+  ) ranked
+  WHERE rn > 1
+);
+
+ALTER TABLE saved DROP CONSTRAINT uq_saved_user_message;
+ALTER TABLE saved ADD CONSTRAINT saved_message_id_key UNIQUE (message_id);
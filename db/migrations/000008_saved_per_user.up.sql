@@ -0,0 +1,6 @@
+ALTER TABLE saved DROP CONSTRAINT saved_message_id_key;
+ALTER TABLE saved ADD CONSTRAINT uq_saved_user_message UNIQUE (user_id, message_id);
+
+ALTER TABLE saved ADD COLUMN saved_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX idx_saved_user_saved_at ON saved (user_id, saved_at DESC, id DESC);
@@ -0,0 +1,9 @@
+DROP INDEX idx_saved_tags;
+DROP INDEX idx_saved_collection;
+
+ALTER TABLE saved DROP COLUMN tags;
+ALTER TABLE saved DROP COLUMN note;
+ALTER TABLE saved DROP CONSTRAINT fk_collection;
+ALTER TABLE saved DROP COLUMN collection_id;
+
+DROP TABLE collection;
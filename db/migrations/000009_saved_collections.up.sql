@@ -0,0 +1,17 @@
+CREATE TABLE collection (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES web_user(id) ON DELETE CASCADE,
+  CONSTRAINT uq_collection_user_name UNIQUE (user_id, name)
+);
+
+-- items of deleted collection stay saved without collection
+ALTER TABLE saved ADD COLUMN collection_id INT;
+ALTER TABLE saved ADD CONSTRAINT fk_collection FOREIGN KEY (collection_id) REFERENCES collection(id) ON DELETE SET NULL;
+ALTER TABLE saved ADD COLUMN note TEXT NOT NULL DEFAULT '';
+ALTER TABLE saved ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_saved_collection ON saved (collection_id);
+CREATE INDEX idx_saved_tags ON saved USING GIN (tags);
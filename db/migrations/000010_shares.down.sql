@@ -0,0 +1,3 @@
+DROP TABLE share_member;
+DROP TABLE share_item;
+DROP TABLE share;
@@ -0,0 +1,31 @@
+CREATE TABLE share (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  title VARCHAR(100) NOT NULL DEFAULT '',
+  -- public link token, null when link is revoked
+  token VARCHAR(64) UNIQUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES web_user(id) ON DELETE CASCADE
+);
+
+-- share keeps messages, not saved rows, so unsaving message doesn't change shares
+CREATE TABLE share_item (
+  share_id INT NOT NULL,
+  message_id INT NOT NULL,
+  added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (share_id, message_id),
+  CONSTRAINT fk_share FOREIGN KEY (share_id) REFERENCES share(id) ON DELETE CASCADE,
+  CONSTRAINT fk_message FOREIGN KEY (message_id) REFERENCES message(id) ON DELETE CASCADE
+);
+
+CREATE TABLE share_member (
+  share_id INT NOT NULL,
+  user_id INT NOT NULL,
+  role VARCHAR(10) NOT NULL,
+  PRIMARY KEY (share_id, user_id),
+  CONSTRAINT fk_share FOREIGN KEY (share_id) REFERENCES share(id) ON DELETE CASCADE,
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES web_user(id) ON DELETE CASCADE,
+  CONSTRAINT chk_share_member_role CHECK (role IN ('viewer', 'editor'))
+);
+
+CREATE INDEX idx_share_member_user ON share_member (user_id);
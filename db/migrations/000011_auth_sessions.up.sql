@@ -0,0 +1,22 @@
+-- session is a family of refresh tokens issued by one sign-in, revoking it logs the family out
+CREATE TABLE auth_session (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  revoked_at TIMESTAMPTZ,
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES web_user(id) ON DELETE CASCADE
+);
+
+-- only sha256 of refresh token is stored, token is used once and then rotated
+CREATE TABLE refresh_token (
+  id SERIAL PRIMARY KEY,
+  session_id INT NOT NULL,
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT fk_session FOREIGN KEY (session_id) REFERENCES auth_session(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_auth_session_user ON auth_session (user_id);
+CREATE INDEX idx_refresh_token_session ON refresh_token (session_id);
@@ -0,0 +1,2 @@
+ALTER TABLE web_user DROP CONSTRAINT chk_web_user_role;
+ALTER TABLE web_user DROP COLUMN role;
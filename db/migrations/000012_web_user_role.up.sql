@@ -0,0 +1,2 @@
+ALTER TABLE web_user ADD COLUMN role VARCHAR(10) NOT NULL DEFAULT 'member';
+ALTER TABLE web_user ADD CONSTRAINT chk_web_user_role CHECK (role IN ('admin', 'member', 'readonly'));
@@ -0,0 +1,16 @@
+-- only sha256 of api key is stored, prefix is kept to tell keys apart in listings
+CREATE TABLE api_key (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  prefix VARCHAR(12) NOT NULL,
+  key_hash CHAR(64) NOT NULL UNIQUE,
+  scope VARCHAR(20) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_used_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ,
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES web_user(id) ON DELETE CASCADE,
+  CONSTRAINT chk_api_key_scope CHECK (scope IN ('read', 'saved-write'))
+);
+
+CREATE INDEX idx_api_key_user ON api_key (user_id);
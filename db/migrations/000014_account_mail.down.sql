@@ -0,0 +1,3 @@
+DROP TABLE mail_outbox;
+DROP TABLE account_token;
+ALTER TABLE web_user DROP COLUMN email_verified_at;
@@ -0,0 +1,29 @@
+ALTER TABLE web_user ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- single-use tokens sent by email, only sha256 of token is stored
+CREATE TABLE account_token (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('verify-email', 'reset-password')),
+  token_hash CHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES web_user(id) ON DELETE CASCADE
+);
+
+-- mails are written here together with the change which causes them and sent in background
+CREATE TABLE mail_outbox (
+  id SERIAL PRIMARY KEY,
+  recipient VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  body TEXT NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT,
+  send_after TIMESTAMPTZ NOT NULL DEFAULT now(),
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_account_token_user ON account_token (user_id, purpose);
+CREATE INDEX idx_mail_outbox_pending ON mail_outbox (send_after) WHERE sent_at IS NULL;
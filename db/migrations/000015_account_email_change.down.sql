@@ -0,0 +1,4 @@
+DELETE FROM account_token WHERE purpose = 'change-email';
+ALTER TABLE account_token DROP CONSTRAINT account_token_purpose_check;
+ALTER TABLE account_token ADD CONSTRAINT account_token_purpose_check CHECK (purpose IN ('verify-email', 'reset-password'));
+ALTER TABLE account_token DROP COLUMN new_email;
@@ -0,0 +1,4 @@
+-- new email waits in token of email change until it's confirmed from the new address
+ALTER TABLE account_token ADD COLUMN new_email VARCHAR(255);
+ALTER TABLE account_token DROP CONSTRAINT account_token_purpose_check;
+ALTER TABLE account_token ADD CONSTRAINT account_token_purpose_check CHECK (purpose IN ('verify-email', 'reset-password', 'change-email'));
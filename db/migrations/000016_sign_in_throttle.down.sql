@@ -0,0 +1,2 @@
+DROP TABLE audit_event;
+DROP TABLE sign_in_attempt;
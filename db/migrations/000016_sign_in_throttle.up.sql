@@ -0,0 +1,22 @@
+-- failed sign-in attempts are counted per account email and per client ip
+CREATE TABLE sign_in_attempt (
+  kind VARCHAR(10) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  failures INT NOT NULL DEFAULT 0,
+  last_failed_at TIMESTAMPTZ NOT NULL,
+  locked_until TIMESTAMPTZ,
+  PRIMARY KEY (kind, subject),
+  CONSTRAINT chk_sign_in_attempt_kind CHECK (kind IN ('account', 'ip'))
+);
+
+CREATE TABLE audit_event (
+  id BIGSERIAL PRIMARY KEY,
+  actor_id INT,
+  action VARCHAR(64) NOT NULL,
+  ip VARCHAR(45) NOT NULL DEFAULT '',
+  details JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT fk_actor FOREIGN KEY (actor_id) REFERENCES web_user(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_audit_event_created_at ON audit_event (created_at);
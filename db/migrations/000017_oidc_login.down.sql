@@ -0,0 +1,2 @@
+DROP TABLE oidc_login;
+DROP TABLE web_user_identity;
@@ -0,0 +1,20 @@
+-- accounts of external identity providers linked to web users, subject is unique only within issuer
+CREATE TABLE web_user_identity (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  issuer VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES web_user(id) ON DELETE CASCADE,
+  CONSTRAINT uq_web_user_identity UNIQUE (issuer, subject)
+);
+
+-- single-use state of started sso logins, only sha256 of state is stored
+CREATE TABLE oidc_login (
+  state_hash CHAR(64) PRIMARY KEY,
+  nonce VARCHAR(64) NOT NULL,
+  code_verifier VARCHAR(128) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_web_user_identity_user ON web_user_identity (user_id);
@@ -0,0 +1,13 @@
+DROP TRIGGER trg_audit_event_no_truncate ON audit_event;
+DROP TRIGGER trg_audit_event_append_only ON audit_event;
+DROP FUNCTION audit_event_append_only();
+
+DROP INDEX idx_audit_event_action;
+DROP INDEX idx_audit_event_actor;
+
+ALTER TABLE audit_event DROP COLUMN after_payload;
+ALTER TABLE audit_event DROP COLUMN before_payload;
+ALTER TABLE audit_event DROP COLUMN user_agent;
+
+UPDATE audit_event SET actor_id = NULL WHERE actor_id NOT IN (SELECT id FROM web_user);
+ALTER TABLE audit_event ADD CONSTRAINT fk_actor FOREIGN KEY (actor_id) REFERENCES web_user(id) ON DELETE SET NULL;
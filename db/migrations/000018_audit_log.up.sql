@@ -0,0 +1,22 @@
+-- actor id is kept after web user is deleted, so the trail still tells who did what
+ALTER TABLE audit_event DROP CONSTRAINT fk_actor;
+
+ALTER TABLE audit_event ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '';
+ALTER TABLE audit_event ADD COLUMN before_payload JSONB;
+ALTER TABLE audit_event ADD COLUMN after_payload JSONB;
+
+CREATE INDEX idx_audit_event_actor ON audit_event (actor_id, id);
+CREATE INDEX idx_audit_event_action ON audit_event (action, id);
+
+-- audit trail is append-only, events can't be changed or deleted
+CREATE FUNCTION audit_event_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_event is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_event_append_only BEFORE UPDATE OR DELETE ON audit_event
+FOR EACH ROW EXECUTE FUNCTION audit_event_append_only();
+
+CREATE TRIGGER trg_audit_event_no_truncate BEFORE TRUNCATE ON audit_event
+FOR EACH STATEMENT EXECUTE FUNCTION audit_event_append_only();
@@ -0,0 +1,2 @@
+-- empty usernames can't be restored, anonymous senders are kept in separate rows
+SELECT 1;
@@ -0,0 +1,2 @@
+-- anonymous senders were stored with empty username and shared one row, keep it without username
+UPDATE tg_user SET username = NULL WHERE username = '';
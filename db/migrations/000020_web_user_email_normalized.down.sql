@@ -0,0 +1,2 @@
+-- original case of email domains isn't kept, normalized emails stay as they are
+SELECT 1;
@@ -0,0 +1,23 @@
+-- emails are looked up in normalized form: without surrounding spaces and with domain in lower case,
+-- web users stored before normalization get the same form
+CREATE TEMP TABLE web_user_email AS
+SELECT id, email,
+  substring(btrim(email) from '^(.*)@') || '@' || lower(substring(btrim(email) from '@([^@]*)$')) AS normalized
+FROM web_user
+WHERE email LIKE '%@%';
+
+-- when several users normalize to the same email, user who already has it or the oldest one gets it,
+-- others keep their email untouched until admin resolves the conflict, so no account is lost
+DELETE FROM web_user_email WHERE id IN (
+  SELECT id FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY normalized ORDER BY email = normalized DESC, id) AS rn
+    FROM web_user_email
+  ) ranked
+  WHERE rn > 1
+);
+
+UPDATE web_user u SET email = e.normalized
+FROM web_user_email e
+WHERE u.id = e.id AND u.email <> e.normalized;
+
+DROP TABLE web_user_email;
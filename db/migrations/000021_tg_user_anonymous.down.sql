@@ -0,0 +1,2 @@
+-- merged anonymous senders can't be split again
+DROP INDEX IF EXISTS uq_tg_user_anonymous;
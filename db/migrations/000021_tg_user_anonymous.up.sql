@@ -0,0 +1,29 @@
+-- anonymous senders got a new row on every delivery, merge rows with the same profile into the oldest one
+CREATE TEMP TABLE anonymous_user AS
+SELECT id, MIN(id) OVER (PARTITION BY fullname, imageurl) AS keep_id
+FROM tg_user
+WHERE tg_id IS NULL AND username IS NULL;
+
+UPDATE message m SET user_id = a.keep_id FROM anonymous_user a WHERE m.user_id = a.id AND a.id <> a.keep_id;
+UPDATE replie r SET user_id = a.keep_id FROM anonymous_user a WHERE r.user_id = a.id AND a.id <> a.keep_id;
+
+-- replies without telegram id which were stored again on redelivery are dropped, the oldest copy is kept
+DELETE FROM replie r
+USING (
+  SELECT id, ROW_NUMBER() OVER (
+    PARTITION BY message_id, user_id, COALESCE(title, ''), COALESCE(imageurl, '') ORDER BY id
+  ) AS rn
+  FROM replie
+  WHERE reply_key LIKE 'md5:%' AND user_id IN (SELECT keep_id FROM anonymous_user)
+) ranked
+WHERE ranked.id = r.id AND ranked.rn > 1;
+
+-- remaining replies are keyed by the merged author the same way ingest does it
+UPDATE replie SET reply_key = 'md5:' || md5(user_id || '|' || COALESCE(title, '') || '|' || COALESCE(imageurl, ''))
+WHERE reply_key LIKE 'md5:%' AND user_id IN (SELECT keep_id FROM anonymous_user);
+
+DELETE FROM tg_user WHERE id IN (SELECT id FROM anonymous_user WHERE id <> keep_id);
+
+DROP TABLE anonymous_user;
+
+CREATE UNIQUE INDEX uq_tg_user_anonymous ON tg_user (fullname, imageurl) WHERE tg_id IS NULL AND username IS NULL;
@@ -19,123 +19,225 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/sign-in": {
-            "post": {
-                "description": "Handler will login user and return JWT token",
-                "consumes": [
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Handler will return public keys which verify access tokens, keys scheduled for rotation are included",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "JWKS",
+                "operationId": "get-jwks",
+                "responses": {
+                    "200": {
+                        "description": "json web key set",
+                        "schema": {
+                            "$ref": "#/definitions/model.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/audit": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will return page of audit events after cursor from query, latest events first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "sigi-up",
-                "operationId": "login-user",
+                "summary": "GetAuditEvents",
+                "operationId": "get-audit-events",
                 "parameters": [
                     {
-                        "description": "user info",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.WebUser"
-                        }
+                        "type": "string",
+                        "description": "cursor from next or prev of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 10 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id of web user who acted",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "action, for example sign-in.failed",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "client ip",
+                        "name": "ip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "events happened at or after this RFC3339 time or day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "events happened before this RFC3339 time or until the end of this day",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "token",
+                        "description": "audit events by page",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.AuditPage"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "404": {
-                        "description": "user with this email not found",
+                        "description": "audit events not found",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/sign-up": {
-            "post": {
-                "description": "Handler will create new user and return message",
-                "consumes": [
-                    "application/json"
+        "/admin/audit/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Handler will stream every audit event matching filter as newline delimited json, latest events first. Export is recorded in audit trail",
                 "produces": [
-                    "application/json"
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "sign-up",
-                "operationId": "create-user",
+                "summary": "ExportAuditEvents",
+                "operationId": "export-audit-events",
                 "parameters": [
                     {
-                        "description": "user info",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.WebUser"
-                        }
+                        "type": "integer",
+                        "description": "id of web user who acted",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "action, for example sign-in.failed",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "client ip",
+                        "name": "ip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "events happened at or after this RFC3339 time or day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "events happened before this RFC3339 time or until the end of this day",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user created",
+                        "description": "one audit event per line",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.AuditEvent"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
-                    "409": {
-                        "description": "user with email is exist",
+                    "401": {
+                        "description": "user is not authorized",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/channel/": {
+        "/admin/ingest-failure/": {
             "get": {
-                "description": "Handler will return channels by page from query",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will return kafka messages which could not be ingested by page from query",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "channel"
+                    "admin"
                 ],
-                "summary": "GetChannelsByPage",
-                "operationId": "get-channels-by-page",
+                "summary": "GetIngestFailuresByPage",
+                "operationId": "get-ingest-failures-by-page",
                 "parameters": [
                     {
                         "type": "integer",
@@ -147,464 +249,529 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "channels by page",
+                        "description": "ingest failures by page",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Channel"
+                                "$ref": "#/definitions/model.IngestFailure"
                             }
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "404": {
-                        "description": "channels not found",
+                        "description": "ingest failures not found",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/channel/count": {
-            "get": {
-                "description": "Handler will return channels count",
+        "/admin/ingest-failure/{failure_id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will ingest quarantined kafka message by id from url again and remove it on success",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "channel"
+                    "admin"
+                ],
+                "summary": "ReplayIngestFailure",
+                "operationId": "replay-ingest-failure",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ingest failure id",
+                        "name": "failure_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "GetChannelsCount",
-                "operationId": "get-channels-count",
                 "responses": {
                     "200": {
-                        "description": "channels count",
+                        "description": "ingest failure replayed",
                         "schema": {
-                            "type": "object"
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "404": {
-                        "description": "channels count not found",
+                        "description": "ingest failure not found",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/channel/{name}": {
-            "get": {
-                "description": "Handler will return channel by name from url",
+        "/admin/sign-in/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will forget failed sign-in attempts of account email and/or client ip, so they can sign in again right away",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "channel"
+                    "admin"
                 ],
-                "summary": "GetChannelByName",
-                "operationId": "get-channel-by-name",
+                "summary": "UnlockSignIn",
+                "operationId": "unlock-sign-in",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "channel name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "description": "email and/or ip to unlock",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UnlockSignInInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "channel by name",
+                        "description": "sign-in unlocked",
                         "schema": {
-                            "$ref": "#/definitions/model.Channel"
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "404": {
-                        "description": "channel not found",
+                        "description": "sign-in attempt not found",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/message/": {
-            "get": {
-                "description": "Handler will return full messages by page from query",
+        "/auth/confirm-email": {
+            "post": {
+                "description": "Handler will change email of user to the new one using token from email change mail",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "message"
+                    "auth"
                 ],
-                "summary": "GetFullMessagesByPage",
-                "operationId": "get-full-messages-by-page",
+                "summary": "confirm-email",
+                "operationId": "confirm-email",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "description": "token",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.VerifyEmailInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "full messages by page",
+                        "description": "email is changed",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.FullMessage"
-                            }
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
-                    "404": {
-                        "description": "full messages not found",
+                    "409": {
+                        "description": "web user with this email already exists",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/message/channel/{channel_id}": {
-            "get": {
-                "description": "Handler will return full messages by page from query and channel id from url",
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Handler will send mail with password reset link when user with email exists, response is the same for unknown email",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "message"
+                    "auth"
                 ],
-                "summary": "GetFullMessagesByChannelIDAndPage",
-                "operationId": "get-full-messages-by-page-and-channel-id",
+                "summary": "forgot-password",
+                "operationId": "forgot-password",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "channel id",
-                        "name": "channel_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "description": "user email",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ForgotPasswordInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "full messages by page and channel id",
+                        "description": "password reset mail is sent",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.FullMessage"
-                            }
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
-                        }
-                    },
-                    "404": {
-                        "description": "full messages not found",
-                        "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/message/count": {
-            "get": {
-                "description": "Handler will return messages count",
-                "produces": [
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will revoke session of access token, its access and refresh tokens stop working",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "message"
+                    "auth"
                 ],
-                "summary": "GetMessagesCount",
-                "operationId": "get-messages-count",
+                "summary": "logout",
+                "operationId": "logout",
                 "responses": {
                     "200": {
-                        "description": "messages count",
+                        "description": "logged out",
                         "schema": {
-                            "type": "object"
+                            "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "messages count not found",
+                    "401": {
+                        "description": "user is not authorized",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/message/count/{channel_id}": {
-            "get": {
-                "description": "Handler will return messages count by channel id from url",
+        "/auth/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will revoke every session of authorized user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "message"
-                ],
-                "summary": "GetMessagesByChannelIDCount",
-                "operationId": "get-messages-by-channel-id-count",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "channel id",
-                        "name": "channel_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "logout-all",
+                "operationId": "logout-all",
                 "responses": {
                     "200": {
-                        "description": "messages count",
-                        "schema": {
-                            "type": "object"
-                        }
-                    },
-                    "400": {
-                        "description": "bad request",
+                        "description": "logged out of all sessions",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "messages count not found",
+                    "401": {
+                        "description": "user is not authorized",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/message/user/{user_id}": {
+        "/auth/oidc/callback": {
             "get": {
-                "description": "Handler will return full messages by user id from url",
+                "description": "Handler will finish sign-in with identity provider and return JWT access token with refresh token. Unknown account is linked to user with the same verified email or new member is created",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "message"
+                    "auth"
                 ],
-                "summary": "GetFullMessagesByUserID",
-                "operationId": "get-full-messages-by-user-id",
+                "summary": "OIDCCallback",
+                "operationId": "oidc-callback",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "state of the sign-in",
+                        "name": "state",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "full messages by user id",
+                        "description": "tokens",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.FullMessage"
-                            }
+                            "$ref": "#/definitions/model.Tokens"
                         }
                     },
                     "400": {
-                        "description": "bad request",
+                        "description": "sign-in is expired or already finished",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "identity provider rejected sign-in or id token is not valid",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "identity provider didn't share email",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "404": {
-                        "description": "full messages not found",
+                        "description": "sign-in with identity provider is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "account with the same email exists and can't be linked",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "identity provider is not available",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/message/{message_id}": {
+        "/auth/oidc/login": {
             "get": {
-                "description": "Handler will return full message by id from url",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Handler will start sign-in with company identity provider and redirect to its sign-in page, the provider redirects back to callback",
                 "tags": [
-                    "message"
-                ],
-                "summary": "GetFullMessageByID",
-                "operationId": "get-full-message-by-id",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "message id",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "OIDCLogin",
+                "operationId": "oidc-login",
                 "responses": {
-                    "200": {
-                        "description": "full message by user id",
+                    "302": {
+                        "description": "redirect to identity provider",
                         "schema": {
-                            "$ref": "#/definitions/model.FullMessage"
-                        }
-                    },
-                    "400": {
-                        "description": "bad request",
-                        "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "type": "string"
                         }
                     },
                     "404": {
-                        "description": "full messages not found",
+                        "description": "sign-in with identity provider is not configured",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "identity provider is not available",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/replie/{message_id}": {
-            "get": {
-                "description": "Handler will return full replies by message id from url",
+        "/auth/refresh": {
+            "post": {
+                "description": "Handler will exchange refresh token for new access and refresh tokens, reused refresh token logs its session out",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "replie"
+                    "auth"
                 ],
-                "summary": "GetFullRepliesByMessageID",
-                "operationId": "get-full-replies-by-message-id",
+                "summary": "refresh",
+                "operationId": "refresh-tokens",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "message id",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
+                        "description": "refresh token",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RefreshInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "replies by message id",
+                        "description": "tokens",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.FullReplie"
-                            }
+                            "$ref": "#/definitions/model.Tokens"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
-                    "404": {
-                        "description": "replies not found",
+                    "401": {
+                        "description": "refresh token is not valid or already used",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/saved/create": {
+        "/auth/reset-password": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Handler will create saved message and return message",
+                "description": "Handler will set new password using token from password reset mail, every session of the user is logged out",
                 "consumes": [
                     "application/json"
                 ],
@@ -612,24 +779,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "saved"
+                    "auth"
                 ],
-                "summary": "CreateSavedMessage",
-                "operationId": "create-saved-message",
+                "summary": "reset-password",
+                "operationId": "reset-password",
                 "parameters": [
                     {
-                        "description": "saved message info",
+                        "description": "token and new password",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Saved"
+                            "$ref": "#/definitions/model.ResetPasswordInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "saved message created",
+                    "200": {
+                        "description": "password is reset",
                         "schema": {
                             "type": "string"
                         }
@@ -637,26 +804,21 @@ const docTemplate = `{
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/saved/delete/{message_id}": {
-            "delete": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Handler will delete saved message by id from url",
+        "/auth/sign-in": {
+            "post": {
+                "description": "Handler will login user and return short-lived JWT access token with refresh token, after too many failed attempts sign-in of account or client ip is locked for a while",
                 "consumes": [
                     "application/json"
                 ],
@@ -664,189 +826,3457 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "saved"
+                    "auth"
                 ],
-                "summary": "DeleteSavedMessage",
-                "operationId": "delete-saved-message",
+                "summary": "sigi-up",
+                "operationId": "login-user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "saved message id",
-                        "name": "message_id",
-                        "in": "path",
-                        "required": true
+                        "description": "user info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SignInInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "saved message deleted",
+                        "description": "tokens",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.Tokens"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "email or password is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "too many failed sign-in attempts",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/saved/{user_id}": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+        "/auth/sign-up": {
+            "post": {
+                "description": "Handler will validate user, create it and send mail with email verification link to the user, user isn't created when the mail can't be queued. Email is stored with domain in lower case, password has to be long enough and not known from data breaches",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Handler will return saved messages by user id from url",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "saved"
+                    "auth"
                 ],
-                "summary": "GetSavedMessages",
-                "operationId": "get-saved-messages",
+                "summary": "sign-up",
+                "operationId": "create-user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
+                        "description": "user info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WebUser"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "saved by user id",
+                    "201": {
+                        "description": "user created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Saved"
-                            }
+                            "type": "string"
                         }
                     },
                     "400": {
-                        "description": "bad request",
+                        "description": "bad request, invalid fields are listed in fields",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
-                    "404": {
-                        "description": "saved messages not found",
+                    "409": {
+                        "description": "user with email is exist",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
         },
-        "/user/{id}": {
-            "get": {
-                "description": "Handler will return user by id from url",
+        "/auth/verify-email": {
+            "post": {
+                "description": "Handler will mark email of user as verified using token from verification mail",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "auth"
                 ],
-                "summary": "GetUserByID",
-                "operationId": "get-user-by-id",
+                "summary": "verify-email",
+                "operationId": "verify-email",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "token",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.VerifyEmailInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user by id",
+                        "description": "email is verified",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "bad request",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
-                    "404": {
-                        "description": "user not found",
+                    "500": {
+                        "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/channel/": {
+            "get": {
+                "description": "Handler will return page of channels after cursor from query",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channel"
+                ],
+                "summary": "GetChannelsByPage",
+                "operationId": "get-channels-by-page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "cursor from next or prev of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 10 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "channels by page",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChannelsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "channels not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/channel/count": {
+            "get": {
+                "description": "Handler will return channels count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channel"
+                ],
+                "summary": "GetChannelsCount",
+                "operationId": "get-channels-count",
+                "responses": {
+                    "200": {
+                        "description": "channels count",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "404": {
+                        "description": "channels count not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/channel/{name}": {
+            "get": {
+                "description": "Handler will return channel by name from url",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channel"
+                ],
+                "summary": "GetChannelByName",
+                "operationId": "get-channel-by-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "channel name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "channel by name",
+                        "schema": {
+                            "$ref": "#/definitions/model.Channel"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will permanently delete authorized user with saved messages, collections, shares, sessions, api keys and linked identities, users created by sso sign-in have no password and must set it with forgot-password first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "DeleteAccount",
+                "operationId": "delete-account",
+                "parameters": [
+                    {
+                        "description": "current password",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeleteAccountInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "account deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "password is incorrect or api key can't be used for this action",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "web user not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will return not revoked api keys of authorized user, latest created first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-key"
+                ],
+                "summary": "GetAPIKeys",
+                "operationId": "get-api-keys",
+                "responses": {
+                    "200": {
+                        "description": "api keys of authorized user",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.APIKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "api key can't be used for this action",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "api keys not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will create api key for authorized user, the key is returned only in this response.\nApi key is sent in \"Authorization: ApiKey \u003ckey\u003e\" header. Key with read scope can only read,\nkey with saved-write scope can also change saved messages and collections, readonly users can't create it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-key"
+                ],
+                "summary": "CreateAPIKey",
+                "operationId": "create-api-key",
+                "parameters": [
+                    {
+                        "description": "api key info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.APIKeyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created api key",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "scope is not allowed for user role or api key can't be used for this action",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     },
                     "500": {
                         "description": "internal server error",
                         "schema": {
-                            "$ref": "#/definitions/lib.HttpError"
+                            "$ref": "#/definitions/lib.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "lib.HttpError": {
-            "description": "Http Error structure",
+        },
+        "/me/api-keys/{key_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will rename not revoked api key of authorized user by id from url, only name from body is used",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-key"
+                ],
+                "summary": "RenameAPIKey",
+                "operationId": "rename-api-key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "api key id",
+                        "name": "key_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "api key info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.APIKeyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "api key renamed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "api key can't be used for this action",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "api key not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will revoke api key of authorized user by id from url, requests with the key are rejected after it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-key"
+                ],
+                "summary": "RevokeAPIKey",
+                "operationId": "revoke-api-key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "api key id",
+                        "name": "key_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "api key revoked",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "api key can't be used for this action",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "api key not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/collections": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will return all collections of authorized user ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collection"
+                ],
+                "summary": "GetCollections",
+                "operationId": "get-collections",
+                "responses": {
+                    "200": {
+                        "description": "collections of authorized user",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Collection"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "collections not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will create collection for authorized user, only name from body is used",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collection"
+                ],
+                "summary": "CreateCollection",
+                "operationId": "create-collection",
+                "parameters": [
+                    {
+                        "description": "collection info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Collection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "collection created",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "collection with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/collections/{collection_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will rename collection of authorized user by id from url",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collection"
+                ],
+                "summary": "UpdateCollection",
+                "operationId": "update-collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "collection id",
+                        "name": "collection_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "collection info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Collection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "collection updated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "collection with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will delete collection of authorized user by id from url, its messages stay saved without collection",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collection"
+                ],
+                "summary": "DeleteCollection",
+                "operationId": "delete-collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "collection id",
+                        "name": "collection_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "collection deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/email": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will send mail with confirmation link to the new email of authorized user, email is changed when the link is opened, users created by sso sign-in have no password and must set it with forgot-password first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "ChangeEmail",
+                "operationId": "change-email",
+                "parameters": [
+                    {
+                        "description": "new email and current password",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangeEmailInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "confirmation mail is sent to new email",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "password is incorrect or api key can't be used for this action",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "web user with this email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will return everything stored about authorized user as downloadable json file",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "ExportAccount",
+                "operationId": "export-account",
+                "responses": {
+                    "200": {
+                        "description": "account export",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountExport"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "api key can't be used for this action",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "web user not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/password": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will set new password of authorized user after checking the old one, other sessions of the user are logged out, users created by sso sign-in have no password and must set it with forgot-password first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "ChangePassword",
+                "operationId": "change-password",
+                "parameters": [
+                    {
+                        "description": "old and new password",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangePasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "password is changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "password is incorrect or api key can't be used for this action",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/saved": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will return page of messages saved by authorized user with full message info after cursor from query",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved"
+                ],
+                "summary": "GetSavedMessages",
+                "operationId": "get-saved-messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "cursor from next or prev of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 10 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "collection id",
+                        "name": "collection",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "saved messages which have all of tags, can be repeated or separated by commas",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "saved messages of authorized user",
+                        "schema": {
+                            "$ref": "#/definitions/model.SavedPage"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "saved messages not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will save message for authorized user with optional collection, note and tags, user id from body is ignored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved"
+                ],
+                "summary": "CreateSavedMessage",
+                "operationId": "create-saved-message",
+                "parameters": [
+                    {
+                        "description": "saved message info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Saved"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "saved message created",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "message is already saved",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/saved/{saved_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will replace collection, note and tags of saved message of authorized user, null collection id moves message out of collection",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved"
+                ],
+                "summary": "UpdateSavedMessage",
+                "operationId": "update-saved-message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "saved message id",
+                        "name": "saved_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "saved message info, only collection id, note and tags are used",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Saved"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "saved message updated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "saved message or collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will delete saved message of authorized user by id from url",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "saved"
+                ],
+                "summary": "DeleteSavedMessage",
+                "operationId": "delete-saved-message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "saved message id",
+                        "name": "saved_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "saved message deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "saved message not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/shares": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will return shares which authorized user owns or was granted, latest created first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "GetShares",
+                "operationId": "get-shares",
+                "responses": {
+                    "200": {
+                        "description": "shares of authorized user",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Share"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "shares not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will create share of chosen saved messages of authorized user and return it with public link token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "CreateShare",
+                "operationId": "create-share",
+                "parameters": [
+                    {
+                        "description": "share info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ShareInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created share",
+                        "schema": {
+                            "$ref": "#/definitions/model.Share"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/shares/{share_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will return messages of share which authorized user owns or was granted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "GetShare",
+                "operationId": "get-share",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "share id",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "shared messages",
+                        "schema": {
+                            "$ref": "#/definitions/model.SharedMessages"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "share not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will delete share of authorized user, only owner can delete share",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "DeleteShare",
+                "operationId": "delete-share",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "share id",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "share deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not share owner",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "share not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/shares/{share_id}/items": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will add messages of chosen saved messages of authorized user to share, owner and editors can add messages",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "AddShareItems",
+                "operationId": "add-share-items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "share id",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "saved ids, title is ignored",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ShareInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "messages added to share",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not share owner or editor",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "share not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/shares/{share_id}/items/{message_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will remove message from share, owner and editors can remove messages",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "DeleteShareItem",
+                "operationId": "delete-share-item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "share id",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "message id",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message removed from share",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not share owner or editor",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "share or shared message not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/shares/{share_id}/link": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will give share a new public link and return share with its token, previous link stops working",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "RotateShareLink",
+                "operationId": "rotate-share-link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "share id",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "share with new public link token",
+                        "schema": {
+                            "$ref": "#/definitions/model.Share"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not share owner",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "share not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will disable public link of share, members of share keep their access",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "RevokeShareLink",
+                "operationId": "revoke-share-link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "share id",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "share link revoked",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not share owner",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "share not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/shares/{share_id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will return web users who were granted access to share, only owner can see them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "GetShareMembers",
+                "operationId": "get-share-members",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "share id",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "share members",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ShareMember"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not share owner",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "share members not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will give web user with email from body viewer or editor access to share, role of existing member is replaced",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "GrantShareMember",
+                "operationId": "grant-share-member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "share id",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "email and role of member",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ShareMember"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "share member granted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not share owner",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "share or web user not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/shares/{share_id}/members/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Handler will take away access to share from web user by id from url",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "RevokeShareMember",
+                "operationId": "revoke-share-member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "share id",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "web user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "share member revoked",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "user is not authorized",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not share owner",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "share or share member not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/message/": {
+            "get": {
+                "description": "Handler will return page of full messages matching filters from query after cursor, newest messages first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "GetFullMessagesByPage",
+                "operationId": "get-full-messages-by-page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "cursor from next or prev of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 10 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "channel id",
+                        "name": "channel_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "channel names",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "telegram id of message author",
+                        "name": "tg_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted at or after this RFC3339 time or day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted before this RFC3339 time or until the end of this day",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum replies count",
+                        "name": "min_replies",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "messages with or without image",
+                        "name": "has_image",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "messages without replies",
+                        "name": "no_replies",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "full messages by page",
+                        "schema": {
+                            "$ref": "#/definitions/model.FullMessagesPage"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "full messages not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/message/channel/{channel_id}": {
+            "get": {
+                "description": "Handler will return page of full messages of channel from url, it's the same as /message/ with channel_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "GetFullMessagesByChannelIDAndPage",
+                "operationId": "get-full-messages-by-page-and-channel-id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "channel id",
+                        "name": "channel_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor from next or prev of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 10 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "channel names",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "telegram id of message author",
+                        "name": "tg_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted at or after this RFC3339 time or day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted before this RFC3339 time or until the end of this day",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum replies count",
+                        "name": "min_replies",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "messages with or without image",
+                        "name": "has_image",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "messages without replies",
+                        "name": "no_replies",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "full messages by page and channel id",
+                        "schema": {
+                            "$ref": "#/definitions/model.FullMessagesPage"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "full messages not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/message/count": {
+            "get": {
+                "description": "Handler will return count of messages matching filters from query",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "GetMessagesCount",
+                "operationId": "get-messages-count",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "channel id",
+                        "name": "channel_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "channel names",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "telegram id of message author",
+                        "name": "tg_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted at or after this RFC3339 time or day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted before this RFC3339 time or until the end of this day",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum replies count",
+                        "name": "min_replies",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "messages with or without image",
+                        "name": "has_image",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "messages without replies",
+                        "name": "no_replies",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "messages count",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "messages count not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/message/count/{channel_id}": {
+            "get": {
+                "description": "Handler will return count of messages of channel from url, it's the same as /message/count with channel_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "GetMessagesByChannelIDCount",
+                "operationId": "get-messages-by-channel-id-count",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "channel id",
+                        "name": "channel_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "channel names",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "telegram id of message author",
+                        "name": "tg_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted at or after this RFC3339 time or day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted before this RFC3339 time or until the end of this day",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum replies count",
+                        "name": "min_replies",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "messages with or without image",
+                        "name": "has_image",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "messages without replies",
+                        "name": "no_replies",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "messages count",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "messages count not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/message/user/{user_id}": {
+            "get": {
+                "description": "Handler will return full messages by user id from url",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "GetFullMessagesByUserID",
+                "operationId": "get-full-messages-by-user-id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "full messages by user id",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.FullMessage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "full messages not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/message/{message_id}": {
+            "get": {
+                "description": "Handler will return full message by id from url",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "message"
+                ],
+                "summary": "GetFullMessageByID",
+                "operationId": "get-full-message-by-id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "message id",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "full message by user id",
+                        "schema": {
+                            "$ref": "#/definitions/model.FullMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "full messages not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/replie/{message_id}": {
+            "get": {
+                "description": "Handler will return page of full replies after cursor from query by message id from url, newest replies first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "replie"
+                ],
+                "summary": "GetFullRepliesByMessageID",
+                "operationId": "get-full-replies-by-message-id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "message id",
+                        "name": "message_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor from next or prev of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, 10 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "replies by message id",
+                        "schema": {
+                            "$ref": "#/definitions/model.FullRepliesPage"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "replies not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Handler will return full messages whose title or replies match search query, best matches first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "SearchMessages",
+                "operationId": "search-messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search query, supports quotes, OR and -",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "language of the query, for example en, ru or uk",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "channel names",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "telegram id of message author",
+                        "name": "tg_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted at or after this RFC3339 time or day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "messages posted before this RFC3339 time or until the end of this day",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "search results",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "search results not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/{token}": {
+            "get": {
+                "description": "Handler will return messages of share by public link token, no authorization is needed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share"
+                ],
+                "summary": "GetPublicSharedMessages",
+                "operationId": "get-public-shared-messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "public link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "shared messages",
+                        "schema": {
+                            "$ref": "#/definitions/model.SharedMessages"
+                        }
+                    },
+                    "404": {
+                        "description": "share not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "description": "Handler will return user by id from url",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "GetUserByID",
+                "operationId": "get-user-by-id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user by id",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/lib.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "lib.FieldError": {
+            "description": "Problem with input field",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Name of invalid field example: password",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "What is wrong with the field example: must be at least 8 characters long",
+                    "type": "string"
+                }
+            }
+        },
+        "lib.Problem": {
+            "description": "Error in RFC 7807 problem details format, problem type is always about:blank so it's omitted",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Stable machine-readable error code example: MESSAGE_NOT_FOUND",
+                    "type": "string"
+                },
+                "correlationId": {
+                    "description": "Id of request under which error is logged, it's also sent in X-Request-ID header",
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "What went wrong, details of server errors are hidden example: full message not found",
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Problems with input fields, they are set only for invalid input",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/lib.FieldError"
+                    }
+                },
+                "status": {
+                    "description": "Http status code example: 404",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Http status name example: Not Found",
+                    "type": "string"
+                }
+            }
+        },
+        "model.APIKey": {
+            "description": "Personal api key of web user",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Time when api key was created",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Api key id example: 1",
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Api key, it's returned only once on creation",
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "description": "Time when api key was used last time",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Api key name example: notebook",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "First characters of api key example: sbk_3kT9aQ1x",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Api key scope example: read",
+                    "type": "string",
+                    "enum": [
+                        "read",
+                        "saved-write"
+                    ]
+                }
+            }
+        },
+        "model.APIKeyInput": {
+            "description": "Api key info for creating and renaming api key",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Api key name example: notebook",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Api key scope example: read",
+                    "type": "string",
+                    "enum": [
+                        "read",
+                        "saved-write"
+                    ]
+                }
+            }
+        },
+        "model.AccountExport": {
+            "description": "Everything stored about web user",
+            "type": "object",
+            "properties": {
+                "account": {
+                    "description": "Account of user",
+                    "$ref": "#/definitions/model.AccountInfo"
+                },
+                "apiKeys": {
+                    "description": "Not revoked api keys of user",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.APIKey"
+                    }
+                },
+                "collections": {
+                    "description": "Collections of user",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Collection"
+                    }
+                },
+                "exportedAt": {
+                    "description": "Time when export was made",
+                    "type": "string"
+                },
+                "identities": {
+                    "description": "Accounts of identity providers linked to user",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebUserIdentity"
+                    }
+                },
+                "savedMessages": {
+                    "description": "Every saved message of user",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SavedMessage"
+                    }
+                },
+                "sessions": {
+                    "description": "Sign-in sessions of user",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Session"
+                    }
+                },
+                "shares": {
+                    "description": "Shares owned by user or shared with user",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Share"
+                    }
+                }
+            }
+        },
+        "model.AccountInfo": {
+            "description": "Account of web user without password",
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "User email example: test@test.com",
+                    "type": "string"
+                },
+                "emailVerifiedAt": {
+                    "description": "Time when email was verified",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "User id example: 1",
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "User role example: member",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditEvent": {
+            "description": "Audit event, events are never changed after they are created",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action example: saved.deleted",
+                    "type": "string"
+                },
+                "actorId": {
+                    "description": "Id of web user who acted, empty for anonymous actions example: 1",
+                    "type": "integer"
+                },
+                "after": {
+                    "description": "Changed data after action",
+                    "type": "object"
+                },
+                "before": {
+                    "description": "Changed data before action",
+                    "type": "object"
+                },
+                "createdAt": {
+                    "description": "Time of action",
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Additional info about action",
+                    "type": "object"
+                },
+                "id": {
+                    "description": "Event id example: 1",
+                    "type": "integer"
+                },
+                "ip": {
+                    "description": "Client ip example: 192.0.2.1",
+                    "type": "string"
+                },
+                "userAgent": {
+                    "description": "Client user agent example: curl/7.81.0",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditPage": {
+            "description": "Page of audit events, latest first",
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "description": "Whether there are more rows in the requested direction",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuditEvent"
+                    }
+                },
+                "next": {
+                    "description": "Cursor of the next page, empty on the last page",
+                    "type": "string"
+                },
+                "prev": {
+                    "description": "Cursor of the previous page, empty on the first page",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChangeEmailInput": {
+            "description": "Change email input model",
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "New email example: new@test.com",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Current password",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChangePasswordInput": {
+            "description": "Change password input model",
+            "type": "object",
+            "properties": {
+                "newPassword": {
+                    "description": "New password",
+                    "type": "string"
+                },
+                "oldPassword": {
+                    "description": "Current password",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Channel": {
+            "description": "Channel model",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "channel id example: 1",
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "description": "channel image url from firebase",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "channel name example: go_go",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "channel title example: GO ukrainian community",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChannelsPage": {
+            "description": "Page of channels",
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "description": "Whether there are more rows in the requested direction",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Channel"
+                    }
+                },
+                "next": {
+                    "description": "Cursor of the next page, empty on the last page",
+                    "type": "string"
+                },
+                "prev": {
+                    "description": "Cursor of the previous page, empty on the first page",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Collection": {
+            "description": "Named collection of saved messages",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Time when collection was created",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Collection id example: 1",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Collection name example: Golang jobs",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "Collection owner id example: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DeleteAccountInput": {
+            "description": "Delete account input model",
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "Current password",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ForgotPasswordInput": {
+            "description": "Forgot password input model",
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "User email example: test@test.com",
+                    "type": "string"
+                }
+            }
+        },
+        "model.FullMessage": {
+            "description": "Full message model includes all info about message",
+            "type": "object",
+            "properties": {
+                "channelImageUrl": {
+                    "description": "Channel image url from firebase",
+                    "type": "string"
+                },
+                "channelName": {
+                    "description": "Channel name example: go_go",
+                    "type": "string"
+                },
+                "channelTitle": {
+                    "description": "Channel title example: GO ukrainian community",
+                    "type": "string"
+                },
+                "editedAt": {
+                    "description": "Message last edit time in telegram, null if message was not edited",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Message id example: 1",
+                    "type": "integer"
+                },
+                "ingestedAt": {
+                    "description": "Time when message was stored",
+                    "type": "string"
+                },
+                "messageImageUrl": {
+                    "description": "Message image url from firebase",
+                    "type": "string"
+                },
+                "messageUrl": {
+                    "description": "Message url from telegram",
+                    "type": "string"
+                },
+                "postedAt": {
+                    "description": "Message posting time in telegram",
+                    "type": "string"
+                },
+                "replies": {
+                    "description": "Replies",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FullReplie"
+                    }
+                },
+                "repliesCount": {
+                    "description": "Replies count example: 50",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Message title example: Hello, anyone works with Golang?",
+                    "type": "string"
+                },
+                "userFullname": {
+                    "description": "User fullname example: Ivan Petrovich",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "User id example: 1",
+                    "type": "integer"
+                },
+                "userImageUrl": {
+                    "description": "User image url from firebase",
+                    "type": "string"
+                }
+            }
+        },
+        "model.FullMessagesPage": {
+            "description": "Page of full messages",
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "description": "Whether there are more rows in the requested direction",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FullMessage"
+                    }
+                },
+                "next": {
+                    "description": "Cursor of the next page, empty on the last page",
+                    "type": "string"
+                },
+                "prev": {
+                    "description": "Cursor of the previous page, empty on the first page",
+                    "type": "string"
+                }
+            }
+        },
+        "model.FullReplie": {
+            "description": "Full replie model includes all info about replie",
+            "type": "object",
+            "properties": {
+                "editedAt": {
+                    "description": "Replie last edit time in telegram, null if replie was not edited",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Replies id example: 1",
+                    "type": "integer"
+                },
+                "imageurl": {
+                    "description": "Replie image url from firebase",
+                    "type": "string"
+                },
+                "ingestedAt": {
+                    "description": "Time when replie was stored",
+                    "type": "string"
+                },
+                "messageId": {
+                    "description": "Replie message id example: 1",
+                    "type": "integer"
+                },
+                "postedAt": {
+                    "description": "Replie posting time in telegram",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Replie title example: Yes",
+                    "type": "string"
+                },
+                "userFullname": {
+                    "description": "Replie user fullname example: Ivan Petrovich",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "Replie user id example: 1",
+                    "type": "integer"
+                },
+                "userImageUrl": {
+                    "description": "Replie user image url from firebase",
+                    "type": "string"
+                }
+            }
+        },
+        "model.FullRepliesPage": {
+            "description": "Page of full replies",
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "description": "Whether there are more rows in the requested direction",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FullReplie"
+                    }
+                },
+                "next": {
+                    "description": "Cursor of the next page, empty on the last page",
+                    "type": "string"
+                },
+                "prev": {
+                    "description": "Cursor of the previous page, empty on the first page",
+                    "type": "string"
+                }
+            }
+        },
+        "model.IngestFailure": {
+            "description": "Kafka message which could not be ingested",
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Ingestion attempts example: 3",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "description": "Time of the first failure",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Last ingestion error",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Ingest failure id example: 1",
+                    "type": "integer"
+                },
+                "offset": {
+                    "description": "Kafka offset example: 42",
+                    "type": "integer"
+                },
+                "partition": {
+                    "description": "Kafka partition example: 0",
+                    "type": "integer"
+                },
+                "payload": {
+                    "description": "Raw message value encoded with base64",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "topic": {
+                    "description": "Kafka topic example: messages.get",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "Time of the last failure",
+                    "type": "string"
+                }
+            }
+        },
+        "model.JWK": {
+            "description": "JSON Web Key model, fields which are not used by key type are omitted",
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "description": "Signing algorithm example: RS256",
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "Curve of OKP key example: Ed25519",
+                    "type": "string"
+                },
+                "e": {
+                    "description": "RSA public exponent example: AQAB",
+                    "type": "string"
+                },
+                "kid": {
+                    "description": "Key id example: 2022-07",
+                    "type": "string"
+                },
+                "kty": {
+                    "description": "Key type example: RSA",
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA modulus",
+                    "type": "string"
+                },
+                "use": {
+                    "description": "Key usage example: sig",
+                    "type": "string"
+                },
+                "x": {
+                    "description": "OKP public key",
+                    "type": "string"
+                }
+            }
+        },
+        "model.JWKS": {
+            "description": "JSON Web Key Set model",
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.JWK"
+                    }
+                }
+            }
+        },
+        "model.RefreshInput": {
+            "description": "Refresh input model",
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "Http status code",
-                    "type": "integer"
-                },
-                "message": {
-                    "description": "Error message",
+                "refreshToken": {
+                    "description": "Refresh token from sign-in or previous refresh",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ResetPasswordInput": {
+            "description": "Reset password input model",
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "New password",
                     "type": "string"
                 },
-                "name": {
-                    "description": "Http status name",
+                "token": {
+                    "description": "Token from password reset email",
                     "type": "string"
                 }
             }
         },
-        "model.Channel": {
-            "description": "Channel model",
+        "model.Saved": {
+            "description": "Saved message model",
             "type": "object",
             "properties": {
+                "collectionId": {
+                    "description": "Collection id, null if message is not in collection example: 1",
+                    "type": "integer"
+                },
                 "id": {
-                    "description": "channel id example: 1",
+                    "description": "Saved id example: 1",
                     "type": "integer"
                 },
-                "imageUrl": {
-                    "description": "channel image url from firebase",
-                    "type": "string"
+                "messageId": {
+                    "description": "Saved message id example: 1",
+                    "type": "integer"
                 },
-                "name": {
-                    "description": "channel name example: go_go",
+                "note": {
+                    "description": "Private note of user example: check later",
                     "type": "string"
                 },
-                "title": {
-                    "description": "channel title example: GO ukrainian community",
-                    "type": "string"
+                "tags": {
+                    "description": "Tags of saved message example: golang,jobs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userId": {
+                    "description": "Saved user id example: 1",
+                    "type": "integer"
                 }
             }
         },
-        "model.FullMessage": {
-            "description": "Full message model includes all info about message",
+        "model.SavedMessage": {
+            "description": "Saved message with all info about message",
             "type": "object",
             "properties": {
                 "channelImageUrl": {
@@ -861,10 +4291,22 @@ const docTemplate = `{
                     "description": "Channel title example: GO ukrainian community",
                     "type": "string"
                 },
+                "collectionId": {
+                    "description": "Collection id, null if message is not in collection example: 1",
+                    "type": "integer"
+                },
+                "editedAt": {
+                    "description": "Message last edit time in telegram, null if message was not edited",
+                    "type": "string"
+                },
                 "id": {
                     "description": "Message id example: 1",
                     "type": "integer"
                 },
+                "ingestedAt": {
+                    "description": "Time when message was stored",
+                    "type": "string"
+                },
                 "messageImageUrl": {
                     "description": "Message image url from firebase",
                     "type": "string"
@@ -873,6 +4315,14 @@ const docTemplate = `{
                     "description": "Message url from telegram",
                     "type": "string"
                 },
+                "note": {
+                    "description": "Private note of user example: check later",
+                    "type": "string"
+                },
+                "postedAt": {
+                    "description": "Message posting time in telegram",
+                    "type": "string"
+                },
                 "replies": {
                     "description": "Replies",
                     "type": "array",
@@ -884,6 +4334,21 @@ const docTemplate = `{
                     "description": "Replies count example: 50",
                     "type": "integer"
                 },
+                "savedAt": {
+                    "description": "Time when message was saved by user",
+                    "type": "string"
+                },
+                "savedId": {
+                    "description": "Saved id example: 1",
+                    "type": "integer"
+                },
+                "tags": {
+                    "description": "Tags of saved message example: golang,jobs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "title": {
                     "description": "Message title example: Hello, anyone works with Golang?",
                     "type": "string"
@@ -902,51 +4367,270 @@ const docTemplate = `{
                 }
             }
         },
-        "model.FullReplie": {
-            "description": "Full replie model includes all info about replie",
+        "model.SavedPage": {
+            "description": "Page of saved messages",
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "description": "Whether there are more rows in the requested direction",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SavedMessage"
+                    }
+                },
+                "next": {
+                    "description": "Cursor of the next page, empty on the last page",
+                    "type": "string"
+                },
+                "prev": {
+                    "description": "Cursor of the previous page, empty on the first page",
+                    "type": "string"
+                }
+            }
+        },
+        "model.SearchResult": {
+            "description": "Search result is full message with its rank and highlighted snippet of the matched text",
             "type": "object",
             "properties": {
+                "channelImageUrl": {
+                    "description": "Channel image url from firebase",
+                    "type": "string"
+                },
+                "channelName": {
+                    "description": "Channel name example: go_go",
+                    "type": "string"
+                },
+                "channelTitle": {
+                    "description": "Channel title example: GO ukrainian community",
+                    "type": "string"
+                },
+                "editedAt": {
+                    "description": "Message last edit time in telegram, null if message was not edited",
+                    "type": "string"
+                },
                 "id": {
-                    "description": "Replies id example: 1",
+                    "description": "Message id example: 1",
                     "type": "integer"
                 },
-                "messageId": {
-                    "description": "Replie message id example: 1",
+                "ingestedAt": {
+                    "description": "Time when message was stored",
+                    "type": "string"
+                },
+                "matchedReplieId": {
+                    "description": "Id of the replie which matched, null when message matched itself",
+                    "type": "integer"
+                },
+                "messageImageUrl": {
+                    "description": "Message image url from firebase",
+                    "type": "string"
+                },
+                "messageUrl": {
+                    "description": "Message url from telegram",
+                    "type": "string"
+                },
+                "postedAt": {
+                    "description": "Message posting time in telegram",
+                    "type": "string"
+                },
+                "rank": {
+                    "description": "Search rank example: 0.0607927",
+                    "type": "number"
+                },
+                "replies": {
+                    "description": "Replies",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FullReplie"
+                    }
+                },
+                "repliesCount": {
+                    "description": "Replies count example: 50",
                     "type": "integer"
                 },
+                "snippet": {
+                    "description": "Matched text with highlighted words example: anyone works with \u003cb\u003eGolang\u003c/b\u003e?",
+                    "type": "string"
+                },
                 "title": {
-                    "description": "Replie title example: Yes",
+                    "description": "Message title example: Hello, anyone works with Golang?",
                     "type": "string"
                 },
                 "userFullname": {
-                    "description": "Replie user fullname example: Ivan Petrovich",
+                    "description": "User fullname example: Ivan Petrovich",
                     "type": "string"
                 },
                 "userId": {
-                    "description": "Replie user id example: 1",
+                    "description": "User id example: 1",
                     "type": "integer"
                 },
                 "userImageUrl": {
-                    "description": "Replie user image url from firebase",
+                    "description": "User image url from firebase",
                     "type": "string"
                 }
             }
         },
-        "model.Saved": {
-            "description": "Saved message model",
+        "model.Session": {
             "type": "object",
             "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
                 "id": {
-                    "description": "Saved id example: 1",
                     "type": "integer"
                 },
-                "messageId": {
-                    "description": "Saved message id example: 1",
+                "revokedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Share": {
+            "description": "Share of saved messages",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Time when share was created",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Share id example: 1",
                     "type": "integer"
                 },
+                "role": {
+                    "description": "Access of authorized user to share",
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor",
+                        "owner"
+                    ]
+                },
+                "title": {
+                    "description": "Share title example: Golang jobs",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Public link token, only owner sees it, null when link is revoked",
+                    "type": "string"
+                },
                 "userId": {
-                    "description": "Saved user id example: 1",
+                    "description": "Share owner id example: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ShareInput": {
+            "description": "Share info for creating share",
+            "type": "object",
+            "properties": {
+                "savedIds": {
+                    "description": "Ids of saved messages of user which are added to share example: 1,2",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
+                    "description": "Share title example: Golang jobs",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ShareMember": {
+            "description": "Web user with access to share",
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "Web user email example: test@test.com",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Access of authorized user to share",
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor"
+                    ]
+                },
+                "shareId": {
+                    "description": "Share id example: 1",
+                    "type": "integer"
+                },
+                "userId": {
+                    "description": "Web user id example: 2",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SharedMessages": {
+            "description": "Share with its messages",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Time when share was created",
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Shared messages, latest added first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FullMessage"
+                    }
+                },
+                "title": {
+                    "description": "Share title example: Golang jobs",
+                    "type": "string"
+                }
+            }
+        },
+        "model.SignInInput": {
+            "description": "Sign in input model",
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "User email example: test@test.com",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "User password",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Tokens": {
+            "description": "Tokens model",
+            "type": "object",
+            "properties": {
+                "expiresIn": {
+                    "description": "Access token lifetime in seconds example: 900",
                     "type": "integer"
+                },
+                "refreshToken": {
+                    "description": "Refresh token, it can be exchanged for new tokens only once",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Short-lived JWT access token",
+                    "type": "string"
+                }
+            }
+        },
+        "model.UnlockSignInInput": {
+            "description": "Unlock sign-in input model, at least one field is required",
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "Email of locked account example: test@test.com",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "Locked client ip example: 192.0.2.1",
+                    "type": "string"
                 }
             }
         },
@@ -966,12 +4650,26 @@ const docTemplate = `{
                     "description": "User image url from firebase",
                     "type": "string"
                 },
+                "tgId": {
+                    "description": "User telegram id example: 5234582918",
+                    "type": "integer"
+                },
                 "username": {
                     "description": "User username example: ivanptr21",
                     "type": "string"
                 }
             }
         },
+        "model.VerifyEmailInput": {
+            "description": "Verify email input model",
+            "type": "object",
+            "properties": {
+                "token": {
+                    "description": "Token from verification email",
+                    "type": "string"
+                }
+            }
+        },
         "model.WebUser": {
             "description": "User model",
             "type": "object",
@@ -987,6 +4685,37 @@ const docTemplate = `{
                 "password": {
                     "description": "user Password example: d1e8a70b5ccab1dc2f56bbf7e99f064a660c08e361a35751b9c483c88943d082",
                     "type": "string"
+                },
+                "role": {
+                    "description": "User role example: member",
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "member",
+                        "readonly"
+                    ]
+                }
+            }
+        },
+        "model.WebUserIdentity": {
+            "description": "Account of identity provider linked to web user",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Time when identity was linked",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Identity id example: 1",
+                    "type": "integer"
+                },
+                "issuer": {
+                    "description": "Issuer of identity provider example: https://sso.example.com",
+                    "type": "string"
+                },
+                "subject": {
+                    "description": "User id at identity provider example: 1001",
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/internal/validation"
+)
+
+// ForgotPasswordHandler godoc
+// @ID           forgot-password
+// @Summary      forgot-password
+// @Description  Handler will send mail with password reset link when user with email exists, response is the same for unknown email
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.ForgotPasswordInput  true  "user email"
+// @Success      200    {string}  string                     "password reset mail is sent"
+// @Failure      400    {object}  lib.Problem                "bad request"
+// @Failure      500    {object}  lib.Problem                "internal server error"
+// @Router       /auth/forgot-password [post]
+func (h *Handler) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	input := model.ForgotPasswordInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	if input.Email == "" {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("email is empty"))
+
+		return
+	}
+
+	if err := h.service.Account.RequestPasswordReset(input.Email); err != nil {
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("email", input.Email))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "password reset mail is sent")
+}
+
+// ResetPasswordHandler godoc
+// @ID           reset-password
+// @Summary      reset-password
+// @Description  Handler will set new password using token from password reset mail, every session of the user is logged out
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.ResetPasswordInput  true  "token and new password"
+// @Success      200    {string}  string                    "password is reset"
+// @Failure      400    {object}  lib.Problem               "bad request"
+// @Failure      500    {object}  lib.Problem               "internal server error"
+// @Router       /auth/reset-password [post]
+func (h *Handler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	input := model.ResetPasswordInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	if input.Token == "" {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("token is empty"))
+
+		return
+	}
+
+	if err := h.service.Account.ResetPassword(input.Token, input.Password, requestOrigin(r)); err != nil {
+		h.writeAccountError(w, r, err)
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "password is reset")
+}
+
+// VerifyEmailHandler godoc
+// @ID           verify-email
+// @Summary      verify-email
+// @Description  Handler will mark email of user as verified using token from verification mail
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.VerifyEmailInput  true  "token"
+// @Success      200    {string}  string                  "email is verified"
+// @Failure      400    {object}  lib.Problem             "bad request"
+// @Failure      500    {object}  lib.Problem             "internal server error"
+// @Router       /auth/verify-email [post]
+func (h *Handler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	input := model.VerifyEmailInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	if input.Token == "" {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("token is empty"))
+
+		return
+	}
+
+	if err := h.service.Account.VerifyEmail(input.Token); err != nil {
+		h.writeAccountError(w, r, err)
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "email is verified")
+}
+
+// ConfirmEmailHandler godoc
+// @ID           confirm-email
+// @Summary      confirm-email
+// @Description  Handler will change email of user to the new one using token from email change mail
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.VerifyEmailInput  true  "token"
+// @Success      200    {string}  string                  "email is changed"
+// @Failure      400    {object}  lib.Problem             "bad request"
+// @Failure      409    {object}  lib.Problem             "web user with this email already exists"
+// @Failure      500    {object}  lib.Problem             "internal server error"
+// @Router       /auth/confirm-email [post]
+func (h *Handler) ConfirmEmailHandler(w http.ResponseWriter, r *http.Request) {
+	input := model.VerifyEmailInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	if input.Token == "" {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("token is empty"))
+
+		return
+	}
+
+	if err := h.service.Account.ConfirmEmailChange(input.Token, requestOrigin(r)); err != nil {
+		h.writeAccountError(w, r, err)
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "email is changed")
+}
+
+// ChangePasswordHandler godoc
+// @ID           change-password
+// @Summary      ChangePassword
+// @Description  Handler will set new password of authorized user after checking the old one, other sessions of the user are logged out, users created by sso sign-in have no password and must set it with forgot-password first
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.ChangePasswordInput  true  "old and new password"
+// @Success      200    {string}  string                     "password is changed"
+// @Failure      400    {object}  lib.Problem                "bad request"
+// @Failure      401    {object}  lib.Problem                "user is not authorized"
+// @Failure      403    {object}  lib.Problem                "password is incorrect or api key can't be used for this action"
+// @Failure      500    {object}  lib.Problem                "internal server error"
+// @Router       /me/password [put]
+func (h *Handler) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	input := model.ChangePasswordInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	if err := h.service.Account.ChangePassword(principal, &input, requestOrigin(r)); err != nil {
+		h.writeAccountError(w, r, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "password is changed")
+}
+
+// ChangeEmailHandler godoc
+// @ID           change-email
+// @Summary      ChangeEmail
+// @Description  Handler will send mail with confirmation link to the new email of authorized user, email is changed when the link is opened, users created by sso sign-in have no password and must set it with forgot-password first
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.ChangeEmailInput  true  "new email and current password"
+// @Success      202    {string}  string                  "confirmation mail is sent to new email"
+// @Failure      400    {object}  lib.Problem             "bad request"
+// @Failure      401    {object}  lib.Problem             "user is not authorized"
+// @Failure      403    {object}  lib.Problem             "password is incorrect or api key can't be used for this action"
+// @Failure      409    {object}  lib.Problem             "web user with this email already exists"
+// @Failure      500    {object}  lib.Problem             "internal server error"
+// @Router       /me/email [put]
+func (h *Handler) ChangeEmailHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	input := model.ChangeEmailInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	if err := h.service.Account.RequestEmailChange(principal, &input); err != nil {
+		h.writeAccountError(w, r, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusAccepted, "confirmation mail is sent to new email")
+}
+
+// DeleteAccountHandler godoc
+// @ID           delete-account
+// @Summary      DeleteAccount
+// @Description  Handler will permanently delete authorized user with saved messages, collections, shares, sessions, api keys and linked identities, users created by sso sign-in have no password and must set it with forgot-password first
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.DeleteAccountInput  true  "current password"
+// @Success      200    {string}  string                    "account deleted"
+// @Failure      400    {object}  lib.Problem               "bad request"
+// @Failure      401    {object}  lib.Problem               "user is not authorized"
+// @Failure      403    {object}  lib.Problem               "password is incorrect or api key can't be used for this action"
+// @Failure      404    {object}  lib.Problem               "web user not found"
+// @Failure      500    {object}  lib.Problem               "internal server error"
+// @Router       /me [delete]
+func (h *Handler) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	input := model.DeleteAccountInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	if err := h.service.Account.DeleteAccount(principal, input.Password, requestOrigin(r)); err != nil {
+		h.writeAccountError(w, r, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "account deleted")
+}
+
+// ExportAccountHandler godoc
+// @ID           export-account
+// @Summary      ExportAccount
+// @Description  Handler will return everything stored about authorized user as downloadable json file
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Produce      json
+// @Success      200  {object}  model.AccountExport  "account export"
+// @Failure      401  {object}  lib.Problem          "user is not authorized"
+// @Failure      403  {object}  lib.Problem          "api key can't be used for this action"
+// @Failure      404  {object}  lib.Problem          "web user not found"
+// @Failure      500  {object}  lib.Problem          "internal server error"
+// @Router       /me/export [get]
+func (h *Handler) ExportAccountHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	export, err := h.service.Account.ExportAccount(principal.UserID)
+	if err != nil {
+		h.writeAccountError(w, r, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+		return
+	}
+
+	w.Header().Set(
+		"Content-Disposition",
+		fmt.Sprintf(`attachment; filename="scanner-export-%d-%s.json"`, principal.UserID, export.ExportedAt.Format("20060102")),
+	)
+
+	h.WriteJSON(w, http.StatusOK, export)
+}
+
+func (h *Handler) writeAccountError(w http.ResponseWriter, r *http.Request, err error, fields ...zap.Field) {
+	var verrs validation.Errors
+
+	switch {
+	case errors.As(err, &verrs):
+		h.WriteValidationError(w, r, verrs, fields...)
+	case errors.Is(err, service.ErrInvalidAccountToken):
+		h.WriteError(w, r, http.StatusBadRequest, err, fields...)
+	case errors.Is(err, service.ErrIncorrectPassword):
+		h.WriteError(w, r, http.StatusForbidden, err, fields...)
+	case errors.Is(err, pg.ErrWebUserExists):
+		h.WriteError(w, r, http.StatusConflict, err, fields...)
+	case errors.Is(err, pg.ErrWebUserNotFound):
+		h.WriteError(w, r, http.StatusNotFound, err, fields...)
+	default:
+		h.WriteError(w, r, http.StatusInternalServerError, err, fields...)
+	}
+}
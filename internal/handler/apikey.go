@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+// GetAPIKeysHandler godoc
+// @ID           get-api-keys
+// @Summary      GetAPIKeys
+// @Description  Handler will return not revoked api keys of authorized user, latest created first
+// @Security     ApiKeyAuth
+// @Tags         api-key
+// @Produce      json
+// @Success      200  {array}   model.APIKey  "api keys of authorized user"
+// @Failure      401  {object}  lib.Problem   "user is not authorized"
+// @Failure      403  {object}  lib.Problem   "api key can't be used for this action"
+// @Failure      404  {object}  lib.Problem   "api keys not found"
+// @Failure      500  {object}  lib.Problem   "internal server error"
+// @Router       /me/api-keys [get]
+func (h *Handler) GetAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	keys, err := h.service.APIKey.GetAPIKeys(principal.UserID)
+	if err != nil {
+		if errors.Is(err, pg.ErrAPIKeysNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+			return
+		}
+
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, keys)
+}
+
+// CreateAPIKeyHandler godoc
+// @ID           create-api-key
+// @Summary      CreateAPIKey
+// @Description  Handler will create api key for authorized user, the key is returned only in this response.
+// @Description  Api key is sent in "Authorization: ApiKey <key>" header. Key with read scope can only read,
+// @Description  key with saved-write scope can also change saved messages and collections, readonly users can't create it.
+// @Security     ApiKeyAuth
+// @Tags         api-key
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.APIKeyInput  true  "api key info"
+// @Success      201    {object}  model.APIKey       "created api key"
+// @Failure      400    {object}  lib.Problem        "bad request"
+// @Failure      401    {object}  lib.Problem        "user is not authorized"
+// @Failure      403    {object}  lib.Problem        "scope is not allowed for user role or api key can't be used for this action"
+// @Failure      500    {object}  lib.Problem        "internal server error"
+// @Router       /me/api-keys [post]
+func (h *Handler) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	input := model.APIKeyInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	key, err := h.service.APIKey.CreateAPIKey(principal, &input)
+	if err != nil {
+		h.writeAPIKeyError(w, r, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusCreated, key)
+}
+
+// RenameAPIKeyHandler godoc
+// @ID           rename-api-key
+// @Summary      RenameAPIKey
+// @Description  Handler will rename not revoked api key of authorized user by id from url, only name from body is used
+// @Security     ApiKeyAuth
+// @Tags         api-key
+// @Accept       json
+// @Produce      json
+// @Param        key_id  path      integer            true  "api key id"
+// @Param        input   body      model.APIKeyInput  true  "api key info"
+// @Success      200     {string}  string             "api key renamed"
+// @Failure      400     {object}  lib.Problem        "bad request"
+// @Failure      401     {object}  lib.Problem        "user is not authorized"
+// @Failure      403     {object}  lib.Problem        "api key can't be used for this action"
+// @Failure      404     {object}  lib.Problem        "api key not found"
+// @Failure      500     {object}  lib.Problem        "internal server error"
+// @Router       /me/api-keys/{key_id} [put]
+func (h *Handler) RenameAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	keyID, err := strconv.Atoi(mux.Vars(r)["key_id"])
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("api key id is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	input := model.APIKeyInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	err = h.service.APIKey.RenameAPIKey(principal.UserID, keyID, input.Name)
+	if err != nil {
+		h.writeAPIKeyError(w, r, err, zap.String("api key id", strconv.Itoa(keyID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "api key renamed")
+}
+
+// RevokeAPIKeyHandler godoc
+// @ID           revoke-api-key
+// @Summary      RevokeAPIKey
+// @Description  Handler will revoke api key of authorized user by id from url, requests with the key are rejected after it
+// @Security     ApiKeyAuth
+// @Tags         api-key
+// @Produce      json
+// @Param        key_id  path      integer      true  "api key id"
+// @Success      200     {string}  string       "api key revoked"
+// @Failure      400     {object}  lib.Problem  "bad request"
+// @Failure      401     {object}  lib.Problem  "user is not authorized"
+// @Failure      403     {object}  lib.Problem  "api key can't be used for this action"
+// @Failure      404     {object}  lib.Problem  "api key not found"
+// @Failure      500     {object}  lib.Problem  "internal server error"
+// @Router       /me/api-keys/{key_id} [delete]
+func (h *Handler) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	keyID, err := strconv.Atoi(mux.Vars(r)["key_id"])
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("api key id is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	err = h.service.APIKey.RevokeAPIKey(principal.UserID, keyID, requestOrigin(r))
+	if err != nil {
+		h.writeAPIKeyError(w, r, err, zap.String("api key id", strconv.Itoa(keyID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "api key revoked")
+}
+
+func (h *Handler) writeAPIKeyError(w http.ResponseWriter, r *http.Request, err error, fields ...zap.Field) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAPIKeyName):
+		h.WriteError(w, r, http.StatusBadRequest, err, fields...)
+	case errors.Is(err, service.ErrInvalidAPIKeyScope):
+		h.WriteError(w, r, http.StatusBadRequest, err, fields...)
+	case errors.Is(err, service.ErrAPIKeyScopeNotAllowed):
+		h.WriteError(w, r, http.StatusForbidden, err, fields...)
+	case errors.Is(err, pg.ErrAPIKeyNotFound):
+		h.WriteError(w, r, http.StatusNotFound, err, fields...)
+	default:
+		h.WriteError(w, r, http.StatusInternalServerError, err, fields...)
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+// GetAuditEventsHandler godoc
+// @ID           get-audit-events
+// @Summary      GetAuditEvents
+// @Description  Handler will return page of audit events after cursor from query, latest events first
+// @Security     ApiKeyAuth
+// @Tags         admin
+// @Produce      json
+// @Param        cursor    query     string           false  "cursor from next or prev of the previous page"
+// @Param        limit     query     integer          false  "page size, 10 by default and 100 at most"
+// @Param        actor_id  query     integer          false  "id of web user who acted"
+// @Param        action    query     string           false  "action, for example sign-in.failed"
+// @Param        ip        query     string           false  "client ip"
+// @Param        from      query     string           false  "events happened at or after this RFC3339 time or day"
+// @Param        to        query     string           false  "events happened before this RFC3339 time or until the end of this day"
+// @Success      200       {object}  model.AuditPage  "audit events by page"
+// @Failure      400       {object}  lib.Problem      "bad request"
+// @Failure      401       {object}  lib.Problem      "user is not authorized"
+// @Failure      403       {object}  lib.Problem      "user is not admin"
+// @Failure      404       {object}  lib.Problem      "audit events not found"
+// @Failure      500       {object}  lib.Problem      "internal server error"
+// @Router       /admin/audit [get]
+func (h *Handler) GetAuditEventsHandler(w http.ResponseWriter, r *http.Request) {
+	page, err := getPageRequest(r)
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, err)
+
+		return
+	}
+
+	filter, err := getAuditFilter(r)
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, err)
+
+		return
+	}
+
+	events, err := h.service.Audit.GetAuditEvents(filter, page)
+	if err != nil {
+		if errors.Is(err, pg.ErrAuditEventsNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err)
+
+			return
+		}
+
+		h.WriteError(w, r, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, events)
+}
+
+// ExportAuditEventsHandler godoc
+// @ID           export-audit-events
+// @Summary      ExportAuditEvents
+// @Description  Handler will stream every audit event matching filter as newline delimited json, latest events first. Export is recorded in audit trail
+// @Security     ApiKeyAuth
+// @Tags         admin
+// @Produce      application/x-ndjson
+// @Param        actor_id  query     integer           false  "id of web user who acted"
+// @Param        action    query     string            false  "action, for example sign-in.failed"
+// @Param        ip        query     string            false  "client ip"
+// @Param        from      query     string            false  "events happened at or after this RFC3339 time or day"
+// @Param        to        query     string            false  "events happened before this RFC3339 time or until the end of this day"
+// @Success      200       {object}  model.AuditEvent  "one audit event per line"
+// @Failure      400       {object}  lib.Problem       "bad request"
+// @Failure      401       {object}  lib.Problem       "user is not authorized"
+// @Failure      403       {object}  lib.Problem       "user is not admin"
+// @Failure      500       {object}  lib.Problem       "internal server error"
+// @Router       /admin/audit/export [get]
+func (h *Handler) ExportAuditEventsHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	filter, err := getAuditFilter(r)
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, err)
+
+		return
+	}
+
+	// status and headers are sent with the first event, so error before it can still be reported to client
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set(
+			"Content-Disposition",
+			fmt.Sprintf(`attachment; filename="scanner-audit-%s.ndjson"`, time.Now().UTC().Format("20060102")),
+		)
+		w.WriteHeader(http.StatusOK)
+
+		started = true
+	}
+
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	err = h.service.Audit.ExportAuditEvents(principal, filter, requestOrigin(r), func(event *model.AuditEvent) error {
+		if !started {
+			start()
+		}
+
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		if !started {
+			h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+		}
+
+		return
+	}
+
+	if !started {
+		start()
+	}
+}
+
+// getAuditFilter returns filter of audit events from query, empty values don't narrow events.
+func getAuditFilter(r *http.Request) (*model.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := &model.AuditFilter{
+		Action: model.AuditAction(strings.TrimSpace(query.Get("action"))),
+		IP:     strings.TrimSpace(query.Get("ip")),
+	}
+
+	var err error
+
+	if value := query.Get("actor_id"); value != "" {
+		filter.ActorID, err = strconv.Atoi(value)
+		if err != nil || filter.ActorID <= 0 {
+			return nil, parameterError("actor id is not valid")
+		}
+	}
+
+	if value := query.Get("from"); value != "" {
+		filter.From, err = parseQueryTime(value, false)
+		if err != nil {
+			return nil, parameterError("from is not valid")
+		}
+	}
+
+	if value := query.Get("to"); value != "" {
+		filter.To, err = parseQueryTime(value, true)
+		if err != nil {
+			return nil, parameterError("to is not valid")
+		}
+	}
+
+	return filter, nil
+}
@@ -3,124 +3,275 @@ package handler
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 
 	"go.uber.org/zap"
 
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
 	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/internal/validation"
 )
 
 // SignUpHandler godoc
 // @ID           create-user
 // @Summary      sign-up
-// @Description  Handler will create new user and return message
+// @Description  Handler will validate user, create it and send mail with email verification link to the user, user isn't created when the mail can't be queued. Email is stored with domain in lower case, password has to be long enough and not known from data breaches
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        input  body      model.WebUser  true  "user info"
-// @Success      200    {string}  string         "user created"
-// @Failure      400    {object}  lib.HttpError  "bad request"
-// @Failure      409    {object}  lib.HttpError  "user with email is exist"
-// @Failure      500    {object}  lib.HttpError  "internal server error"
+// @Success      201    {string}  string         "user created"
+// @Failure      400    {object}  lib.Problem    "bad request, invalid fields are listed in fields"
+// @Failure      409    {object}  lib.Problem    "user with email is exist"
+// @Failure      500    {object}  lib.Problem    "internal server error"
 // @Router       /auth/sign-up [post]
 func (h *Handler) SignUpHandler(w http.ResponseWriter, r *http.Request) {
 	user := model.WebUser{}
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		h.log.Error("failed to decode request body", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "can't decode request body")
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
 
 		return
 	}
 
-	candidate, err := h.service.WebUser.GetWebUserByEmail(user.Email)
+	err := h.service.Account.SignUp(&user, requestOrigin(r))
 	if err != nil {
-		if !errors.Is(err, pg.ErrWebUserNotFound) {
-			h.log.Error("failed to get candidate", zap.String("email", user.Email), zap.Error(err))
-
-			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		var verrs validation.Errors
 
-			return
+		switch {
+		case errors.As(err, &verrs):
+			h.WriteValidationError(w, r, verrs)
+		case errors.Is(err, pg.ErrWebUserExists):
+			h.WriteError(w, r, http.StatusConflict, err, zap.String("email", user.Email))
+		default:
+			h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("email", user.Email))
 		}
+
+		return
 	}
 
-	if candidate != nil {
-		h.WriteError(w, http.StatusConflict, fmt.Sprintf("user with email %s is exist", user.Email))
+	h.WriteJSON(w, http.StatusCreated, "user created")
+}
+
+// SignInHandler godoc
+// @ID           login-user
+// @Summary      sigi-up
+// @Description  Handler will login user and return short-lived JWT access token with refresh token, after too many failed attempts sign-in of account or client ip is locked for a while
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.SignInInput  true  "user info"
+// @Success      200    {object}  model.Tokens       "tokens"
+// @Failure      400    {object}  lib.Problem        "bad request"
+// @Failure      401    {object}  lib.Problem        "email or password is incorrect"
+// @Failure      429    {object}  lib.Problem        "too many failed sign-in attempts"
+// @Failure      500    {object}  lib.Problem        "internal server error"
+// @Router       /auth/sign-in [post]
+func (h *Handler) SignInHandler(w http.ResponseWriter, r *http.Request) {
+	input := model.SignInInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
 
 		return
 	}
 
-	err = h.service.WebUser.CreateWebUser(&user)
+	tokens, err := h.service.SignIn.SignIn(&input, requestOrigin(r))
 	if err != nil {
-		h.log.Error("failed to create user", zap.Any("user structure", user), zap.Error(err))
+		switch {
+		case errors.Is(err, service.ErrInvalidCredentials):
+			h.WriteError(w, r, http.StatusUnauthorized, err, zap.String("email", input.Email), zap.String("ip", clientIP(r)))
+		case errors.Is(err, service.ErrSignInLocked):
+			h.WriteError(w, r, http.StatusTooManyRequests, err, zap.String("email", input.Email), zap.String("ip", clientIP(r)))
+		default:
+			h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("email", input.Email), zap.String("ip", clientIP(r)))
+		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, tokens)
+}
+
+// UnlockSignInHandler godoc
+// @ID           unlock-sign-in
+// @Summary      UnlockSignIn
+// @Description  Handler will forget failed sign-in attempts of account email and/or client ip, so they can sign in again right away
+// @Security     ApiKeyAuth
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.UnlockSignInInput  true  "email and/or ip to unlock"
+// @Success      200    {string}  string                   "sign-in unlocked"
+// @Failure      400    {object}  lib.Problem              "bad request"
+// @Failure      401    {object}  lib.Problem              "user is not authorized"
+// @Failure      403    {object}  lib.Problem              "user is not admin"
+// @Failure      404    {object}  lib.Problem              "sign-in attempt not found"
+// @Failure      500    {object}  lib.Problem              "internal server error"
+// @Router       /admin/sign-in/unlock [post]
+func (h *Handler) UnlockSignInHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
 
 		return
 	}
 
-	h.WriteJSON(w, http.StatusCreated, "user created")
+	input := model.UnlockSignInInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	err := h.service.SignIn.UnlockSignIn(principal, &input, requestOrigin(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmptyUnlockInput):
+			h.WriteError(w, r, http.StatusBadRequest, err, zap.String("email", input.Email), zap.String("ip", input.IP))
+		case errors.Is(err, pg.ErrSignInAttemptNotFound):
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("email", input.Email), zap.String("ip", input.IP))
+		default:
+			h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("email", input.Email), zap.String("ip", input.IP))
+		}
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "sign-in unlocked")
 }
 
-// SignInHandler godoc
-// @ID           login-user
-// @Summary      sigi-up
-// @Description  Handler will login user and return JWT token
+// RefreshHandler godoc
+// @ID           refresh-tokens
+// @Summary      refresh
+// @Description  Handler will exchange refresh token for new access and refresh tokens, reused refresh token logs its session out
 // @Tags         auth
 // @Accept       json
 // @Produce      json
-// @Param        input  body      model.WebUser  true  "user info"
-// @Success      200    {string}  string         "token"
-// @Failure      400    {object}  lib.HttpError  "bad request"
-// @Failure      404    {object}  lib.HttpError  "user with this email not found"
-// @Failure      500    {object}  lib.HttpError  "internal server error"
-// @Router       /auth/sign-in [post]
-func (h *Handler) SignInHandler(w http.ResponseWriter, r *http.Request) {
-	user := model.WebUser{}
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		h.log.Error("failed to decode request body", zap.Error(err))
+// @Param        input  body      model.RefreshInput  true  "refresh token"
+// @Success      200    {object}  model.Tokens        "tokens"
+// @Failure      400    {object}  lib.Problem         "bad request"
+// @Failure      401    {object}  lib.Problem         "refresh token is not valid or already used"
+// @Failure      500    {object}  lib.Problem         "internal server error"
+// @Router       /auth/refresh [post]
+func (h *Handler) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	input := model.RefreshInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
 
-		h.WriteError(w, http.StatusBadRequest, "can't decode request body")
+	if input.RefreshToken == "" {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("refresh token is empty"))
 
 		return
 	}
 
-	candidate, err := h.service.WebUser.GetWebUserByEmail(user.Email)
+	tokens, err := h.service.Jwt.RefreshTokens(input.RefreshToken, requestOrigin(r))
 	if err != nil {
-		if !errors.Is(err, pg.ErrWebUserNotFound) {
-			h.log.Error("failed to get candidate", zap.String("email", user.Email), zap.Error(err))
-
-			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			h.WriteError(w, r, http.StatusUnauthorized, err)
 
 			return
 		}
 
-		if errors.Is(err, pg.ErrWebUserNotFound) {
-			h.log.Error("failed to get user", zap.String("email", user.Email), zap.Error(err))
-
-			h.WriteError(w, http.StatusNotFound, "user not found")
+		if errors.Is(err, service.ErrRefreshTokenReused) {
+			h.WriteError(w, r, http.StatusUnauthorized, err)
 
 			return
 		}
+
+		h.WriteError(w, r, http.StatusInternalServerError, err)
+
+		return
 	}
 
-	ok := h.service.WebUser.ComparePassword(user.Password, candidate.Password)
+	h.WriteJSON(w, http.StatusOK, tokens)
+}
+
+// LogoutHandler godoc
+// @ID           logout
+// @Summary      logout
+// @Description  Handler will revoke session of access token, its access and refresh tokens stop working
+// @Security     ApiKeyAuth
+// @Tags         auth
+// @Produce      json
+// @Success      200  {string}  string       "logged out"
+// @Failure      401  {object}  lib.Problem  "user is not authorized"
+// @Failure      500  {object}  lib.Problem  "internal server error"
+// @Router       /auth/logout [post]
+func (h *Handler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
 	if !ok {
-		h.WriteError(w, http.StatusUnauthorized, "password is incorrect")
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
 
 		return
 	}
 
-	token, err := h.service.Jwt.GenerateToken(candidate.Email)
-	if err != nil {
-		h.log.Error("failed to generate error", zap.Error(err))
+	if err := h.service.Jwt.Logout(principal, requestOrigin(r)); err != nil {
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("session id", strconv.Itoa(principal.SessionID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "logged out")
+}
+
+// LogoutAllHandler godoc
+// @ID           logout-all
+// @Summary      logout-all
+// @Description  Handler will revoke every session of authorized user
+// @Security     ApiKeyAuth
+// @Tags         auth
+// @Produce      json
+// @Success      200  {string}  string       "logged out of all sessions"
+// @Failure      401  {object}  lib.Problem  "user is not authorized"
+// @Failure      500  {object}  lib.Problem  "internal server error"
+// @Router       /auth/logout-all [post]
+func (h *Handler) LogoutAllHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
 
-		h.WriteError(w, http.StatusInternalServerError, "failed to generate jwt token")
+		return
+	}
+
+	if err := h.service.Jwt.LogoutAll(principal, requestOrigin(r)); err != nil {
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("user id", strconv.Itoa(principal.UserID)))
 
 		return
 	}
 
-	h.WriteJSON(w, http.StatusOK, map[string]string{"token": token})
+	h.WriteJSON(w, http.StatusOK, "logged out of all sessions")
+}
+
+// JWKSHandler godoc
+// @ID           get-jwks
+// @Summary      JWKS
+// @Description  Handler will return public keys which verify access tokens, keys scheduled for rotation are included
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  model.JWKS  "json web key set"
+// @Router       /.well-known/jwks.json [get]
+func (h *Handler) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	h.WriteJSON(w, http.StatusOK, h.service.Jwt.JWKS())
+}
+
+// clientIP returns address of client which sent request, proxy headers are ignored since clients can forge them.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// requestOrigin returns client which sent request as it's recorded in audit trail.
+func requestOrigin(r *http.Request) model.Origin {
+	return model.Origin{IP: clientIP(r), UserAgent: r.UserAgent()}
 }
@@ -1,41 +1,194 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
 )
 
+type contextKey string
+
+const (
+	principalKey contextKey = "principal"
+	shareKey     contextKey = "share"
+)
+
+// AuthenticateMiddleware rejects requests without valid access token or api key
+// and stores web user from the token or the key in request context.
 func (h *Handler) AuthenticateMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		header := r.Header.Get("Authorization")
 		if header == "" {
-			h.WriteError(w, http.StatusUnauthorized, "user is not authorized")
+			h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
 
 			return
 		}
 
 		headerParts := strings.Split(header, " ")
-		if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-			h.WriteError(w, http.StatusUnauthorized, "invalid auth header")
+		if len(headerParts) != 2 || (headerParts[0] != "Bearer" && headerParts[0] != "ApiKey") {
+			h.WriteError(w, r, http.StatusUnauthorized, errInvalidAuthHeader)
 
 			return
 		}
 
 		if len(headerParts[1]) == 0 {
-			h.WriteError(w, http.StatusUnauthorized, "token is empty")
+			h.WriteError(w, r, http.StatusUnauthorized, errEmptyAuthToken)
+
+			return
+		}
+
+		if headerParts[0] == "ApiKey" {
+			h.authenticateAPIKey(w, r, next, headerParts[1])
 
 			return
 		}
 
-		userEmail, err := h.service.Jwt.ParseToken(headerParts[1])
+		principal, err := h.service.Jwt.ParseToken(headerParts[1])
 		if err != nil {
-			h.WriteError(w, http.StatusUnauthorized, err.Error())
+			for _, tokenErr := range []error{
+				service.ErrInvalidAccessToken, service.ErrInvalidIssuer, service.ErrInvalidAudience,
+				service.ErrTokenWithoutUser, service.ErrTokenWithoutRole, service.ErrTokenWithoutSession, service.ErrSessionRevoked,
+			} {
+				if errors.Is(err, tokenErr) {
+					h.WriteError(w, r, http.StatusUnauthorized, err)
+
+					return
+				}
+			}
+
+			h.WriteError(w, r, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey, principal)))
+	})
+}
+
+// authenticateAPIKey passes request of api key owner to next handler, acting with role of key scope.
+func (h *Handler) authenticateAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, key string) {
+	principal, err := h.service.APIKey.Authenticate(key)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			h.WriteError(w, r, http.StatusUnauthorized, err)
+
+			return
+		}
+
+		h.WriteError(w, r, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey, principal)))
+}
+
+// SessionOnlyMiddleware rejects requests authenticated with api key, it guards actions
+// which aren't covered by any api key scope. It must run after AuthenticateMiddleware.
+func (h *Handler) SessionOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := principalFromContext(r.Context())
+		if !ok {
+			h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
 
 			return
 		}
 
-		w.Header().Set("email", userEmail)
+		if principal.APIKeyID != 0 {
+			h.WriteError(w, r, http.StatusForbidden, errSessionRequired)
+
+			return
+		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// principalFromContext returns web user stored by AuthenticateMiddleware.
+func principalFromContext(ctx context.Context) (*model.Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(*model.Principal)
+
+	return principal, ok && principal != nil
+}
+
+// AuthorizeMiddleware rejects requests of web users whose role doesn't give rights of required role.
+// It must run after AuthenticateMiddleware.
+func (h *Handler) AuthorizeMiddleware(required model.Role) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := principalFromContext(r.Context())
+			if !ok {
+				h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+				return
+			}
+
+			if !principal.Role.Allows(required) {
+				h.WriteError(w, r, http.StatusForbidden, errForbidden)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ShareAccessMiddleware rejects requests of web users who don't have at least required role
+// in share from url and stores the share in request context. It must run after AuthenticateMiddleware.
+func (h *Handler) ShareAccessMiddleware(required model.ShareRole) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := principalFromContext(r.Context())
+			if !ok {
+				h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+				return
+			}
+
+			shareID, err := strconv.Atoi(mux.Vars(r)["share_id"])
+			if err != nil {
+				h.WriteError(w, r, http.StatusBadRequest, parameterError("share id is not valid"), zap.NamedError("cause", err))
+
+				return
+			}
+
+			share, err := h.service.Share.GetShareAccess(shareID, principal.UserID)
+			if err != nil {
+				if errors.Is(err, pg.ErrShareNotFound) {
+					h.WriteError(w, r, http.StatusNotFound, err, zap.String("share id", strconv.Itoa(shareID)))
+
+					return
+				}
+
+				h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("share id", strconv.Itoa(shareID)))
+
+				return
+			}
+
+			if !share.Role.Allows(required) {
+				h.WriteError(w, r, http.StatusForbidden, errShareForbidden)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shareKey, share)))
+		})
+	}
+}
+
+// shareFromContext returns share stored by ShareAccessMiddleware.
+func shareFromContext(ctx context.Context) (*model.Share, bool) {
+	share, ok := ctx.Value(shareKey).(*model.Share)
+
+	return share, ok && share != nil
+}
@@ -3,7 +3,6 @@ package handler
 import (
 	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
@@ -17,24 +16,20 @@ import (
 // @Description  Handler will return channels count
 // @Tags         channel
 // @Produce      json
-// @Success      200  {object}  object         "channels count"
-// @Failure      404  {object}  lib.HttpError  "channels count not found"
-// @Failure      500  {object}  lib.HttpError  "internal server error"
+// @Success      200  {object}  object       "channels count"
+// @Failure      404  {object}  lib.Problem  "channels count not found"
+// @Failure      500  {object}  lib.Problem  "internal server error"
 // @Router       /channel/count [get]
 func (h *Handler) GetChannelsCountHandler(w http.ResponseWriter, r *http.Request) {
 	count, err := h.service.Channel.GetChannelsCount()
 	if err != nil {
-		h.log.Error("get channels count error", zap.Error(err))
-
 		if errors.Is(err, pg.ErrChannelsCountNotFound) {
-			err = errors.Unwrap(err)
-
-			h.WriteError(w, http.StatusNotFound, err.Error())
+			h.WriteError(w, r, http.StatusNotFound, err)
 
 			return
 		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err)
 
 		return
 	}
@@ -50,26 +45,22 @@ func (h *Handler) GetChannelsCountHandler(w http.ResponseWriter, r *http.Request
 // @Produce      json
 // @Param        name  path      string         true  "channel name"
 // @Success      200   {object}  model.Channel  "channel by name"
-// @Failure      400   {object}  lib.HttpError  "bad request"
-// @Failure      404   {object}  lib.HttpError  "channel not found"
-// @Failure      500   {object}  lib.HttpError  "internal server error"
+// @Failure      400   {object}  lib.Problem    "bad request"
+// @Failure      404   {object}  lib.Problem    "channel not found"
+// @Failure      500   {object}  lib.Problem    "internal server error"
 // @Router       /channel/{name} [get]
 func (h *Handler) GetChannelByNameHandler(w http.ResponseWriter, r *http.Request) {
 	name := mux.Vars(r)["name"]
 
 	channel, err := h.service.Channel.GetChannelByName(name)
 	if err != nil {
-		h.log.Error("get channel by name error", zap.String("name", name), zap.Error(err))
-
 		if errors.Is(err, pg.ErrChannelNotFound) {
-			err = errors.Unwrap(err)
-
-			h.WriteError(w, http.StatusNotFound, err.Error())
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("name", name))
 
 			return
 		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("name", name))
 
 		return
 	}
@@ -80,38 +71,33 @@ func (h *Handler) GetChannelByNameHandler(w http.ResponseWriter, r *http.Request
 // GetChannelsByPageHandler godoc
 // @ID           get-channels-by-page
 // @Summary      GetChannelsByPage
-// @Description  Handler will return channels by page from query
+// @Description  Handler will return page of channels after cursor from query
 // @Tags         channel
 // @Produce      json
-// @Param        page  query     integer        true  "page"
-// @Success      200   {array}   model.Channel  "channels by page"
-// @Failure      400   {object}  lib.HttpError  "bad request"
-// @Failure      404   {object}  lib.HttpError  "channels not found"
-// @Failure      500   {object}  lib.HttpError  "internal server error"
+// @Param        cursor  query     string              false  "cursor from next or prev of the previous page"
+// @Param        limit   query     integer             false  "page size, 10 by default and 100 at most"
+// @Success      200     {object}  model.ChannelsPage  "channels by page"
+// @Failure      400     {object}  lib.Problem         "bad request"
+// @Failure      404     {object}  lib.Problem         "channels not found"
+// @Failure      500     {object}  lib.Problem         "internal server error"
 // @Router       /channel/ [get]
 func (h *Handler) GetChannelsByPageHandler(w http.ResponseWriter, r *http.Request) {
-	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	page, err := getPageRequest(r)
 	if err != nil {
-		h.log.Error("failed to get page", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "page is not valid")
+		h.WriteError(w, r, http.StatusBadRequest, err)
 
 		return
 	}
 
 	channels, err := h.service.Channel.GetChannelsByPage(page)
 	if err != nil {
-		h.log.Error("get channels by page error", zap.String("page", strconv.Itoa(page)), zap.Error(err))
-
 		if errors.Is(err, pg.ErrChannelsNotFound) {
-			err = errors.Unwrap(err)
-
-			h.WriteError(w, http.StatusNotFound, err.Error())
+			h.WriteError(w, r, http.StatusNotFound, err)
 
 			return
 		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err)
 
 		return
 	}
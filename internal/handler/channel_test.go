@@ -24,7 +24,7 @@ func Test_GetChannelsCountHandler(t *testing.T) {
 		name          string
 		mock          func(channelSrv *mocks.ChannelService)
 		wantErr       bool
-		expectedErr   lib.HttpError
+		expectedErr   lib.Problem
 		expectedCount int
 		expectedCode  int
 	}{
@@ -42,7 +42,7 @@ func Test_GetChannelsCountHandler(t *testing.T) {
 				channelSrv.On("GetChannelsCount").Return(0, fmt.Errorf("[Channel] srv.GetChannelsCount error: %w", pg.ErrChannelsCountNotFound))
 			},
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "channels count not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "channels count not found", Code: "CHANNELS_COUNT_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
@@ -51,7 +51,7 @@ func Test_GetChannelsCountHandler(t *testing.T) {
 				channelSrv.On("GetChannelsCount").Return(0, fmt.Errorf("[Channel] srv.GetChannelsCount error: some error"))
 			},
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Channel] srv.GetChannelsCount error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 	}
@@ -77,7 +77,7 @@ func Test_GetChannelsCountHandler(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			decodedCount := map[string]int{}
-			decodedErr := lib.HttpError{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -109,7 +109,7 @@ func Test_GetChannelByNameHandler(t *testing.T) {
 		mock            func(channelSrv *mocks.ChannelService)
 		input           string
 		wantErr         bool
-		expectedErr     lib.HttpError
+		expectedErr     lib.Problem
 		expectedChannel model.Channel
 		expectedCode    int
 	}{
@@ -129,7 +129,7 @@ func Test_GetChannelByNameHandler(t *testing.T) {
 			},
 			input:        "test",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "channel not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "channel not found", Code: "CHANNEL_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
@@ -139,7 +139,7 @@ func Test_GetChannelByNameHandler(t *testing.T) {
 			},
 			input:        "test",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Channel] srv.GetChannelByName error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 	}
@@ -164,7 +164,7 @@ func Test_GetChannelByNameHandler(t *testing.T) {
 			router.HandleFunc("/channel/{name}", handler.GetChannelByNameHandler)
 			router.ServeHTTP(rr, req)
 
-			decodedErr := lib.HttpError{}
+			decodedErr := lib.Problem{}
 			decodedChannel := model.Channel{}
 
 			if tt.wantErr {
@@ -185,9 +185,12 @@ func Test_GetChannelByNameHandler(t *testing.T) {
 }
 
 func Test_GetChannelsByPageHandler(t *testing.T) {
-	testChannels := []model.Channel{
-		{ID: 1, Name: "test", Title: "test test", ImageURL: "test.jpg"},
-		{ID: 2, Name: "test2", Title: "test2 test2", ImageURL: "test2.jpg"},
+	testChannels := &model.ChannelsPage{
+		Items: []model.Channel{
+			{ID: 11, Name: "test", Title: "test test", ImageURL: "test.jpg"},
+			{ID: 12, Name: "test2", Title: "test2 test2", ImageURL: "test2.jpg"},
+		},
+		PageInfo: model.PageInfo{Next: "Zjo6MTI", Prev: "Yjo6MTE", HasMore: true},
 	}
 
 	tests := []struct {
@@ -195,52 +198,60 @@ func Test_GetChannelsByPageHandler(t *testing.T) {
 		mock             func(channelSrv *mocks.ChannelService)
 		input            string
 		wantErr          bool
-		expectedErr      lib.HttpError
-		expectedChannels []model.Channel
+		expectedErr      lib.Problem
+		expectedChannels *model.ChannelsPage
 		expectedCode     int
 	}{
 		{
 			name: "Ok: [channels found]",
 			mock: func(channelSrv *mocks.ChannelService) {
-				channelSrv.On("GetChannelsByPage", 1).Return(testChannels, nil)
+				channelSrv.On("GetChannelsByPage", &model.PageRequest{Cursor: &model.Cursor{ID: 10}, Limit: 2}).Return(testChannels, nil)
 			},
-			input:            "1",
+			input:            "cursor=Zjo6MTA&limit=2",
 			expectedChannels: testChannels,
 			expectedCode:     http.StatusOK,
 		},
 		{
 			name: "Error: [channels not found]",
 			mock: func(channelSrv *mocks.ChannelService) {
-				channelSrv.On("GetChannelsByPage", 1).Return(nil, fmt.Errorf("[Channel] srv.GetChannelsByPage error: %w", pg.ErrChannelsNotFound))
+				channelSrv.On("GetChannelsByPage", &model.PageRequest{Limit: 10}).Return(nil, fmt.Errorf("[Channel] srv.GetChannelsByPage error: %w", pg.ErrChannelsNotFound))
 			},
-			input:        "1",
+			input:        "",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "channels not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "channels not found", Code: "CHANNELS_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
 			name: "Error: [some internal error]",
 			mock: func(channelSrv *mocks.ChannelService) {
-				channelSrv.On("GetChannelsByPage", 1).Return(nil, fmt.Errorf("[Channel] srv.GetChannelsByPage error: some error"))
+				channelSrv.On("GetChannelsByPage", &model.PageRequest{Limit: 10}).Return(nil, fmt.Errorf("[Channel] srv.GetChannelsByPage error: some error"))
 			},
-			input:        "1",
+			input:        "",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Channel] srv.GetChannelsByPage error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 		{
-			name:         "Error: [page is not valid]",
+			name:         "Error: [limit is not valid]",
+			mock:         func(channelSrv *mocks.ChannelService) {},
+			input:        "limit=hello",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "limit is not valid", Code: "INVALID_LIMIT"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Error: [cursor is not valid]",
 			mock:         func(channelSrv *mocks.ChannelService) {},
-			input:        "hello",
+			input:        "cursor=hello",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 400, Name: "Bad Request", Message: "page is not valid"},
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "cursor is not valid", Code: "INVALID_CURSOR"},
 			expectedCode: http.StatusBadRequest,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/channel/?page=%s", tt.input), nil)
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/channel/?%s", tt.input), nil)
 			if err != nil {
 				t.Fatalf("could not create request: %s", err)
 			}
@@ -258,8 +269,8 @@ func Test_GetChannelsByPageHandler(t *testing.T) {
 			router.HandleFunc("/channel/", handler.GetChannelsByPageHandler)
 			router.ServeHTTP(rr, req)
 
-			decodedChannels := []model.Channel{}
-			decodedErr := lib.HttpError{}
+			decodedChannels := &model.ChannelsPage{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -267,7 +278,7 @@ func Test_GetChannelsByPageHandler(t *testing.T) {
 				assert.EqualValues(t, tt.expectedErr, decodedErr)
 				assert.EqualValues(t, tt.expectedCode, rr.Code)
 			} else {
-				json.NewDecoder(rr.Body).Decode(&decodedChannels)
+				json.NewDecoder(rr.Body).Decode(decodedChannels)
 
 				assert.EqualValues(t, tt.expectedChannels, decodedChannels)
 				assert.EqualValues(t, tt.expectedCode, rr.Code)
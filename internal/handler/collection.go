@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+// GetCollectionsHandler godoc
+// @ID           get-collections
+// @Summary      GetCollections
+// @Description  Handler will return all collections of authorized user ordered by name
+// @Security     ApiKeyAuth
+// @Tags         collection
+// @Produce      json
+// @Success      200  {array}   model.Collection  "collections of authorized user"
+// @Failure      401  {object}  lib.Problem       "user is not authorized"
+// @Failure      404  {object}  lib.Problem       "collections not found"
+// @Failure      500  {object}  lib.Problem       "internal server error"
+// @Router       /me/collections [get]
+func (h *Handler) GetCollectionsHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	collections, err := h.service.Saved.GetCollections(principal.UserID)
+	if err != nil {
+		if errors.Is(err, pg.ErrCollectionsNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+			return
+		}
+
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, collections)
+}
+
+// CreateCollectionHandler godoc
+// @ID           create-collection
+// @Summary      CreateCollection
+// @Description  Handler will create collection for authorized user, only name from body is used
+// @Security     ApiKeyAuth
+// @Tags         collection
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.Collection  true  "collection info"
+// @Success      201    {string}  string            "collection created"
+// @Failure      400    {object}  lib.Problem       "bad request"
+// @Failure      401    {object}  lib.Problem       "user is not authorized"
+// @Failure      409    {object}  lib.Problem       "collection with this name already exists"
+// @Failure      500    {object}  lib.Problem       "internal server error"
+// @Router       /me/collections [post]
+func (h *Handler) CreateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	collection := model.Collection{}
+	if err := json.NewDecoder(r.Body).Decode(&collection); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	collection.UserID = principal.UserID
+
+	err := h.service.Saved.CreateCollection(&collection)
+	if err != nil {
+		h.writeCollectionError(w, r, err, zap.Any("collection structure", collection))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusCreated, "collection created")
+}
+
+// UpdateCollectionHandler godoc
+// @ID           update-collection
+// @Summary      UpdateCollection
+// @Description  Handler will rename collection of authorized user by id from url
+// @Security     ApiKeyAuth
+// @Tags         collection
+// @Accept       json
+// @Produce      json
+// @Param        collection_id  path      integer           true  "collection id"
+// @Param        input          body      model.Collection  true  "collection info"
+// @Success      200            {string}  string            "collection updated"
+// @Failure      400            {object}  lib.Problem       "bad request"
+// @Failure      401            {object}  lib.Problem       "user is not authorized"
+// @Failure      404            {object}  lib.Problem       "collection not found"
+// @Failure      409            {object}  lib.Problem       "collection with this name already exists"
+// @Failure      500            {object}  lib.Problem       "internal server error"
+// @Router       /me/collections/{collection_id} [put]
+func (h *Handler) UpdateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	collectionID, err := strconv.Atoi(mux.Vars(r)["collection_id"])
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("collection id is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	collection := model.Collection{}
+	if err := json.NewDecoder(r.Body).Decode(&collection); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	collection.ID = collectionID
+	collection.UserID = principal.UserID
+
+	err = h.service.Saved.UpdateCollection(&collection)
+	if err != nil {
+		h.writeCollectionError(w, r, err, zap.Any("collection structure", collection))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "collection updated")
+}
+
+// DeleteCollectionHandler godoc
+// @ID           delete-collection
+// @Summary      DeleteCollection
+// @Description  Handler will delete collection of authorized user by id from url, its messages stay saved without collection
+// @Security     ApiKeyAuth
+// @Tags         collection
+// @Produce      json
+// @Param        collection_id  path      integer      true  "collection id"
+// @Success      200            {string}  string       "collection deleted"
+// @Failure      400            {object}  lib.Problem  "bad request"
+// @Failure      401            {object}  lib.Problem  "user is not authorized"
+// @Failure      404            {object}  lib.Problem  "collection not found"
+// @Failure      500            {object}  lib.Problem  "internal server error"
+// @Router       /me/collections/{collection_id} [delete]
+func (h *Handler) DeleteCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	collectionID, err := strconv.Atoi(mux.Vars(r)["collection_id"])
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("collection id is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	err = h.service.Saved.DeleteCollection(principal.UserID, collectionID)
+	if err != nil {
+		h.writeCollectionError(w, r, err, zap.String("collection id", strconv.Itoa(collectionID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "collection deleted")
+}
+
+func (h *Handler) writeCollectionError(w http.ResponseWriter, r *http.Request, err error, fields ...zap.Field) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCollectionName):
+		h.WriteError(w, r, http.StatusBadRequest, err, fields...)
+	case errors.Is(err, pg.ErrCollectionNotFound):
+		h.WriteError(w, r, http.StatusNotFound, err, fields...)
+	case errors.Is(err, pg.ErrCollectionExists):
+		h.WriteError(w, r, http.StatusConflict, err, fields...)
+	default:
+		h.WriteError(w, r, http.StatusInternalServerError, err, fields...)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/oidc"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+const (
+	requestIDKey    contextKey = "request id"
+	requestIDHeader            = "X-Request-ID"
+)
+
+var (
+	errUnauthorized         = errors.New("user is not authorized")
+	errInvalidAuthHeader    = errors.New("invalid auth header")
+	errEmptyAuthToken       = errors.New("token is empty")
+	errInvalidBody          = errors.New("can't decode request body")
+	errForbidden            = errors.New("user has no rights for this action")
+	errShareForbidden       = errors.New("user has no rights for this action on share")
+	errSessionRequired      = errors.New("api key can't be used for this action")
+	errProviderRejected     = errors.New("identity provider rejected sign-in")
+	errProviderNotAvailable = errors.New("identity provider is not available")
+)
+
+// parameterError is problem with value of path, query or body parameter, its message is shown to client.
+type parameterError string
+
+func (e parameterError) Error() string {
+	return string(e)
+}
+
+// errorCodes maps errors which clients can handle to stable machine-readable codes.
+// Codes are part of api contract, so they must not be changed once released.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{errUnauthorized, "UNAUTHORIZED"},
+	{errInvalidAuthHeader, "INVALID_AUTH_HEADER"},
+	{errEmptyAuthToken, "INVALID_AUTH_HEADER"},
+	{errInvalidBody, "INVALID_REQUEST_BODY"},
+	{errForbidden, "FORBIDDEN"},
+	{errShareForbidden, "FORBIDDEN"},
+	{errSessionRequired, "API_KEY_NOT_ALLOWED"},
+	{errProviderRejected, "IDENTITY_PROVIDER_REJECTED"},
+	{errProviderNotAvailable, "IDENTITY_PROVIDER_NOT_AVAILABLE"},
+
+	{pg.ErrChannelsCountNotFound, "CHANNELS_COUNT_NOT_FOUND"},
+	{pg.ErrChannelsNotFound, "CHANNELS_NOT_FOUND"},
+	{pg.ErrChannelNotFound, "CHANNEL_NOT_FOUND"},
+	{pg.ErrMessagesCountNotFound, "MESSAGES_COUNT_NOT_FOUND"},
+	{pg.ErrFullMessagesNotFound, "MESSAGES_NOT_FOUND"},
+	{pg.ErrFullMessageNotFound, "MESSAGE_NOT_FOUND"},
+	{pg.ErrFullRepliesNotFound, "REPLIES_NOT_FOUND"},
+	{pg.ErrSearchResultsNotFound, "SEARCH_RESULTS_NOT_FOUND"},
+	{pg.ErrUserNotFound, "USER_NOT_FOUND"},
+	{pg.ErrWebUserNotFound, "WEB_USER_NOT_FOUND"},
+	{pg.ErrWebUserExists, "WEB_USER_EXISTS"},
+	{pg.ErrWebUserIdentityExists, "IDENTITY_ALREADY_LINKED"},
+	{pg.ErrSavedMessagesNotFound, "SAVED_MESSAGES_NOT_FOUND"},
+	{pg.ErrSavedMessageNotFound, "SAVED_MESSAGE_NOT_FOUND"},
+	{pg.ErrSavedMessageExists, "SAVED_MESSAGE_EXISTS"},
+	{pg.ErrCollectionsNotFound, "COLLECTIONS_NOT_FOUND"},
+	{pg.ErrCollectionNotFound, "COLLECTION_NOT_FOUND"},
+	{pg.ErrCollectionExists, "COLLECTION_EXISTS"},
+	{pg.ErrSessionNotFound, "SESSION_NOT_FOUND"},
+	{pg.ErrSharesNotFound, "SHARES_NOT_FOUND"},
+	{pg.ErrShareNotFound, "SHARE_NOT_FOUND"},
+	{pg.ErrShareItemNotFound, "SHARE_ITEM_NOT_FOUND"},
+	{pg.ErrShareMembersNotFound, "SHARE_MEMBERS_NOT_FOUND"},
+	{pg.ErrShareMemberNotFound, "SHARE_MEMBER_NOT_FOUND"},
+	{pg.ErrAPIKeysNotFound, "API_KEYS_NOT_FOUND"},
+	{pg.ErrAPIKeyNotFound, "API_KEY_NOT_FOUND"},
+	{pg.ErrIngestFailuresNotFound, "INGEST_FAILURES_NOT_FOUND"},
+	{pg.ErrIngestFailureNotFound, "INGEST_FAILURE_NOT_FOUND"},
+	{pg.ErrSignInAttemptNotFound, "SIGN_IN_ATTEMPT_NOT_FOUND"},
+	{pg.ErrAuditEventsNotFound, "AUDIT_EVENTS_NOT_FOUND"},
+
+	{service.ErrInvalidCursor, "INVALID_CURSOR"},
+	{service.ErrInvalidLimit, "INVALID_LIMIT"},
+	{service.ErrEmptySearchQuery, "EMPTY_SEARCH_QUERY"},
+	{service.ErrUnknownLanguage, "UNKNOWN_SEARCH_LANGUAGE"},
+	{service.ErrInvalidCredentials, "INVALID_CREDENTIALS"},
+	{service.ErrSignInLocked, "SIGN_IN_LOCKED"},
+	{service.ErrEmptyUnlockInput, "EMPTY_UNLOCK_INPUT"},
+	{service.ErrInvalidAccessToken, "INVALID_ACCESS_TOKEN"},
+	{service.ErrInvalidIssuer, "INVALID_ACCESS_TOKEN"},
+	{service.ErrInvalidAudience, "INVALID_ACCESS_TOKEN"},
+	{service.ErrTokenWithoutUser, "INVALID_ACCESS_TOKEN"},
+	{service.ErrTokenWithoutRole, "INVALID_ACCESS_TOKEN"},
+	{service.ErrTokenWithoutSession, "INVALID_ACCESS_TOKEN"},
+	{service.ErrSessionRevoked, "SESSION_REVOKED"},
+	{service.ErrInvalidRefreshToken, "INVALID_REFRESH_TOKEN"},
+	{service.ErrRefreshTokenReused, "REFRESH_TOKEN_REUSED"},
+	{service.ErrInvalidAccountToken, "INVALID_ACCOUNT_TOKEN"},
+	{service.ErrIncorrectPassword, "INCORRECT_PASSWORD"},
+	{service.ErrInvalidAPIKey, "INVALID_API_KEY"},
+	{service.ErrInvalidAPIKeyName, "INVALID_API_KEY_NAME"},
+	{service.ErrInvalidAPIKeyScope, "INVALID_API_KEY_SCOPE"},
+	{service.ErrAPIKeyScopeNotAllowed, "API_KEY_SCOPE_NOT_ALLOWED"},
+	{service.ErrInvalidCollectionName, "INVALID_COLLECTION_NAME"},
+	{service.ErrInvalidTag, "INVALID_TAG"},
+	{service.ErrInvalidShareTitle, "INVALID_SHARE_TITLE"},
+	{service.ErrInvalidShareRole, "INVALID_SHARE_ROLE"},
+	{service.ErrEmptySavedIDs, "EMPTY_SAVED_IDS"},
+	{service.ErrShareOwnerMember, "SHARE_OWNER_MEMBER"},
+	{service.ErrOIDCNotConfigured, "OIDC_NOT_CONFIGURED"},
+	{service.ErrInvalidOIDCState, "INVALID_OIDC_STATE"},
+	{service.ErrOIDCEmailRequired, "OIDC_EMAIL_REQUIRED"},
+	{service.ErrOIDCAccountExists, "OIDC_ACCOUNT_EXISTS"},
+	{oidc.ErrInvalidIDToken, "INVALID_ID_TOKEN"},
+}
+
+// describeError returns stable code of err and message which can be shown to client.
+// Known errors are described by themselves, so messages of wrapping errors don't leak to client,
+// other errors get generic code and text of http status whatever the status is.
+func describeError(httpCode int, err error) (string, string) {
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			return known.code, known.err.Error()
+		}
+	}
+
+	var paramErr parameterError
+	if errors.As(err, &paramErr) {
+		return "INVALID_PARAMETER", paramErr.Error()
+	}
+
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(httpCode), " ", "_")), http.StatusText(httpCode)
+}
+
+// requestIDPattern limits request ids accepted from clients, so they can't inject anything into logs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware gives every request correlation id, it's taken from X-Request-ID header of request
+// when it's valid and generated otherwise. The id is sent back in X-Request-ID header.
+func (h *Handler) RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, requestID)
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey, requestID)))
+	})
+}
+
+// requestIDFromContext returns correlation id stored by RequestIDMiddleware, it's empty when middleware didn't run.
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+
+	return requestID
+}
+
+func newRequestID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "unknown"
+	}
+
+	return hex.EncodeToString(bytes)
+}
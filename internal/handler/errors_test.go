@@ -0,0 +1,163 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/handler"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/service/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/pkg/lib"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+func Test_RequestIDMiddleware(t *testing.T) {
+	generatedID := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	tests := []struct {
+		name         string
+		mock         func(userSrv *mocks.UserService)
+		requestID    string
+		expectedID   string
+		expectedErr  lib.Problem
+		expectedCode int
+	}{
+		{
+			name: "Ok: [request id from client is kept]",
+			mock: func(userSrv *mocks.UserService) {
+				userSrv.On("GetUserByID", 1).Return(nil, fmt.Errorf("[User] srv.GetUserByID error: %w", pg.ErrUserNotFound))
+			},
+			requestID:    "client-request.1",
+			expectedID:   "client-request.1",
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "user not found", Code: "USER_NOT_FOUND"},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "Ok: [request id is generated when client sent none]",
+			mock: func(userSrv *mocks.UserService) {
+				userSrv.On("GetUserByID", 1).Return(nil, fmt.Errorf("[User] srv.GetUserByID error: connection refused"))
+			},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "Ok: [request id from client is replaced when it's not valid]",
+			mock: func(userSrv *mocks.UserService) {
+				userSrv.On("GetUserByID", 1).Return(nil, fmt.Errorf("[User] srv.GetUserByID error: connection refused"))
+			},
+			requestID:    "bad id\nwith new line",
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/user/1", nil)
+			if err != nil {
+				t.Fatalf("could not create request: %s", err)
+			}
+
+			if tt.requestID != "" {
+				req.Header.Set("X-Request-ID", tt.requestID)
+			}
+
+			rr := httptest.NewRecorder()
+
+			log := logger.Get("debug")
+
+			userSrv := &mocks.UserService{}
+			tt.mock(userSrv)
+
+			handler := handler.New(&service.Manager{User: userSrv}, log)
+
+			router := mux.NewRouter()
+			router.Use(handler.RequestIDMiddleware)
+			router.HandleFunc("/user/{id}", handler.GetUserByIDHandler)
+			router.ServeHTTP(rr, req)
+
+			requestID := rr.Header().Get("X-Request-ID")
+			if tt.expectedID != "" {
+				assert.EqualValues(t, tt.expectedID, requestID)
+			} else {
+				assert.Regexp(t, generatedID, requestID)
+			}
+
+			decodedErr := lib.Problem{}
+			json.NewDecoder(rr.Body).Decode(&decodedErr)
+
+			tt.expectedErr.CorrelationID = requestID
+			assert.EqualValues(t, tt.expectedErr, decodedErr)
+
+			assert.EqualValues(t, lib.ProblemContentType, rr.Header().Get("Content-Type"))
+			assert.EqualValues(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+			assert.EqualValues(t, tt.expectedCode, rr.Code)
+
+			userSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_WriteError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		code        int
+		expectedErr lib.Problem
+	}{
+		{
+			name:        "Ok: [known error is described by itself]",
+			err:         fmt.Errorf("[User] srv.GetUserByID error: %w", pg.ErrUserNotFound),
+			code:        http.StatusNotFound,
+			expectedErr: lib.Problem{Title: "Not Found", Status: 404, Detail: "user not found", Code: "USER_NOT_FOUND"},
+		},
+		{
+			name:        "Ok: [unknown client error is hidden]",
+			err:         errors.New(`pq: invalid input syntax for type integer: "abc"`),
+			code:        http.StatusBadRequest,
+			expectedErr: lib.Problem{Title: "Bad Request", Status: 400, Detail: "Bad Request", Code: "BAD_REQUEST"},
+		},
+		{
+			name:        "Ok: [unknown server error is hidden]",
+			err:         errors.New("dial tcp 10.0.0.1:5432: connection refused"),
+			code:        http.StatusServiceUnavailable,
+			expectedErr: lib.Problem{Title: "Service Unavailable", Status: 503, Detail: "Service Unavailable", Code: "SERVICE_UNAVAILABLE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/error", nil)
+			if err != nil {
+				t.Fatalf("could not create request: %s", err)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler := handler.New(&service.Manager{}, logger.Get("debug"))
+
+			router := mux.NewRouter()
+			router.Use(handler.RequestIDMiddleware)
+			router.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+				handler.WriteError(w, r, tt.code, tt.err)
+			})
+			router.ServeHTTP(rr, req)
+
+			decodedErr := lib.Problem{}
+			json.NewDecoder(rr.Body).Decode(&decodedErr)
+
+			tt.expectedErr.CorrelationID = rr.Header().Get("X-Request-ID")
+			assert.EqualValues(t, tt.expectedErr, decodedErr)
+			assert.EqualValues(t, tt.code, rr.Code)
+		})
+	}
+}
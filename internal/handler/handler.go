@@ -10,7 +10,9 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.uber.org/zap"
 
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/validation"
 	"github.com/VladPetriv/scanner_backend_api/pkg/lib"
 	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
 )
@@ -29,6 +31,7 @@ func New(service *service.Manager, log *logger.Logger) *Handler {
 
 func (h *Handler) InitRoutes() *mux.Router {
 	router := mux.NewRouter()
+	router.Use(h.RequestIDMiddleware)
 
 	channel := router.PathPrefix("/channel").Subrouter()
 	channel.HandleFunc("/count", h.GetChannelsCountHandler).Methods(http.MethodGet)
@@ -49,15 +52,73 @@ func (h *Handler) InitRoutes() *mux.Router {
 	message.HandleFunc("/user/{user_id}", h.GetFullMessagesByUserIDHandler).Methods(http.MethodGet)
 	message.HandleFunc("/{message_id}", h.GetFullMessageByIDHandler).Methods(http.MethodGet)
 
+	router.HandleFunc("/search", h.SearchMessagesHandler).Methods(http.MethodGet)
+	router.HandleFunc("/share/{token}", h.GetPublicSharedMessagesHandler).Methods(http.MethodGet)
+
+	router.HandleFunc("/.well-known/jwks.json", h.JWKSHandler).Methods(http.MethodGet)
+
 	auth := router.PathPrefix("/auth").Subrouter()
 	auth.HandleFunc("/sign-up", h.SignUpHandler).Methods(http.MethodPost)
 	auth.HandleFunc("/sign-in", h.SignInHandler).Methods(http.MethodPost)
-
-	saved := router.PathPrefix("/saved").Subrouter()
-	saved.Use(h.AuthenticateMiddleware)
-	saved.HandleFunc("/{user_id}", h.GetSavedMessagesHandler).Methods(http.MethodGet)
-	saved.HandleFunc("/create", h.CreateSavedMessageHandler).Methods(http.MethodPost)
-	saved.HandleFunc("/delete/{message_id}", h.DeleteSavedMessageHandler).Methods(http.MethodDelete)
+	auth.HandleFunc("/refresh", h.RefreshHandler).Methods(http.MethodPost)
+	auth.HandleFunc("/forgot-password", h.ForgotPasswordHandler).Methods(http.MethodPost)
+	auth.HandleFunc("/reset-password", h.ResetPasswordHandler).Methods(http.MethodPost)
+	auth.HandleFunc("/verify-email", h.VerifyEmailHandler).Methods(http.MethodPost)
+	auth.HandleFunc("/confirm-email", h.ConfirmEmailHandler).Methods(http.MethodPost)
+	auth.HandleFunc("/oidc/login", h.OIDCLoginHandler).Methods(http.MethodGet)
+	auth.HandleFunc("/oidc/callback", h.OIDCCallbackHandler).Methods(http.MethodGet)
+	auth.Handle("/logout", h.AuthenticateMiddleware(h.SessionOnlyMiddleware(http.HandlerFunc(h.LogoutHandler)))).Methods(http.MethodPost)
+	auth.Handle("/logout-all", h.AuthenticateMiddleware(h.SessionOnlyMiddleware(http.HandlerFunc(h.LogoutAllHandler)))).Methods(http.MethodPost)
+
+	me := router.PathPrefix("/me").Subrouter()
+	me.Use(h.AuthenticateMiddleware)
+
+	// readonly web users can only read, changes need at least member role
+	member := h.AuthorizeMiddleware(model.RoleMember)
+	me.HandleFunc("/saved", h.GetSavedMessagesHandler).Methods(http.MethodGet)
+	me.Handle("/saved", member(http.HandlerFunc(h.CreateSavedMessageHandler))).Methods(http.MethodPost)
+	me.Handle("/saved/{saved_id}", member(http.HandlerFunc(h.UpdateSavedMessageHandler))).Methods(http.MethodPut)
+	me.Handle("/saved/{saved_id}", member(http.HandlerFunc(h.DeleteSavedMessageHandler))).Methods(http.MethodDelete)
+	me.HandleFunc("/collections", h.GetCollectionsHandler).Methods(http.MethodGet)
+	me.Handle("/collections", member(http.HandlerFunc(h.CreateCollectionHandler))).Methods(http.MethodPost)
+	me.Handle("/collections/{collection_id}", member(http.HandlerFunc(h.UpdateCollectionHandler))).Methods(http.MethodPut)
+	me.Handle("/collections/{collection_id}", member(http.HandlerFunc(h.DeleteCollectionHandler))).Methods(http.MethodDelete)
+	me.HandleFunc("/shares", h.GetSharesHandler).Methods(http.MethodGet)
+
+	// api keys can read everything, but they can change only saved messages and collections
+	session := h.SessionOnlyMiddleware
+	me.Handle("/shares", session(member(http.HandlerFunc(h.CreateShareHandler)))).Methods(http.MethodPost)
+
+	viewer := h.ShareAccessMiddleware(model.ShareRoleViewer)
+	editor := h.ShareAccessMiddleware(model.ShareRoleEditor)
+	owner := h.ShareAccessMiddleware(model.ShareRoleOwner)
+	me.Handle("/shares/{share_id}", viewer(http.HandlerFunc(h.GetShareHandler))).Methods(http.MethodGet)
+	me.Handle("/shares/{share_id}", session(member(owner(http.HandlerFunc(h.DeleteShareHandler))))).Methods(http.MethodDelete)
+	me.Handle("/shares/{share_id}/link", session(member(owner(http.HandlerFunc(h.RotateShareLinkHandler))))).Methods(http.MethodPost)
+	me.Handle("/shares/{share_id}/link", session(member(owner(http.HandlerFunc(h.RevokeShareLinkHandler))))).Methods(http.MethodDelete)
+	me.Handle("/shares/{share_id}/items", session(member(editor(http.HandlerFunc(h.AddShareItemsHandler))))).Methods(http.MethodPost)
+	me.Handle("/shares/{share_id}/items/{message_id}", session(member(editor(http.HandlerFunc(h.DeleteShareItemHandler))))).Methods(http.MethodDelete)
+	me.Handle("/shares/{share_id}/members", owner(http.HandlerFunc(h.GetShareMembersHandler))).Methods(http.MethodGet)
+	me.Handle("/shares/{share_id}/members", session(member(owner(http.HandlerFunc(h.GrantShareMemberHandler))))).Methods(http.MethodPut)
+	me.Handle("/shares/{share_id}/members/{user_id}", session(member(owner(http.HandlerFunc(h.RevokeShareMemberHandler))))).Methods(http.MethodDelete)
+
+	me.Handle("/api-keys", session(http.HandlerFunc(h.GetAPIKeysHandler))).Methods(http.MethodGet)
+	me.Handle("/api-keys", session(http.HandlerFunc(h.CreateAPIKeyHandler))).Methods(http.MethodPost)
+	me.Handle("/api-keys/{key_id}", session(http.HandlerFunc(h.RenameAPIKeyHandler))).Methods(http.MethodPut)
+	me.Handle("/api-keys/{key_id}", session(http.HandlerFunc(h.RevokeAPIKeyHandler))).Methods(http.MethodDelete)
+
+	me.Handle("", session(http.HandlerFunc(h.DeleteAccountHandler))).Methods(http.MethodDelete)
+	me.Handle("/password", session(http.HandlerFunc(h.ChangePasswordHandler))).Methods(http.MethodPut)
+	me.Handle("/email", session(http.HandlerFunc(h.ChangeEmailHandler))).Methods(http.MethodPut)
+	me.Handle("/export", session(http.HandlerFunc(h.ExportAccountHandler))).Methods(http.MethodGet)
+
+	admin := router.PathPrefix("/admin").Subrouter()
+	admin.Use(h.AuthenticateMiddleware, h.AuthorizeMiddleware(model.RoleAdmin))
+	admin.HandleFunc("/ingest-failure/", h.GetIngestFailuresByPageHandler).Methods(http.MethodGet)
+	admin.HandleFunc("/ingest-failure/{failure_id}/replay", h.ReplayIngestFailureHandler).Methods(http.MethodPost)
+	admin.HandleFunc("/sign-in/unlock", h.UnlockSignInHandler).Methods(http.MethodPost)
+	admin.HandleFunc("/audit", h.GetAuditEventsHandler).Methods(http.MethodGet)
+	admin.HandleFunc("/audit/export", h.ExportAuditEventsHandler).Methods(http.MethodGet)
 
 	router.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:3000/swagger/doc.json"),
@@ -90,6 +151,7 @@ func (h *Handler) logAllRoutes(router *mux.Router) {
 }
 
 func (h *Handler) WriteJSON(w http.ResponseWriter, httpCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(httpCode)
 
 	if data != nil {
@@ -97,14 +159,58 @@ func (h *Handler) WriteJSON(w http.ResponseWriter, httpCode int, data interface{
 	}
 }
 
-func (h *Handler) WriteError(w http.ResponseWriter, httpCode int, err string) {
-	w.WriteHeader(httpCode)
+// WriteError writes error in problem details format. Known errors get stable code and their own message,
+// details of unknown errors are hidden from client. Error is logged only here, with correlation id of request
+// and fields which handler passes.
+func (h *Handler) WriteError(w http.ResponseWriter, r *http.Request, httpCode int, err error, fields ...zap.Field) {
+	code, detail := describeError(httpCode, err)
+	requestID := requestIDFromContext(r.Context())
+
+	h.logError(r, httpCode, err, fields)
+
+	h.writeProblem(w, &lib.Problem{
+		Title:         http.StatusText(httpCode),
+		Status:        httpCode,
+		Detail:        detail,
+		Code:          code,
+		CorrelationID: requestID,
+	})
+}
 
-	data, _ := json.Marshal(lib.HttpError{
-		Code:    httpCode,
-		Name:    http.StatusText(httpCode),
-		Message: err,
+// logError logs error sent to client, server errors as errors and client errors as warnings.
+func (h *Handler) logError(r *http.Request, httpCode int, err error, fields []zap.Field) {
+	fields = append([]zap.Field{
+		zap.String("correlation id", requestIDFromContext(r.Context())),
+		zap.Int("status", httpCode),
+		zap.String("path", r.URL.Path),
+		zap.Error(err),
+	}, fields...)
+
+	if httpCode >= http.StatusInternalServerError {
+		h.log.Error("server error", fields...)
+	} else {
+		h.log.Warn("client error", fields...)
+	}
+}
+
+// WriteValidationError writes bad request error with problem of every invalid input field.
+func (h *Handler) WriteValidationError(w http.ResponseWriter, r *http.Request, verrs validation.Errors, fields ...zap.Field) {
+	h.logError(r, http.StatusBadRequest, verrs, fields)
+
+	h.writeProblem(w, &lib.Problem{
+		Title:         http.StatusText(http.StatusBadRequest),
+		Status:        http.StatusBadRequest,
+		Detail:        "input is not valid",
+		Code:          "VALIDATION_FAILED",
+		CorrelationID: requestIDFromContext(r.Context()),
+		Fields:        verrs,
 	})
+}
+
+func (h *Handler) writeProblem(w http.ResponseWriter, problem *lib.Problem) {
+	w.Header().Set("Content-Type", lib.ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
 
-	http.Error(w, string(data), httpCode)
+	json.NewEncoder(w).Encode(problem)
 }
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+// GetIngestFailuresByPageHandler godoc
+// @ID           get-ingest-failures-by-page
+// @Summary      GetIngestFailuresByPage
+// @Description  Handler will return kafka messages which could not be ingested by page from query
+// @Security     ApiKeyAuth
+// @Tags         admin
+// @Produce      json
+// @Param        page  query     integer              true  "page"
+// @Success      200   {array}   model.IngestFailure  "ingest failures by page"
+// @Failure      400   {object}  lib.Problem          "bad request"
+// @Failure      401   {object}  lib.Problem          "user is not authorized"
+// @Failure      403   {object}  lib.Problem          "user is not admin"
+// @Failure      404   {object}  lib.Problem          "ingest failures not found"
+// @Failure      500   {object}  lib.Problem          "internal server error"
+// @Router       /admin/ingest-failure/ [get]
+func (h *Handler) GetIngestFailuresByPageHandler(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("page is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	failures, err := h.service.Ingest.GetIngestFailuresByPage(page)
+	if err != nil {
+		if errors.Is(err, pg.ErrIngestFailuresNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("page", strconv.Itoa(page)))
+
+			return
+		}
+
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("page", strconv.Itoa(page)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, failures)
+}
+
+// ReplayIngestFailureHandler godoc
+// @ID           replay-ingest-failure
+// @Summary      ReplayIngestFailure
+// @Description  Handler will ingest quarantined kafka message by id from url again and remove it on success
+// @Security     ApiKeyAuth
+// @Tags         admin
+// @Produce      json
+// @Param        failure_id  path      integer      true  "ingest failure id"
+// @Success      200         {string}  string       "ingest failure replayed"
+// @Failure      400         {object}  lib.Problem  "bad request"
+// @Failure      401         {object}  lib.Problem  "user is not authorized"
+// @Failure      403         {object}  lib.Problem  "user is not admin"
+// @Failure      404         {object}  lib.Problem  "ingest failure not found"
+// @Failure      500         {object}  lib.Problem  "internal server error"
+// @Router       /admin/ingest-failure/{failure_id}/replay [post]
+func (h *Handler) ReplayIngestFailureHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	failureID, err := strconv.Atoi(mux.Vars(r)["failure_id"])
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("failure id is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	err = h.service.Ingest.ReplayIngestFailure(principal, failureID, requestOrigin(r))
+	if err != nil {
+		if errors.Is(err, pg.ErrIngestFailureNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("id", strconv.Itoa(failureID)))
+
+			return
+		}
+
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("id", strconv.Itoa(failureID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "ingest failure replayed")
+}
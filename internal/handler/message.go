@@ -2,40 +2,54 @@ package handler
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
 )
 
 // GetMessagesCountHandler godoc
 // @ID           get-messages-count
 // @Summary      GetMessagesCount
-// @Description  Handler will return messages count
+// @Description  Handler will return count of messages matching filters from query
 // @Tags         message
 // @Produce      json
-// @Success      200         {object}  object         "messages count"
-// @Failure      404         {object}  lib.HttpError  "messages count not found"
-// @Failure      500         {object}  lib.HttpError  "internal server error"
+// @Param        channel_id   query     integer      false  "channel id"
+// @Param        channel      query     []string     false  "channel names"  collectionFormat(csv)
+// @Param        tg_user_id   query     integer      false  "telegram id of message author"
+// @Param        from         query     string       false  "messages posted at or after this RFC3339 time or day"
+// @Param        to           query     string       false  "messages posted before this RFC3339 time or until the end of this day"
+// @Param        min_replies  query     integer      false  "minimum replies count"
+// @Param        has_image    query     boolean      false  "messages with or without image"
+// @Param        no_replies   query     boolean      false  "messages without replies"
+// @Success      200          {object}  object       "messages count"
+// @Failure      400          {object}  lib.Problem  "bad request"
+// @Failure      404          {object}  lib.Problem  "messages count not found"
+// @Failure      500          {object}  lib.Problem  "internal server error"
 // @Router       /message/count [get]
 func (h *Handler) GetMessagesCountHandler(w http.ResponseWriter, r *http.Request) {
-	count, err := h.service.Message.GetMessagesCount()
+	filter, err := getMessageFilter(r)
 	if err != nil {
-		h.log.Error("get messages count error", zap.Error(err))
+		h.WriteError(w, r, http.StatusBadRequest, err)
 
-		if errors.Is(err, pg.ErrMessagesCountNotFound) {
-			err = errors.Unwrap(err)
+		return
+	}
 
-			h.WriteError(w, http.StatusNotFound, err.Error())
+	count, err := h.service.Message.GetMessagesCount(filter)
+	if err != nil {
+		if errors.Is(err, pg.ErrMessagesCountNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err)
 
 			return
 		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err)
 
 		return
 	}
@@ -46,80 +60,71 @@ func (h *Handler) GetMessagesCountHandler(w http.ResponseWriter, r *http.Request
 // GetMessagesCountByChannelIDHandler godoc
 // @ID           get-messages-by-channel-id-count
 // @Summary      GetMessagesByChannelIDCount
-// @Description  Handler will return messages count by channel id from url
+// @Description  Handler will return count of messages of channel from url, it's the same as /message/count with channel_id
 // @Tags         message
 // @Produce      json
-// @Param        channel_id  path      integer        true  "channel id"
-// @Success      200  {object}  object         "messages count"
-// @Failure      400         {object}  lib.HttpError  "bad request"
-// @Failure      404  {object}  lib.HttpError  "messages count not found"
-// @Failure      500  {object}  lib.HttpError  "internal server error"
+// @Param        channel_id   path      integer      true   "channel id"
+// @Param        channel      query     []string     false  "channel names"  collectionFormat(csv)
+// @Param        tg_user_id   query     integer      false  "telegram id of message author"
+// @Param        from         query     string       false  "messages posted at or after this RFC3339 time or day"
+// @Param        to           query     string       false  "messages posted before this RFC3339 time or until the end of this day"
+// @Param        min_replies  query     integer      false  "minimum replies count"
+// @Param        has_image    query     boolean      false  "messages with or without image"
+// @Param        no_replies   query     boolean      false  "messages without replies"
+// @Success      200          {object}  object       "messages count"
+// @Failure      400          {object}  lib.Problem  "bad request"
+// @Failure      404          {object}  lib.Problem  "messages count not found"
+// @Failure      500          {object}  lib.Problem  "internal server error"
 // @Router       /message/count/{channel_id} [get]
 func (h *Handler) GetMessagesCountByChannelIDHandler(w http.ResponseWriter, r *http.Request) {
-	channelID, err := strconv.Atoi(mux.Vars(r)["channel_id"])
-	if err != nil {
-		h.log.Error("get channel id from request error", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "channel id is not valid")
-
-		return
-	}
-
-	count, err := h.service.Message.GetMessagesCountByChannelID(channelID)
-	if err != nil {
-		h.log.Error("get messages count by channel id error", zap.String("id", strconv.Itoa(channelID)), zap.Error(err))
-
-		if errors.Is(err, pg.ErrMessagesCountNotFound) {
-			err = errors.Unwrap(err)
-
-			h.WriteError(w, http.StatusNotFound, err.Error())
-
-			return
-		}
-
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
-
-		return
-	}
-
-	h.WriteJSON(w, http.StatusOK, map[string]int{"count": count})
+	h.GetMessagesCountHandler(w, r)
 }
 
 // GetFullMessagesByPageHandler godoc
 // @ID           get-full-messages-by-page
 // @Summary      GetFullMessagesByPage
-// @Description  Handler will return full messages by page from query
+// @Description  Handler will return page of full messages matching filters from query after cursor, newest messages first
 // @Tags         message
 // @Produce      json
-// @Param        page  query     integer            true  "page"
-// @Success      200   {array}   model.FullMessage  "full messages by page"
-// @Failure      400         {object}  lib.HttpError      "bad request"
-// @Failure      404         {object}  lib.HttpError      "full messages not found"
-// @Failure      500         {object}  lib.HttpError      "internal server error"
+// @Param        cursor       query     string                  false  "cursor from next or prev of the previous page"
+// @Param        limit        query     integer                 false  "page size, 10 by default and 100 at most"
+// @Param        channel_id   query     integer                 false  "channel id"
+// @Param        channel      query     []string                false  "channel names"  collectionFormat(csv)
+// @Param        tg_user_id   query     integer                 false  "telegram id of message author"
+// @Param        from         query     string                  false  "messages posted at or after this RFC3339 time or day"
+// @Param        to           query     string                  false  "messages posted before this RFC3339 time or until the end of this day"
+// @Param        min_replies  query     integer                 false  "minimum replies count"
+// @Param        has_image    query     boolean                 false  "messages with or without image"
+// @Param        no_replies   query     boolean                 false  "messages without replies"
+// @Success      200          {object}  model.FullMessagesPage  "full messages by page"
+// @Failure      400          {object}  lib.Problem             "bad request"
+// @Failure      404          {object}  lib.Problem             "full messages not found"
+// @Failure      500          {object}  lib.Problem             "internal server error"
 // @Router       /message/ [get]
 func (h *Handler) GetFullMessagesByPageHandler(w http.ResponseWriter, r *http.Request) {
-	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	page, err := getPageRequest(r)
 	if err != nil {
-		h.log.Error("get page from query error", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "page is not valid")
+		h.WriteError(w, r, http.StatusBadRequest, err)
 
 		return
 	}
 
-	messages, err := h.service.Message.GetFullMessagesByPage(page)
+	filter, err := getMessageFilter(r)
 	if err != nil {
-		h.log.Error("get messages by page error", zap.String("page", strconv.Itoa(page)), zap.Error(err))
+		h.WriteError(w, r, http.StatusBadRequest, err)
 
-		if errors.Is(err, pg.ErrFullMessagesNotFound) {
-			err = errors.Unwrap(err)
+		return
+	}
 
-			h.WriteError(w, http.StatusNotFound, err.Error())
+	messages, err := h.service.Message.GetFullMessagesByPage(page, filter)
+	if err != nil {
+		if errors.Is(err, pg.ErrFullMessagesNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err)
 
 			return
 		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err)
 
 		return
 	}
@@ -130,53 +135,26 @@ func (h *Handler) GetFullMessagesByPageHandler(w http.ResponseWriter, r *http.Re
 // GetFullMessagesByChannelIDAndPageHandler godoc
 // @ID           get-full-messages-by-page-and-channel-id
 // @Summary      GetFullMessagesByChannelIDAndPage
-// @Description  Handler will return full messages by page from query and channel id from url
+// @Description  Handler will return page of full messages of channel from url, it's the same as /message/ with channel_id
 // @Tags         message
 // @Produce      json
-// @Param        channel_id  path      integer            true  "channel id"
-// @Param        page        query     integer            true  "page"
-// @Success      200         {array}   model.FullMessage  "full messages by page and channel id"
-// @Failure      400      {object}  lib.HttpError      "bad request"
-// @Failure      404      {object}  lib.HttpError      "full messages not found"
-// @Failure      500      {object}  lib.HttpError      "internal server error"
+// @Param        channel_id   path      integer                 true   "channel id"
+// @Param        cursor       query     string                  false  "cursor from next or prev of the previous page"
+// @Param        limit        query     integer                 false  "page size, 10 by default and 100 at most"
+// @Param        channel      query     []string                false  "channel names"  collectionFormat(csv)
+// @Param        tg_user_id   query     integer                 false  "telegram id of message author"
+// @Param        from         query     string                  false  "messages posted at or after this RFC3339 time or day"
+// @Param        to           query     string                  false  "messages posted before this RFC3339 time or until the end of this day"
+// @Param        min_replies  query     integer                 false  "minimum replies count"
+// @Param        has_image    query     boolean                 false  "messages with or without image"
+// @Param        no_replies   query     boolean                 false  "messages without replies"
+// @Success      200          {object}  model.FullMessagesPage  "full messages by page and channel id"
+// @Failure      400          {object}  lib.Problem             "bad request"
+// @Failure      404          {object}  lib.Problem             "full messages not found"
+// @Failure      500          {object}  lib.Problem             "internal server error"
 // @Router       /message/channel/{channel_id} [get]
 func (h *Handler) GetFullMessagesByChannelIDAndPageHandler(w http.ResponseWriter, r *http.Request) {
-	page, err := strconv.Atoi(r.URL.Query().Get("page"))
-	if err != nil {
-		h.log.Error("get page from query error", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "page is not valid")
-
-		return
-	}
-
-	channelID, err := strconv.Atoi(mux.Vars(r)["channel_id"])
-	if err != nil {
-		h.log.Error("get id from query error", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "channel id is not valid")
-
-		return
-	}
-
-	messages, err := h.service.Message.GetFullMessagesByChannelIDAndPage(channelID, page)
-	if err != nil {
-		h.log.Error("get full messages by page and channel id error", zap.String("id,page", fmt.Sprintf("%d,%d", channelID, page)))
-
-		if errors.Is(err, pg.ErrFullMessagesNotFound) {
-			err = errors.Unwrap(err)
-
-			h.WriteError(w, http.StatusNotFound, err.Error())
-
-			return
-		}
-
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
-
-		return
-	}
-
-	h.WriteJSON(w, http.StatusOK, messages)
+	h.GetFullMessagesByPageHandler(w, r)
 }
 
 // GetFullMessagesByUserIDHandler godoc
@@ -187,32 +165,26 @@ func (h *Handler) GetFullMessagesByChannelIDAndPageHandler(w http.ResponseWriter
 // @Produce      json
 // @Param        user_id  path      integer            true  "user id"
 // @Success      200      {array}   model.FullMessage  "full messages by user id"
-// @Failure      400         {object}  lib.HttpError      "bad request"
-// @Failure      404         {object}  lib.HttpError      "full messages not found"
-// @Failure      500         {object}  lib.HttpError      "internal server error"
+// @Failure      400      {object}  lib.Problem        "bad request"
+// @Failure      404      {object}  lib.Problem        "full messages not found"
+// @Failure      500      {object}  lib.Problem        "internal server error"
 // @Router       /message/user/{user_id} [get]
 func (h *Handler) GetFullMessagesByUserIDHandler(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
 	if err != nil {
-		h.log.Error("get user id from url error", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "user id is not valid")
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("user id is not valid"), zap.NamedError("cause", err))
 
 		return
 	}
 
 	messages, err := h.service.Message.GetFullMessagesByUserID(userID)
 	if err != nil {
-		h.log.Error("get full messages by user id error", zap.String("id", strconv.Itoa(userID)), zap.Error(err))
-
 		if errors.Is(err, pg.ErrFullMessagesNotFound) {
-			err = errors.Unwrap(err)
-
-			h.WriteError(w, http.StatusNotFound, err.Error())
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("id", strconv.Itoa(userID)))
 
 			return
 		}
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("id", strconv.Itoa(userID)))
 
 		return
 	}
@@ -228,36 +200,120 @@ func (h *Handler) GetFullMessagesByUserIDHandler(w http.ResponseWriter, r *http.
 // @Produce      json
 // @Param        message_id  path      integer            true  "message id"
 // @Success      200         {object}  model.FullMessage  "full message by user id"
-// @Failure      400   {object}  lib.HttpError      "bad request"
-// @Failure      404   {object}  lib.HttpError      "full messages not found"
-// @Failure      500   {object}  lib.HttpError      "internal server error"
+// @Failure      400         {object}  lib.Problem        "bad request"
+// @Failure      404         {object}  lib.Problem        "full messages not found"
+// @Failure      500         {object}  lib.Problem        "internal server error"
 // @Router       /message/{message_id} [get]
 func (h *Handler) GetFullMessageByIDHandler(w http.ResponseWriter, r *http.Request) {
 	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
 	if err != nil {
-		h.log.Error("get message id from url error", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "message id is not valid")
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("message id is not valid"), zap.NamedError("cause", err))
 
 		return
 	}
 
 	message, err := h.service.Message.GetFullMessageByID(messageID)
 	if err != nil {
-		h.log.Error("get message by id error", zap.String("id", strconv.Itoa(messageID)), zap.Error(err))
-
 		if errors.Is(err, pg.ErrFullMessageNotFound) {
-			err = errors.Unwrap(err)
-
-			h.WriteError(w, http.StatusNotFound, err.Error())
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("id", strconv.Itoa(messageID)))
 
 			return
 		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("id", strconv.Itoa(messageID)))
 
 		return
 	}
 
 	h.WriteJSON(w, http.StatusOK, message)
 }
+
+// getMessageFilter reads message filters from query, channel id from url takes precedence over query one.
+// Times are accepted in RFC3339 or as a day, a day in "to" includes the whole day.
+func getMessageFilter(r *http.Request) (*model.MessageFilter, error) {
+	query := r.URL.Query()
+	filter := &model.MessageFilter{}
+
+	channelID, ok := mux.Vars(r)["channel_id"]
+	if !ok {
+		channelID = query.Get("channel_id")
+	}
+
+	if channelID != "" {
+		var err error
+
+		filter.ChannelID, err = strconv.Atoi(channelID)
+		if err != nil || filter.ChannelID <= 0 {
+			return nil, parameterError("channel id is not valid")
+		}
+	}
+
+	for _, names := range query["channel"] {
+		for _, name := range strings.Split(names, ",") {
+			if name != "" {
+				filter.ChannelNames = append(filter.ChannelNames, name)
+			}
+		}
+	}
+
+	var err error
+
+	if value := query.Get("tg_user_id"); value != "" {
+		filter.TgUserID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, parameterError("tg user id is not valid")
+		}
+	}
+
+	if value := query.Get("from"); value != "" {
+		filter.From, err = parseQueryTime(value, false)
+		if err != nil {
+			return nil, parameterError("from is not valid")
+		}
+	}
+
+	if value := query.Get("to"); value != "" {
+		filter.To, err = parseQueryTime(value, true)
+		if err != nil {
+			return nil, parameterError("to is not valid")
+		}
+	}
+
+	if value := query.Get("min_replies"); value != "" {
+		filter.MinReplies, err = strconv.Atoi(value)
+		if err != nil || filter.MinReplies < 0 {
+			return nil, parameterError("min replies is not valid")
+		}
+	}
+
+	if value := query.Get("has_image"); value != "" {
+		hasImage, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, parameterError("has image is not valid")
+		}
+
+		filter.HasImage = &hasImage
+	}
+
+	if value := query.Get("no_replies"); value != "" {
+		filter.NoReplies, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, parameterError("no replies is not valid")
+		}
+	}
+
+	return filter, nil
+}
+
+func parseQueryTime(value string, endOfDay bool) (time.Time, error) {
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Parse(time.RFC3339, value)
+	}
+
+	if endOfDay {
+		return day.AddDate(0, 0, 1), nil
+	}
+
+	return day, nil
+}
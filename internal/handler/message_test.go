@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -23,42 +24,78 @@ func Test_GetMessagesCountHandler(t *testing.T) {
 	tests := []struct {
 		name          string
 		mock          func(messageSrv *mocks.MessageService)
+		query         string
 		wantErr       bool
-		expectedErr   lib.HttpError
+		expectedErr   lib.Problem
 		expectedCount int
 		expectedCode  int
 	}{
 		{
 			name: "Ok: [messages count found]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetMessagesCount").Return(10, nil)
+				messageSrv.On("GetMessagesCount", &model.MessageFilter{}).Return(10, nil)
 			},
 			expectedCount: 10,
 			expectedCode:  http.StatusOK,
 		},
+		{
+			name: "Ok: [filtered messages count found]",
+			mock: func(messageSrv *mocks.MessageService) {
+				hasImage := false
+
+				messageSrv.On("GetMessagesCount", &model.MessageFilter{
+					ChannelNames: []string{"go_go", "golang", "rust"},
+					TgUserID:     100,
+					From:         time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC),
+					To:           time.Date(2022, time.July, 2, 0, 0, 0, 0, time.UTC),
+					MinReplies:   2,
+					HasImage:     &hasImage,
+					NoReplies:    true,
+				}).Return(3, nil)
+			},
+			query:         "?channel=go_go,golang&channel=rust&tg_user_id=100&from=2022-07-01&to=2022-07-01&min_replies=2&has_image=false&no_replies=true",
+			expectedCount: 3,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:         "Error: [from is not valid]",
+			mock:         func(messageSrv *mocks.MessageService) {},
+			query:        "?from=july",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "from is not valid", Code: "INVALID_PARAMETER"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Error: [min replies is not valid]",
+			mock:         func(messageSrv *mocks.MessageService) {},
+			query:        "?min_replies=-1",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "min replies is not valid", Code: "INVALID_PARAMETER"},
+			expectedCode: http.StatusBadRequest,
+		},
 		{
 			name: "Error: [messages count not found]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetMessagesCount").Return(0, fmt.Errorf("[Message] srv.GetMessagesCount error: %w", pg.ErrMessagesCountNotFound))
+				messageSrv.On("GetMessagesCount", &model.MessageFilter{}).Return(0, fmt.Errorf("[Message] srv.GetMessagesCount error: %w", pg.ErrMessagesCountNotFound))
 			},
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "messages count not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "messages count not found", Code: "MESSAGES_COUNT_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
 			name: "Error: [some internal error]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetMessagesCount").Return(0, fmt.Errorf("[Message] srv.GetMessagesCount error: some error"))
+				messageSrv.On("GetMessagesCount", &model.MessageFilter{}).Return(0, fmt.Errorf("[Message] srv.GetMessagesCount error: some error"))
 			},
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Message] srv.GetMessagesCount error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodGet, "/message/count", nil)
+			req, err := http.NewRequest(http.MethodGet, "/message/count"+tt.query, nil)
 			if err != nil {
 				t.Fatalf("could not create request: %s", err)
 			}
@@ -77,7 +114,7 @@ func Test_GetMessagesCountHandler(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			decodedCount := map[string]int{}
-			decodedErr := lib.HttpError{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -102,14 +139,14 @@ func Test_GetMessagesCountByChannelIDHandler(t *testing.T) {
 		mock          func(messageSrv *mocks.MessageService)
 		input         string
 		wantErr       bool
-		expectedErr   lib.HttpError
+		expectedErr   lib.Problem
 		expectedCount int
 		expectedCode  int
 	}{
 		{
 			name: "Ok: [messages count found]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetMessagesCountByChannelID", 1).Return(10, nil)
+				messageSrv.On("GetMessagesCount", &model.MessageFilter{ChannelID: 1}).Return(10, nil)
 			},
 			input:         "1",
 			expectedCount: 10,
@@ -118,21 +155,21 @@ func Test_GetMessagesCountByChannelIDHandler(t *testing.T) {
 		{
 			name: "Error: [messages count not found]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetMessagesCountByChannelID", 1).Return(0, fmt.Errorf("[Message] srv.GetMessagesCountByChannelID error: %w", pg.ErrMessagesCountNotFound))
+				messageSrv.On("GetMessagesCount", &model.MessageFilter{ChannelID: 1}).Return(0, fmt.Errorf("[Message] srv.GetMessagesCount error: %w", pg.ErrMessagesCountNotFound))
 			},
 			input:        "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "messages count not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "messages count not found", Code: "MESSAGES_COUNT_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
 			name: "Error: [some internal error]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetMessagesCountByChannelID", 1).Return(0, fmt.Errorf("[Message] srv.GetMessagesCountByChannelID error: some error"))
+				messageSrv.On("GetMessagesCount", &model.MessageFilter{ChannelID: 1}).Return(0, fmt.Errorf("[Message] srv.GetMessagesCount error: some error"))
 			},
 			input:        "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Message] srv.GetMessagesCountByChannelID error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 		{
@@ -140,7 +177,7 @@ func Test_GetMessagesCountByChannelIDHandler(t *testing.T) {
 			mock:         func(messageSrv *mocks.MessageService) {},
 			input:        "hello",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 400, Name: "Bad Request", Message: "channel id is not valid"},
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "channel id is not valid", Code: "INVALID_PARAMETER"},
 			expectedCode: http.StatusBadRequest,
 		},
 	}
@@ -166,7 +203,7 @@ func Test_GetMessagesCountByChannelIDHandler(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			decodedCount := map[string]int{}
-			decodedErr := lib.HttpError{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -187,61 +224,81 @@ func Test_GetMessagesCountByChannelIDHandler(t *testing.T) {
 }
 
 func Test_GetFullMessagesByPageHandler(t *testing.T) {
-	testMessages := []model.FullMessage{
-		{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5},
+	testMessages := &model.FullMessagesPage{
+		Items:    []model.FullMessage{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}},
+		PageInfo: model.PageInfo{Next: "Zjo6NQ", HasMore: true},
 	}
+	page := &model.PageRequest{Limit: 10}
 
 	tests := []struct {
 		name             string
 		mock             func(messageSrv *mocks.MessageService)
 		input            string
 		wantErr          bool
-		expectedErr      lib.HttpError
-		expectedMessages []model.FullMessage
+		expectedErr      lib.Problem
+		expectedMessages *model.FullMessagesPage
 		expectedCode     int
 	}{
 		{
 			name: "Ok: [messages found]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetFullMessagesByPage", 1).Return(testMessages, nil)
+				messageSrv.On("GetFullMessagesByPage", page, &model.MessageFilter{}).Return(testMessages, nil)
 			},
-			input:            "1",
+			input:            "",
 			expectedMessages: testMessages,
 			expectedCode:     http.StatusOK,
 		},
+		{
+			name: "Ok: [messages of channel with image found]",
+			mock: func(messageSrv *mocks.MessageService) {
+				hasImage := true
+				messageSrv.On("GetFullMessagesByPage", page, &model.MessageFilter{ChannelID: 2, HasImage: &hasImage}).Return(&model.FullMessagesPage{Items: testMessages.Items[:1]}, nil)
+			},
+			input:            "channel_id=2&has_image=true",
+			expectedMessages: &model.FullMessagesPage{Items: testMessages.Items[:1]},
+			expectedCode:     http.StatusOK,
+		},
 		{
 			name: "Error: [messages not found]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetFullMessagesByPage", 1).Return(nil, fmt.Errorf("[Message] srv.GetFullMessagesByPage error: %w", pg.ErrFullMessagesNotFound))
+				messageSrv.On("GetFullMessagesByPage", page, &model.MessageFilter{}).Return(nil, fmt.Errorf("[Message] srv.GetFullMessagesByPage error: %w", pg.ErrFullMessagesNotFound))
 			},
-			input:        "1",
+			input:        "",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "full messages not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "full messages not found", Code: "MESSAGES_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
 			name: "Error: [some internal error]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetFullMessagesByPage", 1).Return(nil, fmt.Errorf("[Message] srv.GetFullMessagesByPage error: some error"))
+				messageSrv.On("GetFullMessagesByPage", page, &model.MessageFilter{}).Return(nil, fmt.Errorf("[Message] srv.GetFullMessagesByPage error: some error"))
 			},
-			input:        "1",
+			input:        "",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Message] srv.GetFullMessagesByPage error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 		{
-			name:         "Error: [page is not valid]",
+			name:         "Error: [limit is not valid]",
 			mock:         func(messageSrv *mocks.MessageService) {},
-			input:        "hello",
+			input:        "limit=hello",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "limit is not valid", Code: "INVALID_LIMIT"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Error: [channel id is not valid]",
+			mock:         func(messageSrv *mocks.MessageService) {},
+			input:        "channel_id=go",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 400, Name: "Bad Request", Message: "page is not valid"},
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "channel id is not valid", Code: "INVALID_PARAMETER"},
 			expectedCode: http.StatusBadRequest,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/message/?page=%s", tt.input), nil)
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/message/?%s", tt.input), nil)
 			if err != nil {
 				t.Fatalf("could not create request: %s", err)
 			}
@@ -259,8 +316,8 @@ func Test_GetFullMessagesByPageHandler(t *testing.T) {
 			router.HandleFunc("/message/", handler.GetFullMessagesByPageHandler)
 			router.ServeHTTP(rr, req)
 
-			decodedMessages := []model.FullMessage{}
-			decodedErr := lib.HttpError{}
+			decodedMessages := &model.FullMessagesPage{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -268,7 +325,7 @@ func Test_GetFullMessagesByPageHandler(t *testing.T) {
 				assert.EqualValues(t, tt.expectedErr, decodedErr)
 				assert.EqualValues(t, tt.expectedCode, rr.Code)
 			} else {
-				json.NewDecoder(rr.Body).Decode(&decodedMessages)
+				json.NewDecoder(rr.Body).Decode(decodedMessages)
 
 				assert.EqualValues(t, tt.expectedMessages, decodedMessages)
 				assert.EqualValues(t, tt.expectedCode, rr.Code)
@@ -286,82 +343,80 @@ func Test_GetFullMessagesByChannelIDAndPageHandler(t *testing.T) {
 		{ID: 11}, {ID: 12}, {ID: 13}, {ID: 14}, {ID: 15},
 		{ID: 16}, {ID: 17}, {ID: 18}, {ID: 19}, {ID: 20},
 	}
+	firstPage := &model.FullMessagesPage{Items: testMessages[:10], PageInfo: model.PageInfo{Next: "Zjo6MTA", HasMore: true}}
+	lastPage := &model.FullMessagesPage{Items: testMessages[10:], PageInfo: model.PageInfo{Prev: "Yjo6MTE"}}
 
 	tests := []struct {
 		name             string
 		mock             func(messageSrv *mocks.MessageService)
-		page             string
+		query            string
 		channelID        string
 		wantErr          bool
-		expectedErr      lib.HttpError
-		expectedMessages []model.FullMessage
+		expectedErr      lib.Problem
+		expectedMessages *model.FullMessagesPage
 		expectedCode     int
 	}{
 		{
-			name: "Ok: [messages found on page 1]",
+			name: "Ok: [first page of messages found]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetFullMessagesByChannelIDAndPage", 1, 1).Return(testMessages[:10], nil)
+				messageSrv.On("GetFullMessagesByPage", &model.PageRequest{Limit: 10}, &model.MessageFilter{ChannelID: 1}).Return(firstPage, nil)
 			},
-			page:             "1",
 			channelID:        "1",
-			expectedMessages: testMessages[:10],
+			expectedMessages: firstPage,
 			expectedCode:     http.StatusOK,
 		},
 		{
-			name: "Ok: [messages found on page 2]",
+			name: "Ok: [messages after cursor found]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetFullMessagesByChannelIDAndPage", 1, 2).Return(testMessages[10:], nil)
+				messageSrv.On("GetFullMessagesByPage", &model.PageRequest{Cursor: &model.Cursor{ID: 10}, Limit: 10}, &model.MessageFilter{ChannelID: 1}).Return(lastPage, nil)
 			},
-			page:             "2",
+			query:            "cursor=Zjo6MTA",
 			channelID:        "1",
-			expectedMessages: testMessages[10:],
+			expectedMessages: lastPage,
 			expectedCode:     http.StatusOK,
 		},
 		{
 			name: "Error: [messages not found]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetFullMessagesByChannelIDAndPage", 1, 1).Return(nil, fmt.Errorf("[Message] srv.GetFullMessagesByChannelIDAndPage error: %w", pg.ErrFullMessagesNotFound))
+				messageSrv.On("GetFullMessagesByPage", &model.PageRequest{Limit: 10}, &model.MessageFilter{ChannelID: 1}).Return(nil, fmt.Errorf("[Message] srv.GetFullMessagesByPage error: %w", pg.ErrFullMessagesNotFound))
 			},
-			page:         "1",
 			channelID:    "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "full messages not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "full messages not found", Code: "MESSAGES_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
 			name: "Error: [some internal error]",
 			mock: func(messageSrv *mocks.MessageService) {
-				messageSrv.On("GetFullMessagesByChannelIDAndPage", 1, 1).Return(nil, fmt.Errorf("[Message] srv.GetFullMessagesByChannelIDAndPage error: some error"))
+				messageSrv.On("GetFullMessagesByPage", &model.PageRequest{Limit: 10}, &model.MessageFilter{ChannelID: 1}).Return(nil, fmt.Errorf("[Message] srv.GetFullMessagesByPage error: some error"))
 			},
-			page:         "1",
 			channelID:    "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Message] srv.GetFullMessagesByChannelIDAndPage error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 		{
-			name:         "Error: [page is not valid]",
+			name:         "Error: [cursor is not valid]",
 			mock:         func(messageSrv *mocks.MessageService) {},
-			page:         "hello",
+			query:        "cursor=hello",
 			channelID:    "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 400, Name: "Bad Request", Message: "page is not valid"},
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "cursor is not valid", Code: "INVALID_CURSOR"},
 			expectedCode: http.StatusBadRequest,
 		},
 		{
 			name:         "Error: [channel id is not valid]",
 			mock:         func(messageSrv *mocks.MessageService) {},
-			page:         "1",
 			channelID:    "hello",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 400, Name: "Bad Request", Message: "channel id is not valid"},
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "channel id is not valid", Code: "INVALID_PARAMETER"},
 			expectedCode: http.StatusBadRequest,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/message/channel/%s?page=%s", tt.channelID, tt.page), nil)
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/message/channel/%s?%s", tt.channelID, tt.query), nil)
 			if err != nil {
 				t.Fatalf("could not create request: %s", err)
 			}
@@ -379,8 +434,8 @@ func Test_GetFullMessagesByChannelIDAndPageHandler(t *testing.T) {
 			router.HandleFunc("/message/channel/{channel_id}", handler.GetFullMessagesByChannelIDAndPageHandler)
 			router.ServeHTTP(rr, req)
 
-			decodedMessages := []model.FullMessage{}
-			decodedErr := lib.HttpError{}
+			decodedMessages := &model.FullMessagesPage{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -388,7 +443,7 @@ func Test_GetFullMessagesByChannelIDAndPageHandler(t *testing.T) {
 				assert.EqualValues(t, tt.expectedErr, decodedErr)
 				assert.EqualValues(t, tt.expectedCode, rr.Code)
 			} else {
-				json.NewDecoder(rr.Body).Decode(&decodedMessages)
+				json.NewDecoder(rr.Body).Decode(decodedMessages)
 
 				assert.EqualValues(t, tt.expectedMessages, decodedMessages)
 				assert.EqualValues(t, tt.expectedCode, rr.Code)
@@ -412,7 +467,7 @@ func Test_GetFullMessagesByUserIDHandler(t *testing.T) {
 		mock             func(messageSrv *mocks.MessageService)
 		input            string
 		wantErr          bool
-		expectedErr      lib.HttpError
+		expectedErr      lib.Problem
 		expectedMessages []model.FullMessage
 		expectedCode     int
 	}{
@@ -441,7 +496,7 @@ func Test_GetFullMessagesByUserIDHandler(t *testing.T) {
 			},
 			input:        "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "full messages not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "full messages not found", Code: "MESSAGES_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
@@ -451,7 +506,7 @@ func Test_GetFullMessagesByUserIDHandler(t *testing.T) {
 			},
 			input:        "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Message] srv.GetFullMessagesByUserID error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 		{
@@ -459,7 +514,7 @@ func Test_GetFullMessagesByUserIDHandler(t *testing.T) {
 			mock:         func(messageSrv *mocks.MessageService) {},
 			input:        "hello",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 400, Name: "Bad Request", Message: "user id is not valid"},
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "user id is not valid", Code: "INVALID_PARAMETER"},
 			expectedCode: http.StatusBadRequest,
 		},
 	}
@@ -485,7 +540,7 @@ func Test_GetFullMessagesByUserIDHandler(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			decodedMessages := []model.FullMessage{}
-			decodedErr := lib.HttpError{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -512,7 +567,7 @@ func Test_GetFullMessageByIDHandler(t *testing.T) {
 		mock            func(messageSrv *mocks.MessageService)
 		input           string
 		wantErr         bool
-		expectedErr     lib.HttpError
+		expectedErr     lib.Problem
 		expectedMessage model.FullMessage
 		expectedCode    int
 	}{
@@ -532,7 +587,7 @@ func Test_GetFullMessageByIDHandler(t *testing.T) {
 			},
 			input:        "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "full message not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "full message not found", Code: "MESSAGE_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
@@ -542,7 +597,7 @@ func Test_GetFullMessageByIDHandler(t *testing.T) {
 			},
 			input:        "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Message] srv.GetFullMessageByID error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 		{
@@ -550,7 +605,7 @@ func Test_GetFullMessageByIDHandler(t *testing.T) {
 			mock:         func(messageSrv *mocks.MessageService) {},
 			input:        "Hello",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 400, Name: "Bad Request", Message: "message id is not valid"},
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "message id is not valid", Code: "INVALID_PARAMETER"},
 			expectedCode: http.StatusBadRequest,
 		},
 	}
@@ -576,7 +631,7 @@ func Test_GetFullMessageByIDHandler(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			decodedMessage := model.FullMessage{}
-			decodedErr := lib.HttpError{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/oidc"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+)
+
+// OIDCLoginHandler godoc
+// @ID           oidc-login
+// @Summary      OIDCLogin
+// @Description  Handler will start sign-in with company identity provider and redirect to its sign-in page, the provider redirects back to callback
+// @Tags         auth
+// @Success      302  {string}  string       "redirect to identity provider"
+// @Failure      404  {object}  lib.Problem  "sign-in with identity provider is not configured"
+// @Failure      502  {object}  lib.Problem  "identity provider is not available"
+// @Failure      500  {object}  lib.Problem  "internal server error"
+// @Router       /auth/oidc/login [get]
+func (h *Handler) OIDCLoginHandler(w http.ResponseWriter, r *http.Request) {
+	authURL, err := h.service.OIDC.StartLogin()
+	if err != nil {
+		h.writeOIDCError(w, r, err)
+
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OIDCCallbackHandler godoc
+// @ID           oidc-callback
+// @Summary      OIDCCallback
+// @Description  Handler will finish sign-in with identity provider and return JWT access token with refresh token. Unknown account is linked to user with the same verified email or new member is created
+// @Tags         auth
+// @Produce      json
+// @Param        code   query     string        true  "authorization code"
+// @Param        state  query     string        true  "state of the sign-in"
+// @Success      200    {object}  model.Tokens  "tokens"
+// @Failure      400    {object}  lib.Problem   "sign-in is expired or already finished"
+// @Failure      401    {object}  lib.Problem   "identity provider rejected sign-in or id token is not valid"
+// @Failure      403    {object}  lib.Problem   "identity provider didn't share email"
+// @Failure      404    {object}  lib.Problem   "sign-in with identity provider is not configured"
+// @Failure      409    {object}  lib.Problem   "account with the same email exists and can't be linked"
+// @Failure      502    {object}  lib.Problem   "identity provider is not available"
+// @Failure      500    {object}  lib.Problem   "internal server error"
+// @Router       /auth/oidc/callback [get]
+func (h *Handler) OIDCCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if providerErr := query.Get("error"); providerErr != "" {
+		h.WriteError(w, r, http.StatusUnauthorized, errProviderRejected, zap.String("error", providerErr), zap.String("description", query.Get("error_description")))
+
+		return
+	}
+
+	code, state := query.Get("code"), query.Get("state")
+	if code == "" || state == "" {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("code and state are required"))
+
+		return
+	}
+
+	tokens, err := h.service.OIDC.FinishLogin(code, state, requestOrigin(r))
+	if err != nil {
+		h.writeOIDCError(w, r, err)
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, tokens)
+}
+
+func (h *Handler) writeOIDCError(w http.ResponseWriter, r *http.Request, err error, fields ...zap.Field) {
+	switch {
+	case errors.Is(err, service.ErrOIDCNotConfigured):
+		h.WriteError(w, r, http.StatusNotFound, err, fields...)
+	case errors.Is(err, service.ErrInvalidOIDCState):
+		h.WriteError(w, r, http.StatusBadRequest, err, fields...)
+	case errors.Is(err, oidc.ErrInvalidIDToken):
+		h.WriteError(w, r, http.StatusUnauthorized, err, fields...)
+	case errors.Is(err, service.ErrOIDCEmailRequired):
+		h.WriteError(w, r, http.StatusForbidden, err, fields...)
+	case errors.Is(err, service.ErrOIDCAccountExists):
+		h.WriteError(w, r, http.StatusConflict, err, fields...)
+	case errors.Is(err, oidc.ErrDiscovery), errors.Is(err, oidc.ErrPKCEUnsupported), errors.Is(err, oidc.ErrTokenExchange):
+		h.WriteError(w, r, http.StatusBadGateway, errProviderNotAvailable, append(fields, zap.NamedError("cause", err))...)
+	default:
+		h.WriteError(w, r, http.StatusInternalServerError, err, fields...)
+	}
+}
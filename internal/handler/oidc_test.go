@@ -0,0 +1,214 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/handler"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/oidc"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/service/mocks"
+	"github.com/VladPetriv/scanner_backend_api/pkg/lib"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+func Test_OIDCLoginHandler(t *testing.T) {
+	authURL := "https://sso.example.com/authorize?client_id=scanner&state=state"
+
+	tests := []struct {
+		name             string
+		mock             func(oidcSrv *mocks.OIDCService)
+		wantErr          bool
+		expectedErr      lib.Problem
+		expectedLocation string
+		expectedCode     int
+	}{
+		{
+			name: "Ok: [redirected to identity provider]",
+			mock: func(oidcSrv *mocks.OIDCService) {
+				oidcSrv.On("StartLogin").Return(authURL, nil)
+			},
+			expectedLocation: authURL,
+			expectedCode:     http.StatusFound,
+		},
+		{
+			name: "Error: [sso is not configured]",
+			mock: func(oidcSrv *mocks.OIDCService) {
+				oidcSrv.On("StartLogin").Return("", fmt.Errorf("[OIDC] srv.StartLogin error: %w", service.ErrOIDCNotConfigured))
+			},
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "sign-in with identity provider is not configured", Code: "OIDC_NOT_CONFIGURED"},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "Error: [identity provider is down]",
+			mock: func(oidcSrv *mocks.OIDCService) {
+				oidcSrv.On("StartLogin").Return("", fmt.Errorf("[OIDC] srv.StartLogin error: %w: unexpected status 503", oidc.ErrDiscovery))
+			},
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Gateway", Status: 502, Detail: "identity provider is not available", Code: "IDENTITY_PROVIDER_NOT_AVAILABLE"},
+			expectedCode: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/auth/oidc/login", nil)
+			if err != nil {
+				t.Fatalf("could not create request: %s", err)
+			}
+
+			rr := httptest.NewRecorder()
+
+			log := logger.Get("debug")
+
+			oidcSrv := &mocks.OIDCService{}
+			tt.mock(oidcSrv)
+
+			handler := handler.New(&service.Manager{OIDC: oidcSrv}, log)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/auth/oidc/login", handler.OIDCLoginHandler)
+			router.ServeHTTP(rr, req)
+
+			if tt.wantErr {
+				decodedErr := lib.Problem{}
+				json.NewDecoder(rr.Body).Decode(&decodedErr)
+
+				assert.EqualValues(t, tt.expectedErr, decodedErr)
+			} else {
+				assert.EqualValues(t, tt.expectedLocation, rr.Header().Get("Location"))
+			}
+
+			assert.EqualValues(t, tt.expectedCode, rr.Code)
+
+			oidcSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_OIDCCallbackHandler(t *testing.T) {
+	testTokens := &model.Tokens{AccessToken: "access-token", RefreshToken: "refresh-token", ExpiresIn: 900}
+
+	tests := []struct {
+		name           string
+		mock           func(oidcSrv *mocks.OIDCService)
+		query          string
+		wantErr        bool
+		expectedErr    lib.Problem
+		expectedResult *model.Tokens
+		expectedCode   int
+	}{
+		{
+			name: "Ok: [user signed in]",
+			mock: func(oidcSrv *mocks.OIDCService) {
+				oidcSrv.On("FinishLogin", "code", "state", model.Origin{}).Return(testTokens, nil)
+			},
+			query:          "?code=code&state=state",
+			expectedResult: testTokens,
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name: "Error: [sign-in is expired]",
+			mock: func(oidcSrv *mocks.OIDCService) {
+				oidcSrv.On("FinishLogin", "code", "state", model.Origin{}).Return(nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w", service.ErrInvalidOIDCState))
+			},
+			query:        "?code=code&state=state",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "sign-in with identity provider is expired or already finished", Code: "INVALID_OIDC_STATE"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error: [id token is not valid]",
+			mock: func(oidcSrv *mocks.OIDCService) {
+				oidcSrv.On("FinishLogin", "code", "state", model.Origin{}).Return(nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w: nonce mismatch", oidc.ErrInvalidIDToken))
+			},
+			query:        "?code=code&state=state",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Unauthorized", Status: 401, Detail: "id token is not valid", Code: "INVALID_ID_TOKEN"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "Error: [account with the same email can't be linked]",
+			mock: func(oidcSrv *mocks.OIDCService) {
+				oidcSrv.On("FinishLogin", "code", "state", model.Origin{}).Return(nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w", service.ErrOIDCAccountExists))
+			},
+			query:        "?code=code&state=state",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Conflict", Status: 409, Detail: "account with this email exists, sign in with password and verify email to link it", Code: "OIDC_ACCOUNT_EXISTS"},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name: "Error: [code exchange failed]",
+			mock: func(oidcSrv *mocks.OIDCService) {
+				oidcSrv.On("FinishLogin", "code", "state", model.Origin{}).Return(nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w: invalid_grant", oidc.ErrTokenExchange))
+			},
+			query:        "?code=code&state=state",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Gateway", Status: 502, Detail: "identity provider is not available", Code: "IDENTITY_PROVIDER_NOT_AVAILABLE"},
+			expectedCode: http.StatusBadGateway,
+		},
+		{
+			name:         "Error: [identity provider rejected sign-in]",
+			mock:         func(oidcSrv *mocks.OIDCService) {},
+			query:        "?error=access_denied&error_description=user+cancelled&state=state",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Unauthorized", Status: 401, Detail: "identity provider rejected sign-in", Code: "IDENTITY_PROVIDER_REJECTED"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "Error: [code is missing]",
+			mock:         func(oidcSrv *mocks.OIDCService) {},
+			query:        "?state=state",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "code and state are required", Code: "INVALID_PARAMETER"},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/auth/oidc/callback"+tt.query, nil)
+			if err != nil {
+				t.Fatalf("could not create request: %s", err)
+			}
+
+			rr := httptest.NewRecorder()
+
+			log := logger.Get("debug")
+
+			oidcSrv := &mocks.OIDCService{}
+			tt.mock(oidcSrv)
+
+			handler := handler.New(&service.Manager{OIDC: oidcSrv}, log)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/auth/oidc/callback", handler.OIDCCallbackHandler)
+			router.ServeHTTP(rr, req)
+
+			decodedResult := &model.Tokens{}
+			decodedErr := lib.Problem{}
+
+			if tt.wantErr {
+				json.NewDecoder(rr.Body).Decode(&decodedErr)
+
+				assert.EqualValues(t, tt.expectedErr, decodedErr)
+				assert.EqualValues(t, tt.expectedCode, rr.Code)
+			} else {
+				json.NewDecoder(rr.Body).Decode(decodedResult)
+
+				assert.EqualValues(t, tt.expectedResult, decodedResult)
+				assert.EqualValues(t, tt.expectedCode, rr.Code)
+			}
+
+			oidcSrv.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+)
+
+// getPageRequest reads cursor and limit from query, both of them are optional.
+func getPageRequest(r *http.Request) (*model.PageRequest, error) {
+	var limit int
+
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, service.ErrInvalidLimit
+		}
+	}
+
+	return service.NewPageRequest(r.URL.Query().Get("cursor"), limit)
+}
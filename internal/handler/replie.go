@@ -14,38 +14,41 @@ import (
 // GetFullRepliesByMessageIDHandler godoc
 // @ID           get-full-replies-by-message-id
 // @Summary      GetFullRepliesByMessageID
-// @Description  Handler will return full replies by message id from url
+// @Description  Handler will return page of full replies after cursor from query by message id from url, newest replies first
 // @Tags         replie
 // @Produce      json
-// @Param        message_id  path      integer           true  "message id"
-// @Success      200         {array}   model.FullReplie  "replies by message id"
-// @Failure      400         {object}  lib.HttpError     "bad request"
-// @Failure      404         {object}  lib.HttpError     "replies not found"
-// @Failure      500         {object}  lib.HttpError     "internal server error"
+// @Param        message_id  path      integer                true   "message id"
+// @Param        cursor      query     string                 false  "cursor from next or prev of the previous page"
+// @Param        limit       query     integer                false  "page size, 10 by default and 100 at most"
+// @Success      200         {object}  model.FullRepliesPage  "replies by message id"
+// @Failure      400         {object}  lib.Problem            "bad request"
+// @Failure      404         {object}  lib.Problem            "replies not found"
+// @Failure      500         {object}  lib.Problem            "internal server error"
 // @Router       /replie/{message_id} [get]
 func (h *Handler) GetFullRepliesByMessageIDHandler(w http.ResponseWriter, r *http.Request) {
 	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
 	if err != nil {
-		h.log.Error("failed to get message id", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "message id is not valid")
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("message id is not valid"), zap.NamedError("cause", err))
 
 		return
 	}
 
-	replies, err := h.service.Replie.GetFullRepliesByMessageID(messageID)
+	page, err := getPageRequest(r)
 	if err != nil {
-		h.log.Error("get full replies by message id error", zap.String("id", strconv.Itoa(messageID)), zap.Error(err))
+		h.WriteError(w, r, http.StatusBadRequest, err)
 
-		if errors.Is(err, pg.ErrFullRepliesNotFound) {
-			err = errors.Unwrap(err)
+		return
+	}
 
-			h.WriteError(w, http.StatusNotFound, err.Error())
+	replies, err := h.service.Replie.GetFullRepliesByMessageID(messageID, page)
+	if err != nil {
+		if errors.Is(err, pg.ErrFullRepliesNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("id", strconv.Itoa(messageID)))
 
 			return
 		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("id", strconv.Itoa(messageID)))
 
 		return
 	}
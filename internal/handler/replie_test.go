@@ -20,6 +20,7 @@ import (
 )
 
 func Test_GetFullRepliesByMessageIDHandler(t *testing.T) {
+	page := &model.PageRequest{Limit: 10}
 	testReplies := []model.FullReplie{
 		{
 			ID:           1,
@@ -44,37 +45,37 @@ func Test_GetFullRepliesByMessageIDHandler(t *testing.T) {
 		mock            func(replieSrv *mocks.ReplieService)
 		input           string
 		wantErr         bool
-		expectedErr     lib.HttpError
-		expectedReplies []model.FullReplie
+		expectedErr     lib.Problem
+		expectedReplies *model.FullRepliesPage
 		expectedCode    int
 	}{
 		{
 			name: "Ok: [replies found]",
 			mock: func(replieSrv *mocks.ReplieService) {
-				replieSrv.On("GetFullRepliesByMessageID", 1).Return(testReplies, nil)
+				replieSrv.On("GetFullRepliesByMessageID", 1, page).Return(&model.FullRepliesPage{Items: testReplies}, nil)
 			},
 			input:           "1",
-			expectedReplies: testReplies,
+			expectedReplies: &model.FullRepliesPage{Items: testReplies},
 			expectedCode:    http.StatusOK,
 		},
 		{
 			name: "Error: [replies not found]",
 			mock: func(replieSrv *mocks.ReplieService) {
-				replieSrv.On("GetFullRepliesByMessageID", 1).Return(nil, fmt.Errorf("[Replie] srv.GetFullRepliesByMessageID error: %w", pg.ErrFullRepliesNotFound))
+				replieSrv.On("GetFullRepliesByMessageID", 1, page).Return(nil, fmt.Errorf("[Replie] srv.GetFullRepliesByMessageID error: %w", pg.ErrFullRepliesNotFound))
 			},
 			input:        "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 404, Name: "Not Found", Message: "full replies not found"},
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "full replies not found", Code: "REPLIES_NOT_FOUND"},
 			expectedCode: http.StatusNotFound,
 		},
 		{
 			name: "Error: [some internal error]",
 			mock: func(replieSrv *mocks.ReplieService) {
-				replieSrv.On("GetFullRepliesByMessageID", 1).Return(nil, fmt.Errorf("[Replie] srv.GetFullRepliesByMessageID error: some error"))
+				replieSrv.On("GetFullRepliesByMessageID", 1, page).Return(nil, fmt.Errorf("[Replie] srv.GetFullRepliesByMessageID error: some error"))
 			},
 			input:        "1",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[Replie] srv.GetFullRepliesByMessageID error: some error"},
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 			expectedCode: http.StatusInternalServerError,
 		},
 		{
@@ -82,7 +83,15 @@ func Test_GetFullRepliesByMessageIDHandler(t *testing.T) {
 			mock:         func(replieSrv *mocks.ReplieService) {},
 			input:        "hello",
 			wantErr:      true,
-			expectedErr:  lib.HttpError{Code: 400, Name: "Bad Request", Message: "message id is not valid"},
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "message id is not valid", Code: "INVALID_PARAMETER"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Error: [limit is not valid]",
+			mock:         func(replieSrv *mocks.ReplieService) {},
+			input:        "1?limit=-5",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "limit is not valid", Code: "INVALID_LIMIT"},
 			expectedCode: http.StatusBadRequest,
 		},
 	}
@@ -107,8 +116,8 @@ func Test_GetFullRepliesByMessageIDHandler(t *testing.T) {
 			router.HandleFunc("/replie/{message_id}", handler.GetFullRepliesByMessageIDHandler)
 			router.ServeHTTP(rr, req)
 
-			decodedReplies := []model.FullReplie{}
-			decodedErr := lib.HttpError{}
+			decodedReplies := &model.FullRepliesPage{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -116,7 +125,7 @@ func Test_GetFullRepliesByMessageIDHandler(t *testing.T) {
 				assert.EqualValues(t, tt.expectedErr, decodedErr)
 				assert.EqualValues(t, tt.expectedCode, rr.Code)
 			} else {
-				json.NewDecoder(rr.Body).Decode(&decodedReplies)
+				json.NewDecoder(rr.Body).Decode(decodedReplies)
 
 				assert.EqualValues(t, tt.expectedReplies, decodedReplies)
 				assert.EqualValues(t, tt.expectedCode, rr.Code)
@@ -5,50 +5,70 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
 	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
 )
 
 // GetSavedMessagesHandler godoc
 // @ID           get-saved-messages
 // @Summary      GetSavedMessages
-// @Description  Handler will return saved messages by user id from url
+// @Description  Handler will return page of messages saved by authorized user with full message info after cursor from query
 // @Security     ApiKeyAuth
 // @Tags         saved
 // @Produce      json
-// @Param        user_id  path      integer        true  "user id"
-// @Success      200      {array}   model.Saved    "saved by user id"
-// @Failure      400      {object}  lib.HttpError  "bad request"
-// @Failure      404      {object}  lib.HttpError  "saved messages not found"
-// @Failure      500      {object}  lib.HttpError  "internal server error"
-// @Router       /saved/{user_id} [get]
+// @Param        cursor      query     string           false  "cursor from next or prev of the previous page"
+// @Param        limit       query     integer          false  "page size, 10 by default and 100 at most"
+// @Param        collection  query     integer          false  "collection id"
+// @Param        tag         query     []string         false  "saved messages which have all of tags, can be repeated or separated by commas"
+// @Success      200         {object}  model.SavedPage  "saved messages of authorized user"
+// @Failure      400         {object}  lib.Problem      "bad request"
+// @Failure      401         {object}  lib.Problem      "user is not authorized"
+// @Failure      404         {object}  lib.Problem      "saved messages not found"
+// @Failure      500         {object}  lib.Problem      "internal server error"
+// @Router       /me/saved [get]
 func (h *Handler) GetSavedMessagesHandler(w http.ResponseWriter, r *http.Request) {
-	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	page, err := getPageRequest(r)
 	if err != nil {
-		h.log.Error("failed to get user id from Request", zap.Error(err))
+		h.WriteError(w, r, http.StatusBadRequest, err)
+
+		return
+	}
 
-		h.WriteError(w, http.StatusBadRequest, "user id is not valid")
+	filter, err := getSavedFilter(r)
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, err)
 
 		return
 	}
 
-	messages, err := h.service.Saved.GetSavedMessages(userID)
+	messages, err := h.service.Saved.GetSavedMessages(principal.UserID, filter, page)
 	if err != nil {
-		h.log.Error("failed to get saved messages", zap.String("user id", strconv.Itoa(userID)), zap.Error(err))
+		if errors.Is(err, service.ErrInvalidTag) {
+			h.WriteError(w, r, http.StatusBadRequest, err, zap.String("user id", strconv.Itoa(principal.UserID)))
 
-		if errors.Is(err, pg.ErrSavedMessagesNotFound) {
-			err = errors.Unwrap(err)
+			return
+		}
 
-			h.WriteError(w, http.StatusNotFound, err.Error())
+		if errors.Is(err, pg.ErrSavedMessagesNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("user id", strconv.Itoa(principal.UserID)))
 
 			return
 		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("user id", strconv.Itoa(principal.UserID)))
 
 		return
 	}
@@ -59,31 +79,45 @@ func (h *Handler) GetSavedMessagesHandler(w http.ResponseWriter, r *http.Request
 // CreateSavedMessageHandler godoc
 // @ID           create-saved-message
 // @Summary      CreateSavedMessage
-// @Description  Handler will create saved message and return message
+// @Description  Handler will save message for authorized user with optional collection, note and tags, user id from body is ignored
 // @Security     ApiKeyAuth
 // @Tags         saved
 // @Accept       json
 // @Produce      json
-// @Param        input  body      model.Saved    true  "saved message info"
-// @Success      201    {string}  string         "saved message created"
-// @Failure      400    {object}  lib.HttpError  "bad request"
-// @Failure      500    {object}  lib.HttpError  "internal server error"
-// @Router       /saved/create [post]
+// @Param        input  body      model.Saved  true  "saved message info"
+// @Success      201    {string}  string       "saved message created"
+// @Failure      400    {object}  lib.Problem  "bad request"
+// @Failure      401    {object}  lib.Problem  "user is not authorized"
+// @Failure      404    {object}  lib.Problem  "collection not found"
+// @Failure      409    {object}  lib.Problem  "message is already saved"
+// @Failure      500    {object}  lib.Problem  "internal server error"
+// @Router       /me/saved [post]
 func (h *Handler) CreateSavedMessageHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
 	saved := model.Saved{}
 	if err := json.NewDecoder(r.Body).Decode(&saved); err != nil {
-		h.log.Error("failed to decode request body", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "can't decode request body")
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
 
 		return
 	}
 
-	err := h.service.Saved.CreateSavedMessage(&saved)
+	saved.UserID = principal.UserID
+
+	err := h.service.Saved.CreateSavedMessage(&saved, requestOrigin(r))
 	if err != nil {
-		h.log.Error("failed to create saved message", zap.Any("saved structure", saved), zap.Error(err))
+		if errors.Is(err, pg.ErrSavedMessageExists) {
+			h.WriteError(w, r, http.StatusConflict, err, zap.Any("saved structure", saved))
+
+			return
+		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.writeSavedMessageError(w, r, err, zap.Any("saved structure", saved))
 
 		return
 	}
@@ -91,37 +125,146 @@ func (h *Handler) CreateSavedMessageHandler(w http.ResponseWriter, r *http.Reque
 	h.WriteJSON(w, http.StatusCreated, "saved message created")
 }
 
+// UpdateSavedMessageHandler godoc
+// @ID           update-saved-message
+// @Summary      UpdateSavedMessage
+// @Description  Handler will replace collection, note and tags of saved message of authorized user, null collection id moves message out of collection
+// @Security     ApiKeyAuth
+// @Tags         saved
+// @Accept       json
+// @Produce      json
+// @Param        saved_id  path      integer      true  "saved message id"
+// @Param        input     body      model.Saved  true  "saved message info, only collection id, note and tags are used"
+// @Success      200       {string}  string       "saved message updated"
+// @Failure      400       {object}  lib.Problem  "bad request"
+// @Failure      401       {object}  lib.Problem  "user is not authorized"
+// @Failure      404       {object}  lib.Problem  "saved message or collection not found"
+// @Failure      500       {object}  lib.Problem  "internal server error"
+// @Router       /me/saved/{saved_id} [put]
+func (h *Handler) UpdateSavedMessageHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	savedID, err := strconv.Atoi(mux.Vars(r)["saved_id"])
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("saved id is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	saved := model.Saved{}
+	if err := json.NewDecoder(r.Body).Decode(&saved); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	saved.ID = savedID
+	saved.UserID = principal.UserID
+
+	err = h.service.Saved.UpdateSavedMessage(&saved)
+	if err != nil {
+		if errors.Is(err, pg.ErrSavedMessageNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.Any("saved structure", saved))
+
+			return
+		}
+
+		h.writeSavedMessageError(w, r, err, zap.Any("saved structure", saved))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "saved message updated")
+}
+
 // DeleteSavedMessageHandler godoc
 // @ID           delete-saved-message
 // @Summary      DeleteSavedMessage
-// @Description  Handler will delete saved message by id from url
+// @Description  Handler will delete saved message of authorized user by id from url
 // @Security     ApiKeyAuth
 // @Tags         saved
 // @Accept       json
 // @Produce      json
-// @Param        message_id  path      integer        true  "saved message id"
-// @Success      200         {string}  string         "saved message deleted"
-// @Failure      400         {object}  lib.HttpError  "bad request"
-// @Failure      500         {object}  lib.HttpError  "internal server error"
-// @Router       /saved/delete/{message_id} [delete]
+// @Param        saved_id  path      integer      true  "saved message id"
+// @Success      200       {string}  string       "saved message deleted"
+// @Failure      400       {object}  lib.Problem  "bad request"
+// @Failure      401       {object}  lib.Problem  "user is not authorized"
+// @Failure      404       {object}  lib.Problem  "saved message not found"
+// @Failure      500       {object}  lib.Problem  "internal server error"
+// @Router       /me/saved/{saved_id} [delete]
 func (h *Handler) DeleteSavedMessageHandler(w http.ResponseWriter, r *http.Request) {
-	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
-	if err != nil {
-		h.log.Error("failed to get message id", zap.Error(err))
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
 
-		h.WriteError(w, http.StatusBadRequest, "message id is not valid")
+	savedID, err := strconv.Atoi(mux.Vars(r)["saved_id"])
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("saved id is not valid"), zap.NamedError("cause", err))
 
 		return
 	}
 
-	err = h.service.Saved.DeleteSavedMessage(messageID)
+	err = h.service.Saved.DeleteSavedMessage(principal.UserID, savedID, requestOrigin(r))
 	if err != nil {
-		h.log.Error("failed to delete saved message", zap.String("message id", strconv.Itoa(messageID)), zap.Error(err))
+		if errors.Is(err, pg.ErrSavedMessageNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("saved id", strconv.Itoa(savedID)))
+
+			return
+		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("saved id", strconv.Itoa(savedID)))
 
 		return
 	}
 
 	h.WriteJSON(w, http.StatusOK, "saved message deleted")
 }
+
+// writeSavedMessageError writes errors which are common for creating and updating saved message.
+func (h *Handler) writeSavedMessageError(w http.ResponseWriter, r *http.Request, err error, fields ...zap.Field) {
+	if errors.Is(err, service.ErrInvalidTag) {
+		h.WriteError(w, r, http.StatusBadRequest, err, fields...)
+
+		return
+	}
+
+	if errors.Is(err, pg.ErrCollectionNotFound) {
+		h.WriteError(w, r, http.StatusNotFound, err, fields...)
+
+		return
+	}
+
+	h.WriteError(w, r, http.StatusInternalServerError, err, fields...)
+}
+
+func getSavedFilter(r *http.Request) (*model.SavedFilter, error) {
+	query := r.URL.Query()
+	filter := &model.SavedFilter{}
+
+	for _, tags := range query["tag"] {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+
+	if value := query.Get("collection"); value != "" {
+		collectionID, err := strconv.Atoi(value)
+		if err != nil || collectionID <= 0 {
+			return nil, parameterError("collection is not valid")
+		}
+
+		filter.CollectionID = collectionID
+	}
+
+	return filter, nil
+}
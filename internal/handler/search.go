@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+// SearchMessagesHandler godoc
+// @ID           search-messages
+// @Summary      SearchMessages
+// @Description  Handler will return full messages whose title or replies match search query, best matches first
+// @Tags         search
+// @Produce      json
+// @Param        q           query     string              true   "search query, supports quotes, OR and -"
+// @Param        lang        query     string              false  "language of the query, for example en, ru or uk"
+// @Param        page        query     integer             true   "page"
+// @Param        channel     query     []string            false  "channel names" collectionFormat(csv)
+// @Param        tg_user_id  query     integer             false  "telegram id of message author"
+// @Param        from        query     string              false  "messages posted at or after this RFC3339 time or day"
+// @Param        to          query     string              false  "messages posted before this RFC3339 time or until the end of this day"
+// @Success      200         {array}   model.SearchResult  "search results"
+// @Failure      400         {object}  lib.Problem         "bad request"
+// @Failure      404         {object}  lib.Problem         "search results not found"
+// @Failure      500         {object}  lib.Problem         "internal server error"
+// @Router       /search [get]
+func (h *Handler) SearchMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("page is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	filter, err := getMessageFilter(r)
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, err)
+
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	lang := r.URL.Query().Get("lang")
+
+	results, err := h.service.Search.SearchMessages(query, lang, page, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrEmptySearchQuery) {
+			h.WriteError(w, r, http.StatusBadRequest, err, zap.String("query", query), zap.String("lang", lang))
+
+			return
+		}
+
+		if errors.Is(err, service.ErrUnknownLanguage) {
+			h.WriteError(w, r, http.StatusBadRequest, err, zap.String("query", query), zap.String("lang", lang))
+
+			return
+		}
+
+		if errors.Is(err, pg.ErrSearchResultsNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("query", query), zap.String("lang", lang))
+
+			return
+		}
+
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("query", query), zap.String("lang", lang))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,135 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/handler"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/service/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/pkg/lib"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+func Test_SearchMessagesHandler(t *testing.T) {
+	replieID := 3
+	testResults := []model.SearchResult{
+		{FullMessage: model.FullMessage{ID: 1, Title: "golang news", ChannelName: "go_go"}, Rank: 0.6, Snippet: "<b>golang</b> news"},
+		{FullMessage: model.FullMessage{ID: 2, Title: "test", ChannelName: "go_go"}, Rank: 0.3, Snippet: "about <b>golang</b>", MatchedReplieID: &replieID},
+	}
+
+	tests := []struct {
+		name           string
+		mock           func(searchSrv *mocks.SearchService)
+		query          string
+		wantErr        bool
+		expectedErr    lib.Problem
+		expectedResult []model.SearchResult
+		expectedCode   int
+	}{
+		{
+			name: "Ok: [search results found]",
+			mock: func(searchSrv *mocks.SearchService) {
+				searchSrv.On("SearchMessages", "golang", "en", 1, &model.MessageFilter{ChannelNames: []string{"go_go"}}).Return(testResults, nil)
+			},
+			query:          "q=golang&lang=en&page=1&channel=go_go",
+			expectedResult: testResults,
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name: "Error: [search results not found]",
+			mock: func(searchSrv *mocks.SearchService) {
+				searchSrv.On("SearchMessages", "golang", "", 1, &model.MessageFilter{}).Return(nil, fmt.Errorf("[Search] srv.SearchMessages error: %w", pg.ErrSearchResultsNotFound))
+			},
+			query:        "q=golang&page=1",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Not Found", Status: 404, Detail: "search results not found", Code: "SEARCH_RESULTS_NOT_FOUND"},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "Error: [search query is empty]",
+			mock: func(searchSrv *mocks.SearchService) {
+				searchSrv.On("SearchMessages", "", "", 1, &model.MessageFilter{}).Return(nil, fmt.Errorf("[Search] srv.SearchMessages error: %w", service.ErrEmptySearchQuery))
+			},
+			query:        "page=1",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "search query is empty", Code: "EMPTY_SEARCH_QUERY"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error: [unknown search language]",
+			mock: func(searchSrv *mocks.SearchService) {
+				searchSrv.On("SearchMessages", "golang", "de", 1, &model.MessageFilter{}).Return(nil, fmt.Errorf("[Search] srv.SearchMessages error: %w", service.ErrUnknownLanguage))
+			},
+			query:        "q=golang&lang=de&page=1",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "unknown search language", Code: "UNKNOWN_SEARCH_LANGUAGE"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Error: [page is not valid]",
+			mock:         func(searchSrv *mocks.SearchService) {},
+			query:        "q=golang&page=hello",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Bad Request", Status: 400, Detail: "page is not valid", Code: "INVALID_PARAMETER"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error: [some internal error]",
+			mock: func(searchSrv *mocks.SearchService) {
+				searchSrv.On("SearchMessages", "golang", "", 1, &model.MessageFilter{}).Return(nil, fmt.Errorf("[Search] srv.SearchMessages error: some error"))
+			},
+			query:        "q=golang&page=1",
+			wantErr:      true,
+			expectedErr:  lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/search?%s", tt.query), nil)
+			if err != nil {
+				t.Fatalf("could not create request: %s", err)
+			}
+
+			rr := httptest.NewRecorder()
+
+			log := logger.Get("debug")
+
+			searchSrv := &mocks.SearchService{}
+			tt.mock(searchSrv)
+
+			handler := handler.New(&service.Manager{Search: searchSrv}, log)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/search", handler.SearchMessagesHandler)
+			router.ServeHTTP(rr, req)
+
+			decodedResult := []model.SearchResult{}
+			decodedErr := lib.Problem{}
+
+			if tt.wantErr {
+				json.NewDecoder(rr.Body).Decode(&decodedErr)
+
+				assert.EqualValues(t, tt.expectedErr, decodedErr)
+				assert.EqualValues(t, tt.expectedCode, rr.Code)
+			} else {
+				json.NewDecoder(rr.Body).Decode(&decodedResult)
+
+				assert.EqualValues(t, tt.expectedResult, decodedResult)
+				assert.EqualValues(t, tt.expectedCode, rr.Code)
+			}
+
+			searchSrv.AssertExpectations(t)
+		})
+	}
+}
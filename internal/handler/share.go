@@ -0,0 +1,476 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+// GetPublicSharedMessagesHandler godoc
+// @ID           get-public-shared-messages
+// @Summary      GetPublicSharedMessages
+// @Description  Handler will return messages of share by public link token, no authorization is needed
+// @Tags         share
+// @Produce      json
+// @Param        token  path      string                true  "public link token"
+// @Success      200    {object}  model.SharedMessages  "shared messages"
+// @Failure      404    {object}  lib.Problem           "share not found"
+// @Failure      500    {object}  lib.Problem           "internal server error"
+// @Router       /share/{token} [get]
+func (h *Handler) GetPublicSharedMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	messages, err := h.service.Share.GetPublicSharedMessages(mux.Vars(r)["token"])
+	if err != nil {
+		h.writeShareError(w, r, err)
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, messages)
+}
+
+// GetSharesHandler godoc
+// @ID           get-shares
+// @Summary      GetShares
+// @Description  Handler will return shares which authorized user owns or was granted, latest created first
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Produce      json
+// @Success      200  {array}   model.Share  "shares of authorized user"
+// @Failure      401  {object}  lib.Problem  "user is not authorized"
+// @Failure      404  {object}  lib.Problem  "shares not found"
+// @Failure      500  {object}  lib.Problem  "internal server error"
+// @Router       /me/shares [get]
+func (h *Handler) GetSharesHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	shares, err := h.service.Share.GetShares(principal.UserID)
+	if err != nil {
+		if errors.Is(err, pg.ErrSharesNotFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+			return
+		}
+
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("user id", strconv.Itoa(principal.UserID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, shares)
+}
+
+// CreateShareHandler godoc
+// @ID           create-share
+// @Summary      CreateShare
+// @Description  Handler will create share of chosen saved messages of authorized user and return it with public link token
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.ShareInput  true  "share info"
+// @Success      201    {object}  model.Share       "created share"
+// @Failure      400    {object}  lib.Problem       "bad request"
+// @Failure      401    {object}  lib.Problem       "user is not authorized"
+// @Failure      500    {object}  lib.Problem       "internal server error"
+// @Router       /me/shares [post]
+func (h *Handler) CreateShareHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	input := model.ShareInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	share, err := h.service.Share.CreateShare(principal.UserID, &input)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.Any("share input structure", input))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusCreated, share)
+}
+
+// GetShareHandler godoc
+// @ID           get-share
+// @Summary      GetShare
+// @Description  Handler will return messages of share which authorized user owns or was granted
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Produce      json
+// @Param        share_id  path      integer               true  "share id"
+// @Success      200       {object}  model.SharedMessages  "shared messages"
+// @Failure      400       {object}  lib.Problem           "bad request"
+// @Failure      401       {object}  lib.Problem           "user is not authorized"
+// @Failure      404       {object}  lib.Problem           "share not found"
+// @Failure      500       {object}  lib.Problem           "internal server error"
+// @Router       /me/shares/{share_id} [get]
+func (h *Handler) GetShareHandler(w http.ResponseWriter, r *http.Request) {
+	share, ok := shareFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusNotFound, pg.ErrShareNotFound)
+
+		return
+	}
+
+	messages, err := h.service.Share.GetSharedMessages(share)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.String("share id", strconv.Itoa(share.ID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, messages)
+}
+
+// DeleteShareHandler godoc
+// @ID           delete-share
+// @Summary      DeleteShare
+// @Description  Handler will delete share of authorized user, only owner can delete share
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Produce      json
+// @Param        share_id  path      integer      true  "share id"
+// @Success      200       {string}  string       "share deleted"
+// @Failure      400       {object}  lib.Problem  "bad request"
+// @Failure      401       {object}  lib.Problem  "user is not authorized"
+// @Failure      403       {object}  lib.Problem  "user is not share owner"
+// @Failure      404       {object}  lib.Problem  "share not found"
+// @Failure      500       {object}  lib.Problem  "internal server error"
+// @Router       /me/shares/{share_id} [delete]
+func (h *Handler) DeleteShareHandler(w http.ResponseWriter, r *http.Request) {
+	share, ok := shareFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusNotFound, pg.ErrShareNotFound)
+
+		return
+	}
+
+	err := h.service.Share.DeleteShare(share.ID)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.String("share id", strconv.Itoa(share.ID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "share deleted")
+}
+
+// RotateShareLinkHandler godoc
+// @ID           rotate-share-link
+// @Summary      RotateShareLink
+// @Description  Handler will give share a new public link and return share with its token, previous link stops working
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Produce      json
+// @Param        share_id  path      integer      true  "share id"
+// @Success      200       {object}  model.Share  "share with new public link token"
+// @Failure      400       {object}  lib.Problem  "bad request"
+// @Failure      401       {object}  lib.Problem  "user is not authorized"
+// @Failure      403       {object}  lib.Problem  "user is not share owner"
+// @Failure      404       {object}  lib.Problem  "share not found"
+// @Failure      500       {object}  lib.Problem  "internal server error"
+// @Router       /me/shares/{share_id}/link [post]
+func (h *Handler) RotateShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	share, ok := shareFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusNotFound, pg.ErrShareNotFound)
+
+		return
+	}
+
+	err := h.service.Share.RotateShareLink(share)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.String("share id", strconv.Itoa(share.ID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, share)
+}
+
+// RevokeShareLinkHandler godoc
+// @ID           revoke-share-link
+// @Summary      RevokeShareLink
+// @Description  Handler will disable public link of share, members of share keep their access
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Produce      json
+// @Param        share_id  path      integer      true  "share id"
+// @Success      200       {string}  string       "share link revoked"
+// @Failure      400       {object}  lib.Problem  "bad request"
+// @Failure      401       {object}  lib.Problem  "user is not authorized"
+// @Failure      403       {object}  lib.Problem  "user is not share owner"
+// @Failure      404       {object}  lib.Problem  "share not found"
+// @Failure      500       {object}  lib.Problem  "internal server error"
+// @Router       /me/shares/{share_id}/link [delete]
+func (h *Handler) RevokeShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	share, ok := shareFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusNotFound, pg.ErrShareNotFound)
+
+		return
+	}
+
+	err := h.service.Share.RevokeShareLink(share.ID)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.String("share id", strconv.Itoa(share.ID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "share link revoked")
+}
+
+// AddShareItemsHandler godoc
+// @ID           add-share-items
+// @Summary      AddShareItems
+// @Description  Handler will add messages of chosen saved messages of authorized user to share, owner and editors can add messages
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Accept       json
+// @Produce      json
+// @Param        share_id  path      integer           true  "share id"
+// @Param        input     body      model.ShareInput  true  "saved ids, title is ignored"
+// @Success      200       {string}  string            "messages added to share"
+// @Failure      400       {object}  lib.Problem       "bad request"
+// @Failure      401       {object}  lib.Problem       "user is not authorized"
+// @Failure      403       {object}  lib.Problem       "user is not share owner or editor"
+// @Failure      404       {object}  lib.Problem       "share not found"
+// @Failure      500       {object}  lib.Problem       "internal server error"
+// @Router       /me/shares/{share_id}/items [post]
+func (h *Handler) AddShareItemsHandler(w http.ResponseWriter, r *http.Request) {
+	principal, ok := principalFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	share, ok := shareFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusNotFound, pg.ErrShareNotFound)
+
+		return
+	}
+
+	input := model.ShareInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	added, err := h.service.Share.AddShareItems(share.ID, principal.UserID, input.SavedIDs)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.String("share id", strconv.Itoa(share.ID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, fmt.Sprintf("%d messages added to share", added))
+}
+
+// DeleteShareItemHandler godoc
+// @ID           delete-share-item
+// @Summary      DeleteShareItem
+// @Description  Handler will remove message from share, owner and editors can remove messages
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Produce      json
+// @Param        share_id    path      integer      true  "share id"
+// @Param        message_id  path      integer      true  "message id"
+// @Success      200         {string}  string       "message removed from share"
+// @Failure      400         {object}  lib.Problem  "bad request"
+// @Failure      401         {object}  lib.Problem  "user is not authorized"
+// @Failure      403         {object}  lib.Problem  "user is not share owner or editor"
+// @Failure      404         {object}  lib.Problem  "share or shared message not found"
+// @Failure      500         {object}  lib.Problem  "internal server error"
+// @Router       /me/shares/{share_id}/items/{message_id} [delete]
+func (h *Handler) DeleteShareItemHandler(w http.ResponseWriter, r *http.Request) {
+	share, ok := shareFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusNotFound, pg.ErrShareNotFound)
+
+		return
+	}
+
+	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("message id is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	err = h.service.Share.DeleteShareItem(share.ID, messageID)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.String("share id", strconv.Itoa(share.ID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "message removed from share")
+}
+
+// GetShareMembersHandler godoc
+// @ID           get-share-members
+// @Summary      GetShareMembers
+// @Description  Handler will return web users who were granted access to share, only owner can see them
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Produce      json
+// @Param        share_id  path      integer            true  "share id"
+// @Success      200       {array}   model.ShareMember  "share members"
+// @Failure      400       {object}  lib.Problem        "bad request"
+// @Failure      401       {object}  lib.Problem        "user is not authorized"
+// @Failure      403       {object}  lib.Problem        "user is not share owner"
+// @Failure      404       {object}  lib.Problem        "share members not found"
+// @Failure      500       {object}  lib.Problem        "internal server error"
+// @Router       /me/shares/{share_id}/members [get]
+func (h *Handler) GetShareMembersHandler(w http.ResponseWriter, r *http.Request) {
+	share, ok := shareFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusNotFound, pg.ErrShareNotFound)
+
+		return
+	}
+
+	members, err := h.service.Share.GetShareMembers(share.ID)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.String("share id", strconv.Itoa(share.ID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, members)
+}
+
+// GrantShareMemberHandler godoc
+// @ID           grant-share-member
+// @Summary      GrantShareMember
+// @Description  Handler will give web user with email from body viewer or editor access to share, role of existing member is replaced
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Accept       json
+// @Produce      json
+// @Param        share_id  path      integer            true  "share id"
+// @Param        input     body      model.ShareMember  true  "email and role of member"
+// @Success      200       {string}  string             "share member granted"
+// @Failure      400       {object}  lib.Problem        "bad request"
+// @Failure      401       {object}  lib.Problem        "user is not authorized"
+// @Failure      403       {object}  lib.Problem        "user is not share owner"
+// @Failure      404       {object}  lib.Problem        "share or web user not found"
+// @Failure      500       {object}  lib.Problem        "internal server error"
+// @Router       /me/shares/{share_id}/members [put]
+func (h *Handler) GrantShareMemberHandler(w http.ResponseWriter, r *http.Request) {
+	share, ok := shareFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusNotFound, pg.ErrShareNotFound)
+
+		return
+	}
+
+	member := model.ShareMember{}
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, errInvalidBody, zap.NamedError("cause", err))
+
+		return
+	}
+
+	member.ShareID = share.ID
+
+	err := h.service.Share.GrantShareMember(share.UserID, &member)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.Any("share member structure", member))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "share member granted")
+}
+
+// RevokeShareMemberHandler godoc
+// @ID           revoke-share-member
+// @Summary      RevokeShareMember
+// @Description  Handler will take away access to share from web user by id from url
+// @Security     ApiKeyAuth
+// @Tags         share
+// @Produce      json
+// @Param        share_id  path      integer      true  "share id"
+// @Param        user_id   path      integer      true  "web user id"
+// @Success      200       {string}  string       "share member revoked"
+// @Failure      400       {object}  lib.Problem  "bad request"
+// @Failure      401       {object}  lib.Problem  "user is not authorized"
+// @Failure      403       {object}  lib.Problem  "user is not share owner"
+// @Failure      404       {object}  lib.Problem  "share or share member not found"
+// @Failure      500       {object}  lib.Problem  "internal server error"
+// @Router       /me/shares/{share_id}/members/{user_id} [delete]
+func (h *Handler) RevokeShareMemberHandler(w http.ResponseWriter, r *http.Request) {
+	share, ok := shareFromContext(r.Context())
+	if !ok {
+		h.WriteError(w, r, http.StatusNotFound, pg.ErrShareNotFound)
+
+		return
+	}
+
+	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	if err != nil {
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("user id is not valid"), zap.NamedError("cause", err))
+
+		return
+	}
+
+	err = h.service.Share.RevokeShareMember(share.ID, userID)
+	if err != nil {
+		h.writeShareError(w, r, err, zap.String("share id", strconv.Itoa(share.ID)))
+
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, "share member revoked")
+}
+
+func (h *Handler) writeShareError(w http.ResponseWriter, r *http.Request, err error, fields ...zap.Field) {
+	for _, notFound := range []error{
+		pg.ErrShareNotFound, pg.ErrShareItemNotFound, pg.ErrShareMembersNotFound, pg.ErrShareMemberNotFound, pg.ErrWebUserNotFound,
+	} {
+		if errors.Is(err, notFound) {
+			h.WriteError(w, r, http.StatusNotFound, err, fields...)
+
+			return
+		}
+	}
+
+	for _, badRequest := range []error{
+		service.ErrInvalidShareTitle, service.ErrInvalidShareRole, service.ErrEmptySavedIDs, service.ErrShareOwnerMember,
+	} {
+		if errors.Is(err, badRequest) {
+			h.WriteError(w, r, http.StatusBadRequest, err, fields...)
+
+			return
+		}
+	}
+
+	h.WriteError(w, r, http.StatusInternalServerError, err, fields...)
+}
@@ -17,35 +17,29 @@ import (
 // @Description  Handler will return user by id from url
 // @Tags         user
 // @Produce      json
-// @Param        id   path      integer        true  "user id"
-// @Success      200  {object}  model.User     "user by id"
-// @Failure      400  {object}  lib.HttpError  "bad request"
-// @Failure      404  {object}  lib.HttpError  "user not found"
-// @Failure      500  {object}  lib.HttpError  "internal server error"
+// @Param        id   path      integer      true  "user id"
+// @Success      200  {object}  model.User   "user by id"
+// @Failure      400  {object}  lib.Problem  "bad request"
+// @Failure      404  {object}  lib.Problem  "user not found"
+// @Failure      500  {object}  lib.Problem  "internal server error"
 // @Router       /user/{id} [get]
 func (h *Handler) GetUserByIDHandler(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.Atoi(mux.Vars(r)["id"])
 	if err != nil {
-		h.log.Error("failed to get user id", zap.Error(err))
-
-		h.WriteError(w, http.StatusBadRequest, "user id is not valid")
+		h.WriteError(w, r, http.StatusBadRequest, parameterError("user id is not valid"), zap.NamedError("cause", err))
 
 		return
 	}
 
 	user, err := h.service.User.GetUserByID(userID)
 	if err != nil {
-		h.log.Error("get user by id error", zap.String("id", strconv.Itoa(userID)), zap.Error(err))
-
 		if errors.Is(err, pg.ErrUserNotFound) {
-			err = errors.Unwrap(err)
-
-			h.WriteError(w, http.StatusNotFound, err.Error())
+			h.WriteError(w, r, http.StatusNotFound, err, zap.String("id", strconv.Itoa(userID)))
 
 			return
 		}
 
-		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		h.WriteError(w, r, http.StatusInternalServerError, err, zap.String("id", strconv.Itoa(userID)))
 
 		return
 	}
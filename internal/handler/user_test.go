@@ -32,7 +32,7 @@ func Test_GetUserByIDHandler(t *testing.T) {
 		mock          func(userSrv *mocks.UserService)
 		input         string
 		wantErr       bool
-		expectedError lib.HttpError
+		expectedError lib.Problem
 		expectedUser  model.User
 		expectedCode  int
 	}{
@@ -53,7 +53,7 @@ func Test_GetUserByIDHandler(t *testing.T) {
 			input:         "1",
 			wantErr:       true,
 			expectedCode:  http.StatusNotFound,
-			expectedError: lib.HttpError{Code: 404, Name: "Not Found", Message: "user not found"},
+			expectedError: lib.Problem{Title: "Not Found", Status: 404, Detail: "user not found", Code: "USER_NOT_FOUND"},
 		},
 		{
 			name: "Error: [some internal error]",
@@ -63,7 +63,7 @@ func Test_GetUserByIDHandler(t *testing.T) {
 			input:         "1",
 			wantErr:       true,
 			expectedCode:  http.StatusInternalServerError,
-			expectedError: lib.HttpError{Code: 500, Name: "Internal Server Error", Message: "[User] srv.GetUserByID error: some internal error"},
+			expectedError: lib.Problem{Title: "Internal Server Error", Status: 500, Detail: "Internal Server Error", Code: "INTERNAL_SERVER_ERROR"},
 		},
 		{
 			name:          "Error: [user id is not valid]",
@@ -71,7 +71,7 @@ func Test_GetUserByIDHandler(t *testing.T) {
 			input:         "hello",
 			wantErr:       true,
 			expectedCode:  http.StatusBadRequest,
-			expectedError: lib.HttpError{Code: 400, Name: "Bad Request", Message: "user id is not valid"},
+			expectedError: lib.Problem{Title: "Bad Request", Status: 400, Detail: "user id is not valid", Code: "INVALID_PARAMETER"},
 		},
 	}
 
@@ -96,7 +96,7 @@ func Test_GetUserByIDHandler(t *testing.T) {
 			router.ServeHTTP(rr, req)
 
 			decodedUser := model.User{}
-			decodedErr := lib.HttpError{}
+			decodedErr := lib.Problem{}
 
 			if tt.wantErr {
 				json.NewDecoder(rr.Body).Decode(&decodedErr)
@@ -108,6 +108,7 @@ func Test_GetUserByIDHandler(t *testing.T) {
 
 				assert.EqualValues(t, tt.expectedUser, decodedUser)
 				assert.EqualValues(t, tt.expectedCode, rr.Code)
+				assert.EqualValues(t, "application/json", rr.Header().Get("Content-Type"))
 			}
 		})
 	}
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+// FileMailer writes mails to writer instead of sending them, it's used for local development and tests.
+type FileMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+func NewFileMailer(w io.Writer, from string) *FileMailer {
+	return &FileMailer{w: w, from: from}
+}
+
+func (f *FileMailer) Send(mail *model.Mail) error {
+	message, err := buildMessage(f.from, mail)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.w.Write(append(message, "\r\n"...)); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/pkg/config"
+)
+
+const defaultFrom = "no-reply@localhost"
+
+var (
+	ErrInvalidHeader = errors.New("mail header contains line break")
+	ErrNotConfigured = errors.New("mail delivery is not configured, set SMTP_ADDR, MAIL_FILE or MAIL_STDOUT for local development")
+)
+
+// Mailer delivers mails taken from outbox.
+type Mailer interface {
+	Send(mail *model.Mail) error
+}
+
+// New returns SMTP mailer when SMTP address is configured, otherwise mails are written to mail file.
+// Mails are written to stdout only when it's enabled explicitly for local development,
+// so server without mail delivery doesn't start and lose mails silently.
+func New(cfg *config.Config) (Mailer, error) {
+	from := cfg.MailFrom
+	if from == "" {
+		from = defaultFrom
+	}
+
+	if cfg.SMTPAddr != "" {
+		return NewSMTPMailer(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, from), nil
+	}
+
+	if cfg.MailFile != "" {
+		file, err := os.OpenFile(cfg.MailFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open mail file: %w", err)
+		}
+
+		return NewFileMailer(file, from), nil
+	}
+
+	if cfg.MailStdout == "" {
+		return nil, ErrNotConfigured
+	}
+
+	stdout, err := strconv.ParseBool(cfg.MailStdout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse MAIL_STDOUT: %w", err)
+	}
+
+	if !stdout {
+		return nil, ErrNotConfigured
+	}
+
+	return NewFileMailer(os.Stdout, from), nil
+}
+
+// buildMessage returns plain text mail with headers, line breaks in headers are rejected
+// so recipient or subject can't add headers of their own.
+func buildMessage(from string, mail *model.Mail) ([]byte, error) {
+	for _, header := range []string{from, mail.Recipient, mail.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	var message strings.Builder
+
+	message.WriteString("From: " + from + "\r\n")
+	message.WriteString("To: " + mail.Recipient + "\r\n")
+	message.WriteString("Subject: " + mail.Subject + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	message.WriteString("\r\n")
+
+	return []byte(message.String()), nil
+}
@@ -0,0 +1,151 @@
+package mailer_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/mailer"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/pkg/config"
+)
+
+func Test_New(t *testing.T) {
+	mailFile := filepath.Join(t.TempDir(), "mails.txt")
+
+	tests := []struct {
+		name           string
+		cfg            *config.Config
+		want           mailer.Mailer
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [smtp mailer is used when smtp address is set]",
+			cfg:  &config.Config{SMTPAddr: "localhost:25", MailFile: mailFile, MailStdout: "true"},
+			want: &mailer.SMTPMailer{},
+		},
+		{
+			name: "Ok: [mails are written to mail file]",
+			cfg:  &config.Config{MailFile: mailFile},
+			want: &mailer.FileMailer{},
+		},
+		{
+			name: "Ok: [mails are written to stdout when it's enabled]",
+			cfg:  &config.Config{MailStdout: "true"},
+			want: &mailer.FileMailer{},
+		},
+		{
+			name:           "Error: [mail delivery is not configured]",
+			cfg:            &config.Config{},
+			wantErr:        true,
+			expectedErrMsg: mailer.ErrNotConfigured.Error(),
+		},
+		{
+			name:           "Error: [stdout is disabled]",
+			cfg:            &config.Config{MailStdout: "false"},
+			wantErr:        true,
+			expectedErrMsg: mailer.ErrNotConfigured.Error(),
+		},
+		{
+			name:           "Error: [stdout setting is not valid]",
+			cfg:            &config.Config{MailStdout: "yes"},
+			wantErr:        true,
+			expectedErrMsg: `failed to parse MAIL_STDOUT: strconv.ParseBool: parsing "yes": invalid syntax`,
+		},
+		{
+			name:           "Error: [mail file can't be opened]",
+			cfg:            &config.Config{MailFile: filepath.Join(t.TempDir(), "missing", "mails.txt")},
+			wantErr:        true,
+			expectedErrMsg: "failed to open mail file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mailer.New(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.IsType(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_FileMailerSend(t *testing.T) {
+	tests := []struct {
+		name        string
+		mail        *model.Mail
+		want        string
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name: "Ok: [mail is written with headers]",
+			mail: &model.Mail{Recipient: "test@test.com", Subject: "Confirm your email", Body: "Open the link:\n\nhttps://scanner.test"},
+			want: "From: no-reply@test.com\r\n" +
+				"To: test@test.com\r\n" +
+				"Subject: Confirm your email\r\n" +
+				"MIME-Version: 1.0\r\n" +
+				"Content-Type: text/plain; charset=UTF-8\r\n" +
+				"\r\n" +
+				"Open the link:\r\n\r\nhttps://scanner.test\r\n" +
+				"\r\n",
+		},
+		{
+			name:        "Error: [recipient adds header]",
+			mail:        &model.Mail{Recipient: "test@test.com\r\nBcc: spam@test.com", Subject: "Confirm your email", Body: "link"},
+			wantErr:     true,
+			expectedErr: mailer.ErrInvalidHeader,
+		},
+		{
+			name:        "Error: [subject adds header]",
+			mail:        &model.Mail{Recipient: "test@test.com", Subject: "Confirm\nBcc: spam@test.com", Body: "link"},
+			wantErr:     true,
+			expectedErr: mailer.ErrInvalidHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written := &bytes.Buffer{}
+
+			err := mailer.NewFileMailer(written, "no-reply@test.com").Send(tt.mail)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, written.String())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, written.String())
+			}
+		})
+	}
+}
+
+func Test_FileMailerAppendsToMailFile(t *testing.T) {
+	mailFile := filepath.Join(t.TempDir(), "mails.txt")
+
+	for i := 0; i < 2; i++ {
+		sender, err := mailer.New(&config.Config{MailFile: mailFile, MailFrom: "scanner@test.com"})
+		if err != nil {
+			t.Fatalf("failed to create mailer: %s", err)
+		}
+
+		if err := sender.Send(&model.Mail{Recipient: "test@test.com", Subject: "Reset your password", Body: "link"}); err != nil {
+			t.Fatalf("failed to send mail: %s", err)
+		}
+	}
+
+	written, err := os.ReadFile(mailFile)
+	if err != nil {
+		t.Fatalf("failed to read mail file: %s", err)
+	}
+
+	assert.EqualValues(t, 2, bytes.Count(written, []byte("From: scanner@test.com\r\n")))
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer creates mailer which sends mails through SMTP server at addr,
+// PLAIN auth is used when username is set.
+func NewSMTPMailer(addr, username, password, from string) *SMTPMailer {
+	mailer := &SMTPMailer{addr: addr, from: from}
+
+	if username != "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return mailer
+}
+
+func (s *SMTPMailer) Send(mail *model.Mail) error {
+	message, err := buildMessage(s.from, mail)
+	if err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{mail.Recipient}, message); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package model
+
+import "time"
+
+// AccountTokenPurpose is action which account token sent by email confirms.
+type AccountTokenPurpose string
+
+const (
+	AccountTokenVerifyEmail   AccountTokenPurpose = "verify-email"
+	AccountTokenResetPassword AccountTokenPurpose = "reset-password"
+	AccountTokenChangeEmail   AccountTokenPurpose = "change-email"
+)
+
+// AccountToken is single-use token sent to web user by email, only its hash is stored.
+type AccountToken struct {
+	ID        int                 `db:"id"`
+	UserID    int                 `db:"user_id"`
+	Purpose   AccountTokenPurpose `db:"purpose"`
+	TokenHash string              `db:"token_hash"`
+	NewEmail  *string             `db:"new_email"`
+	ExpiresAt time.Time           `db:"expires_at"`
+	UsedAt    *time.Time          `db:"used_at"`
+}
+
+// EmailChange is email of web user before and after it was changed by email change token.
+type EmailChange struct {
+	UserID   int    `db:"id"`
+	OldEmail string `db:"old_email"`
+	NewEmail string `db:"new_email"`
+}
+
+// Mail is email waiting in outbox to be sent.
+type Mail struct {
+	ID        int        `db:"id"`
+	Recipient string     `db:"recipient"`
+	Subject   string     `db:"subject"`
+	Body      string     `db:"body"`
+	Attempts  int        `db:"attempts"`
+	LastError *string    `db:"last_error"`
+	SendAfter time.Time  `db:"send_after"`
+	SentAt    *time.Time `db:"sent_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// @Description Forgot password input model
+type ForgotPasswordInput struct {
+	Email string `json:"email"` // User email example: test@test.com
+}
+
+// @Description Reset password input model
+type ResetPasswordInput struct {
+	Token    string `json:"token"`    // Token from password reset email
+	Password string `json:"password"` // New password
+}
+
+// @Description Verify email input model
+type VerifyEmailInput struct {
+	Token string `json:"token"` // Token from verification email
+}
+
+// @Description Change password input model
+type ChangePasswordInput struct {
+	OldPassword string `json:"oldPassword"` // Current password
+	NewPassword string `json:"newPassword"` // New password
+}
+
+// @Description Change email input model
+type ChangeEmailInput struct {
+	Email    string `json:"email"`    // New email example: new@test.com
+	Password string `json:"password"` // Current password
+}
+
+// @Description Delete account input model
+type DeleteAccountInput struct {
+	Password string `json:"password"` // Current password
+}
+
+// @Description Account of web user without password
+type AccountInfo struct {
+	ID              int        `json:"id"`                        // User id example: 1
+	Email           string     `json:"email"`                     // User email example: test@test.com
+	Role            Role       `json:"role"`                      // User role example: member
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt,omitempty"` // Time when email was verified
+}
+
+// @Description Everything stored about web user
+type AccountExport struct {
+	ExportedAt    time.Time         `json:"exportedAt"`    // Time when export was made
+	Account       AccountInfo       `json:"account"`       // Account of user
+	SavedMessages []SavedMessage    `json:"savedMessages"` // Every saved message of user
+	Collections   []Collection      `json:"collections"`   // Collections of user
+	Shares        []Share           `json:"shares"`        // Shares owned by user or shared with user
+	Sessions      []Session         `json:"sessions"`      // Sign-in sessions of user
+	APIKeys       []APIKey          `json:"apiKeys"`       // Not revoked api keys of user
+	Identities    []WebUserIdentity `json:"identities"`    // Accounts of identity providers linked to user
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// APIKeyScope limits what request authenticated with api key can do.
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead       APIKeyScope = "read"
+	APIKeyScopeSavedWrite APIKeyScope = "saved-write"
+)
+
+// Role returns role which api key with scope acts with, it's never higher than role of key owner.
+func (s APIKeyScope) Role(ownerRole Role) Role {
+	if s != APIKeyScopeSavedWrite || !ownerRole.Allows(RoleMember) {
+		return RoleReadonly
+	}
+
+	return RoleMember
+}
+
+// @Description Personal api key of web user
+type APIKey struct {
+	ID         int         `json:"id" db:"id"`                                // Api key id example: 1
+	UserID     int         `json:"-" db:"user_id"`                            // Api key owner id
+	Name       string      `json:"name" db:"name"`                            // Api key name example: notebook
+	Prefix     string      `json:"prefix" db:"prefix"`                        // First characters of api key example: sbk_3kT9aQ1x
+	Key        string      `json:"key,omitempty" db:"-"`                      // Api key, it's returned only once on creation
+	KeyHash    string      `json:"-" db:"key_hash"`                           // Sha256 of api key
+	Scope      APIKeyScope `json:"scope" db:"scope" enums:"read,saved-write"` // Api key scope example: read
+	CreatedAt  time.Time   `json:"createdAt" db:"created_at"`                 // Time when api key was created
+	LastUsedAt *time.Time  `json:"lastUsedAt,omitempty" db:"last_used_at"`    // Time when api key was used last time
+}
+
+// @Description Api key info for creating and renaming api key
+type APIKeyInput struct {
+	Name  string      `json:"name"`                           // Api key name example: notebook
+	Scope APIKeyScope `json:"scope" enums:"read,saved-write"` // Api key scope, it's ignored on renaming example: read
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction is name of security event or data change stored in audit trail.
+type AuditAction string
+
+const (
+	AuditSignUp                AuditAction = "sign-up"
+	AuditSignInSucceeded       AuditAction = "sign-in.succeeded"
+	AuditSignInFailed          AuditAction = "sign-in.failed"
+	AuditSignInLocked          AuditAction = "sign-in.locked"
+	AuditSignInUnlocked        AuditAction = "sign-in.unlocked"
+	AuditSessionRevoked        AuditAction = "session.revoked"
+	AuditAllSessionsRevoked    AuditAction = "session.revoked-all"
+	AuditRefreshTokenReused    AuditAction = "session.refresh-token-reused"
+	AuditPasswordReset         AuditAction = "password.reset"
+	AuditPasswordChanged       AuditAction = "password.changed"
+	AuditEmailChanged          AuditAction = "email.changed"
+	AuditAccountDeleted        AuditAction = "account.deleted"
+	AuditRoleGranted           AuditAction = "role.granted"
+	AuditIdentityLinked        AuditAction = "identity.linked"
+	AuditAPIKeyRevoked         AuditAction = "api-key.revoked"
+	AuditSavedCreated          AuditAction = "saved.created"
+	AuditSavedDeleted          AuditAction = "saved.deleted"
+	AuditIngestFailureReplayed AuditAction = "ingest-failure.replayed"
+	AuditExported              AuditAction = "audit.exported"
+)
+
+// Origin is client which made request, it's recorded in audit trail.
+type Origin struct {
+	IP        string
+	UserAgent string
+}
+
+// @Description Audit event, events are never changed after they are created
+type AuditEvent struct {
+	ID        int              `json:"id" db:"id"`                                                // Event id example: 1
+	ActorID   *int             `json:"actorId,omitempty" db:"actor_id"`                           // Id of web user who acted, empty for anonymous actions example: 1
+	Action    AuditAction      `json:"action" db:"action"`                                        // Action example: saved.deleted
+	IP        string           `json:"ip" db:"ip"`                                                // Client ip example: 192.0.2.1
+	UserAgent string           `json:"userAgent" db:"user_agent"`                                 // Client user agent example: curl/7.81.0
+	Details   json.RawMessage  `json:"details" db:"details" swaggertype:"object"`                 // Additional info about action
+	Before    *json.RawMessage `json:"before,omitempty" db:"before_payload" swaggertype:"object"` // Changed data before action
+	After     *json.RawMessage `json:"after,omitempty" db:"after_payload" swaggertype:"object"`   // Changed data after action
+	CreatedAt time.Time        `json:"createdAt" db:"created_at"`                                 // Time of action
+}
+
+// AuditFilter narrows audit trail to events of actor, action and client ip happened in [From, To) time range.
+type AuditFilter struct {
+	ActorID int
+	Action  AuditAction
+	IP      string
+	From    time.Time
+	To      time.Time
+}
+
+// @Description Page of audit events, latest first
+type AuditPage struct {
+	Items []AuditEvent `json:"items"`
+	PageInfo
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// @Description Kafka message which could not be ingested
+type IngestFailure struct {
+	ID        int       `json:"id" db:"id"`                     // Ingest failure id example: 1
+	Topic     string    `json:"topic" db:"topic"`               // Kafka topic example: messages.get
+	Partition int32     `json:"partition" db:"kafka_partition"` // Kafka partition example: 0
+	Offset    int64     `json:"offset" db:"kafka_offset"`       // Kafka offset example: 42
+	Payload   []byte    `json:"payload" db:"payload"`           // Raw message value encoded with base64
+	Error     string    `json:"error" db:"error"`               // Last ingestion error
+	Attempts  int       `json:"attempts" db:"attempts"`         // Ingestion attempts example: 3
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`      // Time of the first failure
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`      // Time of the last failure
+}
@@ -0,0 +1,18 @@
+package model
+
+// @Description JSON Web Key Set model
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// @Description JSON Web Key model, fields which are not used by key type are omitted
+type JWK struct {
+	Kty string `json:"kty"`           // Key type example: RSA
+	Kid string `json:"kid"`           // Key id example: 2022-07
+	Alg string `json:"alg"`           // Signing algorithm example: RS256
+	Use string `json:"use"`           // Key usage example: sig
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA public exponent example: AQAB
+	Crv string `json:"crv,omitempty"` // Curve of OKP key example: Ed25519
+	X   string `json:"x,omitempty"`   // OKP public key
+}
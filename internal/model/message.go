@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // @Description Full message model includes all info about message
 type FullMessage struct {
 	ID              int    `json:"id" db:"messageid"`                    // Message id example: 1
@@ -7,6 +9,10 @@ type FullMessage struct {
 	MessageURL      string `json:"messageUrl" db:"messageurl"`           // Message url from telegram
 	MessageImageURL string `json:"messageImageUrl" db:"messageimageurl"` // Message image url from firebase
 
+	PostedAt   time.Time  `json:"postedAt" db:"messagepostedat"`     // Message posting time in telegram
+	EditedAt   *time.Time `json:"editedAt" db:"messageeditedat"`     // Message last edit time in telegram, null if message was not edited
+	IngestedAt time.Time  `json:"ingestedAt" db:"messageingestedat"` // Time when message was stored
+
 	ChannelName     string `json:"channelName" db:"channelname"`         // Channel name example: go_go
 	ChannelTitle    string `json:"channelTitle" db:"channeltitle"`       // Channel title example: GO ukrainian community
 	ChannelImageURL string `json:"channelImageUrl" db:"channelimageurl"` // Channel image url from firebase
@@ -19,20 +25,37 @@ type FullMessage struct {
 	Replies      []FullReplie `json:"replies"`                 // Replies
 }
 
+// MessageFilter narrows message listings and counts, zero fields don't filter anything.
+type MessageFilter struct {
+	ChannelID    int
+	ChannelNames []string
+	TgUserID     int64
+	From         time.Time // Messages posted at or after
+	To           time.Time // Messages posted before
+	MinReplies   int
+	HasImage     *bool
+	NoReplies    bool
+}
+
 type MessageDTO struct {
-	ChannelID  int    `db:"channel_id"`
-	UserID     int    `db:"user_id"`
-	Title      string `db:"title"`
-	MessageURL string `db:"message_url"`
-	ImageURL   string `db:"imageurl"`
+	ChannelID  int        `db:"channel_id"`
+	UserID     int        `db:"user_id"`
+	Title      string     `db:"title"`
+	MessageURL string     `db:"message_url"`
+	ImageURL   string     `db:"imageurl"`
+	PostedAt   time.Time  `db:"posted_at"`
+	EditedAt   *time.Time `db:"edited_at"`
 }
 
 type TgMessage struct {
 	Message    string `json:"Message"`
 	MessageURL string `json:"MessageURL"`
 	ImageURL   string `json:"ImageURL"`
+	Date       int64  `json:"Date"`     // Unix time when message was posted
+	EditDate   int64  `json:"EditDate"` // Unix time of the last message edit, 0 if message was not edited
 
 	FromID struct {
+		ID       int64  `json:"ID"`
 		Username string `json:"Username"`
 		ImageURL string `json:"ImageURL"`
 		Fullname string `json:"Fullname"`
@@ -45,7 +68,9 @@ type TgMessage struct {
 	Replies struct {
 		Count    int `json:"Count"`
 		Messages []struct {
+			ID     int `json:"ID"`
 			FromID struct {
+				ID       int64  `json:"ID"`
 				Username string `json:"Username"`
 				Fullname string `json:"Fullname"`
 				ImageURL string `json:"ImageURL"`
@@ -53,6 +78,8 @@ type TgMessage struct {
 
 			Message  string `json:"Message"`
 			ImageURL string `json:"ImageURL"`
+			Date     int64  `json:"Date"`
+			EditDate int64  `json:"EditDate"`
 		} `json:"Messages"`
 	} `json:"Replies"`
 }
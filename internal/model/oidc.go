@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// OIDCLogin is started sso login waiting for identity provider to redirect user back, only hash of its state is stored.
+type OIDCLogin struct {
+	StateHash    string    `db:"state_hash"`
+	Nonce        string    `db:"nonce"`
+	CodeVerifier string    `db:"code_verifier"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+// @Description Account of identity provider linked to web user
+type WebUserIdentity struct {
+	ID        int       `json:"id" db:"id"`                // Identity id example: 1
+	UserID    int       `json:"-" db:"user_id"`            // Id of linked web user
+	Issuer    string    `json:"issuer" db:"issuer"`        // Issuer of identity provider example: https://sso.example.com
+	Subject   string    `json:"subject" db:"subject"`      // User id at identity provider example: 1001
+	CreatedAt time.Time `json:"createdAt" db:"created_at"` // Time when identity was linked
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Cursor points at the last row of a page in keyset ordered listing.
+// Listing continues after it, or before it when Backward is set.
+type Cursor struct {
+	PostedAt time.Time
+	ID       int
+	Backward bool
+}
+
+// PageRequest asks for at most Limit rows following Cursor, first page has no cursor.
+type PageRequest struct {
+	Cursor *Cursor
+	Limit  int
+}
+
+// IsBackward reports whether page is requested towards the start of listing.
+func (p *PageRequest) IsBackward() bool {
+	return p.Cursor != nil && p.Cursor.Backward
+}
+
+// @Description Page cursors
+type PageInfo struct {
+	Next    string `json:"next"`    // Cursor of the next page, empty on the last page
+	Prev    string `json:"prev"`    // Cursor of the previous page, empty on the first page
+	HasMore bool   `json:"hasMore"` // Whether there are more rows in the requested direction
+}
+
+// @Description Page of full messages
+type FullMessagesPage struct {
+	Items []FullMessage `json:"items"`
+	PageInfo
+}
+
+// @Description Page of channels
+type ChannelsPage struct {
+	Items []Channel `json:"items"`
+	PageInfo
+}
+
+// @Description Page of full replies
+type FullRepliesPage struct {
+	Items []FullReplie `json:"items"`
+	PageInfo
+}
+
+// @Description Page of saved messages
+type SavedPage struct {
+	Items []SavedMessage `json:"items"`
+	PageInfo
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // @Description Full replie model includes all info about replie
 type FullReplie struct {
 	ID           int    `json:"id" db:"id"`                     // Replies id example: 1
@@ -9,11 +11,18 @@ type FullReplie struct {
 	UserID       int    `json:"userId" db:"userid"`             // Replie user id example: 1
 	UserFullname string `json:"userFullname" db:"fullname"`     // Replie user fullname example: Ivan Petrovich
 	UserImageURL string `json:"userImageUrl" db:"userimageurl"` // Replie user image url from firebase
+
+	PostedAt   time.Time  `json:"postedAt" db:"posted_at"`     // Replie posting time in telegram
+	EditedAt   *time.Time `json:"editedAt" db:"edited_at"`     // Replie last edit time in telegram, null if replie was not edited
+	IngestedAt time.Time  `json:"ingestedAt" db:"ingested_at"` // Time when replie was stored
 }
 
 type ReplieDTO struct {
-	MessageID int    `db:"message_id"`
-	UserID    int    `db:"user_id"`
-	Title     string `db:"title"`
-	ImageURL  string `db:"imageurl"`
+	MessageID int        `db:"message_id"`
+	ReplyKey  string     `db:"reply_key"`
+	UserID    int        `db:"user_id"`
+	Title     string     `db:"title"`
+	ImageURL  string     `db:"imageurl"`
+	PostedAt  time.Time  `db:"posted_at"`
+	EditedAt  *time.Time `db:"edited_at"`
 }
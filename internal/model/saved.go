@@ -1,8 +1,42 @@
 package model
 
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
 // @Description Saved message model
 type Saved struct {
-	ID        int `json:"id" db:"id"`                // Saved id example: 1
-	UserID    int `json:"userId" db:"user_id"`       // Saved user id example: 1
-	MessageID int `json:"messageId" db:"message_id"` // Saved message id example: 1
+	ID           int            `json:"id" db:"id"`                                // Saved id example: 1
+	UserID       int            `json:"userId" db:"user_id"`                       // Saved user id example: 1
+	MessageID    int            `json:"messageId" db:"message_id"`                 // Saved message id example: 1
+	CollectionID *int           `json:"collectionId" db:"collection_id"`           // Collection id, null if message is not in collection example: 1
+	Note         string         `json:"note" db:"note"`                            // Private note of user example: check later
+	Tags         pq.StringArray `json:"tags" db:"tags" swaggertype:"array,string"` // Tags of saved message example: golang,jobs
+}
+
+// @Description Saved message with all info about message
+type SavedMessage struct {
+	SavedID      int            `json:"savedId" db:"savedid"`                           // Saved id example: 1
+	SavedAt      time.Time      `json:"savedAt" db:"savedat"`                           // Time when message was saved by user
+	CollectionID *int           `json:"collectionId" db:"savedcollectionid"`            // Collection id, null if message is not in collection example: 1
+	Note         string         `json:"note" db:"savednote"`                            // Private note of user example: check later
+	Tags         pq.StringArray `json:"tags" db:"savedtags" swaggertype:"array,string"` // Tags of saved message example: golang,jobs
+
+	FullMessage
+}
+
+// SavedFilter narrows saved messages of user to collection and messages which have all tags.
+type SavedFilter struct {
+	CollectionID int
+	Tags         []string
+}
+
+// @Description Named collection of saved messages
+type Collection struct {
+	ID        int       `json:"id" db:"id"`                // Collection id example: 1
+	UserID    int       `json:"userId" db:"user_id"`       // Collection owner id example: 1
+	Name      string    `json:"name" db:"name"`            // Collection name example: Golang jobs
+	CreatedAt time.Time `json:"createdAt" db:"created_at"` // Time when collection was created
 }
@@ -0,0 +1,10 @@
+package model
+
+// @Description Search result is full message with its rank and highlighted snippet of the matched text
+type SearchResult struct {
+	FullMessage
+
+	Rank            float32 `json:"rank" db:"rank"`                       // Search rank example: 0.0607927
+	Snippet         string  `json:"snippet" db:"snippet"`                 // Matched text with highlighted words example: anyone works with <b>Golang</b>?
+	MatchedReplieID *int    `json:"matchedReplieId" db:"matchedreplieid"` // Id of the replie which matched, null when message matched itself
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Session is a family of refresh tokens issued by one sign-in.
+type Session struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"userId" db:"user_id"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+}
+
+type RefreshToken struct {
+	ID        int        `db:"id"`
+	SessionID int        `db:"session_id"`
+	UserID    int        `db:"user_id"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+}
+
+// @Description Tokens model
+type Tokens struct {
+	AccessToken  string `json:"token"`        // Short-lived JWT access token
+	RefreshToken string `json:"refreshToken"` // Refresh token, it can be exchanged for new tokens only once
+	ExpiresIn    int    `json:"expiresIn"`    // Access token lifetime in seconds example: 900
+}
+
+// @Description Refresh input model
+type RefreshInput struct {
+	RefreshToken string `json:"refreshToken"` // Refresh token from sign-in or previous refresh
+}
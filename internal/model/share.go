@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// ShareRole is access level of web user to share, owner is the web user who created it.
+type ShareRole string
+
+const (
+	ShareRoleViewer ShareRole = "viewer"
+	ShareRoleEditor ShareRole = "editor"
+	ShareRoleOwner  ShareRole = "owner"
+)
+
+var shareRoleLevels = map[ShareRole]int{ShareRoleViewer: 1, ShareRoleEditor: 2, ShareRoleOwner: 3}
+
+// Allows reports whether role gives at least access of required role.
+func (r ShareRole) Allows(required ShareRole) bool {
+	return shareRoleLevels[r] != 0 && shareRoleLevels[r] >= shareRoleLevels[required]
+}
+
+// @Description Share of saved messages
+type Share struct {
+	ID        int       `json:"id" db:"id"`                                 // Share id example: 1
+	UserID    int       `json:"userId" db:"user_id"`                        // Share owner id example: 1
+	Title     string    `json:"title" db:"title"`                           // Share title example: Golang jobs
+	Token     *string   `json:"token,omitempty" db:"token"`                 // Public link token, only owner sees it, null when link is revoked
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`                  // Time when share was created
+	Role      ShareRole `json:"role" db:"role" enums:"viewer,editor,owner"` // Access of authorized user to share
+}
+
+// @Description Share info for creating share
+type ShareInput struct {
+	Title    string `json:"title"`    // Share title example: Golang jobs
+	SavedIDs []int  `json:"savedIds"` // Ids of saved messages of user which are added to share example: 1,2
+}
+
+// @Description Web user with access to share
+type ShareMember struct {
+	ShareID int       `json:"shareId" db:"share_id"`                // Share id example: 1
+	UserID  int       `json:"userId" db:"user_id"`                  // Web user id example: 2
+	Email   string    `json:"email" db:"email"`                     // Web user email example: test@test.com
+	Role    ShareRole `json:"role" db:"role" enums:"viewer,editor"` // Access of web user to share
+}
+
+// @Description Share with its messages
+type SharedMessages struct {
+	Title     string        `json:"title"`     // Share title example: Golang jobs
+	CreatedAt time.Time     `json:"createdAt"` // Time when share was created
+	Items     []FullMessage `json:"items"`     // Shared messages, latest added first
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// SignInAttemptKind is what failed sign-in attempts are counted for.
+type SignInAttemptKind string
+
+const (
+	SignInAttemptAccount SignInAttemptKind = "account"
+	SignInAttemptIP      SignInAttemptKind = "ip"
+)
+
+// SignInAttempt is counter of failed sign-in attempts of account email or client ip.
+type SignInAttempt struct {
+	Kind         SignInAttemptKind `db:"kind"`
+	Subject      string            `db:"subject"`
+	Failures     int               `db:"failures"`
+	LastFailedAt time.Time         `db:"last_failed_at"`
+	LockedUntil  *time.Time        `db:"locked_until"`
+}
+
+// @Description Sign in input model
+type SignInInput struct {
+	Email    string `json:"email"`    // User email example: test@test.com
+	Password string `json:"password"` // User password
+}
+
+// @Description Unlock sign-in input model, at least one field is required
+type UnlockSignInInput struct {
+	Email string `json:"email"` // Email of locked account example: test@test.com
+	IP    string `json:"ip"`    // Locked client ip example: 192.0.2.1
+}
@@ -1,21 +1,55 @@
 package model
 
+import "time"
+
 // @Description Telegram user model
 type User struct {
-	ID       int    `json:"id"`       // User id example: 1
-	Username string `json:"username"` // User username example: ivanptr21
-	Fullname string `json:"fullname"` // User fullname example Ivan Petrovich
-	ImageURL string `json:"imageUrl"` // User image url from firebase
+	ID       int    `json:"id"`              // User id example: 1
+	TgID     int64  `json:"tgId" db:"tg_id"` // User telegram id example: 5234582918
+	Username string `json:"username"`        // User username example: ivanptr21
+	Fullname string `json:"fullname"`        // User fullname example Ivan Petrovich
+	ImageURL string `json:"imageUrl"`        // User image url from firebase
+}
+
+// Role is set of rights of web user, every role has rights of roles below it.
+type Role string
+
+const (
+	RoleReadonly Role = "readonly"
+	RoleMember   Role = "member"
+	RoleAdmin    Role = "admin"
+)
+
+var roleLevels = map[Role]int{RoleReadonly: 1, RoleMember: 2, RoleAdmin: 3}
+
+// Allows reports whether role gives at least rights of required role.
+func (r Role) Allows(required Role) bool {
+	return roleLevels[r] != 0 && roleLevels[r] >= roleLevels[required]
 }
 
 // @Description User model
 type WebUser struct {
-	ID       int    `json:"id"`       // User id example: 1
-	Email    string `json:"email"`    // User email example: test@test.com
-	Password string `json:"password"` // user Password example: d1e8a70b5ccab1dc2f56bbf7e99f064a660c08e361a35751b9c483c88943d082
+	ID       int    `json:"id"`                                           // User id example: 1
+	Email    string `json:"email"`                                        // User email example: test@test.com
+	Password string `json:"password"`                                     // user Password example: d1e8a70b5ccab1dc2f56bbf7e99f064a660c08e361a35751b9c483c88943d082
+	Role     Role   `json:"role,omitempty" enums:"admin,member,readonly"` // User role, it's ignored on sign-up example: member
+
+	EmailVerifiedAt *time.Time `json:"-" db:"email_verified_at"`
+}
+
+// Principal is web user on whose behalf request is made, it's taken from access token or api key.
+// SessionID is set for access tokens and APIKeyID with Scope for api keys.
+type Principal struct {
+	UserID    int         `db:"user_id"`
+	Email     string      `db:"email"`
+	Role      Role        `db:"role"`
+	SessionID int         `db:"session_id"`
+	APIKeyID  int         `db:"api_key_id"`
+	Scope     APIKeyScope `db:"scope"`
 }
 
 type UserDTO struct {
+	TgID     int64  `db:"tg_id"`
 	Username string `db:"username"`
 	Fullname string `db:"fullname"`
 	ImageURL string `db:"imageurl"`
@@ -0,0 +1,302 @@
+package oidc_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/oidc"
+	"github.com/VladPetriv/scanner_backend_api/internal/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const redirectURL = "http://localhost:3000/auth/oidc/callback"
+
+func Test_Exchange(t *testing.T) {
+	tests := []struct {
+		name           string
+		clientSecret   string
+		providerSecret string
+		user           oidctest.User
+		modifyClaims   func(claims jwt.MapClaims)
+		nonce          string
+		want           *oidc.Identity
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name:           "Ok: [confidential client]",
+			clientSecret:   "secret",
+			providerSecret: "secret",
+			user:           oidctest.User{Subject: "1001", Email: "test@test.com", EmailVerified: true},
+			want:           &oidc.Identity{Subject: "1001", Email: "test@test.com", EmailVerified: true},
+		},
+		{
+			name: "Ok: [public client]",
+			user: oidctest.User{Subject: "1001", Email: "test@test.com"},
+			want: &oidc.Identity{Subject: "1001", Email: "test@test.com"},
+		},
+		{
+			name: "Ok: [email verified as string]",
+			user: oidctest.User{Subject: "1001", Email: "test@test.com"},
+			modifyClaims: func(claims jwt.MapClaims) {
+				claims["email_verified"] = "true"
+			},
+			want: &oidc.Identity{Subject: "1001", Email: "test@test.com", EmailVerified: true},
+		},
+		{
+			name:           "Error: [wrong client secret]",
+			clientSecret:   "wrong",
+			providerSecret: "secret",
+			user:           oidctest.User{Subject: "1001"},
+			wantErr:        true,
+			expectedErrMsg: "failed to exchange authorization code: invalid_client",
+		},
+		{
+			name:           "Error: [nonce mismatch]",
+			user:           oidctest.User{Subject: "1001"},
+			nonce:          "other-nonce",
+			wantErr:        true,
+			expectedErrMsg: "id token is not valid: nonce mismatch",
+		},
+		{
+			name: "Error: [token for another client]",
+			user: oidctest.User{Subject: "1001"},
+			modifyClaims: func(claims jwt.MapClaims) {
+				claims["aud"] = "other-client"
+			},
+			wantErr:        true,
+			expectedErrMsg: "id token is not valid: issued for another client",
+		},
+		{
+			name: "Error: [token authorized for another client]",
+			user: oidctest.User{Subject: "1001"},
+			modifyClaims: func(claims jwt.MapClaims) {
+				claims["aud"] = []string{"scanner", "other-client"}
+				claims["azp"] = "other-client"
+			},
+			wantErr:        true,
+			expectedErrMsg: "id token is not valid: authorized for another client",
+		},
+		{
+			name: "Error: [token of another issuer]",
+			user: oidctest.User{Subject: "1001"},
+			modifyClaims: func(claims jwt.MapClaims) {
+				claims["iss"] = "https://evil.example.com"
+			},
+			wantErr:        true,
+			expectedErrMsg: `id token is not valid: issued by "https://evil.example.com"`,
+		},
+		{
+			name: "Error: [expired token]",
+			user: oidctest.User{Subject: "1001"},
+			modifyClaims: func(claims jwt.MapClaims) {
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			},
+			wantErr:        true,
+			expectedErrMsg: "id token is not valid: expired",
+		},
+		{
+			name:           "Error: [token without subject]",
+			user:           oidctest.User{},
+			wantErr:        true,
+			expectedErrMsg: "id token is not valid: no subject",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := oidctest.NewServer("scanner", tt.providerSecret)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			defer server.Close()
+
+			server.SignIn(tt.user)
+			server.ModifyClaims = tt.modifyClaims
+
+			provider := oidc.NewProvider(oidc.Config{
+				Issuer:       server.Issuer(),
+				ClientID:     "scanner",
+				ClientSecret: tt.clientSecret,
+				RedirectURL:  redirectURL,
+			}, nil)
+
+			authURL, err := provider.AuthCodeURL("state", "nonce", "code-verifier-code-verifier-code-verifier-1")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			callback, err := server.Authorize(authURL)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			assert.Equal(t, "state", callback.Query().Get("state"))
+
+			nonce := "nonce"
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			got, err := provider.Exchange(callback.Query().Get("code"), "code-verifier-code-verifier-code-verifier-1", nonce)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				tt.want.Issuer = server.Issuer()
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_ExchangeWrongCodeVerifier(t *testing.T) {
+	server, err := oidctest.NewServer("scanner", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer server.Close()
+
+	server.SignIn(oidctest.User{Subject: "1001"})
+
+	provider := oidc.NewProvider(oidc.Config{Issuer: server.Issuer(), ClientID: "scanner", RedirectURL: redirectURL}, nil)
+
+	authURL, err := provider.AuthCodeURL("state", "nonce", "code-verifier-code-verifier-code-verifier-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	callback, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = provider.Exchange(callback.Query().Get("code"), "code-verifier-code-verifier-code-verifier-2", "nonce")
+	assert.EqualError(t, err, "failed to exchange authorization code: invalid_grant")
+}
+
+func Test_VerifyIDToken(t *testing.T) {
+	server, err := oidctest.NewServer("scanner", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer server.Close()
+
+	other, err := oidctest.NewServer("scanner", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer other.Close()
+
+	provider := oidc.NewProvider(oidc.Config{Issuer: server.Issuer(), ClientID: "scanner", RedirectURL: redirectURL}, nil)
+
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   server.Issuer(),
+			"sub":   "1001",
+			"aud":   "scanner",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Add(30 * time.Second).Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	tests := []struct {
+		name           string
+		token          func() (string, error)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name:  "Ok: [token issued slightly in the future]",
+			token: func() (string, error) { return server.IDToken(claims()) },
+		},
+		{
+			name:           "Error: [token signed by another key]",
+			token:          func() (string, error) { return other.IDToken(claims()) },
+			wantErr:        true,
+			expectedErrMsg: "id token is not valid: crypto/rsa: verification error",
+		},
+		{
+			name: "Error: [token issued in the future]",
+			token: func() (string, error) {
+				c := claims()
+				c["iat"] = time.Now().Add(time.Hour).Unix()
+
+				return server.IDToken(c)
+			},
+			wantErr:        true,
+			expectedErrMsg: "id token is not valid: issued in the future",
+		},
+		{
+			name: "Error: [unsigned token]",
+			token: func() (string, error) {
+				return jwt.NewWithClaims(jwt.SigningMethodNone, claims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+			},
+			wantErr:        true,
+			expectedErrMsg: "id token is not valid: signing method none is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.token()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got, err := provider.VerifyIDToken(token, "nonce")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &oidc.Identity{Issuer: server.Issuer(), Subject: "1001"}, got)
+			}
+		})
+	}
+}
+
+func Test_Discovery(t *testing.T) {
+	tests := []struct {
+		name           string
+		document       string
+		expectedErrMsg string
+	}{
+		{
+			name:           "Error: [document of another issuer]",
+			document:       `{"issuer":"https://evil.example.com","authorization_endpoint":"a","token_endpoint":"t","jwks_uri":"j"}`,
+			expectedErrMsg: `failed to discover identity provider: document is published for issuer "https://evil.example.com"`,
+		},
+		{
+			name:           "Error: [pkce is not supported]",
+			document:       `{"issuer":"ISSUER","authorization_endpoint":"a","token_endpoint":"t","jwks_uri":"j","code_challenge_methods_supported":["plain"]}`,
+			expectedErrMsg: "identity provider doesn't support S256 code challenge",
+		},
+		{
+			name:           "Error: [document without endpoints]",
+			document:       `{"issuer":"ISSUER"}`,
+			expectedErrMsg: "failed to discover identity provider: document has no authorization, token or jwks endpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(strings.ReplaceAll(tt.document, "ISSUER", server.URL)))
+			}))
+			defer server.Close()
+
+			provider := oidc.NewProvider(oidc.Config{Issuer: server.URL, ClientID: "scanner", RedirectURL: redirectURL}, nil)
+
+			_, err := provider.AuthCodeURL("state", "nonce", "code-verifier")
+			assert.EqualError(t, err, tt.expectedErrMsg)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+const (
+	pollPeriod = 10 * time.Second
+	batchSize  = 20
+)
+
+// Runner periodically sends mails from outbox. Mails are claimed in database,
+// so runner can work on every API instance at the same time.
+type Runner struct {
+	mail service.MailService
+	log  *logger.Logger
+}
+
+func NewRunner(srvManager *service.Manager, log *logger.Logger) *Runner {
+	return &Runner{mail: srvManager.Mail, log: log}
+}
+
+// Run sends pending mails every poll period until ctx is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollPeriod)
+	defer ticker.Stop()
+
+	for {
+		r.sendPending()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendPending sends batches of mails while outbox has full batches of due mails.
+func (r *Runner) sendPending() {
+	for {
+		count, err := r.mail.SendPendingMails(batchSize)
+		if err != nil {
+			r.log.Error("failed to send mails from outbox", zap.Error(err))
+
+			return
+		}
+
+		if count < batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package outbox_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/mailer"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/outbox"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+func Test_Run(t *testing.T) {
+	fullBatch := make([]model.Mail, 20)
+	for i := range fullBatch {
+		fullBatch[i] = model.Mail{ID: i + 1, Recipient: fmt.Sprintf("user%d@test.com", i+1), Subject: "Confirm your email", Body: "link"}
+	}
+
+	badMail := model.Mail{ID: 21, Recipient: "bad\r\nBcc: spam@test.com", Subject: "Confirm your email", Body: "link", Attempts: 3}
+	// third attempt is retried in 9 minutes
+	retryAt := mock.MatchedBy(func(retryAt time.Time) bool {
+		return retryAt.After(time.Now().Add(8*time.Minute)) && retryAt.Before(time.Now().Add(10*time.Minute))
+	})
+	leaseUntil := mock.AnythingOfType("time.Time")
+
+	tests := []struct {
+		name      string
+		mock      func(outboxRepo *mocks.OutboxRepo)
+		wantSent  int
+		wantClaim int
+	}{
+		{
+			name: "Ok: [mails are claimed until batch is not full]",
+			mock: func(outboxRepo *mocks.OutboxRepo) {
+				outboxRepo.On("ClaimMails", 20, service.MaxMailAttempts, leaseUntil).Return(fullBatch, nil).Once()
+				outboxRepo.On("ClaimMails", 20, service.MaxMailAttempts, leaseUntil).Return([]model.Mail{badMail}, nil).Once()
+				for _, mail := range fullBatch {
+					outboxRepo.On("MarkMailSent", mail.ID).Return(mail.ID, nil).Once()
+				}
+				outboxRepo.On("MarkMailFailed", 21, mailer.ErrInvalidHeader.Error(), retryAt).Return(21, nil).Once()
+			},
+			wantSent:  20,
+			wantClaim: 2,
+		},
+		{
+			name: "Ok: [failed mail is postponed for retry]",
+			mock: func(outboxRepo *mocks.OutboxRepo) {
+				outboxRepo.On("ClaimMails", 20, service.MaxMailAttempts, leaseUntil).Return([]model.Mail{badMail}, nil).Once()
+				outboxRepo.On("MarkMailFailed", 21, mailer.ErrInvalidHeader.Error(), retryAt).Return(21, nil).Once()
+			},
+			wantClaim: 1,
+		},
+		{
+			name: "Error: [sending stops when mails can't be claimed]",
+			mock: func(outboxRepo *mocks.OutboxRepo) {
+				outboxRepo.On("ClaimMails", 20, service.MaxMailAttempts, leaseUntil).
+					Return(nil, fmt.Errorf("failed to claim mails: some error")).Once()
+			},
+			wantClaim: 1,
+		},
+		{
+			name: "Error: [sending stops when mail can't be marked as sent]",
+			mock: func(outboxRepo *mocks.OutboxRepo) {
+				outboxRepo.On("ClaimMails", 20, service.MaxMailAttempts, leaseUntil).Return(fullBatch, nil).Once()
+				outboxRepo.On("MarkMailSent", 1).Return(0, fmt.Errorf("failed to mark mail as sent: some error")).Once()
+			},
+			wantSent:  1,
+			wantClaim: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := &bytes.Buffer{}
+			outboxRepo := &mocks.OutboxRepo{}
+			mailSrv := service.NewMailService(&store.Store{Outbox: outboxRepo}, mailer.NewFileMailer(sent, "no-reply@test.com"))
+
+			tt.mock(outboxRepo)
+
+			// runner sends pending mails once and stops since context is already cancelled
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			outbox.NewRunner(&service.Manager{Mail: mailSrv}, logger.Get("debug")).Run(ctx)
+
+			assert.EqualValues(t, tt.wantSent, strings.Count(sent.String(), "To: "))
+			outboxRepo.AssertNumberOfCalls(t, "ClaimMails", tt.wantClaim)
+			outboxRepo.AssertExpectations(t)
+		})
+	}
+}
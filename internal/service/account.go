@@ -0,0 +1,426 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/internal/validation"
+)
+
+const (
+	defaultAppURL = "http://localhost:3000"
+
+	// accountTokenSize is number of random bytes in tokens sent by email.
+	accountTokenSize = 32
+
+	verifyEmailTokenTTL   = 48 * time.Hour
+	resetPasswordTokenTTL = time.Hour
+	changeEmailTokenTTL   = 24 * time.Hour
+
+	// exportPageLimit is number of saved messages read at once while exporting account.
+	exportPageLimit = 100
+)
+
+var (
+	ErrInvalidAccountToken = errors.New("token is not valid or expired")
+	ErrIncorrectPassword   = errors.New("password is incorrect")
+)
+
+type AccountDBService struct {
+	store   *store.Store
+	webUser *WebUserDBService
+	appURL  string
+}
+
+// NewAccountService creates account service, links in mails lead to appURL or to local server when it's empty.
+// New passwords have to follow passwords policy, default policy is used when it's nil.
+func NewAccountService(store *store.Store, appURL string, passwords *validation.PasswordPolicy) *AccountDBService {
+	if appURL == "" {
+		appURL = defaultAppURL
+	}
+
+	return &AccountDBService{store: store, webUser: NewWebUserService(store, passwords), appURL: strings.TrimSuffix(appURL, "/")}
+}
+
+// SignUp validates user and creates it with member role the same way as WebUserService.CreateWebUser.
+// Mail with email verification link is put into outbox in the same transaction, so no user is created without it.
+func (a *AccountDBService) SignUp(user *model.WebUser, origin model.Origin) error {
+	if err := a.webUser.prepareWebUser(user); err != nil {
+		return fmt.Errorf("[Account] srv.SignUp error: %w", err)
+	}
+
+	body := "Please confirm your email by opening the link below, it's valid for 48 hours:\n\n%s\n\n" +
+		"If you didn't sign up, ignore this mail."
+
+	err := a.store.InTx(func(tx *store.Store) error {
+		if err := insertWebUser(tx, user, origin); err != nil {
+			return err
+		}
+
+		return a.sendAccountToken(
+			tx,
+			&model.AccountToken{UserID: user.ID, Purpose: model.AccountTokenVerifyEmail, ExpiresAt: time.Now().Add(verifyEmailTokenTTL)},
+			user.Email, "Confirm your email", body, "/verify-email",
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("[Account] srv.SignUp error: %w", err)
+	}
+
+	return nil
+}
+
+// RequestPasswordReset puts mail with password reset link into outbox when web user with email exists.
+// Unknown email isn't reported, so the request can't be used to find out who is signed up.
+func (a *AccountDBService) RequestPasswordReset(email string) error {
+	user, err := a.store.WebUser.GetWebUserByEmail(lookupEmail(email))
+	if errors.Is(err, pg.ErrWebUserNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("[Account] srv.RequestPasswordReset error: %w", err)
+	}
+
+	body := "Somebody asked to reset password of your account. Open the link below to set new password, " +
+		"it's valid for 1 hour:\n\n%s\n\nIf it wasn't you, ignore this mail."
+
+	err = a.sendAccountToken(
+		a.store,
+		&model.AccountToken{UserID: user.ID, Purpose: model.AccountTokenResetPassword, ExpiresAt: time.Now().Add(resetPasswordTokenTTL)},
+		user.Email, "Reset your password", body, "/reset-password",
+	)
+	if err != nil {
+		return fmt.Errorf("[Account] srv.RequestPasswordReset error: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets new password using token from password reset mail and logs out every session of web user,
+// the reset is recorded in audit trail in the same transaction.
+func (a *AccountDBService) ResetPassword(token, password string, origin model.Origin) error {
+	var verrs validation.Errors
+	verrs.Add("password", a.webUser.passwords.Validate(password))
+
+	if err := verrs.Err(); err != nil {
+		return fmt.Errorf("[Account] srv.ResetPassword error: %w", err)
+	}
+
+	hashedPassword, err := a.webUser.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("[Account] srv.ResetPassword error: %w", err)
+	}
+
+	err = a.store.InTx(func(tx *store.Store) error {
+		userID, err := tx.Account.ResetPassword(hashToken(token), hashedPassword)
+		if err != nil {
+			return err
+		}
+
+		revoked, err := tx.Session.RevokeUserSessions(userID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &userID,
+			Action:  model.AuditPasswordReset,
+			Origin:  origin,
+			Details: map[string]int{"sessions": revoked},
+		})
+	})
+	if errors.Is(err, pg.ErrAccountTokenNotFound) {
+		return fmt.Errorf("[Account] srv.ResetPassword error: %w", ErrInvalidAccountToken)
+	}
+
+	if err != nil {
+		return fmt.Errorf("[Account] srv.ResetPassword error: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyEmail marks email of web user as verified using token from verification mail.
+func (a *AccountDBService) VerifyEmail(token string) error {
+	_, err := a.store.Account.VerifyEmail(hashToken(token))
+	if errors.Is(err, pg.ErrAccountTokenNotFound) {
+		return fmt.Errorf("[Account] srv.VerifyEmail error: %w", ErrInvalidAccountToken)
+	}
+
+	if err != nil {
+		return fmt.Errorf("[Account] srv.VerifyEmail error: %w", err)
+	}
+
+	return nil
+}
+
+// ChangePassword sets new password of principal after checking the old one,
+// every other session of web user is logged out and the change is recorded in audit trail in one transaction.
+func (a *AccountDBService) ChangePassword(principal *model.Principal, input *model.ChangePasswordInput, origin model.Origin) error {
+	var verrs validation.Errors
+	verrs.Add("newPassword", a.webUser.passwords.Validate(input.NewPassword))
+
+	if err := verrs.Err(); err != nil {
+		return fmt.Errorf("[Account] srv.ChangePassword error: %w", err)
+	}
+
+	if _, err := a.checkPassword(principal.UserID, input.OldPassword); err != nil {
+		return fmt.Errorf("[Account] srv.ChangePassword error: %w", err)
+	}
+
+	hashedPassword, err := a.webUser.HashPassword(input.NewPassword)
+	if err != nil {
+		return fmt.Errorf("[Account] srv.ChangePassword error: %w", err)
+	}
+
+	err = a.store.InTx(func(tx *store.Store) error {
+		_, err := tx.WebUser.UpdateWebUserPassword(principal.UserID, hashedPassword)
+		if err != nil {
+			return err
+		}
+
+		revoked, err := tx.Session.RevokeOtherUserSessions(principal.UserID, principal.SessionID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &principal.UserID,
+			Action:  model.AuditPasswordChanged,
+			Origin:  origin,
+			Details: map[string]int{"sessions": revoked},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("[Account] srv.ChangePassword error: %w", err)
+	}
+
+	return nil
+}
+
+// RequestEmailChange checks password of principal and puts mail with confirmation link into outbox
+// of the new email, email is changed only when the link is opened.
+func (a *AccountDBService) RequestEmailChange(principal *model.Principal, input *model.ChangeEmailInput) error {
+	var verrs validation.Errors
+
+	email, err := validation.NormalizeEmail(input.Email)
+	verrs.Add("email", err)
+
+	if err := verrs.Err(); err != nil {
+		return fmt.Errorf("[Account] srv.RequestEmailChange error: %w", err)
+	}
+
+	user, err := a.checkPassword(principal.UserID, input.Password)
+	if err != nil {
+		return fmt.Errorf("[Account] srv.RequestEmailChange error: %w", err)
+	}
+
+	_, err = a.store.WebUser.GetWebUserByEmail(email)
+	if err == nil {
+		return fmt.Errorf("[Account] srv.RequestEmailChange error: %w", pg.ErrWebUserExists)
+	}
+
+	if !errors.Is(err, pg.ErrWebUserNotFound) {
+		return fmt.Errorf("[Account] srv.RequestEmailChange error: %w", err)
+	}
+
+	body := "Somebody asked to change email of your account to this one. Open the link below to confirm it, " +
+		"it's valid for 24 hours:\n\n%s\n\nIf it wasn't you, ignore this mail."
+
+	err = a.sendAccountToken(
+		a.store,
+		&model.AccountToken{UserID: user.ID, Purpose: model.AccountTokenChangeEmail, NewEmail: &email, ExpiresAt: time.Now().Add(changeEmailTokenTTL)},
+		email, "Confirm your new email", body, "/confirm-email",
+	)
+	if err != nil {
+		return fmt.Errorf("[Account] srv.RequestEmailChange error: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange sets new email using token from email change mail,
+// old and new emails are recorded in audit trail in the same transaction.
+func (a *AccountDBService) ConfirmEmailChange(token string, origin model.Origin) error {
+	err := a.store.InTx(func(tx *store.Store) error {
+		change, err := tx.Account.ChangeEmail(hashToken(token))
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &change.UserID,
+			Action:  model.AuditEmailChanged,
+			Origin:  origin,
+			Before:  map[string]string{"email": change.OldEmail},
+			After:   map[string]string{"email": change.NewEmail},
+		})
+	})
+	if errors.Is(err, pg.ErrAccountTokenNotFound) {
+		return fmt.Errorf("[Account] srv.ConfirmEmailChange error: %w", ErrInvalidAccountToken)
+	}
+
+	if err != nil {
+		return fmt.Errorf("[Account] srv.ConfirmEmailChange error: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAccount checks password of principal and permanently deletes web user with everything stored about it.
+// Deleted account is recorded in audit trail in the same transaction.
+func (a *AccountDBService) DeleteAccount(principal *model.Principal, password string, origin model.Origin) error {
+	user, err := a.checkPassword(principal.UserID, password)
+	if err != nil {
+		return fmt.Errorf("[Account] srv.DeleteAccount error: %w", err)
+	}
+
+	err = a.store.InTx(func(tx *store.Store) error {
+		_, err := tx.WebUser.DeleteWebUser(user.ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &user.ID,
+			Action:  model.AuditAccountDeleted,
+			Origin:  origin,
+			Before:  model.AccountInfo{ID: user.ID, Email: user.Email, Role: user.Role, EmailVerifiedAt: user.EmailVerifiedAt},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("[Account] srv.DeleteAccount error: %w", err)
+	}
+
+	return nil
+}
+
+// ExportAccount returns everything stored about web user, missing data is exported as empty lists.
+func (a *AccountDBService) ExportAccount(userID int) (*model.AccountExport, error) {
+	user, err := a.store.WebUser.GetWebUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("[Account] srv.ExportAccount error: %w", err)
+	}
+
+	export := &model.AccountExport{
+		ExportedAt: time.Now().UTC(),
+		Account:    model.AccountInfo{ID: user.ID, Email: user.Email, Role: user.Role, EmailVerifiedAt: user.EmailVerifiedAt},
+	}
+
+	export.SavedMessages, err = a.exportSavedMessages(userID)
+	if err != nil {
+		return nil, fmt.Errorf("[Account] srv.ExportAccount error: %w", err)
+	}
+
+	export.Collections, err = a.store.Saved.GetCollections(userID)
+	if err != nil && !errors.Is(err, pg.ErrCollectionsNotFound) {
+		return nil, fmt.Errorf("[Account] srv.ExportAccount error: %w", err)
+	}
+
+	export.Shares, err = a.store.Share.GetSharesByUserID(userID)
+	if err != nil && !errors.Is(err, pg.ErrSharesNotFound) {
+		return nil, fmt.Errorf("[Account] srv.ExportAccount error: %w", err)
+	}
+
+	export.Sessions, err = a.store.Session.GetSessionsByUserID(userID)
+	if err != nil && !errors.Is(err, pg.ErrSessionNotFound) {
+		return nil, fmt.Errorf("[Account] srv.ExportAccount error: %w", err)
+	}
+
+	export.APIKeys, err = a.store.APIKey.GetAPIKeys(userID)
+	if err != nil && !errors.Is(err, pg.ErrAPIKeysNotFound) {
+		return nil, fmt.Errorf("[Account] srv.ExportAccount error: %w", err)
+	}
+
+	export.Identities, err = a.store.OIDC.GetWebUserIdentities(userID)
+	if err != nil && !errors.Is(err, pg.ErrWebUserIdentitiesNotFound) {
+		return nil, fmt.Errorf("[Account] srv.ExportAccount error: %w", err)
+	}
+
+	if export.Collections == nil {
+		export.Collections = []model.Collection{}
+	}
+
+	if export.Shares == nil {
+		export.Shares = []model.Share{}
+	}
+
+	if export.Sessions == nil {
+		export.Sessions = []model.Session{}
+	}
+
+	if export.APIKeys == nil {
+		export.APIKeys = []model.APIKey{}
+	}
+
+	if export.Identities == nil {
+		export.Identities = []model.WebUserIdentity{}
+	}
+
+	return export, nil
+}
+
+// exportSavedMessages reads every saved message of user page by page, latest saved first.
+func (a *AccountDBService) exportSavedMessages(userID int) ([]model.SavedMessage, error) {
+	savedMessages := make([]model.SavedMessage, 0)
+	page := &model.PageRequest{Limit: exportPageLimit}
+
+	for {
+		items, err := a.store.Saved.GetSavedMessages(userID, nil, page)
+		if errors.Is(err, pg.ErrSavedMessagesNotFound) {
+			return savedMessages, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// page has one extra row when there are more saved messages
+		if len(items) <= page.Limit {
+			return append(savedMessages, items...), nil
+		}
+
+		items = items[:page.Limit]
+		savedMessages = append(savedMessages, items...)
+
+		last := items[len(items)-1]
+		page = &model.PageRequest{Limit: exportPageLimit, Cursor: &model.Cursor{PostedAt: last.SavedAt, ID: last.SavedID}}
+	}
+}
+
+// checkPassword returns web user when password is its password.
+func (a *AccountDBService) checkPassword(userID int, password string) (*model.WebUser, error) {
+	user, err := a.store.WebUser.GetWebUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.webUser.ComparePassword(password, user.Password) {
+		return nil, ErrIncorrectPassword
+	}
+
+	return user, nil
+}
+
+// sendAccountToken creates token and puts mail with link to path with the token for recipient into outbox of store s,
+// body is format with one verb for the link.
+func (a *AccountDBService) sendAccountToken(s *store.Store, token *model.AccountToken, recipient, subject, body, path string) error {
+	value, err := newRandomToken(accountTokenSize)
+	if err != nil {
+		return err
+	}
+
+	token.TokenHash = hashToken(value)
+	link := fmt.Sprintf("%s%s?token=%s", a.appURL, path, url.QueryEscape(value))
+
+	_, err = s.Account.CreateAccountToken(token, &model.Mail{Recipient: recipient, Subject: subject, Body: fmt.Sprintf(body, link)})
+
+	return err
+}
@@ -0,0 +1,706 @@
+package service_test
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testPasswordUser returns web user whose password is "password".
+func testPasswordUser(t *testing.T) *model.WebUser {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %s", err)
+	}
+
+	return &model.WebUser{ID: 1, Email: "test@test.com", Password: string(hashedPassword), Role: model.RoleMember}
+}
+
+func Test_RequestPasswordReset(t *testing.T) {
+	user := &model.WebUser{ID: 1, Email: "test@test.com"}
+
+	tests := []struct {
+		name           string
+		mock           func(webUserRepo *mocks.WebUserRepo, accountRepo *mocks.AccountRepo)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [password reset mail is put into outbox]",
+			mock: func(webUserRepo *mocks.WebUserRepo, accountRepo *mocks.AccountRepo) {
+				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(user, nil)
+				accountRepo.On(
+					"CreateAccountToken",
+					mock.MatchedBy(func(token *model.AccountToken) bool {
+						return token.UserID == 1 && token.Purpose == model.AccountTokenResetPassword && len(token.TokenHash) == 64
+					}),
+					mock.MatchedBy(func(mail *model.Mail) bool {
+						return mail.Recipient == "test@test.com" &&
+							strings.Contains(mail.Body, "https://scanner.test/reset-password?token=") &&
+							!strings.Contains(mail.Body, "%!")
+					}),
+				).Return(1, nil)
+			},
+		},
+		{
+			name: "Ok: [unknown email is not reported]",
+			mock: func(webUserRepo *mocks.WebUserRepo, accountRepo *mocks.AccountRepo) {
+				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(nil, pg.ErrWebUserNotFound)
+			},
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(webUserRepo *mocks.WebUserRepo, accountRepo *mocks.AccountRepo) {
+				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(user, nil)
+				accountRepo.On("CreateAccountToken", mock.AnythingOfType("*model.AccountToken"), mock.AnythingOfType("*model.Mail")).
+					Return(0, fmt.Errorf("failed to create account token: some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.RequestPasswordReset error: failed to create account token: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webUserRepo := &mocks.WebUserRepo{}
+			accountRepo := &mocks.AccountRepo{}
+			srv := service.NewAccountService(&store.Store{WebUser: webUserRepo, Account: accountRepo}, "https://scanner.test/", nil)
+
+			tt.mock(webUserRepo, accountRepo)
+
+			err := srv.RequestPasswordReset("test@test.com")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			webUserRepo.AssertExpectations(t)
+			accountRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// bcryptHashOf matches bcrypt hash of password in query arguments.
+type bcryptHashOf string
+
+func (b bcryptHashOf) Match(value driver.Value) bool {
+	hash, ok := value.(string)
+
+	return ok && bcrypt.CompareHashAndPassword([]byte(hash), []byte(b)) == nil
+}
+
+// textContaining matches text query argument which contains substring.
+type textContaining string
+
+func (c textContaining) Match(value driver.Value) bool {
+	text, ok := value.(string)
+
+	return ok && strings.Contains(text, string(c)) && !strings.Contains(text, "%!")
+}
+
+func Test_SignUp(t *testing.T) {
+	createQuery := "INSERT INTO web_user(email, password, role) VALUES ($1, $2, $3) RETURNING id;"
+	tokenQuery := `WITH old_tokens AS (
+			UPDATE account_token SET used_at = now()
+			WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+		), new_token AS (
+			INSERT INTO account_token(user_id, purpose, token_hash, expires_at, new_email) VALUES ($1, $2, $3, $4, $5) RETURNING id
+		), new_mail AS (
+			INSERT INTO mail_outbox(recipient, subject, body)
+			SELECT $6, $7, $8 FROM new_token
+		)
+		SELECT id FROM new_token;`
+	expectUser := func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(createQuery).WithArgs("test@test.com", bcryptHashOf("test_pswd"), model.RoleMember)
+	}
+	expectToken := func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+		return mock.ExpectQuery(tokenQuery).WithArgs(
+			2, model.AccountTokenVerifyEmail, sqlmock.AnyArg(), sqlmock.AnyArg(), nil,
+			"test@test.com", "Confirm your email", textContaining("https://scanner.test/verify-email?token="),
+		)
+	}
+
+	tests := []struct {
+		name           string
+		mock           func(mock sqlmock.Sqlmock)
+		input          *model.WebUser
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [user created with verification mail]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectUser(mock).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				expectAuditEvent(mock, 2, model.AuditSignUp, "", nil, `{"email":"test@test.com","id":2,"role":"member"}`)
+				expectToken(mock).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectCommit()
+			},
+			input: &model.WebUser{Email: " test@Test.com ", Password: "test_pswd", Role: model.RoleAdmin},
+		},
+		{
+			name: "Error: [user is not created when verification mail is not queued]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectUser(mock).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				expectAuditEvent(mock, 2, model.AuditSignUp, "", nil, `{"email":"test@test.com","id":2,"role":"member"}`)
+				expectToken(mock).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectRollback()
+			},
+			input:          &model.WebUser{Email: "test@test.com", Password: "test_pswd"},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.SignUp error: failed to create account token: some error",
+		},
+		{
+			name: "Error: [user not created]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectUser(mock).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			input:          &model.WebUser{Email: "test@test.com", Password: "test_pswd"},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.SignUp error: web user not created",
+		},
+		{
+			name:           "Error: [email and password are not valid]",
+			mock:           func(mock sqlmock.Sqlmock) {},
+			input:          &model.WebUser{Email: "test", Password: "test"},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.SignUp error: input is not valid: email is not valid email address; password must be at least 8 characters long",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, dbMock := newTxStore(t)
+			srv := service.NewAccountService(stores, "https://scanner.test/", nil)
+
+			tt.mock(dbMock)
+
+			err := srv.SignUp(tt.input, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 2, tt.input.ID)
+				assert.EqualValues(t, model.RoleMember, tt.input.Role)
+			}
+
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_ResetPassword(t *testing.T) {
+	resetQuery := `WITH used AS (
+			UPDATE account_token SET used_at = now()
+			WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id
+		)
+		UPDATE web_user u SET password = $3, email_verified_at = COALESCE(u.email_verified_at, now())
+		FROM used WHERE u.id = used.user_id
+		RETURNING u.id;`
+
+	tests := []struct {
+		name           string
+		mock           func(dbMock sqlmock.Sqlmock)
+		password       string
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [password reset and sessions revoked]",
+			mock: func(dbMock sqlmock.Sqlmock) {
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery(resetQuery).WithArgs(sqlmock.AnyArg(), model.AccountTokenResetPassword, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				dbMock.ExpectExec("UPDATE auth_session SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL;").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				expectAuditEvent(dbMock, 1, model.AuditPasswordReset, `{"sessions":2}`, nil, nil)
+				dbMock.ExpectCommit()
+			},
+			password: "new password",
+		},
+		{
+			name:           "Error: [password is empty]",
+			mock:           func(dbMock sqlmock.Sqlmock) {},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.ResetPassword error: input is not valid: password is required",
+		},
+		{
+			name: "Error: [token is not valid]",
+			mock: func(dbMock sqlmock.Sqlmock) {
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery(resetQuery).WithArgs(sqlmock.AnyArg(), model.AccountTokenResetPassword, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				dbMock.ExpectRollback()
+			},
+			password:       "new password",
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.ResetPassword error: token is not valid or expired",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, dbMock := newTxStore(t)
+			srv := service.NewAccountService(stores, "", nil)
+
+			tt.mock(dbMock)
+
+			err := srv.ResetPassword("token", tt.password, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(accountRepo *mocks.AccountRepo)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [email verified]",
+			mock: func(accountRepo *mocks.AccountRepo) {
+				accountRepo.On("VerifyEmail", mock.AnythingOfType("string")).Return(1, nil)
+			},
+		},
+		{
+			name: "Error: [token is not valid]",
+			mock: func(accountRepo *mocks.AccountRepo) {
+				accountRepo.On("VerifyEmail", mock.AnythingOfType("string")).Return(0, pg.ErrAccountTokenNotFound)
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.VerifyEmail error: token is not valid or expired",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(accountRepo *mocks.AccountRepo) {
+				accountRepo.On("VerifyEmail", mock.AnythingOfType("string")).Return(0, fmt.Errorf("failed to verify email: some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.VerifyEmail error: failed to verify email: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accountRepo := &mocks.AccountRepo{}
+			srv := service.NewAccountService(&store.Store{Account: accountRepo}, "", nil)
+
+			tt.mock(accountRepo)
+
+			err := srv.VerifyEmail("token")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			accountRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_ChangePassword(t *testing.T) {
+	user := testPasswordUser(t)
+	principal := &model.Principal{UserID: 1, Email: "test@test.com", Role: model.RoleMember, SessionID: 5}
+
+	tests := []struct {
+		name           string
+		mock           func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock)
+		input          *model.ChangePasswordInput
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [password changed and other sessions revoked]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByID", 1).Return(user, nil)
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("UPDATE web_user SET password = $1 WHERE id = $2 RETURNING id;").
+					WithArgs(bcryptHashOf("new password"), 1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				dbMock.ExpectExec("UPDATE auth_session SET revoked_at = now() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;").
+					WithArgs(1, 5).WillReturnResult(sqlmock.NewResult(0, 2))
+				expectAuditEvent(dbMock, 1, model.AuditPasswordChanged, `{"sessions":2}`, nil, nil)
+				dbMock.ExpectCommit()
+			},
+			input: &model.ChangePasswordInput{OldPassword: "password", NewPassword: "new password"},
+		},
+		{
+			name: "Error: [old password is incorrect]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByID", 1).Return(user, nil)
+			},
+			input:          &model.ChangePasswordInput{OldPassword: "wrong", NewPassword: "new password"},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.ChangePassword error: password is incorrect",
+		},
+		{
+			name:           "Error: [new password is empty]",
+			mock:           func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {},
+			input:          &model.ChangePasswordInput{OldPassword: "password"},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.ChangePassword error: input is not valid: newPassword is required",
+		},
+		{
+			name: "Error: [audit event not recorded]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByID", 1).Return(user, nil)
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("UPDATE web_user SET password = $1 WHERE id = $2 RETURNING id;").
+					WithArgs(bcryptHashOf("new password"), 1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				dbMock.ExpectExec("UPDATE auth_session SET revoked_at = now() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;").
+					WithArgs(1, 5).WillReturnResult(sqlmock.NewResult(0, 2))
+				dbMock.ExpectQuery(auditEventQuery).WillReturnError(fmt.Errorf("some error"))
+				dbMock.ExpectRollback()
+			},
+			input:          &model.ChangePasswordInput{OldPassword: "password", NewPassword: "new password"},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.ChangePassword error: failed to create audit event: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, dbMock := newTxStore(t)
+			webUserRepo := &mocks.WebUserRepo{}
+			stores.WebUser = webUserRepo
+			srv := service.NewAccountService(stores, "", nil)
+
+			tt.mock(webUserRepo, dbMock)
+
+			err := srv.ChangePassword(principal, tt.input, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			webUserRepo.AssertExpectations(t)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_RequestEmailChange(t *testing.T) {
+	user := testPasswordUser(t)
+	principal := &model.Principal{UserID: 1, Email: "test@test.com", Role: model.RoleMember, SessionID: 5}
+
+	tests := []struct {
+		name           string
+		mock           func(webUserRepo *mocks.WebUserRepo, accountRepo *mocks.AccountRepo)
+		input          *model.ChangeEmailInput
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [confirmation mail is sent to new email]",
+			mock: func(webUserRepo *mocks.WebUserRepo, accountRepo *mocks.AccountRepo) {
+				webUserRepo.On("GetWebUserByID", 1).Return(user, nil)
+				webUserRepo.On("GetWebUserByEmail", "new@test.com").Return(nil, pg.ErrWebUserNotFound)
+				accountRepo.On(
+					"CreateAccountToken",
+					mock.MatchedBy(func(token *model.AccountToken) bool {
+						return token.UserID == 1 && token.Purpose == model.AccountTokenChangeEmail &&
+							token.NewEmail != nil && *token.NewEmail == "new@test.com"
+					}),
+					mock.MatchedBy(func(mail *model.Mail) bool {
+						return mail.Recipient == "new@test.com" && strings.Contains(mail.Body, "/confirm-email?token=")
+					}),
+				).Return(1, nil)
+			},
+			input: &model.ChangeEmailInput{Email: " new@test.com ", Password: "password"},
+		},
+		{
+			name: "Error: [new email is taken]",
+			mock: func(webUserRepo *mocks.WebUserRepo, accountRepo *mocks.AccountRepo) {
+				webUserRepo.On("GetWebUserByID", 1).Return(user, nil)
+				webUserRepo.On("GetWebUserByEmail", "new@test.com").Return(&model.WebUser{ID: 2, Email: "new@test.com"}, nil)
+			},
+			input:          &model.ChangeEmailInput{Email: "new@test.com", Password: "password"},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.RequestEmailChange error: web user with this email already exists",
+		},
+		{
+			name: "Error: [password is incorrect]",
+			mock: func(webUserRepo *mocks.WebUserRepo, accountRepo *mocks.AccountRepo) {
+				webUserRepo.On("GetWebUserByID", 1).Return(user, nil)
+			},
+			input:          &model.ChangeEmailInput{Email: "new@test.com", Password: "wrong"},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.RequestEmailChange error: password is incorrect",
+		},
+		{
+			name:           "Error: [email is empty]",
+			mock:           func(webUserRepo *mocks.WebUserRepo, accountRepo *mocks.AccountRepo) {},
+			input:          &model.ChangeEmailInput{Email: " ", Password: "password"},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.RequestEmailChange error: input is not valid: email is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webUserRepo := &mocks.WebUserRepo{}
+			accountRepo := &mocks.AccountRepo{}
+			srv := service.NewAccountService(&store.Store{WebUser: webUserRepo, Account: accountRepo}, "", nil)
+
+			tt.mock(webUserRepo, accountRepo)
+
+			err := srv.RequestEmailChange(principal, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			webUserRepo.AssertExpectations(t)
+			accountRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_ConfirmEmailChange(t *testing.T) {
+	changeQuery := `WITH used AS (
+			UPDATE account_token SET used_at = now()
+			WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id, new_email
+		)
+		UPDATE web_user u SET email = used.new_email, email_verified_at = now()
+		FROM used JOIN web_user old ON old.id = used.user_id
+		WHERE u.id = used.user_id
+		RETURNING u.id, old.email AS old_email, u.email AS new_email;`
+
+	tests := []struct {
+		name           string
+		mock           func(dbMock sqlmock.Sqlmock)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [email changed]",
+			mock: func(dbMock sqlmock.Sqlmock) {
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery(changeQuery).WithArgs(sqlmock.AnyArg(), model.AccountTokenChangeEmail).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "old_email", "new_email"}).AddRow(1, "old@test.com", "new@test.com"))
+				expectAuditEvent(dbMock, 1, model.AuditEmailChanged, "", `{"email":"old@test.com"}`, `{"email":"new@test.com"}`)
+				dbMock.ExpectCommit()
+			},
+		},
+		{
+			name: "Error: [token is not valid]",
+			mock: func(dbMock sqlmock.Sqlmock) {
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery(changeQuery).WithArgs(sqlmock.AnyArg(), model.AccountTokenChangeEmail).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "old_email", "new_email"}))
+				dbMock.ExpectRollback()
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.ConfirmEmailChange error: token is not valid or expired",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, dbMock := newTxStore(t)
+			srv := service.NewAccountService(stores, "", nil)
+
+			tt.mock(dbMock)
+
+			err := srv.ConfirmEmailChange("token", testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_DeleteAccount(t *testing.T) {
+	user := testPasswordUser(t)
+	principal := &model.Principal{UserID: 1, Email: "test@test.com", Role: model.RoleMember, SessionID: 5}
+
+	tests := []struct {
+		name           string
+		mock           func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock)
+		password       string
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [account deleted]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByID", 1).Return(user, nil)
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("DELETE FROM web_user WHERE id = $1 RETURNING id;").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				expectAuditEvent(dbMock, 1, model.AuditAccountDeleted, "", `{"id":1,"email":"test@test.com","role":"member"}`, nil)
+				dbMock.ExpectCommit()
+			},
+			password: "password",
+		},
+		{
+			name: "Error: [password is incorrect]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByID", 1).Return(user, nil)
+			},
+			password:       "wrong",
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.DeleteAccount error: password is incorrect",
+		},
+		{
+			name: "Error: [audit event not recorded]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByID", 1).Return(user, nil)
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("DELETE FROM web_user WHERE id = $1 RETURNING id;").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				dbMock.ExpectQuery(auditEventQuery).WillReturnError(fmt.Errorf("some error"))
+				dbMock.ExpectRollback()
+			},
+			password:       "password",
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.DeleteAccount error: failed to create audit event: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, dbMock := newTxStore(t)
+			webUserRepo := &mocks.WebUserRepo{}
+			stores.WebUser = webUserRepo
+			srv := service.NewAccountService(stores, "", nil)
+
+			tt.mock(webUserRepo, dbMock)
+
+			err := srv.DeleteAccount(principal, tt.password, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			webUserRepo.AssertExpectations(t)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_ExportAccount(t *testing.T) {
+	savedAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	user := &model.WebUser{ID: 1, Email: "test@test.com", Password: "hash", Role: model.RoleMember}
+
+	// first page has one extra row, so the second page is read after the last kept row
+	firstPage := make([]model.SavedMessage, 101)
+	for i := range firstPage {
+		firstPage[i] = model.SavedMessage{SavedID: 200 - i, SavedAt: savedAt}
+	}
+
+	secondPage := []model.SavedMessage{{SavedID: 100, SavedAt: savedAt}}
+	collections := []model.Collection{{ID: 1, UserID: 1, Name: "golang"}}
+	identities := []model.WebUserIdentity{{ID: 1, UserID: 1, Issuer: "https://sso.example.com", Subject: "1001"}}
+
+	tests := []struct {
+		name           string
+		mock           func(stores *store.Store)
+		wantSaved      int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [everything about user exported]",
+			mock: func(stores *store.Store) {
+				stores.WebUser.(*mocks.WebUserRepo).On("GetWebUserByID", 1).Return(user, nil)
+				stores.Saved.(*mocks.SavedRepo).On("GetSavedMessages", 1, (*model.SavedFilter)(nil), &model.PageRequest{Limit: 100}).
+					Return(firstPage, nil)
+				stores.Saved.(*mocks.SavedRepo).On(
+					"GetSavedMessages", 1, (*model.SavedFilter)(nil), &model.PageRequest{Limit: 100, Cursor: &model.Cursor{PostedAt: savedAt, ID: 101}},
+				).Return(secondPage, nil)
+				stores.Saved.(*mocks.SavedRepo).On("GetCollections", 1).Return(collections, nil)
+				stores.Share.(*mocks.ShareRepo).On("GetSharesByUserID", 1).Return(nil, pg.ErrSharesNotFound)
+				stores.Session.(*mocks.SessionRepo).On("GetSessionsByUserID", 1).Return(nil, pg.ErrSessionNotFound)
+				stores.APIKey.(*mocks.APIKeyRepo).On("GetAPIKeys", 1).Return(nil, pg.ErrAPIKeysNotFound)
+				stores.OIDC.(*mocks.OIDCRepo).On("GetWebUserIdentities", 1).Return(identities, nil)
+			},
+			wantSaved: 101,
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(stores *store.Store) {
+				stores.WebUser.(*mocks.WebUserRepo).On("GetWebUserByID", 1).Return(user, nil)
+				stores.Saved.(*mocks.SavedRepo).On("GetSavedMessages", 1, (*model.SavedFilter)(nil), &model.PageRequest{Limit: 100}).
+					Return(nil, fmt.Errorf("failed to get saved messages by user id: some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Account] srv.ExportAccount error: failed to get saved messages by user id: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores := &store.Store{
+				WebUser: &mocks.WebUserRepo{},
+				Saved:   &mocks.SavedRepo{},
+				Share:   &mocks.ShareRepo{},
+				Session: &mocks.SessionRepo{},
+				APIKey:  &mocks.APIKeyRepo{},
+				OIDC:    &mocks.OIDCRepo{},
+			}
+			srv := service.NewAccountService(stores, "", nil)
+
+			tt.mock(stores)
+
+			got, err := srv.ExportAccount(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, model.AccountInfo{ID: 1, Email: "test@test.com", Role: model.RoleMember}, got.Account)
+				assert.Len(t, got.SavedMessages, tt.wantSaved)
+				assert.EqualValues(t, collections, got.Collections)
+				assert.NotNil(t, got.Shares)
+				assert.NotNil(t, got.Sessions)
+				assert.NotNil(t, got.APIKeys)
+				assert.EqualValues(t, identities, got.Identities)
+			}
+
+			stores.WebUser.(*mocks.WebUserRepo).AssertExpectations(t)
+			stores.Saved.(*mocks.SavedRepo).AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+const (
+	MaxAPIKeyNameLength = 100
+
+	// apiKeyPrefix marks api keys, so they are easy to find in configs and logs.
+	apiKeyPrefix = "sbk_"
+	// apiKeySize is number of random bytes in api key.
+	apiKeySize = 32
+	// apiKeyShownLength is number of first api key characters which are stored to tell keys apart.
+	apiKeyShownLength = 12
+)
+
+var (
+	ErrInvalidAPIKeyName     = errors.New("api key name is not valid")
+	ErrInvalidAPIKeyScope    = errors.New("api key scope is not valid")
+	ErrAPIKeyScopeNotAllowed = errors.New("api key scope is not allowed for user role")
+	ErrInvalidAPIKey         = errors.New("api key is not valid")
+)
+
+type APIKeyDBService struct {
+	store *store.Store
+}
+
+func NewAPIKeyService(store *store.Store) *APIKeyDBService {
+	return &APIKeyDBService{store: store}
+}
+
+// CreateAPIKey creates api key of principal and returns it with the key itself,
+// only hash of the key is stored so it can't be shown again.
+func (a *APIKeyDBService) CreateAPIKey(principal *model.Principal, input *model.APIKeyInput) (*model.APIKey, error) {
+	name, err := validateAPIKeyName(input.Name)
+	if err != nil {
+		return nil, fmt.Errorf("[APIKey] srv.CreateAPIKey error: %w", err)
+	}
+
+	if input.Scope != model.APIKeyScopeRead && input.Scope != model.APIKeyScopeSavedWrite {
+		return nil, fmt.Errorf("[APIKey] srv.CreateAPIKey error: %w", ErrInvalidAPIKeyScope)
+	}
+
+	if input.Scope == model.APIKeyScopeSavedWrite && !principal.Role.Allows(model.RoleMember) {
+		return nil, fmt.Errorf("[APIKey] srv.CreateAPIKey error: %w", ErrAPIKeyScopeNotAllowed)
+	}
+
+	token, err := newRandomToken(apiKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("[APIKey] srv.CreateAPIKey error: %w", err)
+	}
+
+	key := &model.APIKey{
+		UserID:  principal.UserID,
+		Name:    name,
+		Prefix:  (apiKeyPrefix + token)[:apiKeyShownLength],
+		Key:     apiKeyPrefix + token,
+		KeyHash: hashToken(apiKeyPrefix + token),
+		Scope:   input.Scope,
+	}
+
+	key.ID, err = a.store.APIKey.CreateAPIKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("[APIKey] srv.CreateAPIKey error: %w", err)
+	}
+
+	return key, nil
+}
+
+func (a *APIKeyDBService) GetAPIKeys(userID int) ([]model.APIKey, error) {
+	keys, err := a.store.APIKey.GetAPIKeys(userID)
+	if err != nil {
+		return nil, fmt.Errorf("[APIKey] srv.GetAPIKeys error: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (a *APIKeyDBService) RenameAPIKey(userID, ID int, name string) error {
+	name, err := validateAPIKeyName(name)
+	if err != nil {
+		return fmt.Errorf("[APIKey] srv.RenameAPIKey error: %w", err)
+	}
+
+	_, err = a.store.APIKey.UpdateAPIKeyName(userID, ID, name)
+	if err != nil {
+		return fmt.Errorf("[APIKey] srv.RenameAPIKey error: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAPIKey revokes api key of web user and records it in audit trail in one transaction.
+func (a *APIKeyDBService) RevokeAPIKey(userID, ID int, origin model.Origin) error {
+	err := a.store.InTx(func(tx *store.Store) error {
+		_, err := tx.APIKey.RevokeAPIKey(userID, ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &userID,
+			Action:  model.AuditAPIKeyRevoked,
+			Origin:  origin,
+			Details: map[string]int{"apiKeyId": ID},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("[APIKey] srv.RevokeAPIKey error: %w", err)
+	}
+
+	return nil
+}
+
+// Authenticate returns owner of api key acting with role of key scope and records key usage.
+func (a *APIKeyDBService) Authenticate(key string) (*model.Principal, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, fmt.Errorf("[APIKey] srv.Authenticate error: %w", ErrInvalidAPIKey)
+	}
+
+	principal, err := a.store.APIKey.UseAPIKey(hashToken(key))
+	if errors.Is(err, pg.ErrAPIKeyNotFound) {
+		return nil, fmt.Errorf("[APIKey] srv.Authenticate error: %w", ErrInvalidAPIKey)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("[APIKey] srv.Authenticate error: %w", err)
+	}
+
+	principal.Role = principal.Scope.Role(principal.Role)
+
+	return principal, nil
+}
+
+func validateAPIKeyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxAPIKeyNameLength {
+		return "", ErrInvalidAPIKeyName
+	}
+
+	return name, nil
+}
@@ -0,0 +1,282 @@
+package service_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(apiKeyRepo *mocks.APIKeyRepo)
+		principal      *model.Principal
+		input          *model.APIKeyInput
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [api key created]",
+			mock: func(apiKeyRepo *mocks.APIKeyRepo) {
+				apiKeyRepo.On("CreateAPIKey", mock.MatchedBy(func(key *model.APIKey) bool {
+					return key.UserID == 1 && key.Name == "notebook" && key.Scope == model.APIKeyScopeSavedWrite &&
+						strings.HasPrefix(key.Key, "sbk_") && strings.HasPrefix(key.Key, key.Prefix) &&
+						len(key.KeyHash) == 64 && !strings.Contains(key.KeyHash, key.Key)
+				})).Return(3, nil)
+			},
+			principal: &model.Principal{UserID: 1, Role: model.RoleMember},
+			input:     &model.APIKeyInput{Name: " notebook ", Scope: model.APIKeyScopeSavedWrite},
+		},
+		{
+			name:           "Error: [api key name is not valid]",
+			mock:           func(apiKeyRepo *mocks.APIKeyRepo) {},
+			principal:      &model.Principal{UserID: 1, Role: model.RoleMember},
+			input:          &model.APIKeyInput{Name: " ", Scope: model.APIKeyScopeRead},
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.CreateAPIKey error: api key name is not valid",
+		},
+		{
+			name:           "Error: [api key scope is not valid]",
+			mock:           func(apiKeyRepo *mocks.APIKeyRepo) {},
+			principal:      &model.Principal{UserID: 1, Role: model.RoleMember},
+			input:          &model.APIKeyInput{Name: "notebook", Scope: "admin"},
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.CreateAPIKey error: api key scope is not valid",
+		},
+		{
+			name:           "Error: [readonly user can't create saved-write api key]",
+			mock:           func(apiKeyRepo *mocks.APIKeyRepo) {},
+			principal:      &model.Principal{UserID: 1, Role: model.RoleReadonly},
+			input:          &model.APIKeyInput{Name: "notebook", Scope: model.APIKeyScopeSavedWrite},
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.CreateAPIKey error: api key scope is not allowed for user role",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(apiKeyRepo *mocks.APIKeyRepo) {
+				apiKeyRepo.On("CreateAPIKey", mock.AnythingOfType("*model.APIKey")).Return(0, fmt.Errorf("failed to create api key: some error"))
+			},
+			principal:      &model.Principal{UserID: 1, Role: model.RoleReadonly},
+			input:          &model.APIKeyInput{Name: "notebook", Scope: model.APIKeyScopeRead},
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.CreateAPIKey error: failed to create api key: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeyRepo := &mocks.APIKeyRepo{}
+			srv := service.NewAPIKeyService(&store.Store{APIKey: apiKeyRepo})
+
+			tt.mock(apiKeyRepo)
+
+			got, err := srv.CreateAPIKey(tt.principal, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 3, got.ID)
+				assert.NotEmpty(t, got.Key)
+			}
+
+			apiKeyRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_RenameAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(apiKeyRepo *mocks.APIKeyRepo)
+		input          string
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [api key renamed]",
+			mock: func(apiKeyRepo *mocks.APIKeyRepo) {
+				apiKeyRepo.On("UpdateAPIKeyName", 1, 3, "ci").Return(3, nil)
+			},
+			input: " ci ",
+		},
+		{
+			name:           "Error: [api key name is not valid]",
+			mock:           func(apiKeyRepo *mocks.APIKeyRepo) {},
+			input:          strings.Repeat("a", service.MaxAPIKeyNameLength+1),
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.RenameAPIKey error: api key name is not valid",
+		},
+		{
+			name: "Error: [api key not found]",
+			mock: func(apiKeyRepo *mocks.APIKeyRepo) {
+				apiKeyRepo.On("UpdateAPIKeyName", 1, 3, "ci").Return(0, pg.ErrAPIKeyNotFound)
+			},
+			input:          "ci",
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.RenameAPIKey error: api key not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeyRepo := &mocks.APIKeyRepo{}
+			srv := service.NewAPIKeyService(&store.Store{APIKey: apiKeyRepo})
+
+			tt.mock(apiKeyRepo)
+
+			err := srv.RenameAPIKey(1, 3, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			apiKeyRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_RevokeAPIKey(t *testing.T) {
+	revokeQuery := "UPDATE api_key SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL RETURNING id;"
+
+	tests := []struct {
+		name           string
+		mock           func(mock sqlmock.Sqlmock)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [api key revoked]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(revokeQuery).WithArgs(3, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				expectAuditEvent(mock, 1, model.AuditAPIKeyRevoked, `{"apiKeyId":3}`, nil, nil)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Error: [api key not found]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(revokeQuery).WithArgs(3, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.RevokeAPIKey error: api key not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, mock := newTxStore(t)
+			srv := service.NewAPIKeyService(stores)
+
+			tt.mock(mock)
+
+			err := srv.RevokeAPIKey(1, 3, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_AuthenticateAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(apiKeyRepo *mocks.APIKeyRepo)
+		input          string
+		want           *model.Principal
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [saved-write api key of member acts as member]",
+			mock: func(apiKeyRepo *mocks.APIKeyRepo) {
+				apiKeyRepo.On("UseAPIKey", mock.AnythingOfType("string")).
+					Return(&model.Principal{UserID: 1, Role: model.RoleMember, APIKeyID: 3, Scope: model.APIKeyScopeSavedWrite}, nil)
+			},
+			input: "sbk_key",
+			want:  &model.Principal{UserID: 1, Role: model.RoleMember, APIKeyID: 3, Scope: model.APIKeyScopeSavedWrite},
+		},
+		{
+			name: "Ok: [read api key of admin acts as readonly user]",
+			mock: func(apiKeyRepo *mocks.APIKeyRepo) {
+				apiKeyRepo.On("UseAPIKey", mock.AnythingOfType("string")).
+					Return(&model.Principal{UserID: 1, Role: model.RoleAdmin, APIKeyID: 3, Scope: model.APIKeyScopeRead}, nil)
+			},
+			input: "sbk_key",
+			want:  &model.Principal{UserID: 1, Role: model.RoleReadonly, APIKeyID: 3, Scope: model.APIKeyScopeRead},
+		},
+		{
+			name: "Ok: [saved-write api key of user demoted to readonly acts as readonly user]",
+			mock: func(apiKeyRepo *mocks.APIKeyRepo) {
+				apiKeyRepo.On("UseAPIKey", mock.AnythingOfType("string")).
+					Return(&model.Principal{UserID: 1, Role: model.RoleReadonly, APIKeyID: 3, Scope: model.APIKeyScopeSavedWrite}, nil)
+			},
+			input: "sbk_key",
+			want:  &model.Principal{UserID: 1, Role: model.RoleReadonly, APIKeyID: 3, Scope: model.APIKeyScopeSavedWrite},
+		},
+		{
+			name:           "Error: [api key without prefix]",
+			mock:           func(apiKeyRepo *mocks.APIKeyRepo) {},
+			input:          "key",
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.Authenticate error: api key is not valid",
+		},
+		{
+			name: "Error: [api key is revoked or unknown]",
+			mock: func(apiKeyRepo *mocks.APIKeyRepo) {
+				apiKeyRepo.On("UseAPIKey", mock.AnythingOfType("string")).Return(nil, pg.ErrAPIKeyNotFound)
+			},
+			input:          "sbk_key",
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.Authenticate error: api key is not valid",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(apiKeyRepo *mocks.APIKeyRepo) {
+				apiKeyRepo.On("UseAPIKey", mock.AnythingOfType("string")).Return(nil, fmt.Errorf("failed to use api key: some error"))
+			},
+			input:          "sbk_key",
+			wantErr:        true,
+			expectedErrMsg: "[APIKey] srv.Authenticate error: failed to use api key: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeyRepo := &mocks.APIKeyRepo{}
+			srv := service.NewAPIKeyService(&store.Store{APIKey: apiKeyRepo})
+
+			tt.mock(apiKeyRepo)
+
+			got, err := srv.Authenticate(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			apiKeyRepo.AssertExpectations(t)
+		})
+	}
+}
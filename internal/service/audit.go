@@ -0,0 +1,177 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+// auditExportBatch is number of audit events read from store at once during export.
+const auditExportBatch = 100
+
+// auditEntry is action recorded in audit trail, details and payloads are marshaled to json.
+type auditEntry struct {
+	ActorID *int
+	Action  model.AuditAction
+	Origin  model.Origin
+	Details interface{}
+	Before  interface{}
+	After   interface{}
+}
+
+// recordAuditEvent appends entry to audit trail.
+func recordAuditEvent(store *store.Store, entry auditEntry) error {
+	event := &model.AuditEvent{
+		ActorID:   entry.ActorID,
+		Action:    entry.Action,
+		IP:        entry.Origin.IP,
+		UserAgent: entry.Origin.UserAgent,
+	}
+
+	if entry.Details != nil {
+		details, err := json.Marshal(entry.Details)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit details: %w", err)
+		}
+
+		event.Details = details
+	}
+
+	var err error
+
+	event.Before, err = auditPayload(entry.Before)
+	if err != nil {
+		return err
+	}
+
+	event.After, err = auditPayload(entry.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Audit.CreateAuditEvent(event)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func auditPayload(value interface{}) (*json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit payload: %w", err)
+	}
+
+	payload := json.RawMessage(data)
+
+	return &payload, nil
+}
+
+type AuditDBService struct {
+	store *store.Store
+}
+
+func NewAuditService(store *store.Store) *AuditDBService {
+	return &AuditDBService{store: store}
+}
+
+// GetAuditEvents returns page of audit events matching filter, latest first.
+func (a *AuditDBService) GetAuditEvents(filter *model.AuditFilter, page *model.PageRequest) (*model.AuditPage, error) {
+	events, err := a.store.Audit.GetAuditEvents(filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("[Audit] srv.GetAuditEvents error: %w", err)
+	}
+
+	kept, info := paginate(
+		page, len(events),
+		func(i, j int) { events[i], events[j] = events[j], events[i] },
+		func(i int) model.Cursor { return model.Cursor{ID: events[i].ID} },
+	)
+
+	return &model.AuditPage{Items: events[:kept], PageInfo: info}, nil
+}
+
+// ExportAuditEvents passes every audit event matching filter to write, latest first.
+// Export itself is recorded in audit trail as action of actor before the first event is written,
+// so events are read in batches and the export doesn't hold all of them in memory.
+func (a *AuditDBService) ExportAuditEvents(actor *model.Principal, filter *model.AuditFilter, origin model.Origin, write func(event *model.AuditEvent) error) error {
+	err := recordAuditEvent(a.store, auditEntry{
+		ActorID: &actor.UserID,
+		Action:  model.AuditExported,
+		Origin:  origin,
+		Details: auditFilterDetails(filter),
+	})
+	if err != nil {
+		return fmt.Errorf("[Audit] srv.ExportAuditEvents error: %w", err)
+	}
+
+	page := &model.PageRequest{Limit: auditExportBatch}
+
+	for {
+		events, err := a.store.Audit.GetAuditEvents(filter, page)
+		if errors.Is(err, pg.ErrAuditEventsNotFound) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("[Audit] srv.ExportAuditEvents error: %w", err)
+		}
+
+		hasMore := len(events) > page.Limit
+		if hasMore {
+			events = events[:page.Limit]
+		}
+
+		for i := range events {
+			if err := write(&events[i]); err != nil {
+				return fmt.Errorf("[Audit] srv.ExportAuditEvents error: %w", err)
+			}
+		}
+
+		if !hasMore {
+			return nil
+		}
+
+		page = &model.PageRequest{Cursor: &model.Cursor{ID: events[len(events)-1].ID}, Limit: auditExportBatch}
+	}
+}
+
+// auditFilterDetails returns filter of export in form it's recorded in audit trail, empty fields are omitted.
+func auditFilterDetails(filter *model.AuditFilter) map[string]interface{} {
+	details := map[string]interface{}{}
+	if filter == nil {
+		return details
+	}
+
+	if filter.ActorID != 0 {
+		details["actorId"] = filter.ActorID
+	}
+
+	if filter.Action != "" {
+		details["action"] = filter.Action
+	}
+
+	if filter.IP != "" {
+		details["ip"] = filter.IP
+	}
+
+	if !filter.From.IsZero() {
+		details["from"] = filter.From.UTC().Format(time.RFC3339)
+	}
+
+	if !filter.To.IsZero() {
+		details["to"] = filter.To.UTC().Format(time.RFC3339)
+	}
+
+	return details
+}
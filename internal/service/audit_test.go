@@ -0,0 +1,209 @@
+package service_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+var testOrigin = model.Origin{IP: "192.0.2.1", UserAgent: "test-agent"}
+
+const auditEventQuery = `INSERT INTO audit_event(actor_id, action, ip, user_agent, details, before_payload, after_payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
+
+// newTxStore returns store on top of stub database for services which write in transaction.
+func newTxStore(t *testing.T) (*store.Store, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	return store.NewFromDB(&pg.DB{DB: sqlx.NewDb(db, "postgres")}, logger.Get("debug")), mock
+}
+
+// expectAuditEvent expects event of action from testOrigin to be inserted, empty details are stored as {}
+// and before and after are json strings or nil.
+func expectAuditEvent(mock sqlmock.Sqlmock, actorID interface{}, action model.AuditAction, details string, before, after interface{}) {
+	if details == "" {
+		details = "{}"
+	}
+
+	mock.ExpectQuery(auditEventQuery).
+		WithArgs(actorID, action, testOrigin.IP, testOrigin.UserAgent, details, before, after).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+// auditEvent returns event which service is expected to record for action of actor from testOrigin.
+func auditEvent(actorID *int, action model.AuditAction, details string) *model.AuditEvent {
+	event := &model.AuditEvent{ActorID: actorID, Action: action, IP: testOrigin.IP, UserAgent: testOrigin.UserAgent}
+	if details != "" {
+		event.Details = json.RawMessage(details)
+	}
+
+	return event
+}
+
+func rawJSON(value string) *json.RawMessage {
+	raw := json.RawMessage(value)
+
+	return &raw
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func Test_GetAuditEvents(t *testing.T) {
+	createdAt := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	events := []model.AuditEvent{
+		{ID: 3, ActorID: intPtr(1), Action: model.AuditSignInSucceeded, IP: "192.0.2.1", Details: json.RawMessage(`{}`), CreatedAt: createdAt},
+		{ID: 2, Action: model.AuditSignInFailed, IP: "192.0.2.1", Details: json.RawMessage(`{}`), CreatedAt: createdAt},
+		{ID: 1, ActorID: intPtr(1), Action: model.AuditSignUp, IP: "192.0.2.1", Details: json.RawMessage(`{}`), CreatedAt: createdAt},
+	}
+	filter := &model.AuditFilter{IP: "192.0.2.1"}
+
+	tests := []struct {
+		name           string
+		mock           func(auditRepo *mocks.AuditRepo)
+		page           *model.PageRequest
+		expected       *model.AuditPage
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [first page of audit events]",
+			mock: func(auditRepo *mocks.AuditRepo) {
+				auditRepo.On("GetAuditEvents", filter, &model.PageRequest{Limit: 2}).Return(append([]model.AuditEvent{}, events...), nil)
+			},
+			page: &model.PageRequest{Limit: 2},
+			expected: &model.AuditPage{
+				Items:    events[:2],
+				PageInfo: model.PageInfo{HasMore: true, Next: "Zjo6Mg"},
+			},
+		},
+		{
+			name: "Error: [audit events not found]",
+			mock: func(auditRepo *mocks.AuditRepo) {
+				auditRepo.On("GetAuditEvents", filter, &model.PageRequest{Limit: 2}).Return(nil, pg.ErrAuditEventsNotFound)
+			},
+			page:           &model.PageRequest{Limit: 2},
+			wantErr:        true,
+			expectedErrMsg: "[Audit] srv.GetAuditEvents error: audit events not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditRepo := &mocks.AuditRepo{}
+			srv := service.NewAuditService(&store.Store{Audit: auditRepo})
+
+			tt.mock(auditRepo)
+
+			got, err := srv.GetAuditEvents(filter, tt.page)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.expected, got)
+			}
+
+			auditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_ExportAuditEvents(t *testing.T) {
+	actor := &model.Principal{UserID: 1, Role: model.RoleAdmin}
+	filter := &model.AuditFilter{Action: model.AuditSignInFailed, From: time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)}
+	exported := auditEvent(intPtr(1), model.AuditExported, `{"action":"sign-in.failed","from":"2022-09-01T00:00:00Z"}`)
+
+	// batch is one row longer than export reads at once, so export has to continue after the last kept row
+	batch := make([]model.AuditEvent, 101)
+	for i := range batch {
+		batch[i] = model.AuditEvent{ID: 150 - i, Action: model.AuditSignInFailed}
+	}
+
+	tests := []struct {
+		name           string
+		mock           func(auditRepo *mocks.AuditRepo)
+		expectedIDs    []int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [events exported in batches]",
+			mock: func(auditRepo *mocks.AuditRepo) {
+				auditRepo.On("CreateAuditEvent", exported).Return(151, nil)
+				auditRepo.On("GetAuditEvents", filter, &model.PageRequest{Limit: 100}).Return(batch, nil)
+				auditRepo.On("GetAuditEvents", filter, &model.PageRequest{Cursor: &model.Cursor{ID: 51}, Limit: 100}).
+					Return([]model.AuditEvent{{ID: 50}, {ID: 49}}, nil)
+			},
+			expectedIDs: func() []int {
+				IDs := make([]int, 0, 102)
+				for ID := 150; ID >= 49; ID-- {
+					IDs = append(IDs, ID)
+				}
+
+				return IDs
+			}(),
+		},
+		{
+			name: "Ok: [no events match filter]",
+			mock: func(auditRepo *mocks.AuditRepo) {
+				auditRepo.On("CreateAuditEvent", exported).Return(151, nil)
+				auditRepo.On("GetAuditEvents", filter, &model.PageRequest{Limit: 100}).Return(nil, pg.ErrAuditEventsNotFound)
+			},
+			expectedIDs: []int{},
+		},
+		{
+			name: "Error: [export is not recorded]",
+			mock: func(auditRepo *mocks.AuditRepo) {
+				auditRepo.On("CreateAuditEvent", exported).Return(0, fmt.Errorf("failed to create audit event: some error"))
+			},
+			expectedIDs:    []int{},
+			wantErr:        true,
+			expectedErrMsg: "[Audit] srv.ExportAuditEvents error: failed to create audit event: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditRepo := &mocks.AuditRepo{}
+			srv := service.NewAuditService(&store.Store{Audit: auditRepo})
+
+			tt.mock(auditRepo)
+
+			IDs := []int{}
+			err := srv.ExportAuditEvents(actor, filter, testOrigin, func(event *model.AuditEvent) error {
+				IDs = append(IDs, event.ID)
+
+				return nil
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.EqualValues(t, tt.expectedIDs, IDs)
+
+			auditRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/store"
-	"github.com/VladPetriv/scanner_backend_api/pkg/utils"
 )
 
 type ChannelDBService struct {
@@ -34,13 +33,19 @@ func (c *ChannelDBService) GetChannelsCount() (int, error) {
 	return count, nil
 }
 
-func (c *ChannelDBService) GetChannelsByPage(page int) ([]model.Channel, error) {
-	channels, err := c.store.Channel.GetChannelsByPage(utils.FormatPage(page))
+func (c *ChannelDBService) GetChannelsByPage(page *model.PageRequest) (*model.ChannelsPage, error) {
+	channels, err := c.store.Channel.GetChannelsByPage(page)
 	if err != nil {
 		return nil, fmt.Errorf("[Channel] srv.GetChannelsByPage error: %w", err)
 	}
 
-	return channels, nil
+	kept, info := paginate(
+		page, len(channels),
+		func(i, j int) { channels[i], channels[j] = channels[j], channels[i] },
+		func(i int) model.Cursor { return model.Cursor{ID: channels[i].ID} },
+	)
+
+	return &model.ChannelsPage{Items: channels[:kept], PageInfo: info}, nil
 }
 
 func (c *ChannelDBService) GetChannelByName(name string) (*model.Channel, error) {
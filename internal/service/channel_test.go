@@ -116,51 +116,60 @@ func Test_GetChannelsByPage(t *testing.T) {
 	tests := []struct {
 		name           string
 		mock           func(channelRepo *mocks.ChannelRepo)
-		input          int
-		want           []model.Channel
+		input          *model.PageRequest
+		want           *model.ChannelsPage
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
-			name: "Ok: [channels on page 1 found with input 0]",
+			name: "Ok: [first page of channels found]",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 0).Return(data[:10], nil)
+				channelRepo.On("GetChannelsByPage", &model.PageRequest{Limit: 10}).Return(data[:11], nil)
+			},
+			input: &model.PageRequest{Limit: 10},
+			want: &model.ChannelsPage{
+				Items:    data[:10],
+				PageInfo: model.PageInfo{Next: "Zjo6MTA", HasMore: true},
 			},
-			input: 0,
-			want:  data[:10],
 		},
 		{
-			name: "Ok: [channels on page 1 found with input 1]",
+			name: "Ok: [last page of channels found]",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 0).Return(data[:10], nil)
+				channelRepo.On("GetChannelsByPage", &model.PageRequest{Cursor: &model.Cursor{ID: 10}, Limit: 10}).Return(data[10:], nil)
+			},
+			input: &model.PageRequest{Cursor: &model.Cursor{ID: 10}, Limit: 10},
+			want: &model.ChannelsPage{
+				Items:    data[10:],
+				PageInfo: model.PageInfo{Prev: "Yjo6MTE"},
 			},
-			input: 1,
-			want:  data[:10],
 		},
 		{
-			name: "Ok: [channels on page 2 found with input 2]",
+			name: "Ok: [previous page of channels found]",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 10).Return(data[11:], nil)
+				channelRepo.On("GetChannelsByPage", &model.PageRequest{Cursor: &model.Cursor{ID: 11, Backward: true}, Limit: 5}).
+					Return([]model.Channel{{ID: 10}, {ID: 9}, {ID: 8}, {ID: 7}, {ID: 6}, {ID: 5}}, nil)
+			},
+			input: &model.PageRequest{Cursor: &model.Cursor{ID: 11, Backward: true}, Limit: 5},
+			want: &model.ChannelsPage{
+				Items:    data[5:10],
+				PageInfo: model.PageInfo{Next: "Zjo6MTA", Prev: "Yjo6Ng", HasMore: true},
 			},
-			input: 2,
-			want:  data[11:],
 		},
 		{
-
-			name: "Error: [channels on page 3 not found with input ]",
+			name: "Error: [channels not found]",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 20).Return(nil, fmt.Errorf("channel/s not found"))
+				channelRepo.On("GetChannelsByPage", &model.PageRequest{Limit: 10}).Return(nil, fmt.Errorf("channels not found"))
 			},
-			input:          3,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
-			expectedErrMsg: "[Channel] srv.GetChannelsByPage error: channel/s not found",
+			expectedErrMsg: "[Channel] srv.GetChannelsByPage error: channels not found",
 		},
 		{
 			name: "Error: [some store error]",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 0).Return(nil, fmt.Errorf("failed to get channels by page: some error"))
+				channelRepo.On("GetChannelsByPage", &model.PageRequest{Limit: 10}).Return(nil, fmt.Errorf("failed to get channels by page: some error"))
 			},
-			input:          0,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "[Channel] srv.GetChannelsByPage error: failed to get channels by page: some error",
 		},
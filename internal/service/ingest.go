@@ -0,0 +1,231 @@
+package service
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/pkg/utils"
+)
+
+const (
+	ChannelsTopic = "channels.get"
+	MessagesTopic = "messages.get"
+)
+
+var (
+	ErrInvalidPayload = errors.New("invalid payload")
+	ErrUnknownTopic   = errors.New("unknown topic")
+)
+
+type IngestDBService struct {
+	store *store.Store
+}
+
+func NewIngestService(store *store.Store) *IngestDBService {
+	return &IngestDBService{store: store}
+}
+
+// IngestPayload decodes raw kafka value according to its topic and stores it.
+// Payloads which can never be ingested are reported with ErrInvalidPayload or ErrUnknownTopic.
+func (i *IngestDBService) IngestPayload(topic string, payload []byte) error {
+	switch topic {
+	case ChannelsTopic:
+		channel := model.ChannelDTO{}
+		if err := json.Unmarshal(payload, &channel); err != nil {
+			return fmt.Errorf("[Ingest] srv.IngestPayload error: %w: %v", ErrInvalidPayload, err)
+		}
+
+		return i.IngestChannel(&channel)
+	case MessagesTopic:
+		message := model.TgMessage{}
+		if err := json.Unmarshal(payload, &message); err != nil {
+			return fmt.Errorf("[Ingest] srv.IngestPayload error: %w: %v", ErrInvalidPayload, err)
+		}
+
+		return i.IngestMessage(&message)
+	default:
+		return fmt.Errorf("[Ingest] srv.IngestPayload error: %w: %s", ErrUnknownTopic, topic)
+	}
+}
+
+func (i *IngestDBService) IngestChannel(channel *model.ChannelDTO) error {
+	_, err := i.store.Channel.GetChannelByName(channel.Name)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, pg.ErrChannelNotFound) {
+		return fmt.Errorf("[Ingest] srv.IngestChannel error: %w", err)
+	}
+
+	err = i.store.Channel.CreateChannel(channel)
+	if err != nil {
+		return fmt.Errorf("[Ingest] srv.IngestChannel error: %w", err)
+	}
+
+	return nil
+}
+
+// IngestMessage stores telegram message with its author and replies in one transaction,
+// so a failure on any step leaves nothing behind. Messages are keyed by url: scanner re-publishes
+// message as its thread grows, so a known message keeps its id and gets only replies it has not seen.
+func (i *IngestDBService) IngestMessage(message *model.TgMessage) error {
+	if message.MessageURL == "" {
+		return fmt.Errorf("[Ingest] srv.IngestMessage error: %w: message url is empty", ErrInvalidPayload)
+	}
+
+	err := i.store.InTx(func(tx *store.Store) error {
+		channel, err := tx.Channel.GetChannelByName(message.PeerID.Username)
+		if err != nil {
+			return err
+		}
+
+		userID, err := upsertUser(tx, &model.UserDTO{
+			TgID:     message.FromID.ID,
+			Username: message.FromID.Username,
+			Fullname: message.FromID.Fullname,
+			ImageURL: message.FromID.ImageURL,
+		})
+		if err != nil {
+			return err
+		}
+
+		messageID, err := tx.Message.CreateMessage(&model.MessageDTO{
+			ChannelID:  channel.ID,
+			UserID:     userID,
+			Title:      message.Message,
+			MessageURL: message.MessageURL,
+			ImageURL:   message.ImageURL,
+			PostedAt:   postedAt(message.Date),
+			EditedAt:   editedAt(message.EditDate),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, replie := range message.Replies.Messages {
+			userID, err := upsertUser(tx, &model.UserDTO{
+				TgID:     replie.FromID.ID,
+				Username: replie.FromID.Username,
+				Fullname: replie.FromID.Fullname,
+				ImageURL: replie.FromID.ImageURL,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = tx.Replie.CreateReplie(&model.ReplieDTO{
+				MessageID: messageID,
+				ReplyKey:  replyKey(replie.ID, userID, replie.Message, replie.ImageURL),
+				UserID:    userID,
+				Title:     replie.Message,
+				ImageURL:  replie.ImageURL,
+				PostedAt:  postedAt(replie.Date),
+				EditedAt:  editedAt(replie.EditDate),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("[Ingest] srv.IngestMessage error: %w", err)
+	}
+
+	return nil
+}
+
+// replyKey identifies replie within its message. Telegram id is used when scanner sends it,
+// otherwise replie is keyed by its author and content the same way as replies stored before keys existed.
+func replyKey(ID, userID int, title, imageURL string) string {
+	if ID != 0 {
+		return strconv.Itoa(ID)
+	}
+
+	return fmt.Sprintf("md5:%x", md5.Sum([]byte(fmt.Sprintf("%d|%s|%s", userID, title, imageURL))))
+}
+
+// postedAt converts telegram date, payloads without it are treated as posted at ingestion time.
+func postedAt(date int64) time.Time {
+	if date == 0 {
+		return time.Now().UTC()
+	}
+
+	return time.Unix(date, 0).UTC()
+}
+
+func editedAt(date int64) *time.Time {
+	if date == 0 {
+		return nil
+	}
+
+	t := time.Unix(date, 0).UTC()
+
+	return &t
+}
+
+func (i *IngestDBService) QuarantineIngestFailure(failure *model.IngestFailure) error {
+	_, err := i.store.IngestFailure.CreateIngestFailure(failure)
+	if err != nil {
+		return fmt.Errorf("[Ingest] srv.QuarantineIngestFailure error: %w", err)
+	}
+
+	return nil
+}
+
+func (i *IngestDBService) GetIngestFailuresByPage(page int) ([]model.IngestFailure, error) {
+	failures, err := i.store.IngestFailure.GetIngestFailuresByPage(utils.FormatPage(page))
+	if err != nil {
+		return nil, fmt.Errorf("[Ingest] srv.GetIngestFailuresByPage error: %w", err)
+	}
+
+	return failures, nil
+}
+
+// ReplayIngestFailure ingests quarantined payload again and removes it on success.
+// When ingestion fails one more time the failure keeps the new error and attempts count.
+// Successful replay is recorded in audit trail as action of actor.
+func (i *IngestDBService) ReplayIngestFailure(actor *model.Principal, ID int, origin model.Origin) error {
+	failure, err := i.store.IngestFailure.GetIngestFailureByID(ID)
+	if err != nil {
+		return fmt.Errorf("[Ingest] srv.ReplayIngestFailure error: %w", err)
+	}
+
+	ingestErr := i.IngestPayload(failure.Topic, failure.Payload)
+	if ingestErr != nil {
+		err = i.store.IngestFailure.UpdateIngestFailure(ID, ingestErr.Error())
+		if err != nil {
+			return fmt.Errorf("[Ingest] srv.ReplayIngestFailure error: %w", err)
+		}
+
+		return fmt.Errorf("[Ingest] srv.ReplayIngestFailure error: %w", ingestErr)
+	}
+
+	err = i.store.InTx(func(tx *store.Store) error {
+		_, err := tx.IngestFailure.DeleteIngestFailure(ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &actor.UserID,
+			Action:  model.AuditIngestFailureReplayed,
+			Origin:  origin,
+			Details: map[string]interface{}{"failureId": ID, "topic": failure.Topic, "attempts": failure.Attempts},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("[Ingest] srv.ReplayIngestFailure error: %w", err)
+	}
+
+	return nil
+}
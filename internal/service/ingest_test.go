@@ -0,0 +1,464 @@
+package service_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+func Test_IngestMessage(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	srv := service.NewIngestService(store.NewFromDB(&pg.DB{DB: sqlxDB}, logger.Get("debug")))
+
+	input := &model.TgMessage{}
+	err = json.Unmarshal([]byte(`{
+		"Message": "test",
+		"MessageURL": "test.url",
+		"ImageURL": "test.jpg",
+		"Date": 1656633600,
+		"EditDate": 1656637200,
+		"FromID": {"ID": 100, "Username": "user", "Fullname": "User", "ImageURL": "user.jpg"},
+		"PeerID": {"Username": "channel"},
+		"Replies": {"Count": 1, "Messages": [
+			{"ID": 7, "Date": 1656633660, "FromID": {"Username": "replier", "Fullname": "Replier", "ImageURL": "replier.jpg"}, "Message": "replie", "ImageURL": ""},
+			{"FromID": {"Username": "lurker", "Fullname": "Lurker", "ImageURL": "lurker.jpg"}, "Message": "+1", "ImageURL": "", "Date": 1656633720}
+		]}
+	}`), input)
+	if err != nil {
+		t.Fatalf("failed to unmarshal test message: %s", err)
+	}
+
+	channelRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "title", "imageurl"}).AddRow(1, "channel", "Channel", "channel.jpg")
+	}
+	claimUsername := func() {
+		mock.ExpectExec(`UPDATE tg_user SET tg_id = $1
+		WHERE username = $2 AND tg_id IS NULL AND NOT EXISTS (SELECT 1 FROM tg_user WHERE tg_id = $1);`).
+			WithArgs(100, "user").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE tg_user SET username = NULL WHERE username = $1 AND tg_id IS DISTINCT FROM $2;").
+			WithArgs("user", 100).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	userByTgIDQuery := `INSERT INTO tg_user(tg_id, username, fullname, imageurl) VALUES ($1, NULLIF($2, ''), $3, $4)
+		ON CONFLICT (tg_id) DO UPDATE SET username = EXCLUDED.username, fullname = EXCLUDED.fullname, imageurl = EXCLUDED.imageurl
+		RETURNING id;`
+	userByUsernameQuery := `INSERT INTO tg_user(username, fullname, imageurl) VALUES ($1, $2, $3)
+		ON CONFLICT (username) DO UPDATE SET fullname = EXCLUDED.fullname, imageurl = EXCLUDED.imageurl
+		RETURNING id;`
+	messageQuery := `WITH upserted AS (
+			INSERT INTO message(channel_id, user_id, title, message_url, imageurl, posted_at, edited_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (message_url) DO UPDATE
+			SET title = EXCLUDED.title, imageurl = EXCLUDED.imageurl, edited_at = EXCLUDED.edited_at
+			WHERE EXCLUDED.edited_at IS NOT NULL AND (message.edited_at IS NULL OR EXCLUDED.edited_at > message.edited_at)
+			RETURNING id
+		)
+		SELECT id FROM upserted
+		UNION ALL
+		SELECT id FROM message WHERE message_url = $4 AND NOT EXISTS (SELECT 1 FROM upserted);`
+
+	postedAt := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	editedAt := postedAt.Add(time.Hour)
+	replieQuery := `INSERT INTO replie(message_id, reply_key, user_id, title, imageurl, posted_at, edited_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (message_id, reply_key) DO UPDATE
+		SET title = EXCLUDED.title, imageurl = EXCLUDED.imageurl, edited_at = EXCLUDED.edited_at
+		WHERE EXCLUDED.edited_at IS NOT NULL AND (replie.edited_at IS NULL OR EXCLUDED.edited_at > replie.edited_at);`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          *model.TgMessage
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [message ingested]",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT * FROM channel WHERE name = $1;").
+					WithArgs("channel").WillReturnRows(channelRows())
+				claimUsername()
+				mock.ExpectQuery(userByTgIDQuery).
+					WithArgs(100, "user", "User", "user.jpg").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(messageQuery).
+					WithArgs(1, 1, "test", "test.url", "test.jpg", postedAt, editedAt).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(userByUsernameQuery).
+					WithArgs("replier", "Replier", "replier.jpg").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec(replieQuery).
+					WithArgs(1, "7", 2, "replie", "", postedAt.Add(time.Minute), nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(userByUsernameQuery).
+					WithArgs("lurker", "Lurker", "lurker.jpg").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectExec(replieQuery).
+					WithArgs(1, "md5:cff69d72da4bc2e10829b526aecac749", 3, "+1", "", postedAt.Add(2*time.Minute), nil).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			input: input,
+		},
+		{
+			name: "Error: [channel not found]",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT * FROM channel WHERE name = $1;").
+					WithArgs("channel").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "title", "imageurl"}))
+				mock.ExpectRollback()
+			},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.IngestMessage error: channel not found",
+		},
+		{
+			name: "Error: [replie not created]",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT * FROM channel WHERE name = $1;").
+					WithArgs("channel").WillReturnRows(channelRows())
+				claimUsername()
+				mock.ExpectQuery(userByTgIDQuery).
+					WithArgs(100, "user", "User", "user.jpg").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(messageQuery).
+					WithArgs(1, 1, "test", "test.url", "test.jpg", postedAt, editedAt).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(userByUsernameQuery).
+					WithArgs("replier", "Replier", "replier.jpg").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec(replieQuery).
+					WithArgs(1, "7", 2, "replie", "", postedAt.Add(time.Minute), nil).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectRollback()
+			},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.IngestMessage error: failed to create replie: some error",
+		},
+		{
+			name:           "Error: [message url is empty]",
+			mock:           func() {},
+			input:          &model.TgMessage{Message: "test"},
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.IngestMessage error: invalid payload: message url is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := srv.IngestMessage(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_IngestMessage_RedeliveredAnonymousReplie(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	srv := service.NewIngestService(store.NewFromDB(&pg.DB{DB: sqlxDB}, logger.Get("debug")))
+
+	input := &model.TgMessage{}
+	err = json.Unmarshal([]byte(`{
+		"Message": "test",
+		"MessageURL": "test.url",
+		"Date": 1656633600,
+		"FromID": {"Fullname": "Anonymous"},
+		"PeerID": {"Username": "channel"},
+		"Replies": {"Count": 1, "Messages": [
+			{"FromID": {"Fullname": "Anonymous"}, "Message": "+1", "Date": 1656633720}
+		]}
+	}`), input)
+	if err != nil {
+		t.Fatalf("failed to unmarshal test message: %s", err)
+	}
+
+	postedAt := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	anonymousUserQuery := `INSERT INTO tg_user(fullname, imageurl) VALUES ($1, $2)
+		ON CONFLICT (fullname, imageurl) WHERE tg_id IS NULL AND username IS NULL DO UPDATE SET fullname = EXCLUDED.fullname
+		RETURNING id;`
+	messageQuery := `WITH upserted AS (
+			INSERT INTO message(channel_id, user_id, title, message_url, imageurl, posted_at, edited_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (message_url) DO UPDATE
+			SET title = EXCLUDED.title, imageurl = EXCLUDED.imageurl, edited_at = EXCLUDED.edited_at
+			WHERE EXCLUDED.edited_at IS NOT NULL AND (message.edited_at IS NULL OR EXCLUDED.edited_at > message.edited_at)
+			RETURNING id
+		)
+		SELECT id FROM upserted
+		UNION ALL
+		SELECT id FROM message WHERE message_url = $4 AND NOT EXISTS (SELECT 1 FROM upserted);`
+	replieQuery := `INSERT INTO replie(message_id, reply_key, user_id, title, imageurl, posted_at, edited_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (message_id, reply_key) DO UPDATE
+		SET title = EXCLUDED.title, imageurl = EXCLUDED.imageurl, edited_at = EXCLUDED.edited_at
+		WHERE EXCLUDED.edited_at IS NOT NULL AND (replie.edited_at IS NULL OR EXCLUDED.edited_at > replie.edited_at);`
+
+	// second delivery must reuse the same sender row and hit the replie stored by the first one
+	for _, rowsAffected := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT * FROM channel WHERE name = $1;").
+			WithArgs("channel").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "title", "imageurl"}).AddRow(1, "channel", "Channel", "channel.jpg"))
+		mock.ExpectQuery(anonymousUserQuery).
+			WithArgs("Anonymous", "").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectQuery(messageQuery).
+			WithArgs(1, 4, "test", "test.url", "", postedAt, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(anonymousUserQuery).
+			WithArgs("Anonymous", "").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectExec(replieQuery).
+			WithArgs(1, "md5:bd1060909909cad450eb462ea3668d82", 4, "+1", "", postedAt.Add(2*time.Minute), nil).
+			WillReturnResult(sqlmock.NewResult(0, rowsAffected))
+		mock.ExpectCommit()
+
+		if err := srv.IngestMessage(input); err != nil {
+			t.Fatalf("unexpected ingest error: %s", err)
+		}
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_IngestMessage_OlderPayloadAfterEdit(t *testing.T) {
+	stores, mock := newTxStore(t)
+	srv := service.NewIngestService(stores)
+
+	payload := func(editDate int64) *model.TgMessage {
+		message := &model.TgMessage{Message: "test", MessageURL: "test.url", Date: 1656633600, EditDate: editDate}
+		message.FromID.Fullname = "Anonymous"
+		message.PeerID.Username = "channel"
+
+		return message
+	}
+
+	postedAt := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	editedAt := postedAt.Add(time.Hour)
+	messageQuery := `WITH upserted AS (
+			INSERT INTO message(channel_id, user_id, title, message_url, imageurl, posted_at, edited_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (message_url) DO UPDATE
+			SET title = EXCLUDED.title, imageurl = EXCLUDED.imageurl, edited_at = EXCLUDED.edited_at
+			WHERE EXCLUDED.edited_at IS NOT NULL AND (message.edited_at IS NULL OR EXCLUDED.edited_at > message.edited_at)
+			RETURNING id
+		)
+		SELECT id FROM upserted
+		UNION ALL
+		SELECT id FROM message WHERE message_url = $4 AND NOT EXISTS (SELECT 1 FROM upserted);`
+
+	// original payload redelivered after the edit carries no edit time, so the guard keeps stored edit
+	// and its id is still returned
+	for _, edited := range []interface{}{editedAt, nil} {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT * FROM channel WHERE name = $1;").
+			WithArgs("channel").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "title", "imageurl"}).AddRow(1, "channel", "Channel", "channel.jpg"))
+		mock.ExpectQuery(`INSERT INTO tg_user(fullname, imageurl) VALUES ($1, $2)
+		ON CONFLICT (fullname, imageurl) WHERE tg_id IS NULL AND username IS NULL DO UPDATE SET fullname = EXCLUDED.fullname
+		RETURNING id;`).
+			WithArgs("Anonymous", "").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectQuery(messageQuery).
+			WithArgs(1, 4, "test", "test.url", "", postedAt, edited).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+	}
+
+	if err := srv.IngestMessage(payload(editedAt.Unix())); err != nil {
+		t.Fatalf("unexpected ingest error: %s", err)
+	}
+
+	if err := srv.IngestMessage(payload(0)); err != nil {
+		t.Fatalf("unexpected ingest error: %s", err)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_IngestPayload(t *testing.T) {
+	channel := &model.ChannelDTO{Name: "channel", Title: "Channel", ImageURL: "channel.jpg"}
+
+	tests := []struct {
+		name           string
+		mock           func(channelRepo *mocks.ChannelRepo)
+		topic          string
+		payload        []byte
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [channel created]",
+			mock: func(channelRepo *mocks.ChannelRepo) {
+				channelRepo.On("GetChannelByName", "channel").Return(nil, pg.ErrChannelNotFound)
+				channelRepo.On("CreateChannel", channel).Return(nil)
+			},
+			topic:   service.ChannelsTopic,
+			payload: []byte(`{"Username":"channel","Title":"Channel","ImageURL":"channel.jpg"}`),
+		},
+		{
+			name: "Ok: [channel is exist]",
+			mock: func(channelRepo *mocks.ChannelRepo) {
+				channelRepo.On("GetChannelByName", "channel").Return(&model.Channel{ID: 1, Name: "channel"}, nil)
+			},
+			topic:   service.ChannelsTopic,
+			payload: []byte(`{"Username":"channel","Title":"Channel","ImageURL":"channel.jpg"}`),
+		},
+		{
+			name:           "Error: [invalid payload]",
+			mock:           func(channelRepo *mocks.ChannelRepo) {},
+			topic:          service.ChannelsTopic,
+			payload:        []byte("hello"),
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.IngestPayload error: invalid payload: invalid character 'h' looking for beginning of value",
+		},
+		{
+			name:           "Error: [unknown topic]",
+			mock:           func(channelRepo *mocks.ChannelRepo) {},
+			topic:          "users.get",
+			payload:        []byte("{}"),
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.IngestPayload error: unknown topic: users.get",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(channelRepo *mocks.ChannelRepo) {
+				channelRepo.On("GetChannelByName", "channel").Return(nil, fmt.Errorf("failed to get channel by name: some error"))
+			},
+			topic:          service.ChannelsTopic,
+			payload:        []byte(`{"Username":"channel","Title":"Channel","ImageURL":"channel.jpg"}`),
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.IngestChannel error: failed to get channel by name: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channelRepo := &mocks.ChannelRepo{}
+			srv := service.NewIngestService(&store.Store{Channel: channelRepo})
+
+			tt.mock(channelRepo)
+
+			err := srv.IngestPayload(tt.topic, tt.payload)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			channelRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_ReplayIngestFailure(t *testing.T) {
+	failure := &model.IngestFailure{
+		ID:       1,
+		Topic:    service.ChannelsTopic,
+		Payload:  []byte(`{"Username":"channel","Title":"Channel","ImageURL":"channel.jpg"}`),
+		Error:    "failed to create channel: some error",
+		Attempts: 3,
+	}
+	channel := &model.ChannelDTO{Name: "channel", Title: "Channel", ImageURL: "channel.jpg"}
+
+	tests := []struct {
+		name           string
+		mock           func(failureRepo *mocks.IngestFailureRepo, channelRepo *mocks.ChannelRepo, mock sqlmock.Sqlmock)
+		input          int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [ingest failure replayed]",
+			mock: func(failureRepo *mocks.IngestFailureRepo, channelRepo *mocks.ChannelRepo, mock sqlmock.Sqlmock) {
+				failureRepo.On("GetIngestFailureByID", 1).Return(failure, nil)
+				channelRepo.On("GetChannelByName", "channel").Return(nil, pg.ErrChannelNotFound)
+				channelRepo.On("CreateChannel", channel).Return(nil)
+				mock.ExpectBegin()
+				mock.ExpectQuery("DELETE FROM ingest_failure WHERE id = $1 RETURNING id;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				expectAuditEvent(mock, 1, model.AuditIngestFailureReplayed, `{"attempts":3,"failureId":1,"topic":"channels.get"}`, nil, nil)
+				mock.ExpectCommit()
+			},
+			input: 1,
+		},
+		{
+			name: "Error: [ingest failure replayed by somebody else]",
+			mock: func(failureRepo *mocks.IngestFailureRepo, channelRepo *mocks.ChannelRepo, mock sqlmock.Sqlmock) {
+				failureRepo.On("GetIngestFailureByID", 1).Return(failure, nil)
+				channelRepo.On("GetChannelByName", "channel").Return(nil, pg.ErrChannelNotFound)
+				channelRepo.On("CreateChannel", channel).Return(nil)
+				mock.ExpectBegin()
+				mock.ExpectQuery("DELETE FROM ingest_failure WHERE id = $1 RETURNING id;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.ReplayIngestFailure error: ingest failure not found",
+		},
+		{
+			name: "Error: [ingest failure not found]",
+			mock: func(failureRepo *mocks.IngestFailureRepo, channelRepo *mocks.ChannelRepo, mock sqlmock.Sqlmock) {
+				failureRepo.On("GetIngestFailureByID", 1).Return(nil, pg.ErrIngestFailureNotFound)
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.ReplayIngestFailure error: ingest failure not found",
+		},
+		{
+			name: "Error: [ingestion failed again]",
+			mock: func(failureRepo *mocks.IngestFailureRepo, channelRepo *mocks.ChannelRepo, mock sqlmock.Sqlmock) {
+				failureRepo.On("GetIngestFailureByID", 1).Return(failure, nil)
+				channelRepo.On("GetChannelByName", "channel").Return(nil, pg.ErrChannelNotFound)
+				channelRepo.On("CreateChannel", channel).Return(fmt.Errorf("failed to create channel: some error"))
+				failureRepo.On("UpdateIngestFailure", 1, "[Ingest] srv.IngestChannel error: failed to create channel: some error").Return(nil)
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.ReplayIngestFailure error: [Ingest] srv.IngestChannel error: failed to create channel: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failureRepo := &mocks.IngestFailureRepo{}
+			channelRepo := &mocks.ChannelRepo{}
+			stores, dbMock := newTxStore(t)
+			stores.Channel = channelRepo
+			stores.IngestFailure = failureRepo
+			srv := service.NewIngestService(stores)
+
+			tt.mock(failureRepo, channelRepo, dbMock)
+
+			err := srv.ReplayIngestFailure(&model.Principal{UserID: 1, Role: model.RoleAdmin}, tt.input, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			failureRepo.AssertExpectations(t)
+			channelRepo.AssertExpectations(t)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
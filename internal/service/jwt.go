@@ -6,53 +6,307 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/pkg/config"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	// refreshTokenSize is number of random bytes in refresh token.
+	refreshTokenSize = 32
 )
 
 var (
 	ErrInvalidSigningMethod = errors.New("invalid signing method")
+	ErrInvalidIssuer        = errors.New("token issuer is not valid")
+	ErrInvalidAudience      = errors.New("token audience is not valid")
+	ErrNoActiveSigningKey   = errors.New("no active signing key")
+	ErrTokenWithoutUser     = errors.New("token has no user")
+	ErrTokenWithoutSession  = errors.New("token has no session")
+	ErrTokenWithoutRole     = errors.New("token has no role")
+	ErrSessionRevoked       = errors.New("session is revoked")
+	ErrInvalidAccessToken   = errors.New("access token is not valid")
+	ErrInvalidRefreshToken  = errors.New("refresh token is not valid")
+	ErrRefreshTokenReused   = errors.New("refresh token is already used")
 )
 
 type tokenClaims struct {
 	jwt.RegisteredClaims
+	UserID    int
 	UserEmail string
+	Role      model.Role
+	SessionID int
 }
 
 type JwtDBService struct {
-	secretKey string
+	store           *store.Store
+	secretKey       string
+	keys            []*signingKey
+	issuer          string
+	audience        string
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
 }
 
-func NewJwtService(key string) *JwtDBService {
-	return &JwtDBService{secretKey: key}
+// NewJwtService creates jwt service, token lifetimes are taken from config in go duration format
+// and default ones are used when they are empty or not valid.
+// Tokens are signed with asymmetric keys from config, HS256 with secret key is used when there are none.
+func NewJwtService(store *store.Store, cfg *config.Config) (*JwtDBService, error) {
+	keys, err := loadSigningKeys(cfg.JwtKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jwt keys: %w", err)
+	}
+
+	return &JwtDBService{
+		store:           store,
+		secretKey:       cfg.JwtSecretKey,
+		keys:            keys,
+		issuer:          cfg.JwtIssuer,
+		audience:        cfg.JwtAudience,
+		accessTokenTTL:  parseTTL(cfg.AccessTokenTTL, defaultAccessTokenTTL),
+		refreshTokenTTL: parseTTL(cfg.RefreshTokenTTL, defaultRefreshTokenTTL),
+	}, nil
 }
 
-func (j *JwtDBService) GenerateToken(email string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
-		jwt.RegisteredClaims{
-			ExpiresAt: &jwt.NumericDate{Time: time.Now().Add(12 * time.Hour)},
-			IssuedAt:  &jwt.NumericDate{Time: time.Now()},
-		},
-		email,
+func parseTTL(value string, defaultTTL time.Duration) time.Duration {
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return defaultTTL
+	}
+
+	return ttl
+}
+
+// GenerateTokens starts new session of web user and returns access token with first refresh token of it.
+func (j *JwtDBService) GenerateTokens(userID int, email string, role model.Role) (*model.Tokens, error) {
+	refreshToken, err := newRandomToken(refreshTokenSize)
+	if err != nil {
+		return nil, fmt.Errorf("[Jwt] srv.GenerateTokens error: %w", err)
+	}
+
+	sessionID, err := j.store.Session.CreateSession(userID, hashToken(refreshToken), time.Now().Add(j.refreshTokenTTL))
+	if err != nil {
+		return nil, fmt.Errorf("[Jwt] srv.GenerateTokens error: %w", err)
+	}
+
+	tokens, err := j.newTokens(&model.Principal{UserID: userID, Email: email, Role: role, SessionID: sessionID}, refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("[Jwt] srv.GenerateTokens error: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// RefreshTokens exchanges refresh token for new access and refresh tokens of the same session.
+// Using refresh token second time revokes its whole session, since one of the holders is not its owner,
+// the revocation is recorded in audit trail with client which used the token.
+func (j *JwtDBService) RefreshTokens(refreshToken string, origin model.Origin) (*model.Tokens, error) {
+	newRefreshToken, err := newRandomToken(refreshTokenSize)
+	if err != nil {
+		return nil, fmt.Errorf("[Jwt] srv.RefreshTokens error: %w", err)
+	}
+
+	tokenHash := hashToken(refreshToken)
+
+	principal, err := j.store.Session.RotateRefreshToken(tokenHash, hashToken(newRefreshToken), time.Now().Add(j.refreshTokenTTL))
+	if errors.Is(err, pg.ErrRefreshTokenNotFound) {
+		return nil, fmt.Errorf("[Jwt] srv.RefreshTokens error: %w", j.checkRefreshTokenReuse(tokenHash, origin))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("[Jwt] srv.RefreshTokens error: %w", err)
+	}
+
+	tokens, err := j.newTokens(principal, newRefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("[Jwt] srv.RefreshTokens error: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// checkRefreshTokenReuse is called for refresh token which can't be rotated,
+// it revokes session of the token and records it in audit trail when the token was already used.
+func (j *JwtDBService) checkRefreshTokenReuse(tokenHash string, origin model.Origin) error {
+	token, err := j.store.Session.GetRefreshToken(tokenHash)
+	if errors.Is(err, pg.ErrRefreshTokenNotFound) {
+		return ErrInvalidRefreshToken
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if token.UsedAt == nil {
+		return ErrInvalidRefreshToken
+	}
+
+	err = j.store.InTx(func(tx *store.Store) error {
+		_, err := tx.Session.RevokeSession(token.SessionID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &token.UserID,
+			Action:  model.AuditRefreshTokenReused,
+			Origin:  origin,
+			Details: map[string]int{"sessionId": token.SessionID, "refreshTokenId": token.ID},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+// Logout revokes session of principal, its access and refresh tokens stop working.
+// Revocation is recorded in audit trail in the same transaction.
+func (j *JwtDBService) Logout(principal *model.Principal, origin model.Origin) error {
+	err := j.store.InTx(func(tx *store.Store) error {
+		_, err := tx.Session.RevokeSession(principal.SessionID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &principal.UserID,
+			Action:  model.AuditSessionRevoked,
+			Origin:  origin,
+			Details: map[string]int{"sessionId": principal.SessionID},
+		})
 	})
+	if err != nil {
+		return fmt.Errorf("[Jwt] srv.Logout error: %w", err)
+	}
 
-	return token.SignedString([]byte(j.secretKey))
+	return nil
 }
 
-func (j *JwtDBService) ParseToken(accessToken string) (string, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidSigningMethod
+// LogoutAll revokes every session of principal and records it in audit trail in one transaction.
+func (j *JwtDBService) LogoutAll(principal *model.Principal, origin model.Origin) error {
+	err := j.store.InTx(func(tx *store.Store) error {
+		revoked, err := tx.Session.RevokeUserSessions(principal.UserID)
+		if err != nil {
+			return err
 		}
 
-		return []byte(j.secretKey), nil
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &principal.UserID,
+			Action:  model.AuditAllSessionsRevoked,
+			Origin:  origin,
+			Details: map[string]int{"sessions": revoked},
+		})
 	})
 	if err != nil {
-		return "", err
+		return fmt.Errorf("[Jwt] srv.LogoutAll error: %w", err)
+	}
+
+	return nil
+}
+
+func (j *JwtDBService) newTokens(principal *model.Principal, refreshToken string) (*model.Tokens, error) {
+	now := time.Now()
+
+	claims := &tokenClaims{
+		jwt.RegisteredClaims{
+			Issuer:    j.issuer,
+			ExpiresAt: &jwt.NumericDate{Time: now.Add(j.accessTokenTTL)},
+			IssuedAt:  &jwt.NumericDate{Time: now},
+		},
+		principal.UserID,
+		principal.Email,
+		principal.Role,
+		principal.SessionID,
+	}
+
+	if j.audience != "" {
+		claims.Audience = jwt.ClaimStrings{j.audience}
+	}
+
+	accessToken, err := j.signToken(claims, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return &model.Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(j.accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// signToken signs claims with current asymmetric key and puts its kid into token header,
+// HS256 is used when no asymmetric keys are configured.
+func (j *JwtDBService) signToken(claims *tokenClaims, now time.Time) (string, error) {
+	if len(j.keys) == 0 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.secretKey))
+	}
+
+	key := j.currentSigningKey(now)
+	if key == nil {
+		return "", ErrNoActiveSigningKey
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	return token.SignedString(key.private)
+}
+
+// ParseToken validates access token and returns web user it was issued to.
+// Tokens issued before user id, role or session was added to claims are rejected,
+// as well as tokens of revoked sessions. Wrapped store errors mean that token couldn't be checked,
+// not that it's invalid.
+func (j *JwtDBService) ParseToken(accessToken string) (*model.Principal, error) {
+	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, j.verificationKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAccessToken, err)
 	}
 
 	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return "", fmt.Errorf("token claims are not of type *tokenClaims")
+		return nil, fmt.Errorf("%w: token claims are not of type *tokenClaims", ErrInvalidAccessToken)
+	}
+
+	if j.issuer != "" && !claims.VerifyIssuer(j.issuer, true) {
+		return nil, ErrInvalidIssuer
+	}
+
+	if j.audience != "" && !claims.VerifyAudience(j.audience, true) {
+		return nil, ErrInvalidAudience
+	}
+
+	if claims.UserID == 0 {
+		return nil, ErrTokenWithoutUser
+	}
+
+	if claims.Role == "" {
+		return nil, ErrTokenWithoutRole
+	}
+
+	if claims.SessionID == 0 {
+		return nil, ErrTokenWithoutSession
+	}
+
+	session, err := j.store.Session.GetSession(claims.SessionID)
+	if errors.Is(err, pg.ErrSessionNotFound) {
+		return nil, ErrSessionRevoked
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("[Jwt] srv.ParseToken error: %w", err)
+	}
+
+	if session.RevokedAt != nil || session.UserID != claims.UserID {
+		return nil, ErrSessionRevoked
 	}
 
-	return claims.UserEmail, nil
+	return &model.Principal{UserID: claims.UserID, Email: claims.UserEmail, Role: claims.Role, SessionID: claims.SessionID}, nil
 }
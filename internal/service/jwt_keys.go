@@ -0,0 +1,173 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+var (
+	ErrInvalidSigningKey = errors.New("signing key is not valid")
+	ErrUnknownSigningKey = errors.New("unknown signing key")
+)
+
+// signingKey is private key which signs access tokens with method of its type,
+// it's used for signing from activeFrom and for verification all the time it's configured.
+type signingKey struct {
+	kid        string
+	method     jwt.SigningMethod
+	private    interface{}
+	public     interface{}
+	activeFrom time.Time
+}
+
+// loadSigningKeys loads keys in "kid:path[:activeFrom]" format separated by commas, where path is path
+// to PEM encoded RSA or Ed25519 private key and activeFrom is RFC3339 time since which key signs tokens.
+// Keys without activeFrom are active at once. Keys are returned ordered by activeFrom.
+func loadSigningKeys(keys string) ([]*signingKey, error) {
+	if strings.TrimSpace(keys) == "" {
+		return nil, nil
+	}
+
+	loaded := make([]*signingKey, 0)
+	kids := make(map[string]bool)
+
+	for _, entry := range strings.Split(keys, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSigningKey, entry)
+		}
+
+		if kids[parts[0]] {
+			return nil, fmt.Errorf("%w: duplicated kid %q", ErrInvalidSigningKey, parts[0])
+		}
+
+		key, err := loadSigningKey(parts[0], parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(parts) == 3 {
+			key.activeFrom, err = time.Parse(time.RFC3339, parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("%w: activation time of %q: %s", ErrInvalidSigningKey, parts[0], err)
+			}
+		}
+
+		kids[key.kid] = true
+		loaded = append(loaded, key)
+	}
+
+	sort.SliceStable(loaded, func(i, j int) bool {
+		return loaded[i].activeFrom.Before(loaded[j].activeFrom)
+	})
+
+	return loaded, nil
+}
+
+func loadSigningKey(kid, path string) (*signingKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key %q: %w", kid, err)
+	}
+
+	if rsaKey, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return &signingKey{kid: kid, method: jwt.SigningMethodRS256, private: rsaKey, public: &rsaKey.PublicKey}, nil
+	}
+
+	edKey, err := jwt.ParseEdPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is neither RSA nor Ed25519 private key", ErrInvalidSigningKey, kid)
+	}
+
+	privateKey, ok := edKey.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is neither RSA nor Ed25519 private key", ErrInvalidSigningKey, kid)
+	}
+
+	return &signingKey{
+		kid:     kid,
+		method:  jwt.SigningMethodEdDSA,
+		private: privateKey,
+		public:  privateKey.Public(),
+	}, nil
+}
+
+// currentSigningKey returns key with the latest activation time which has already come,
+// nil is returned when there is no such key.
+func (j *JwtDBService) currentSigningKey(now time.Time) *signingKey {
+	var current *signingKey
+
+	for _, key := range j.keys {
+		if key.activeFrom.After(now) {
+			break
+		}
+
+		current = key
+	}
+
+	return current
+}
+
+// verificationKey returns key which verifies token, asymmetric keys are chosen by kid from token header.
+func (j *JwtDBService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if j.secretKey == "" {
+			return nil, ErrInvalidSigningMethod
+		}
+
+		return []byte(j.secretKey), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	for _, key := range j.keys {
+		if key.kid != kid {
+			continue
+		}
+
+		if key.method.Alg() != token.Method.Alg() {
+			return nil, ErrInvalidSigningMethod
+		}
+
+		return key.public, nil
+	}
+
+	return nil, ErrUnknownSigningKey
+}
+
+// JWKS returns public keys of every configured asymmetric key, including keys scheduled for future
+// so verifiers know them before they start signing tokens. HMAC secret is never published.
+func (j *JwtDBService) JWKS() *model.JWKS {
+	jwks := &model.JWKS{Keys: make([]model.JWK, 0, len(j.keys))}
+
+	for _, key := range j.keys {
+		jwk := model.JWK{Kid: key.kid, Alg: key.method.Alg(), Use: "sig"}
+
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
@@ -0,0 +1,553 @@
+package service_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/pkg/config"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var jwtConfig = &config.Config{JwtSecretKey: "secret", AccessTokenTTL: "10m"}
+
+func Test_GenerateTokens(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(sessionRepo *mocks.SessionRepo)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [tokens generated]",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("CreateSession", 1, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(5, nil)
+			},
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("CreateSession", 1, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(0, fmt.Errorf("failed to create session: some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Jwt] srv.GenerateTokens error: failed to create session: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := &mocks.SessionRepo{}
+			srv, err := service.NewJwtService(&store.Store{Session: sessionRepo}, jwtConfig)
+			if err != nil {
+				t.Fatalf("could not create jwt service: %s", err)
+			}
+
+			tt.mock(sessionRepo)
+
+			got, err := srv.GenerateTokens(1, "test@test.com", model.RoleMember)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, got.AccessToken)
+				assert.NotEmpty(t, got.RefreshToken)
+				assert.EqualValues(t, 600, got.ExpiresIn)
+			}
+
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_RefreshTokens(t *testing.T) {
+	usedAt := time.Now()
+
+	tests := []struct {
+		name           string
+		mock           func(sessionRepo *mocks.SessionRepo, dbMock sqlmock.Sqlmock)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [tokens refreshed]",
+			mock: func(sessionRepo *mocks.SessionRepo, dbMock sqlmock.Sqlmock) {
+				sessionRepo.On("RotateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+					Return(&model.Principal{UserID: 1, Email: "test@test.com", Role: model.RoleMember, SessionID: 5}, nil)
+			},
+		},
+		{
+			name: "Error: [refresh token is not valid]",
+			mock: func(sessionRepo *mocks.SessionRepo, dbMock sqlmock.Sqlmock) {
+				sessionRepo.On("RotateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+					Return(nil, pg.ErrRefreshTokenNotFound)
+				sessionRepo.On("GetRefreshToken", mock.AnythingOfType("string")).Return(nil, pg.ErrRefreshTokenNotFound)
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Jwt] srv.RefreshTokens error: refresh token is not valid",
+		},
+		{
+			name: "Error: [refresh token of revoked session is not valid]",
+			mock: func(sessionRepo *mocks.SessionRepo, dbMock sqlmock.Sqlmock) {
+				sessionRepo.On("RotateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+					Return(nil, pg.ErrRefreshTokenNotFound)
+				sessionRepo.On("GetRefreshToken", mock.AnythingOfType("string")).Return(&model.RefreshToken{ID: 1, SessionID: 5}, nil)
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Jwt] srv.RefreshTokens error: refresh token is not valid",
+		},
+		{
+			name: "Error: [refresh token is already used]",
+			mock: func(sessionRepo *mocks.SessionRepo, dbMock sqlmock.Sqlmock) {
+				sessionRepo.On("RotateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+					Return(nil, pg.ErrRefreshTokenNotFound)
+				sessionRepo.On("GetRefreshToken", mock.AnythingOfType("string")).Return(&model.RefreshToken{ID: 1, SessionID: 5, UserID: 1, UsedAt: &usedAt}, nil)
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("UPDATE auth_session SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1 RETURNING id;").
+					WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				expectAuditEvent(dbMock, 1, model.AuditRefreshTokenReused, `{"refreshTokenId":1,"sessionId":5}`, nil, nil)
+				dbMock.ExpectCommit()
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Jwt] srv.RefreshTokens error: refresh token is already used",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(sessionRepo *mocks.SessionRepo, dbMock sqlmock.Sqlmock) {
+				sessionRepo.On("RotateRefreshToken", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+					Return(nil, fmt.Errorf("failed to rotate refresh token: some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Jwt] srv.RefreshTokens error: failed to rotate refresh token: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, dbMock := newTxStore(t)
+			sessionRepo := &mocks.SessionRepo{}
+			stores.Session = sessionRepo
+			srv, err := service.NewJwtService(stores, jwtConfig)
+			if err != nil {
+				t.Fatalf("could not create jwt service: %s", err)
+			}
+
+			tt.mock(sessionRepo, dbMock)
+
+			got, err := srv.RefreshTokens("refresh-token", testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, got.AccessToken)
+				assert.NotEqual(t, "refresh-token", got.RefreshToken)
+			}
+
+			sessionRepo.AssertExpectations(t)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_Logout(t *testing.T) {
+	principal := &model.Principal{UserID: 1, Email: "test@test.com", Role: model.RoleMember, SessionID: 5}
+	revokeQuery := "UPDATE auth_session SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1 RETURNING id;"
+
+	tests := []struct {
+		name           string
+		mock           func(mock sqlmock.Sqlmock)
+		all            bool
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [session revoked]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(revokeQuery).WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				expectAuditEvent(mock, 1, model.AuditSessionRevoked, `{"sessionId":5}`, nil, nil)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Ok: [every session revoked]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE auth_session SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL;").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+				expectAuditEvent(mock, 1, model.AuditAllSessionsRevoked, `{"sessions":3}`, nil, nil)
+				mock.ExpectCommit()
+			},
+			all: true,
+		},
+		{
+			name: "Error: [session not found]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(revokeQuery).WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Jwt] srv.Logout error: session not found",
+		},
+		{
+			name: "Error: [audit event not recorded]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(revokeQuery).WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectQuery(auditEventQuery).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectRollback()
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Jwt] srv.Logout error: failed to create audit event: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, mock := newTxStore(t)
+			srv, err := service.NewJwtService(stores, jwtConfig)
+			if err != nil {
+				t.Fatalf("could not create jwt service: %s", err)
+			}
+
+			tt.mock(mock)
+
+			if tt.all {
+				err = srv.LogoutAll(principal, testOrigin)
+			} else {
+				err = srv.Logout(principal, testOrigin)
+			}
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_ParseToken(t *testing.T) {
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name           string
+		mock           func(sessionRepo *mocks.SessionRepo)
+		want           *model.Principal
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [token of active session parsed]",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSession", 5).Return(&model.Session{ID: 5, UserID: 1}, nil)
+			},
+			want: &model.Principal{UserID: 1, Email: "test@test.com", Role: model.RoleMember, SessionID: 5},
+		},
+		{
+			name: "Error: [session is revoked]",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSession", 5).Return(&model.Session{ID: 5, UserID: 1, RevokedAt: &revokedAt}, nil)
+			},
+			wantErr:        true,
+			expectedErrMsg: "session is revoked",
+		},
+		{
+			name: "Error: [session not found]",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSession", 5).Return(nil, pg.ErrSessionNotFound)
+			},
+			wantErr:        true,
+			expectedErrMsg: "session is revoked",
+		},
+		{
+			name: "Error: [session can't be checked]",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSession", 5).Return(nil, fmt.Errorf("failed to get session: some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Jwt] srv.ParseToken error: failed to get session: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := &mocks.SessionRepo{}
+			srv, err := service.NewJwtService(&store.Store{Session: sessionRepo}, jwtConfig)
+			if err != nil {
+				t.Fatalf("could not create jwt service: %s", err)
+			}
+
+			sessionRepo.On("CreateSession", 1, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(5, nil)
+
+			tokens, err := srv.GenerateTokens(1, "test@test.com", model.RoleMember)
+			if err != nil {
+				t.Fatalf("could not generate tokens: %s", err)
+			}
+
+			tt.mock(sessionRepo)
+
+			got, err := srv.ParseToken(tokens.AccessToken)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func writeTestSigningKeys(t *testing.T) (string, string) {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate rsa key: %s", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate ed25519 key: %s", err)
+	}
+
+	dir := t.TempDir()
+
+	for name, key := range map[string]interface{}{"rsa.pem": rsaKey, "ed25519.pem": edKey} {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("could not marshal key: %s", err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+			t.Fatalf("could not write key: %s", err)
+		}
+	}
+
+	return filepath.Join(dir, "rsa.pem"), filepath.Join(dir, "ed25519.pem")
+}
+
+func Test_SignedTokens(t *testing.T) {
+	rsaPath, edPath := writeTestSigningKeys(t)
+
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name           string
+		signer         *config.Config
+		verifier       *config.Config
+		wantAlg        string
+		wantKid        string
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name:     "Ok: [token signed with rsa key, scheduled key is not used yet]",
+			signer:   &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s,2022-08:%s:%s", rsaPath, edPath, future)},
+			verifier: &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s,2022-08:%s:%s", rsaPath, edPath, future)},
+			wantAlg:  "RS256",
+			wantKid:  "2022-07",
+		},
+		{
+			name:     "Ok: [token signed with the latest active ed25519 key]",
+			signer:   &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s,2022-08:%s:%s", rsaPath, edPath, past)},
+			verifier: &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s,2022-08:%s:%s", rsaPath, edPath, past)},
+			wantAlg:  "EdDSA",
+			wantKid:  "2022-08",
+		},
+		{
+			name:     "Ok: [token of rotated key is still verified]",
+			signer:   &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath)},
+			verifier: &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s,2022-08:%s:%s", rsaPath, edPath, past)},
+			wantAlg:  "RS256",
+			wantKid:  "2022-07",
+		},
+		{
+			name:     "Ok: [HS256 token is accepted while secret key is set]",
+			signer:   &config.Config{JwtSecretKey: "secret"},
+			verifier: &config.Config{JwtSecretKey: "secret", JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath)},
+			wantAlg:  "HS256",
+		},
+		{
+			name:     "Ok: [issuer and audience are verified]",
+			signer:   &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath), JwtIssuer: "scanner", JwtAudience: "web"},
+			verifier: &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath), JwtIssuer: "scanner", JwtAudience: "web"},
+			wantAlg:  "RS256",
+			wantKid:  "2022-07",
+		},
+		{
+			name:           "Error: [HS256 token without secret key]",
+			signer:         &config.Config{JwtSecretKey: "secret"},
+			verifier:       &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath)},
+			wantAlg:        "HS256",
+			wantErr:        true,
+			expectedErrMsg: "invalid signing method",
+		},
+		{
+			name:           "Error: [unknown signing key]",
+			signer:         &config.Config{JwtKeys: fmt.Sprintf("2022-08:%s", edPath)},
+			verifier:       &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath)},
+			wantAlg:        "EdDSA",
+			wantKid:        "2022-08",
+			wantErr:        true,
+			expectedErrMsg: "unknown signing key",
+		},
+		{
+			name:           "Error: [token issuer is not valid]",
+			signer:         &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath), JwtIssuer: "other"},
+			verifier:       &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath), JwtIssuer: "scanner"},
+			wantAlg:        "RS256",
+			wantKid:        "2022-07",
+			wantErr:        true,
+			expectedErrMsg: "token issuer is not valid",
+		},
+		{
+			name:           "Error: [token audience is not valid]",
+			signer:         &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath), JwtAudience: "other"},
+			verifier:       &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s", rsaPath), JwtAudience: "web"},
+			wantAlg:        "RS256",
+			wantKid:        "2022-07",
+			wantErr:        true,
+			expectedErrMsg: "token audience is not valid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := &mocks.SessionRepo{}
+			sessionRepo.On("CreateSession", 1, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(5, nil)
+			sessionRepo.On("GetSession", 5).Return(&model.Session{ID: 5, UserID: 1}, nil).Maybe()
+
+			signer, err := service.NewJwtService(&store.Store{Session: sessionRepo}, tt.signer)
+			if err != nil {
+				t.Fatalf("could not create signer: %s", err)
+			}
+
+			verifier, err := service.NewJwtService(&store.Store{Session: sessionRepo}, tt.verifier)
+			if err != nil {
+				t.Fatalf("could not create verifier: %s", err)
+			}
+
+			tokens, err := signer.GenerateTokens(1, "test@test.com", model.RoleMember)
+			if err != nil {
+				t.Fatalf("could not generate tokens: %s", err)
+			}
+
+			header, _, err := new(jwt.Parser).ParseUnverified(tokens.AccessToken, &jwt.RegisteredClaims{})
+			if err != nil {
+				t.Fatalf("could not parse token header: %s", err)
+			}
+
+			kid, _ := header.Header["kid"].(string)
+			assert.EqualValues(t, tt.wantAlg, header.Method.Alg())
+			assert.EqualValues(t, tt.wantKid, kid)
+
+			got, err := verifier.ParseToken(tokens.AccessToken)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, &model.Principal{UserID: 1, Email: "test@test.com", Role: model.RoleMember, SessionID: 5}, got)
+			}
+
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_JWKS(t *testing.T) {
+	rsaPath, edPath := writeTestSigningKeys(t)
+
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name           string
+		input          *config.Config
+		wantKeys       []model.JWK
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name:  "Ok: [active and scheduled keys published]",
+			input: &config.Config{JwtSecretKey: "secret", JwtKeys: fmt.Sprintf("2022-08:%s:%s, 2022-07:%s", edPath, future, rsaPath)},
+			wantKeys: []model.JWK{
+				{Kty: "RSA", Kid: "2022-07", Alg: "RS256", Use: "sig", E: "AQAB"},
+				{Kty: "OKP", Kid: "2022-08", Alg: "EdDSA", Use: "sig", Crv: "Ed25519"},
+			},
+		},
+		{
+			name:     "Ok: [secret key is not published]",
+			input:    &config.Config{JwtSecretKey: "secret"},
+			wantKeys: []model.JWK{},
+		},
+		{
+			name:           "Error: [duplicated kid]",
+			input:          &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s,2022-07:%s", rsaPath, edPath)},
+			wantErr:        true,
+			expectedErrMsg: "failed to load jwt keys: signing key is not valid: duplicated kid \"2022-07\"",
+		},
+		{
+			name:           "Error: [activation time is not valid]",
+			input:          &config.Config{JwtKeys: fmt.Sprintf("2022-07:%s:tomorrow", rsaPath)},
+			wantErr:        true,
+			expectedErrMsg: "failed to load jwt keys: signing key is not valid: activation time of \"2022-07\": parsing time \"tomorrow\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"tomorrow\" as \"2006\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, err := service.NewJwtService(&store.Store{}, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			got := srv.JWKS()
+			assert.Len(t, got.Keys, len(tt.wantKeys))
+
+			for i, key := range got.Keys {
+				assert.EqualValues(t, tt.wantKeys[i].Kid, key.Kid)
+				assert.EqualValues(t, tt.wantKeys[i].Kty, key.Kty)
+				assert.EqualValues(t, tt.wantKeys[i].Alg, key.Alg)
+				assert.EqualValues(t, tt.wantKeys[i].Use, key.Use)
+				assert.EqualValues(t, tt.wantKeys[i].Crv, key.Crv)
+
+				if key.Kty == "RSA" {
+					assert.EqualValues(t, tt.wantKeys[i].E, key.E)
+					assert.NotEmpty(t, key.N)
+				} else {
+					assert.Len(t, key.X, 43)
+				}
+			}
+		})
+	}
+}
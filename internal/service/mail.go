@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/mailer"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+)
+
+const (
+	// MaxMailAttempts is number of attempts after which mail stays in outbox unsent.
+	MaxMailAttempts = 10
+
+	// mailLease is time during which claimed mail isn't taken by other instances.
+	mailLease = 5 * time.Minute
+	// maxMailRetryDelay limits delay between attempts of failing mail.
+	maxMailRetryDelay = time.Hour
+)
+
+type MailDBService struct {
+	store  *store.Store
+	mailer mailer.Mailer
+}
+
+func NewMailService(store *store.Store, sender mailer.Mailer) *MailDBService {
+	return &MailDBService{store: store, mailer: sender}
+}
+
+// SendPendingMails sends up to limit due mails from outbox and returns number of claimed mails.
+// Failed mails are retried later with growing delay.
+func (m *MailDBService) SendPendingMails(limit int) (int, error) {
+	now := time.Now()
+
+	mails, err := m.store.Outbox.ClaimMails(limit, MaxMailAttempts, now.Add(mailLease))
+	if err != nil {
+		return 0, fmt.Errorf("[Mail] srv.SendPendingMails error: %w", err)
+	}
+
+	for i := range mails {
+		mail := &mails[i]
+
+		if sendErr := m.mailer.Send(mail); sendErr != nil {
+			_, err = m.store.Outbox.MarkMailFailed(mail.ID, sendErr.Error(), now.Add(mailRetryDelay(mail.Attempts)))
+		} else {
+			_, err = m.store.Outbox.MarkMailSent(mail.ID)
+		}
+
+		if err != nil {
+			return i, fmt.Errorf("[Mail] srv.SendPendingMails error: %w", err)
+		}
+	}
+
+	return len(mails), nil
+}
+
+// mailRetryDelay returns delay before next attempt which grows quadratically with number of attempts.
+func mailRetryDelay(attempts int) time.Duration {
+	delay := time.Duration(attempts*attempts) * time.Minute
+	if delay > maxMailRetryDelay {
+		return maxMailRetryDelay
+	}
+
+	return delay
+}
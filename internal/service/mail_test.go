@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/mailer"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_SendPendingMails(t *testing.T) {
+	mails := []model.Mail{
+		{ID: 1, Recipient: "test@test.com", Subject: "Confirm your email", Body: "link", Attempts: 1},
+		{ID: 2, Recipient: "bad\r\nBcc: spam@test.com", Subject: "Confirm your email", Body: "link", Attempts: 3},
+	}
+
+	tests := []struct {
+		name           string
+		mock           func(outboxRepo *mocks.OutboxRepo)
+		want           int
+		wantSent       string
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [mails sent and failed mail postponed]",
+			mock: func(outboxRepo *mocks.OutboxRepo) {
+				outboxRepo.On("ClaimMails", 20, service.MaxMailAttempts, mock.AnythingOfType("time.Time")).Return(mails, nil)
+				outboxRepo.On("MarkMailSent", 1).Return(1, nil)
+				outboxRepo.On("MarkMailFailed", 2, mailer.ErrInvalidHeader.Error(), mock.AnythingOfType("time.Time")).Return(2, nil)
+			},
+			want:     2,
+			wantSent: "To: test@test.com\r\n",
+		},
+		{
+			name: "Ok: [no pending mails]",
+			mock: func(outboxRepo *mocks.OutboxRepo) {
+				outboxRepo.On("ClaimMails", 20, service.MaxMailAttempts, mock.AnythingOfType("time.Time")).Return([]model.Mail{}, nil)
+			},
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(outboxRepo *mocks.OutboxRepo) {
+				outboxRepo.On("ClaimMails", 20, service.MaxMailAttempts, mock.AnythingOfType("time.Time")).
+					Return(nil, fmt.Errorf("failed to claim mails: some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Mail] srv.SendPendingMails error: failed to claim mails: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := &bytes.Buffer{}
+			outboxRepo := &mocks.OutboxRepo{}
+			srv := service.NewMailService(&store.Store{Outbox: outboxRepo}, mailer.NewFileMailer(sent, "no-reply@test.com"))
+
+			tt.mock(outboxRepo)
+
+			got, err := srv.SendPendingMails(20)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+				assert.True(t, strings.Contains(sent.String(), tt.wantSent))
+				assert.False(t, strings.Contains(sent.String(), "spam@test.com"))
+			}
+
+			outboxRepo.AssertExpectations(t)
+		})
+	}
+}
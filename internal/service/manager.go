@@ -3,7 +3,9 @@ package service
 import (
 	"errors"
 
+	"github.com/VladPetriv/scanner_backend_api/internal/mailer"
 	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/pkg/config"
 )
 
 var ErrNoStore = errors.New("no store provided")
@@ -11,25 +13,63 @@ var ErrNoStore = errors.New("no store provided")
 type Manager struct {
 	Channel ChannelService
 	Message MessageService
+	Search  SearchService
 	Replie  ReplieService
 	User    UserService
 	WebUser WebUserService
+	SignIn  SignInService
+	OIDC    OIDCService
+	Account AccountService
+	Mail    MailService
 	Saved   SavedService
+	Share   ShareService
+	APIKey  APIKeyService
+	Ingest  IngestService
+	Audit   AuditService
 	Jwt     JwtService
 }
 
-func New(store *store.Store, secretJWTKey string) (*Manager, error) {
+func New(store *store.Store, cfg *config.Config) (*Manager, error) {
 	if store == nil {
 		return nil, ErrNoStore
 	}
 
+	jwt, err := NewJwtService(store, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sender, err := mailer.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	passwords, err := NewPasswordPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewOIDCProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Manager{
 		Channel: NewChannelService(store),
 		Message: NewMessageService(store),
+		Search:  NewSearchService(store, cfg.SearchLanguages),
 		Replie:  NewReplieService(store),
 		User:    NewUserService(store),
-		WebUser: NewWebUserService(store),
+		WebUser: NewWebUserService(store, passwords),
+		SignIn:  NewSignInService(store, jwt),
+		OIDC:    NewOIDCService(store, jwt, provider),
+		Account: NewAccountService(store, cfg.AppURL, passwords),
+		Mail:    NewMailService(store, sender),
 		Saved:   NewSavedService(store),
-		Jwt:     NewJwtService(secretJWTKey),
+		Share:   NewShareService(store),
+		APIKey:  NewAPIKeyService(store),
+		Ingest:  NewIngestService(store),
+		Audit:   NewAuditService(store),
+		Jwt:     jwt,
 	}, nil
 }
@@ -5,7 +5,6 @@ import (
 
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/store"
-	"github.com/VladPetriv/scanner_backend_api/pkg/utils"
 )
 
 type MessageDBService struct {
@@ -25,8 +24,8 @@ func (m *MessageDBService) CreateMessage(message *model.MessageDTO) (int, error)
 	return id, nil
 }
 
-func (m *MessageDBService) GetMessagesCount() (int, error) {
-	count, err := m.store.Message.GetMessagesCount()
+func (m *MessageDBService) GetMessagesCount(filter *model.MessageFilter) (int, error) {
+	count, err := m.store.Message.GetMessagesCount(filter)
 	if err != nil {
 		return 0, fmt.Errorf("[Message] srv.GetMessagesCount error: %w", err)
 	}
@@ -34,31 +33,13 @@ func (m *MessageDBService) GetMessagesCount() (int, error) {
 	return count, nil
 }
 
-func (m *MessageDBService) GetMessagesCountByChannelID(ID int) (int, error) {
-	count, err := m.store.Message.GetMessagesCountByChannelID(ID)
-	if err != nil {
-		return 0, fmt.Errorf("[Message] srv.GetMessagesCountByChannelID error: %w", err)
-	}
-
-	return count, nil
-}
-
-func (m *MessageDBService) GetFullMessagesByPage(page int) ([]model.FullMessage, error) {
-	messages, err := m.store.Message.GetFullMessagesByPage(utils.FormatPage(page))
+func (m *MessageDBService) GetFullMessagesByPage(page *model.PageRequest, filter *model.MessageFilter) (*model.FullMessagesPage, error) {
+	messages, err := m.store.Message.GetFullMessagesByPage(filter, page)
 	if err != nil {
 		return nil, fmt.Errorf("[Message] srv.GetFullMessagesByPage error: %w", err)
 	}
 
-	return messages, nil
-}
-
-func (m *MessageDBService) GetFullMessagesByChannelIDAndPage(ID, page int) ([]model.FullMessage, error) {
-	messages, err := m.store.Message.GetFullMessagesByChannelIDAndPage(ID, utils.FormatPage(page))
-	if err != nil {
-		return nil, fmt.Errorf("[Message] srv.GetFullMessagesByChannelIDAndPage error: %w", err)
-	}
-
-	return messages, nil
+	return newFullMessagesPage(messages, page), nil
 }
 
 func (m *MessageDBService) GetFullMessagesByUserID(ID int) ([]model.FullMessage, error) {
@@ -78,3 +59,13 @@ func (m *MessageDBService) GetFullMessageByID(ID int) (*model.FullMessage, error
 
 	return message, nil
 }
+
+func newFullMessagesPage(messages []model.FullMessage, page *model.PageRequest) *model.FullMessagesPage {
+	kept, info := paginate(
+		page, len(messages),
+		func(i, j int) { messages[i], messages[j] = messages[j], messages[i] },
+		func(i int) model.Cursor { return model.Cursor{PostedAt: messages[i].PostedAt, ID: messages[i].ID} },
+	)
+
+	return &model.FullMessagesPage{Items: messages[:kept], PageInfo: info}
+}
@@ -3,6 +3,7 @@ package service_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/service"
@@ -83,14 +84,14 @@ func Test_GetMessagesCount(t *testing.T) {
 		{
 			name: "Ok: [messages count found]",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(10, nil)
+				messageRepo.On("GetMessagesCount", (*model.MessageFilter)(nil)).Return(10, nil)
 			},
 			want: 10,
 		},
 		{
 			name: "Error: [messages count not found]",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(0, fmt.Errorf("failed to get messages count: sql: no rows in result set"))
+				messageRepo.On("GetMessagesCount", (*model.MessageFilter)(nil)).Return(0, fmt.Errorf("failed to get messages count: sql: no rows in result set"))
 			},
 			wantErr:        true,
 			expectedErrMsg: "[Message] srv.GetMessagesCount error: failed to get messages count: sql: no rows in result set",
@@ -98,7 +99,7 @@ func Test_GetMessagesCount(t *testing.T) {
 		{
 			name: "Error: [some store error]",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(0, fmt.Errorf("failed to get messages count: some error"))
+				messageRepo.On("GetMessagesCount", (*model.MessageFilter)(nil)).Return(0, fmt.Errorf("failed to get messages count: some error"))
 			},
 			wantErr:        true,
 			expectedErrMsg: "[Message] srv.GetMessagesCount error: failed to get messages count: some error",
@@ -112,66 +113,7 @@ func Test_GetMessagesCount(t *testing.T) {
 
 			tt.mock(messageRepo)
 
-			got, err := srv.GetMessagesCount()
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
-			} else {
-				assert.NoError(t, err)
-				assert.EqualValues(t, tt.want, got)
-			}
-
-			messageRepo.AssertExpectations(t)
-		})
-	}
-}
-
-func Test_GetMessagesCountByChannelID(t *testing.T) {
-
-	tests := []struct {
-		name           string
-		mock           func(messageRepo *mocks.MessageRepo)
-		input          int
-		want           int
-		wantErr        bool
-		expectedErrMsg string
-	}{
-		{
-			name: "Ok: [messages count by channel id found]",
-			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCountByChannelID", 1).Return(10, nil)
-			},
-			input: 1,
-			want:  10,
-		},
-		{
-			name: "Error: [message count by channel id not found]",
-			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCountByChannelID", 404).Return(0, fmt.Errorf("failed to get messages count by channel id: sql: no rows in result set"))
-			},
-			input:          404,
-			wantErr:        true,
-			expectedErrMsg: "[Message] srv.GetMessagesCountByChannelID error: failed to get messages count by channel id: sql: no rows in result set",
-		},
-		{
-			name: "Error: [some store error]",
-			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCountByChannelID", 0).Return(0, fmt.Errorf("failed to get messages count by channel id: some error"))
-			},
-			input:          0,
-			wantErr:        true,
-			expectedErrMsg: "[Message] srv.GetMessagesCountByChannelID error: failed to get messages count by channel id: some error",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			messageRepo := &mocks.MessageRepo{}
-			srv := service.NewMessageService(&store.Store{Message: messageRepo})
-
-			tt.mock(messageRepo)
-
-			got, err := srv.GetMessagesCountByChannelID(tt.input)
+			got, err := srv.GetMessagesCount(nil)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -186,52 +128,53 @@ func Test_GetMessagesCountByChannelID(t *testing.T) {
 }
 
 func Test_GetFullMessagesByPage(t *testing.T) {
+	postedAt := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
 	data := []model.FullMessage{
-		{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5},
-		{ID: 6}, {ID: 7}, {ID: 8}, {ID: 9}, {ID: 10},
-		{ID: 11}, {ID: 12}, {ID: 13}, {ID: 14}, {ID: 15},
-		{ID: 16}, {ID: 17}, {ID: 18}, {ID: 19}, {ID: 20},
+		{ID: 3, PostedAt: postedAt}, {ID: 2, PostedAt: postedAt}, {ID: 1, PostedAt: postedAt},
 	}
 
 	tests := []struct {
 		name           string
 		mock           func(messageRepo *mocks.MessageRepo)
-		input          int
-		want           []model.FullMessage
+		input          *model.PageRequest
+		want           *model.FullMessagesPage
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
-			name: "Ok: [messages found on page 1]",
+			name: "Ok: [first page of messages found]",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByPage", 0).Return(data[:10], nil)
+				messageRepo.On("GetFullMessagesByPage", (*model.MessageFilter)(nil), &model.PageRequest{Limit: 2}).Return(data, nil)
+			},
+			input: &model.PageRequest{Limit: 2},
+			want: &model.FullMessagesPage{
+				Items:    data[:2],
+				PageInfo: model.PageInfo{Next: "ZjoxNjU2NjMzNjAwMDAwMDAwMDAwOjI", HasMore: true},
 			},
-			input: 1,
-			want:  data[:10],
 		},
 		{
-			name: "Ok: [messages found on page 2]",
+			name: "Ok: [single page of messages found]",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByPage", 10).Return(data[11:], nil)
+				messageRepo.On("GetFullMessagesByPage", (*model.MessageFilter)(nil), &model.PageRequest{Limit: 10}).Return(data, nil)
 			},
-			input: 2,
-			want:  data[11:],
+			input: &model.PageRequest{Limit: 10},
+			want:  &model.FullMessagesPage{Items: data},
 		},
 		{
-			name: "Error: [messages not found on page 3]",
+			name: "Error: [messages not found]",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByPage", 20).Return(nil, fmt.Errorf("full messages not found"))
+				messageRepo.On("GetFullMessagesByPage", (*model.MessageFilter)(nil), &model.PageRequest{Limit: 10}).Return(nil, fmt.Errorf("full messages not found"))
 			},
-			input:          3,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "[Message] srv.GetFullMessagesByPage error: full messages not found",
 		},
 		{
 			name: "Error: [some store error]",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByPage", 0).Return(nil, fmt.Errorf("failed to get full messages by page: some error"))
+				messageRepo.On("GetFullMessagesByPage", (*model.MessageFilter)(nil), &model.PageRequest{Limit: 10}).Return(nil, fmt.Errorf("failed to get full messages by page: some error"))
 			},
-			input:          1,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "[Message] srv.GetFullMessagesByPage error: failed to get full messages by page: some error",
 		},
@@ -244,85 +187,7 @@ func Test_GetFullMessagesByPage(t *testing.T) {
 
 			tt.mock(messageRepo)
 
-			got, err := srv.GetFullMessagesByPage(tt.input)
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
-			} else {
-				assert.NoError(t, err)
-				assert.EqualValues(t, tt.want, got)
-			}
-
-			messageRepo.AssertExpectations(t)
-		})
-	}
-}
-
-func Test_GetFullMessagesByChannelIDAndPage(t *testing.T) {
-	data := []model.FullMessage{
-		{ID: 1, ChannelName: "test1"}, {ID: 2, ChannelName: "test1"}, {ID: 3, ChannelName: "test1"}, {ID: 4, ChannelName: "test1"}, {ID: 5, ChannelName: "test1"},
-		{ID: 6, ChannelName: "test1"}, {ID: 7, ChannelName: "test1"}, {ID: 8, ChannelName: "test1"}, {ID: 9, ChannelName: "test1"}, {ID: 10, ChannelName: "test1"},
-		{ID: 11, ChannelName: "test2"}, {ID: 12, ChannelName: "test2"}, {ID: 13, ChannelName: "test2"}, {ID: 14, ChannelName: "test2"}, {ID: 15, ChannelName: "test2"},
-		{ID: 16, ChannelName: "test2"}, {ID: 17, ChannelName: "test2"}, {ID: 18, ChannelName: "test2"}, {ID: 19, ChannelName: "test2"}, {ID: 20, ChannelName: "test2"},
-	}
-
-	tests := []struct {
-		name           string
-		mock           func(messageRepo *mocks.MessageRepo)
-		ID             int
-		page           int
-		want           []model.FullMessage
-		wantErr        bool
-		expectedErrMsg string
-	}{
-		{
-			name: "Ok: [messages by channel id on page 1 found]",
-			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByChannelIDAndPage", 1, 0).Return(data[:10], nil)
-			},
-			ID:   1,
-			page: 1,
-			want: data[:10],
-		},
-		{
-			name: "Ok: [messages by channel id on page 2 found]",
-			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByChannelIDAndPage", 2, 10).Return(data[11:], nil)
-			},
-			ID:   2,
-			page: 2,
-			want: data[11:],
-		},
-		{
-			name: "Error: [messages by channel id on page 3 not found]",
-			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByChannelIDAndPage", 1, 20).Return(nil, fmt.Errorf("full messages not found"))
-			},
-			ID:             1,
-			page:           3,
-			wantErr:        true,
-			expectedErrMsg: "[Message] srv.GetFullMessagesByChannelIDAndPage error: full messages not found",
-		},
-		{
-			name: "Error: [some store error]",
-			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByChannelIDAndPage", 1, 0).Return(nil, fmt.Errorf("failed to get full messages by channel id and page: some error"))
-			},
-			ID:             1,
-			page:           1,
-			wantErr:        true,
-			expectedErrMsg: "[Message] srv.GetFullMessagesByChannelIDAndPage error: failed to get full messages by channel id and page: some error",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			messageRepo := &mocks.MessageRepo{}
-			srv := service.NewMessageService(&store.Store{Message: messageRepo})
-
-			tt.mock(messageRepo)
-
-			got, err := srv.GetFullMessagesByChannelIDAndPage(tt.ID, tt.page)
+			got, err := srv.GetFullMessagesByPage(tt.input, nil)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
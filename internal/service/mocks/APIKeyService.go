@@ -0,0 +1,125 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyService is an autogenerated mock type for the APIKeyService type
+type APIKeyService struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: key
+func (_m *APIKeyService) Authenticate(key string) (*model.Principal, error) {
+	ret := _m.Called(key)
+
+	var r0 *model.Principal
+	if rf, ok := ret.Get(0).(func(string) *model.Principal); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Principal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAPIKey provides a mock function with given fields: principal, input
+func (_m *APIKeyService) CreateAPIKey(principal *model.Principal, input *model.APIKeyInput) (*model.APIKey, error) {
+	ret := _m.Called(principal, input)
+
+	var r0 *model.APIKey
+	if rf, ok := ret.Get(0).(func(*model.Principal, *model.APIKeyInput) *model.APIKey); ok {
+		r0 = rf(principal, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.APIKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Principal, *model.APIKeyInput) error); ok {
+		r1 = rf(principal, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeys provides a mock function with given fields: userID
+func (_m *APIKeyService) GetAPIKeys(userID int) ([]model.APIKey, error) {
+	ret := _m.Called(userID)
+
+	var r0 []model.APIKey
+	if rf, ok := ret.Get(0).(func(int) []model.APIKey); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.APIKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenameAPIKey provides a mock function with given fields: userID, ID, name
+func (_m *APIKeyService) RenameAPIKey(userID int, ID int, name string) error {
+	ret := _m.Called(userID, ID, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, string) error); ok {
+		r0 = rf(userID, ID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAPIKey provides a mock function with given fields: userID, ID, origin
+func (_m *APIKeyService) RevokeAPIKey(userID int, ID int, origin model.Origin) error {
+	ret := _m.Called(userID, ID, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, model.Origin) error); ok {
+		r0 = rf(userID, ID, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAPIKeyService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyService creates a new instance of APIKeyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyService(t mockConstructorTestingTNewAPIKeyService) *APIKeyService {
+	mock := &APIKeyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
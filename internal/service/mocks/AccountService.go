@@ -0,0 +1,163 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccountService is an autogenerated mock type for the AccountService type
+type AccountService struct {
+	mock.Mock
+}
+
+// ChangePassword provides a mock function with given fields: principal, input, origin
+func (_m *AccountService) ChangePassword(principal *model.Principal, input *model.ChangePasswordInput, origin model.Origin) error {
+	ret := _m.Called(principal, input, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, *model.ChangePasswordInput, model.Origin) error); ok {
+		r0 = rf(principal, input, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmEmailChange provides a mock function with given fields: token, origin
+func (_m *AccountService) ConfirmEmailChange(token string, origin model.Origin) error {
+	ret := _m.Called(token, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, model.Origin) error); ok {
+		r0 = rf(token, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAccount provides a mock function with given fields: principal, password, origin
+func (_m *AccountService) DeleteAccount(principal *model.Principal, password string, origin model.Origin) error {
+	ret := _m.Called(principal, password, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, string, model.Origin) error); ok {
+		r0 = rf(principal, password, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportAccount provides a mock function with given fields: userID
+func (_m *AccountService) ExportAccount(userID int) (*model.AccountExport, error) {
+	ret := _m.Called(userID)
+
+	var r0 *model.AccountExport
+	if rf, ok := ret.Get(0).(func(int) *model.AccountExport); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AccountExport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestEmailChange provides a mock function with given fields: principal, input
+func (_m *AccountService) RequestEmailChange(principal *model.Principal, input *model.ChangeEmailInput) error {
+	ret := _m.Called(principal, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, *model.ChangeEmailInput) error); ok {
+		r0 = rf(principal, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestPasswordReset provides a mock function with given fields: email
+func (_m *AccountService) RequestPasswordReset(email string) error {
+	ret := _m.Called(email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: token, password, origin
+func (_m *AccountService) ResetPassword(token string, password string, origin model.Origin) error {
+	ret := _m.Called(token, password, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, model.Origin) error); ok {
+		r0 = rf(token, password, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SignUp provides a mock function with given fields: user, origin
+func (_m *AccountService) SignUp(user *model.WebUser, origin model.Origin) error {
+	ret := _m.Called(user, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.WebUser, model.Origin) error); ok {
+		r0 = rf(user, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyEmail provides a mock function with given fields: token
+func (_m *AccountService) VerifyEmail(token string) error {
+	ret := _m.Called(token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAccountService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAccountService creates a new instance of AccountService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAccountService(t mockConstructorTestingTNewAccountService) *AccountService {
+	mock := &AccountService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditService is an autogenerated mock type for the AuditService type
+type AuditService struct {
+	mock.Mock
+}
+
+// ExportAuditEvents provides a mock function with given fields: actor, filter, origin, write
+func (_m *AuditService) ExportAuditEvents(actor *model.Principal, filter *model.AuditFilter, origin model.Origin, write func(*model.AuditEvent) error) error {
+	ret := _m.Called(actor, filter, origin, write)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, *model.AuditFilter, model.Origin, func(*model.AuditEvent) error) error); ok {
+		r0 = rf(actor, filter, origin, write)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAuditEvents provides a mock function with given fields: filter, page
+func (_m *AuditService) GetAuditEvents(filter *model.AuditFilter, page *model.PageRequest) (*model.AuditPage, error) {
+	ret := _m.Called(filter, page)
+
+	var r0 *model.AuditPage
+	if rf, ok := ret.Get(0).(func(*model.AuditFilter, *model.PageRequest) *model.AuditPage); ok {
+		r0 = rf(filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuditPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.AuditFilter, *model.PageRequest) error); ok {
+		r1 = rf(filter, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAuditService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuditService creates a new instance of AuditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuditService(t mockConstructorTestingTNewAuditService) *AuditService {
+	mock := &AuditService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -50,20 +50,20 @@ func (_m *ChannelService) GetChannelByName(name string) (*model.Channel, error)
 }
 
 // GetChannelsByPage provides a mock function with given fields: page
-func (_m *ChannelService) GetChannelsByPage(page int) ([]model.Channel, error) {
+func (_m *ChannelService) GetChannelsByPage(page *model.PageRequest) (*model.ChannelsPage, error) {
 	ret := _m.Called(page)
 
-	var r0 []model.Channel
-	if rf, ok := ret.Get(0).(func(int) []model.Channel); ok {
+	var r0 *model.ChannelsPage
+	if rf, ok := ret.Get(0).(func(*model.PageRequest) *model.ChannelsPage); ok {
 		r0 = rf(page)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Channel)
+			r0 = ret.Get(0).(*model.ChannelsPage)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
+	if rf, ok := ret.Get(1).(func(*model.PageRequest) error); ok {
 		r1 = rf(page)
 	} else {
 		r1 = ret.Error(1)
@@ -0,0 +1,121 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IngestService is an autogenerated mock type for the IngestService type
+type IngestService struct {
+	mock.Mock
+}
+
+// GetIngestFailuresByPage provides a mock function with given fields: page
+func (_m *IngestService) GetIngestFailuresByPage(page int) ([]model.IngestFailure, error) {
+	ret := _m.Called(page)
+
+	var r0 []model.IngestFailure
+	if rf, ok := ret.Get(0).(func(int) []model.IngestFailure); ok {
+		r0 = rf(page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.IngestFailure)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IngestChannel provides a mock function with given fields: channel
+func (_m *IngestService) IngestChannel(channel *model.ChannelDTO) error {
+	ret := _m.Called(channel)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.ChannelDTO) error); ok {
+		r0 = rf(channel)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IngestMessage provides a mock function with given fields: message
+func (_m *IngestService) IngestMessage(message *model.TgMessage) error {
+	ret := _m.Called(message)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.TgMessage) error); ok {
+		r0 = rf(message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IngestPayload provides a mock function with given fields: topic, payload
+func (_m *IngestService) IngestPayload(topic string, payload []byte) error {
+	ret := _m.Called(topic, payload)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []byte) error); ok {
+		r0 = rf(topic, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QuarantineIngestFailure provides a mock function with given fields: failure
+func (_m *IngestService) QuarantineIngestFailure(failure *model.IngestFailure) error {
+	ret := _m.Called(failure)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.IngestFailure) error); ok {
+		r0 = rf(failure)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplayIngestFailure provides a mock function with given fields: actor, ID, origin
+func (_m *IngestService) ReplayIngestFailure(actor *model.Principal, ID int, origin model.Origin) error {
+	ret := _m.Called(actor, ID, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, int, model.Origin) error); ok {
+		r0 = rf(actor, ID, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewIngestService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIngestService creates a new instance of IngestService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIngestService(t mockConstructorTestingTNewIngestService) *IngestService {
+	mock := &IngestService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
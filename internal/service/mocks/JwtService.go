@@ -2,27 +2,32 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // JwtService is an autogenerated mock type for the JwtService type
 type JwtService struct {
 	mock.Mock
 }
 
-// GenerateToken provides a mock function with given fields: userEmail
-func (_m *JwtService) GenerateToken(userEmail string) (string, error) {
-	ret := _m.Called(userEmail)
+// GenerateTokens provides a mock function with given fields: userID, userEmail, role
+func (_m *JwtService) GenerateTokens(userID int, userEmail string, role model.Role) (*model.Tokens, error) {
+	ret := _m.Called(userID, userEmail, role)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(userEmail)
+	var r0 *model.Tokens
+	if rf, ok := ret.Get(0).(func(int, string, model.Role) *model.Tokens); ok {
+		r0 = rf(userID, userEmail, role)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tokens)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(userEmail)
+	if rf, ok := ret.Get(1).(func(int, string, model.Role) error); ok {
+		r1 = rf(userID, userEmail, role)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -30,15 +35,61 @@ func (_m *JwtService) GenerateToken(userEmail string) (string, error) {
 	return r0, r1
 }
 
+// JWKS provides a mock function with given fields:
+func (_m *JwtService) JWKS() *model.JWKS {
+	ret := _m.Called()
+
+	var r0 *model.JWKS
+	if rf, ok := ret.Get(0).(func() *model.JWKS); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.JWKS)
+		}
+	}
+
+	return r0
+}
+
+// Logout provides a mock function with given fields: principal, origin
+func (_m *JwtService) Logout(principal *model.Principal, origin model.Origin) error {
+	ret := _m.Called(principal, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, model.Origin) error); ok {
+		r0 = rf(principal, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LogoutAll provides a mock function with given fields: principal, origin
+func (_m *JwtService) LogoutAll(principal *model.Principal, origin model.Origin) error {
+	ret := _m.Called(principal, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, model.Origin) error); ok {
+		r0 = rf(principal, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ParseToken provides a mock function with given fields: accessToken
-func (_m *JwtService) ParseToken(accessToken string) (string, error) {
+func (_m *JwtService) ParseToken(accessToken string) (*model.Principal, error) {
 	ret := _m.Called(accessToken)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
+	var r0 *model.Principal
+	if rf, ok := ret.Get(0).(func(string) *model.Principal); ok {
 		r0 = rf(accessToken)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Principal)
+		}
 	}
 
 	var r1 error
@@ -51,6 +102,29 @@ func (_m *JwtService) ParseToken(accessToken string) (string, error) {
 	return r0, r1
 }
 
+// RefreshTokens provides a mock function with given fields: refreshToken, origin
+func (_m *JwtService) RefreshTokens(refreshToken string, origin model.Origin) (*model.Tokens, error) {
+	ret := _m.Called(refreshToken, origin)
+
+	var r0 *model.Tokens
+	if rf, ok := ret.Get(0).(func(string, model.Origin) *model.Tokens); ok {
+		r0 = rf(refreshToken, origin)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tokens)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, model.Origin) error); ok {
+		r1 = rf(refreshToken, origin)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewJwtService interface {
 	mock.TestingT
 	Cleanup(func())
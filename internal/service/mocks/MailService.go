@@ -0,0 +1,46 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// MailService is an autogenerated mock type for the MailService type
+type MailService struct {
+	mock.Mock
+}
+
+// SendPendingMails provides a mock function with given fields: limit
+func (_m *MailService) SendPendingMails(limit int) (int, error) {
+	ret := _m.Called(limit)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int) int); ok {
+		r0 = rf(limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewMailService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMailService creates a new instance of MailService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMailService(t mockConstructorTestingTNewMailService) *MailService {
+	mock := &MailService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -56,45 +56,22 @@ func (_m *MessageService) GetFullMessageByID(ID int) (*model.FullMessage, error)
 	return r0, r1
 }
 
-// GetFullMessagesByChannelIDAndPage provides a mock function with given fields: ID, offset
-func (_m *MessageService) GetFullMessagesByChannelIDAndPage(ID int, offset int) ([]model.FullMessage, error) {
-	ret := _m.Called(ID, offset)
+// GetFullMessagesByPage provides a mock function with given fields: page, filter
+func (_m *MessageService) GetFullMessagesByPage(page *model.PageRequest, filter *model.MessageFilter) (*model.FullMessagesPage, error) {
+	ret := _m.Called(page, filter)
 
-	var r0 []model.FullMessage
-	if rf, ok := ret.Get(0).(func(int, int) []model.FullMessage); ok {
-		r0 = rf(ID, offset)
+	var r0 *model.FullMessagesPage
+	if rf, ok := ret.Get(0).(func(*model.PageRequest, *model.MessageFilter) *model.FullMessagesPage); ok {
+		r0 = rf(page, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.FullMessage)
-		}
-	}
-
-	var r1 error
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(ID, offset)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// GetFullMessagesByPage provides a mock function with given fields: offset
-func (_m *MessageService) GetFullMessagesByPage(offset int) ([]model.FullMessage, error) {
-	ret := _m.Called(offset)
-
-	var r0 []model.FullMessage
-	if rf, ok := ret.Get(0).(func(int) []model.FullMessage); ok {
-		r0 = rf(offset)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.FullMessage)
+			r0 = ret.Get(0).(*model.FullMessagesPage)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(offset)
+	if rf, ok := ret.Get(1).(func(*model.PageRequest, *model.MessageFilter) error); ok {
+		r1 = rf(page, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -125,41 +102,20 @@ func (_m *MessageService) GetFullMessagesByUserID(ID int) ([]model.FullMessage,
 	return r0, r1
 }
 
-// GetMessagesCount provides a mock function with given fields:
-func (_m *MessageService) GetMessagesCount() (int, error) {
-	ret := _m.Called()
+// GetMessagesCount provides a mock function with given fields: filter
+func (_m *MessageService) GetMessagesCount(filter *model.MessageFilter) (int, error) {
+	ret := _m.Called(filter)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func() int); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(*model.MessageFilter) int); ok {
+		r0 = rf(filter)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// GetMessagesCountByChannelID provides a mock function with given fields: ID
-func (_m *MessageService) GetMessagesCountByChannelID(ID int) (int, error) {
-	ret := _m.Called(ID)
-
-	var r0 int
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(ID)
-	} else {
-		r0 = ret.Get(0).(int)
-	}
-
-	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(ID)
+	if rf, ok := ret.Get(1).(func(*model.MessageFilter) error); ok {
+		r1 = rf(filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,72 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCService is an autogenerated mock type for the OIDCService type
+type OIDCService struct {
+	mock.Mock
+}
+
+// FinishLogin provides a mock function with given fields: code, state, origin
+func (_m *OIDCService) FinishLogin(code string, state string, origin model.Origin) (*model.Tokens, error) {
+	ret := _m.Called(code, state, origin)
+
+	var r0 *model.Tokens
+	if rf, ok := ret.Get(0).(func(string, string, model.Origin) *model.Tokens); ok {
+		r0 = rf(code, state, origin)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tokens)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, model.Origin) error); ok {
+		r1 = rf(code, state, origin)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartLogin provides a mock function with given fields:
+func (_m *OIDCService) StartLogin() (string, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewOIDCService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOIDCService creates a new instance of OIDCService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOIDCService(t mockConstructorTestingTNewOIDCService) *OIDCService {
+	mock := &OIDCService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -12,13 +12,41 @@ type SavedService struct {
 	mock.Mock
 }
 
-// CreateSavedMessage provides a mock function with given fields: savedMessage
-func (_m *SavedService) CreateSavedMessage(savedMessage *model.Saved) error {
-	ret := _m.Called(savedMessage)
+// CreateCollection provides a mock function with given fields: collection
+func (_m *SavedService) CreateCollection(collection *model.Collection) error {
+	ret := _m.Called(collection)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Saved) error); ok {
-		r0 = rf(savedMessage)
+	if rf, ok := ret.Get(0).(func(*model.Collection) error); ok {
+		r0 = rf(collection)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSavedMessage provides a mock function with given fields: savedMessage, origin
+func (_m *SavedService) CreateSavedMessage(savedMessage *model.Saved, origin model.Origin) error {
+	ret := _m.Called(savedMessage, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Saved, model.Origin) error); ok {
+		r0 = rf(savedMessage, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCollection provides a mock function with given fields: userID, ID
+func (_m *SavedService) DeleteCollection(userID int, ID int) error {
+	ret := _m.Called(userID, ID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, ID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +54,13 @@ func (_m *SavedService) CreateSavedMessage(savedMessage *model.Saved) error {
 	return r0
 }
 
-// DeleteSavedMessage provides a mock function with given fields: ID
-func (_m *SavedService) DeleteSavedMessage(ID int) error {
-	ret := _m.Called(ID)
+// DeleteSavedMessage provides a mock function with given fields: userID, ID, origin
+func (_m *SavedService) DeleteSavedMessage(userID int, ID int, origin model.Origin) error {
+	ret := _m.Called(userID, ID, origin)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(ID)
+	if rf, ok := ret.Get(0).(func(int, int, model.Origin) error); ok {
+		r0 = rf(userID, ID, origin)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -40,22 +68,45 @@ func (_m *SavedService) DeleteSavedMessage(ID int) error {
 	return r0
 }
 
-// GetSavedMessages provides a mock function with given fields: ID
-func (_m *SavedService) GetSavedMessages(ID int) ([]model.Saved, error) {
-	ret := _m.Called(ID)
+// GetCollections provides a mock function with given fields: userID
+func (_m *SavedService) GetCollections(userID int) ([]model.Collection, error) {
+	ret := _m.Called(userID)
 
-	var r0 []model.Saved
-	if rf, ok := ret.Get(0).(func(int) []model.Saved); ok {
-		r0 = rf(ID)
+	var r0 []model.Collection
+	if rf, ok := ret.Get(0).(func(int) []model.Collection); ok {
+		r0 = rf(userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Saved)
+			r0 = ret.Get(0).([]model.Collection)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(ID)
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSavedMessages provides a mock function with given fields: ID, filter, page
+func (_m *SavedService) GetSavedMessages(ID int, filter *model.SavedFilter, page *model.PageRequest) (*model.SavedPage, error) {
+	ret := _m.Called(ID, filter, page)
+
+	var r0 *model.SavedPage
+	if rf, ok := ret.Get(0).(func(int, *model.SavedFilter, *model.PageRequest) *model.SavedPage); ok {
+		r0 = rf(ID, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SavedPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, *model.SavedFilter, *model.PageRequest) error); ok {
+		r1 = rf(ID, filter, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -63,6 +114,34 @@ func (_m *SavedService) GetSavedMessages(ID int) ([]model.Saved, error) {
 	return r0, r1
 }
 
+// UpdateCollection provides a mock function with given fields: collection
+func (_m *SavedService) UpdateCollection(collection *model.Collection) error {
+	ret := _m.Called(collection)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Collection) error); ok {
+		r0 = rf(collection)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSavedMessage provides a mock function with given fields: savedMessage
+func (_m *SavedService) UpdateSavedMessage(savedMessage *model.Saved) error {
+	ret := _m.Called(savedMessage)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Saved) error); ok {
+		r0 = rf(savedMessage)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewSavedService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,51 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SearchService is an autogenerated mock type for the SearchService type
+type SearchService struct {
+	mock.Mock
+}
+
+// SearchMessages provides a mock function with given fields: query, lang, page, filter
+func (_m *SearchService) SearchMessages(query string, lang string, page int, filter *model.MessageFilter) ([]model.SearchResult, error) {
+	ret := _m.Called(query, lang, page, filter)
+
+	var r0 []model.SearchResult
+	if rf, ok := ret.Get(0).(func(string, string, int, *model.MessageFilter) []model.SearchResult); ok {
+		r0 = rf(query, lang, page, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.SearchResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, int, *model.MessageFilter) error); ok {
+		r1 = rf(query, lang, page, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewSearchService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSearchService creates a new instance of SearchService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSearchService(t mockConstructorTestingTNewSearchService) *SearchService {
+	mock := &SearchService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,271 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ShareService is an autogenerated mock type for the ShareService type
+type ShareService struct {
+	mock.Mock
+}
+
+// AddShareItems provides a mock function with given fields: shareID, userID, savedIDs
+func (_m *ShareService) AddShareItems(shareID int, userID int, savedIDs []int) (int, error) {
+	ret := _m.Called(shareID, userID, savedIDs)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, int, []int) int); ok {
+		r0 = rf(shareID, userID, savedIDs)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, []int) error); ok {
+		r1 = rf(shareID, userID, savedIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateShare provides a mock function with given fields: userID, input
+func (_m *ShareService) CreateShare(userID int, input *model.ShareInput) (*model.Share, error) {
+	ret := _m.Called(userID, input)
+
+	var r0 *model.Share
+	if rf, ok := ret.Get(0).(func(int, *model.ShareInput) *model.Share); ok {
+		r0 = rf(userID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Share)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, *model.ShareInput) error); ok {
+		r1 = rf(userID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteShare provides a mock function with given fields: ID
+func (_m *ShareService) DeleteShare(ID int) error {
+	ret := _m.Called(ID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteShareItem provides a mock function with given fields: shareID, messageID
+func (_m *ShareService) DeleteShareItem(shareID int, messageID int) error {
+	ret := _m.Called(shareID, messageID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(shareID, messageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPublicSharedMessages provides a mock function with given fields: token
+func (_m *ShareService) GetPublicSharedMessages(token string) (*model.SharedMessages, error) {
+	ret := _m.Called(token)
+
+	var r0 *model.SharedMessages
+	if rf, ok := ret.Get(0).(func(string) *model.SharedMessages); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SharedMessages)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShareAccess provides a mock function with given fields: ID, userID
+func (_m *ShareService) GetShareAccess(ID int, userID int) (*model.Share, error) {
+	ret := _m.Called(ID, userID)
+
+	var r0 *model.Share
+	if rf, ok := ret.Get(0).(func(int, int) *model.Share); ok {
+		r0 = rf(ID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Share)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(ID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShareMembers provides a mock function with given fields: shareID
+func (_m *ShareService) GetShareMembers(shareID int) ([]model.ShareMember, error) {
+	ret := _m.Called(shareID)
+
+	var r0 []model.ShareMember
+	if rf, ok := ret.Get(0).(func(int) []model.ShareMember); ok {
+		r0 = rf(shareID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ShareMember)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(shareID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSharedMessages provides a mock function with given fields: share
+func (_m *ShareService) GetSharedMessages(share *model.Share) (*model.SharedMessages, error) {
+	ret := _m.Called(share)
+
+	var r0 *model.SharedMessages
+	if rf, ok := ret.Get(0).(func(*model.Share) *model.SharedMessages); ok {
+		r0 = rf(share)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SharedMessages)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Share) error); ok {
+		r1 = rf(share)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShares provides a mock function with given fields: userID
+func (_m *ShareService) GetShares(userID int) ([]model.Share, error) {
+	ret := _m.Called(userID)
+
+	var r0 []model.Share
+	if rf, ok := ret.Get(0).(func(int) []model.Share); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Share)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantShareMember provides a mock function with given fields: ownerID, member
+func (_m *ShareService) GrantShareMember(ownerID int, member *model.ShareMember) error {
+	ret := _m.Called(ownerID, member)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, *model.ShareMember) error); ok {
+		r0 = rf(ownerID, member)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeShareLink provides a mock function with given fields: ID
+func (_m *ShareService) RevokeShareLink(ID int) error {
+	ret := _m.Called(ID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeShareMember provides a mock function with given fields: shareID, userID
+func (_m *ShareService) RevokeShareMember(shareID int, userID int) error {
+	ret := _m.Called(shareID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(shareID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateShareLink provides a mock function with given fields: share
+func (_m *ShareService) RotateShareLink(share *model.Share) error {
+	ret := _m.Called(share)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Share) error); ok {
+		r0 = rf(share)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewShareService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewShareService creates a new instance of ShareService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewShareService(t mockConstructorTestingTNewShareService) *ShareService {
+	mock := &ShareService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SignInService is an autogenerated mock type for the SignInService type
+type SignInService struct {
+	mock.Mock
+}
+
+// SignIn provides a mock function with given fields: input, origin
+func (_m *SignInService) SignIn(input *model.SignInInput, origin model.Origin) (*model.Tokens, error) {
+	ret := _m.Called(input, origin)
+
+	var r0 *model.Tokens
+	if rf, ok := ret.Get(0).(func(*model.SignInInput, model.Origin) *model.Tokens); ok {
+		r0 = rf(input, origin)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tokens)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.SignInInput, model.Origin) error); ok {
+		r1 = rf(input, origin)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnlockSignIn provides a mock function with given fields: actor, input, origin
+func (_m *SignInService) UnlockSignIn(actor *model.Principal, input *model.UnlockSignInInput, origin model.Origin) error {
+	ret := _m.Called(actor, input, origin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, *model.UnlockSignInInput, model.Origin) error); ok {
+		r0 = rf(actor, input, origin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSignInService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSignInService creates a new instance of SignInService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSignInService(t mockConstructorTestingTNewSignInService) *SignInService {
+	mock := &SignInService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
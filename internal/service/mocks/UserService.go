@@ -12,27 +12,6 @@ type UserService struct {
 	mock.Mock
 }
 
-// CreateUser provides a mock function with given fields: user
-func (_m *UserService) CreateUser(user *model.UserDTO) (int, error) {
-	ret := _m.Called(user)
-
-	var r0 int
-	if rf, ok := ret.Get(0).(func(*model.UserDTO) int); ok {
-		r0 = rf(user)
-	} else {
-		r0 = ret.Get(0).(int)
-	}
-
-	var r1 error
-	if rf, ok := ret.Get(1).(func(*model.UserDTO) error); ok {
-		r1 = rf(user)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // GetUserByID provides a mock function with given fields: ID
 func (_m *UserService) GetUserByID(ID int) (*model.User, error) {
 	ret := _m.Called(ID)
@@ -79,6 +58,27 @@ func (_m *UserService) GetUserByUsername(username string) (*model.User, error) {
 	return r0, r1
 }
 
+// UpsertUser provides a mock function with given fields: user
+func (_m *UserService) UpsertUser(user *model.UserDTO) (int, error) {
+	ret := _m.Called(user)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.UserDTO) int); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.UserDTO) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewUserService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -12,6 +12,27 @@ type WebUserService struct {
 	mock.Mock
 }
 
+// BootstrapAdmin provides a mock function with given fields: email, password, origin
+func (_m *WebUserService) BootstrapAdmin(email string, password string, origin model.Origin) (bool, error) {
+	ret := _m.Called(email, password, origin)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, model.Origin) bool); ok {
+		r0 = rf(email, password, origin)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, model.Origin) error); ok {
+		r1 = rf(email, password, origin)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ComparePassword provides a mock function with given fields: password, HashPassword
 func (_m *WebUserService) ComparePassword(password string, HashPassword string) bool {
 	ret := _m.Called(password, HashPassword)
@@ -26,13 +47,13 @@ func (_m *WebUserService) ComparePassword(password string, HashPassword string)
 	return r0
 }
 
-// CreateWebUser provides a mock function with given fields: user
-func (_m *WebUserService) CreateWebUser(user *model.WebUser) error {
-	ret := _m.Called(user)
+// CreateWebUser provides a mock function with given fields: user, origin
+func (_m *WebUserService) CreateWebUser(user *model.WebUser, origin model.Origin) error {
+	ret := _m.Called(user, origin)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.WebUser) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(*model.WebUser, model.Origin) error); ok {
+		r0 = rf(user, origin)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -63,20 +84,20 @@ func (_m *WebUserService) GetWebUserByEmail(email string) (*model.WebUser, error
 	return r0, r1
 }
 
-// HashPassword provides a mock function with given fields: passoword
-func (_m *WebUserService) HashPassword(passoword string) (string, error) {
-	ret := _m.Called(passoword)
+// HashPassword provides a mock function with given fields: password
+func (_m *WebUserService) HashPassword(password string) (string, error) {
+	ret := _m.Called(password)
 
 	var r0 string
 	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(passoword)
+		r0 = rf(password)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(passoword)
+		r1 = rf(password)
 	} else {
 		r1 = ret.Error(1)
 	}
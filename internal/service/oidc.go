@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/oidc"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/internal/validation"
+	"github.com/VladPetriv/scanner_backend_api/pkg/config"
+)
+
+const (
+	// oidcSecretSize is number of random bytes in state, nonce and PKCE code verifier of sso login.
+	oidcSecretSize = 32
+
+	// oidcLoginTTL is time user has to sign in at identity provider after sso login was started.
+	oidcLoginTTL = 10 * time.Minute
+)
+
+var (
+	ErrOIDCNotConfigured = errors.New("sign-in with identity provider is not configured")
+	ErrOIDCConfig        = errors.New("oidc client id and redirect url are required when oidc issuer is set")
+	ErrInvalidOIDCState  = errors.New("sign-in with identity provider is expired or already finished")
+	ErrOIDCEmailRequired = errors.New("identity provider didn't share valid email of the account")
+	ErrOIDCAccountExists = errors.New("account with this email exists, sign in with password and verify email to link it")
+)
+
+type OIDCDBService struct {
+	store    *store.Store
+	jwt      JwtService
+	provider *oidc.Provider
+}
+
+// NewOIDCProvider returns client of identity provider from config or nil when sso isn't configured.
+// Scopes are separated by spaces, default ones are used when they're not set.
+func NewOIDCProvider(cfg *config.Config) (*oidc.Provider, error) {
+	if cfg.OIDCIssuer == "" {
+		return nil, nil
+	}
+
+	if cfg.OIDCClientID == "" || cfg.OIDCRedirectURL == "" {
+		return nil, ErrOIDCConfig
+	}
+
+	return oidc.NewProvider(oidc.Config{
+		Issuer:       cfg.OIDCIssuer,
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCClientSecret,
+		RedirectURL:  cfg.OIDCRedirectURL,
+		Scopes:       strings.Fields(cfg.OIDCScopes),
+	}, nil), nil
+}
+
+// NewOIDCService creates service of sign-in with identity provider, every call fails with ErrOIDCNotConfigured when provider is nil.
+func NewOIDCService(store *store.Store, jwt JwtService, provider *oidc.Provider) *OIDCDBService {
+	return &OIDCDBService{store: store, jwt: jwt, provider: provider}
+}
+
+// StartLogin starts sso login and returns url of identity provider sign-in page.
+// Only hash of state is stored, nonce and code verifier never leave the server.
+func (o *OIDCDBService) StartLogin() (string, error) {
+	if o.provider == nil {
+		return "", fmt.Errorf("[OIDC] srv.StartLogin error: %w", ErrOIDCNotConfigured)
+	}
+
+	secrets := make([]string, 3)
+	for i := range secrets {
+		secret, err := newRandomToken(oidcSecretSize)
+		if err != nil {
+			return "", fmt.Errorf("[OIDC] srv.StartLogin error: %w", err)
+		}
+
+		secrets[i] = secret
+	}
+
+	state, nonce, codeVerifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err := o.provider.AuthCodeURL(state, nonce, codeVerifier)
+	if err != nil {
+		return "", fmt.Errorf("[OIDC] srv.StartLogin error: %w", err)
+	}
+
+	err = o.store.OIDC.CreateOIDCLogin(&model.OIDCLogin{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(oidcLoginTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("[OIDC] srv.StartLogin error: %w", err)
+	}
+
+	return authURL, nil
+}
+
+// FinishLogin exchanges authorization code of started sso login for id token and returns tokens
+// of web user linked to the identity. Unknown identity is linked to web user with the same email when
+// both identity provider and the app verified it, otherwise new member is created just in time.
+// Sign-in and sign-up of created member are recorded in audit trail.
+func (o *OIDCDBService) FinishLogin(code, state string, origin model.Origin) (*model.Tokens, error) {
+	if o.provider == nil {
+		return nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w", ErrOIDCNotConfigured)
+	}
+
+	login, err := o.store.OIDC.UseOIDCLogin(hashToken(state))
+	if errors.Is(err, pg.ErrOIDCLoginNotFound) {
+		return nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w", ErrInvalidOIDCState)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w", err)
+	}
+
+	identity, err := o.provider.Exchange(code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w", err)
+	}
+
+	user, err := o.linkIdentity(identity, origin)
+	if err != nil {
+		return nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w", err)
+	}
+
+	tokens, err := o.jwt.GenerateTokens(user.ID, user.Email, user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w", err)
+	}
+
+	err = recordAuditEvent(o.store, auditEntry{
+		ActorID: &user.ID,
+		Action:  model.AuditSignInSucceeded,
+		Origin:  origin,
+		Details: map[string]string{"method": "oidc", "issuer": identity.Issuer},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[OIDC] srv.FinishLogin error: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// linkIdentity returns web user linked to identity, linking or creating one when there is none.
+func (o *OIDCDBService) linkIdentity(identity *oidc.Identity, origin model.Origin) (*model.WebUser, error) {
+	user, err := o.store.OIDC.GetWebUserByIdentity(identity.Issuer, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+
+	if !errors.Is(err, pg.ErrWebUserNotFound) {
+		return nil, err
+	}
+
+	email, err := validation.NormalizeEmail(identity.Email)
+	if err != nil {
+		return nil, ErrOIDCEmailRequired
+	}
+
+	link := &model.WebUserIdentity{Issuer: identity.Issuer, Subject: identity.Subject}
+
+	user, err = o.store.WebUser.GetWebUserByEmail(email)
+	if err == nil {
+		// linking unverified email would let anybody who signed up with it first take over the account
+		if !identity.EmailVerified || user.EmailVerifiedAt == nil {
+			return nil, ErrOIDCAccountExists
+		}
+
+		link.UserID = user.ID
+
+		err = o.store.InTx(func(tx *store.Store) error {
+			_, err := tx.OIDC.CreateWebUserIdentity(link)
+			if err != nil {
+				return err
+			}
+
+			return recordAuditEvent(tx, auditEntry{
+				ActorID: &user.ID,
+				Action:  model.AuditIdentityLinked,
+				Origin:  origin,
+				After:   map[string]interface{}{"userId": user.ID, "issuer": link.Issuer, "subject": link.Subject},
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return user, nil
+	}
+
+	if !errors.Is(err, pg.ErrWebUserNotFound) {
+		return nil, err
+	}
+
+	// users from identity provider have no password, they can set one with password reset
+	user = &model.WebUser{Email: email, Role: model.RoleMember}
+	if identity.EmailVerified {
+		verifiedAt := time.Now()
+		user.EmailVerifiedAt = &verifiedAt
+	}
+
+	err = o.store.InTx(func(tx *store.Store) error {
+		ID, err := tx.OIDC.CreateWebUserWithIdentity(user, link)
+		if err != nil {
+			return err
+		}
+
+		user.ID = ID
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &user.ID,
+			Action:  model.AuditSignUp,
+			Origin:  origin,
+			Details: map[string]string{"method": "oidc", "issuer": identity.Issuer},
+			After:   map[string]interface{}{"id": user.ID, "email": user.Email, "role": user.Role},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,264 @@
+package service_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/oidc"
+	"github.com/VladPetriv/scanner_backend_api/internal/oidc/oidctest"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	srvmocks "github.com/VladPetriv/scanner_backend_api/internal/service/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_OIDCLogin(t *testing.T) {
+	server, err := oidctest.NewServer("scanner", "secret")
+	if err != nil {
+		t.Fatalf("failed to start identity provider: %s", err)
+	}
+	defer server.Close()
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "scanner",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/auth/oidc/callback",
+	}, nil)
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	tokens := &model.Tokens{AccessToken: "access-token", RefreshToken: "refresh-token", ExpiresIn: 900}
+	oidcDetails := fmt.Sprintf(`{"issuer":%q,"method":"oidc"}`, server.Issuer())
+	linked := fmt.Sprintf(`{"issuer":%q,"subject":"1001","userId":1}`, server.Issuer())
+	createIdentityQuery := "INSERT INTO web_user_identity(user_id, issuer, subject) VALUES ($1, $2, $3) RETURNING id;"
+	createWithIdentityQuery := `WITH new_user AS (
+			INSERT INTO web_user(email, password, role, email_verified_at) VALUES ($1, $2, $3, $4) RETURNING id
+		), new_identity AS (
+			INSERT INTO web_user_identity(user_id, issuer, subject)
+			SELECT id, $5, $6 FROM new_user
+		)
+		SELECT id FROM new_user;`
+
+	tests := []struct {
+		name           string
+		user           oidctest.User
+		mock           func(oidcRepo *mocks.OIDCRepo, webUserRepo *mocks.WebUserRepo, auditRepo *mocks.AuditRepo, jwtSrv *srvmocks.JwtService, dbMock sqlmock.Sqlmock)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [linked user signed in]",
+			user: oidctest.User{Subject: "1001", Email: "test@test.com", EmailVerified: true},
+			mock: func(oidcRepo *mocks.OIDCRepo, webUserRepo *mocks.WebUserRepo, auditRepo *mocks.AuditRepo, jwtSrv *srvmocks.JwtService, dbMock sqlmock.Sqlmock) {
+				oidcRepo.On("GetWebUserByIdentity", server.Issuer(), "1001").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com", Role: model.RoleAdmin}, nil)
+				jwtSrv.On("GenerateTokens", 1, "test@test.com", model.RoleAdmin).Return(tokens, nil)
+				auditRepo.On("CreateAuditEvent", auditEvent(intPtr(1), model.AuditSignInSucceeded, oidcDetails)).Return(1, nil)
+			},
+		},
+		{
+			name: "Ok: [new user provisioned]",
+			user: oidctest.User{Subject: "1001", Email: "New@Example.COM", EmailVerified: true},
+			mock: func(oidcRepo *mocks.OIDCRepo, webUserRepo *mocks.WebUserRepo, auditRepo *mocks.AuditRepo, jwtSrv *srvmocks.JwtService, dbMock sqlmock.Sqlmock) {
+				oidcRepo.On("GetWebUserByIdentity", server.Issuer(), "1001").Return(nil, pg.ErrWebUserNotFound)
+				webUserRepo.On("GetWebUserByEmail", "New@example.com").Return(nil, pg.ErrWebUserNotFound)
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery(createWithIdentityQuery).
+					WithArgs("New@example.com", "", model.RoleMember, sqlmock.AnyArg(), server.Issuer(), "1001").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				expectAuditEvent(dbMock, 5, model.AuditSignUp, oidcDetails, nil, `{"email":"New@example.com","id":5,"role":"member"}`)
+				dbMock.ExpectCommit()
+				jwtSrv.On("GenerateTokens", 5, "New@example.com", model.RoleMember).Return(tokens, nil)
+				auditRepo.On("CreateAuditEvent", auditEvent(intPtr(5), model.AuditSignInSucceeded, oidcDetails)).Return(2, nil)
+			},
+		},
+		{
+			name: "Ok: [verified user with the same email linked]",
+			user: oidctest.User{Subject: "1001", Email: "test@test.com", EmailVerified: true},
+			mock: func(oidcRepo *mocks.OIDCRepo, webUserRepo *mocks.WebUserRepo, auditRepo *mocks.AuditRepo, jwtSrv *srvmocks.JwtService, dbMock sqlmock.Sqlmock) {
+				oidcRepo.On("GetWebUserByIdentity", server.Issuer(), "1001").Return(nil, pg.ErrWebUserNotFound)
+				webUserRepo.On("GetWebUserByEmail", "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com", Role: model.RoleMember, EmailVerifiedAt: &verifiedAt}, nil)
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery(createIdentityQuery).
+					WithArgs(1, server.Issuer(), "1001").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				expectAuditEvent(dbMock, 1, model.AuditIdentityLinked, "", nil, linked)
+				dbMock.ExpectCommit()
+				jwtSrv.On("GenerateTokens", 1, "test@test.com", model.RoleMember).Return(tokens, nil)
+				auditRepo.On("CreateAuditEvent", auditEvent(intPtr(1), model.AuditSignInSucceeded, oidcDetails)).Return(1, nil)
+			},
+		},
+		{
+			name: "Error: [identity linked by concurrent login]",
+			user: oidctest.User{Subject: "1001", Email: "test@test.com", EmailVerified: true},
+			mock: func(oidcRepo *mocks.OIDCRepo, webUserRepo *mocks.WebUserRepo, auditRepo *mocks.AuditRepo, jwtSrv *srvmocks.JwtService, dbMock sqlmock.Sqlmock) {
+				oidcRepo.On("GetWebUserByIdentity", server.Issuer(), "1001").Return(nil, pg.ErrWebUserNotFound)
+				webUserRepo.On("GetWebUserByEmail", "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com", Role: model.RoleMember, EmailVerifiedAt: &verifiedAt}, nil)
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery(createIdentityQuery).
+					WithArgs(1, server.Issuer(), "1001").WillReturnError(&pq.Error{Code: "23505"})
+				dbMock.ExpectRollback()
+			},
+			wantErr:        true,
+			expectedErrMsg: "[OIDC] srv.FinishLogin error: identity is already linked to web user",
+		},
+		{
+			name: "Error: [user with the same email didn't verify it]",
+			user: oidctest.User{Subject: "1001", Email: "test@test.com", EmailVerified: true},
+			mock: func(oidcRepo *mocks.OIDCRepo, webUserRepo *mocks.WebUserRepo, auditRepo *mocks.AuditRepo, jwtSrv *srvmocks.JwtService, dbMock sqlmock.Sqlmock) {
+				oidcRepo.On("GetWebUserByIdentity", server.Issuer(), "1001").Return(nil, pg.ErrWebUserNotFound)
+				webUserRepo.On("GetWebUserByEmail", "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com", Role: model.RoleMember}, nil)
+			},
+			wantErr:        true,
+			expectedErrMsg: "[OIDC] srv.FinishLogin error: account with this email exists, sign in with password and verify email to link it",
+		},
+		{
+			name: "Error: [identity provider didn't verify email]",
+			user: oidctest.User{Subject: "1001", Email: "test@test.com"},
+			mock: func(oidcRepo *mocks.OIDCRepo, webUserRepo *mocks.WebUserRepo, auditRepo *mocks.AuditRepo, jwtSrv *srvmocks.JwtService, dbMock sqlmock.Sqlmock) {
+				oidcRepo.On("GetWebUserByIdentity", server.Issuer(), "1001").Return(nil, pg.ErrWebUserNotFound)
+				webUserRepo.On("GetWebUserByEmail", "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com", Role: model.RoleMember, EmailVerifiedAt: &verifiedAt}, nil)
+			},
+			wantErr:        true,
+			expectedErrMsg: "[OIDC] srv.FinishLogin error: account with this email exists, sign in with password and verify email to link it",
+		},
+		{
+			name: "Error: [identity without email]",
+			user: oidctest.User{Subject: "1001"},
+			mock: func(oidcRepo *mocks.OIDCRepo, webUserRepo *mocks.WebUserRepo, auditRepo *mocks.AuditRepo, jwtSrv *srvmocks.JwtService, dbMock sqlmock.Sqlmock) {
+				oidcRepo.On("GetWebUserByIdentity", server.Issuer(), "1001").Return(nil, pg.ErrWebUserNotFound)
+			},
+			wantErr:        true,
+			expectedErrMsg: "[OIDC] srv.FinishLogin error: identity provider didn't share valid email of the account",
+		},
+		{
+			name: "Error: [some store error]",
+			user: oidctest.User{Subject: "1001", Email: "test@test.com"},
+			mock: func(oidcRepo *mocks.OIDCRepo, webUserRepo *mocks.WebUserRepo, auditRepo *mocks.AuditRepo, jwtSrv *srvmocks.JwtService, dbMock sqlmock.Sqlmock) {
+				oidcRepo.On("GetWebUserByIdentity", server.Issuer(), "1001").
+					Return(nil, fmt.Errorf("failed to get web user by identity: some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[OIDC] srv.FinishLogin error: failed to get web user by identity: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oidcRepo := &mocks.OIDCRepo{}
+			webUserRepo := &mocks.WebUserRepo{}
+			auditRepo := &mocks.AuditRepo{}
+			jwtSrv := &srvmocks.JwtService{}
+			stores, dbMock := newTxStore(t)
+			stores.OIDC = oidcRepo
+			stores.WebUser = webUserRepo
+			stores.Audit = auditRepo
+			srv := service.NewOIDCService(stores, jwtSrv, provider)
+
+			var login *model.OIDCLogin
+			oidcRepo.On("CreateOIDCLogin", mock.AnythingOfType("*model.OIDCLogin")).
+				Run(func(args mock.Arguments) { login = args.Get(0).(*model.OIDCLogin) }).
+				Return(nil)
+
+			server.SignIn(tt.user)
+
+			authURL, err := srv.StartLogin()
+			assert.NoError(t, err)
+
+			callback, err := server.Authorize(authURL)
+			assert.NoError(t, err)
+
+			state := callback.Query().Get("state")
+			assert.Equal(t, login.StateHash, fmt.Sprintf("%x", sha256.Sum256([]byte(state))))
+			assert.WithinDuration(t, time.Now().Add(10*time.Minute), login.ExpiresAt, time.Minute)
+
+			oidcRepo.On("UseOIDCLogin", login.StateHash).Return(login, nil)
+			tt.mock(oidcRepo, webUserRepo, auditRepo, jwtSrv, dbMock)
+
+			got, err := srv.FinishLogin(callback.Query().Get("code"), state, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tokens, got)
+			}
+
+			oidcRepo.AssertExpectations(t)
+			webUserRepo.AssertExpectations(t)
+			auditRepo.AssertExpectations(t)
+			jwtSrv.AssertExpectations(t)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_FinishOIDCLogin(t *testing.T) {
+	server, err := oidctest.NewServer("scanner", "")
+	if err != nil {
+		t.Fatalf("failed to start identity provider: %s", err)
+	}
+	defer server.Close()
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:      server.Issuer(),
+		ClientID:    "scanner",
+		RedirectURL: "http://localhost:3000/auth/oidc/callback",
+	}, nil)
+
+	tests := []struct {
+		name           string
+		provider       *oidc.Provider
+		mock           func(oidcRepo *mocks.OIDCRepo)
+		expectedErrMsg string
+	}{
+		{
+			name:     "Error: [login is expired or already finished]",
+			provider: provider,
+			mock: func(oidcRepo *mocks.OIDCRepo) {
+				oidcRepo.On("UseOIDCLogin", mock.AnythingOfType("string")).Return(nil, pg.ErrOIDCLoginNotFound)
+			},
+			expectedErrMsg: "[OIDC] srv.FinishLogin error: sign-in with identity provider is expired or already finished",
+		},
+		{
+			name:     "Error: [authorization code is not valid]",
+			provider: provider,
+			mock: func(oidcRepo *mocks.OIDCRepo) {
+				oidcRepo.On("UseOIDCLogin", mock.AnythingOfType("string")).
+					Return(&model.OIDCLogin{Nonce: "nonce", CodeVerifier: "code-verifier"}, nil)
+			},
+			expectedErrMsg: "[OIDC] srv.FinishLogin error: failed to exchange authorization code: invalid_grant",
+		},
+		{
+			name:           "Error: [sso is not configured]",
+			mock:           func(oidcRepo *mocks.OIDCRepo) {},
+			expectedErrMsg: "[OIDC] srv.FinishLogin error: sign-in with identity provider is not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oidcRepo := &mocks.OIDCRepo{}
+			srv := service.NewOIDCService(&store.Store{OIDC: oidcRepo}, &srvmocks.JwtService{}, tt.provider)
+
+			tt.mock(oidcRepo)
+
+			got, err := srv.FinishLogin("code", "state", testOrigin)
+			assert.Nil(t, got)
+			assert.EqualError(t, err, tt.expectedErrMsg)
+
+			oidcRepo.AssertExpectations(t)
+		})
+	}
+}
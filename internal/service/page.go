@@ -0,0 +1,132 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+const (
+	DefaultPageLimit = 10
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("cursor is not valid")
+	ErrInvalidLimit  = errors.New("limit is not valid")
+)
+
+// NewPageRequest decodes cursor from previous page and bounds limit by MaxPageLimit.
+// Empty cursor requests the first page and zero limit requests DefaultPageLimit rows.
+func NewPageRequest(cursor string, limit int) (*model.PageRequest, error) {
+	if limit < 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	page := &model.PageRequest{Limit: limit}
+	if cursor == "" {
+		return page, nil
+	}
+
+	decoded, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	page.Cursor = decoded
+
+	return page, nil
+}
+
+// encodeCursor packs cursor into opaque url safe string in "direction:posted at:id" format.
+func encodeCursor(cursor model.Cursor) string {
+	direction := "f"
+	if cursor.Backward {
+		direction = "b"
+	}
+
+	var postedAt string
+	if !cursor.PostedAt.IsZero() {
+		postedAt = strconv.FormatInt(cursor.PostedAt.UnixNano(), 10)
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s:%d", direction, postedAt, cursor.ID)))
+}
+
+func decodeCursor(value string) (*model.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(string(data), ":")
+	if len(parts) != 3 || (parts[0] != "f" && parts[0] != "b") {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &model.Cursor{Backward: parts[0] == "b"}
+
+	if parts[1] != "" {
+		nanos, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		cursor.PostedAt = time.Unix(0, nanos).UTC()
+	}
+
+	cursor.ID, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return cursor, nil
+}
+
+// paginate trims extra row which store fetches to detect further rows and restores listing order of backward page.
+// It returns number of rows to keep and cursors around them, swap and cursorAt work with rows as returned by store.
+func paginate(page *model.PageRequest, fetched int, swap func(i, j int), cursorAt func(i int) model.Cursor) (int, model.PageInfo) {
+	kept := fetched
+	info := model.PageInfo{HasMore: fetched > page.Limit}
+
+	if info.HasMore {
+		kept = page.Limit
+	}
+
+	backward := page.IsBackward()
+	if backward {
+		for i, j := 0, kept-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	if kept == 0 {
+		return 0, info
+	}
+
+	if backward || info.HasMore {
+		info.Next = encodeCursor(cursorAt(kept - 1))
+	}
+
+	if (backward && info.HasMore) || (!backward && page.Cursor != nil) {
+		first := cursorAt(0)
+		first.Backward = true
+
+		info.Prev = encodeCursor(first)
+	}
+
+	return kept, info
+}
@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewPageRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		cursor         string
+		limit          int
+		want           *model.PageRequest
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [first page with default limit]",
+			want: &model.PageRequest{Limit: 10},
+		},
+		{
+			name:  "Ok: [limit is bounded by maximum]",
+			limit: 500,
+			want:  &model.PageRequest{Limit: 100},
+		},
+		{
+			name:   "Ok: [cursor with posted at decoded]",
+			cursor: "ZjoxNjU2NjMzNjAwMDAwMDAwMDAwOjI",
+			limit:  20,
+			want: &model.PageRequest{
+				Cursor: &model.Cursor{PostedAt: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), ID: 2},
+				Limit:  20,
+			},
+		},
+		{
+			name:   "Ok: [backward cursor decoded]",
+			cursor: "Yjo6MTE",
+			want:   &model.PageRequest{Cursor: &model.Cursor{ID: 11, Backward: true}, Limit: 10},
+		},
+		{
+			name:           "Error: [cursor is not valid]",
+			cursor:         "hello",
+			wantErr:        true,
+			expectedErrMsg: "cursor is not valid",
+		},
+		{
+			name:           "Error: [limit is not valid]",
+			limit:          -1,
+			wantErr:        true,
+			expectedErrMsg: "limit is not valid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.NewPageRequest(tt.cursor, tt.limit)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+		})
+	}
+}
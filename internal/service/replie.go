@@ -24,11 +24,17 @@ func (r *ReplieDBService) CreateReplie(replie *model.ReplieDTO) error {
 	return nil
 }
 
-func (r *ReplieDBService) GetFullRepliesByMessageID(ID int) ([]model.FullReplie, error) {
-	replies, err := r.store.Replie.GetFullRepliesByMessageID(ID)
+func (r *ReplieDBService) GetFullRepliesByMessageID(ID int, page *model.PageRequest) (*model.FullRepliesPage, error) {
+	replies, err := r.store.Replie.GetFullRepliesByMessageID(ID, page)
 	if err != nil {
 		return nil, fmt.Errorf("[Replie] srv.GetFullRepliesByMessageID error: %w", err)
 	}
 
-	return replies, nil
+	kept, info := paginate(
+		page, len(replies),
+		func(i, j int) { replies[i], replies[j] = replies[j], replies[i] },
+		func(i int) model.Cursor { return model.Cursor{PostedAt: replies[i].PostedAt, ID: replies[i].ID} },
+	)
+
+	return &model.FullRepliesPage{Items: replies[:kept], PageInfo: info}, nil
 }
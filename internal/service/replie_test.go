@@ -65,26 +65,28 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 		{ID: 2, Title: "test2", MessageID: 1, UserID: 2, UserFullname: "test2 test2", UserImageURL: "test2.jpg"},
 	}
 
+	page := &model.PageRequest{Limit: 10}
+
 	tests := []struct {
 		name           string
 		mock           func(replieRepo *mocks.ReplieRepo)
 		input          int
-		want           []model.FullReplie
+		want           *model.FullRepliesPage
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
 			name: "Ok: [full replies found]",
 			mock: func(replieRepo *mocks.ReplieRepo) {
-				replieRepo.On("GetFullRepliesByMessageID", 1).Return(data, nil)
+				replieRepo.On("GetFullRepliesByMessageID", 1, page).Return(data, nil)
 			},
 			input: 1,
-			want:  data,
+			want:  &model.FullRepliesPage{Items: data},
 		},
 		{
 			name: "Error: [full replies not found]",
 			mock: func(replieRepo *mocks.ReplieRepo) {
-				replieRepo.On("GetFullRepliesByMessageID", 1).Return(nil, fmt.Errorf("full replies not found"))
+				replieRepo.On("GetFullRepliesByMessageID", 1, page).Return(nil, fmt.Errorf("full replies not found"))
 			},
 			input:          1,
 			wantErr:        true,
@@ -93,7 +95,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 		{
 			name: "Error: [some store error]",
 			mock: func(replieRepo *mocks.ReplieRepo) {
-				replieRepo.On("GetFullRepliesByMessageID", 1).Return(nil, fmt.Errorf("failed to get full replies by message ID: some error"))
+				replieRepo.On("GetFullRepliesByMessageID", 1, page).Return(nil, fmt.Errorf("failed to get full replies by message ID: some error"))
 			},
 			input:          1,
 			wantErr:        true,
@@ -108,7 +110,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 
 			tt.mock(replieRepo)
 
-			got, err := srv.GetFullRepliesByMessageID(tt.input)
+			got, err := srv.GetFullRepliesByMessageID(tt.input, page)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -1,12 +1,25 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/store"
 )
 
+const (
+	MaxCollectionNameLength = 100
+	MaxTagLength            = 50
+)
+
+var (
+	ErrInvalidCollectionName = errors.New("collection name is not valid")
+	ErrInvalidTag            = errors.New("tag is not valid")
+)
+
 type SavedDBService struct {
 	store *store.Store
 }
@@ -15,16 +28,54 @@ func NewSavedService(store *store.Store) *SavedDBService {
 	return &SavedDBService{store: store}
 }
 
-func (s *SavedDBService) GetSavedMessages(ID int) ([]model.Saved, error) {
-	savedMessages, err := s.store.Saved.GetSavedMessages(ID)
+func (s *SavedDBService) GetSavedMessages(ID int, filter *model.SavedFilter, page *model.PageRequest) (*model.SavedPage, error) {
+	if filter != nil {
+		tags, err := normalizeTags(filter.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("[Saved] srv.GetSavedMessages error: %w", err)
+		}
+
+		filter.Tags = tags
+	}
+
+	savedMessages, err := s.store.Saved.GetSavedMessages(ID, filter, page)
 	if err != nil {
 		return nil, fmt.Errorf("[Saved] srv.GetSavedMessages error: %w", err)
 	}
 
-	return savedMessages, nil
+	kept, info := paginate(
+		page, len(savedMessages),
+		func(i, j int) { savedMessages[i], savedMessages[j] = savedMessages[j], savedMessages[i] },
+		func(i int) model.Cursor {
+			return model.Cursor{PostedAt: savedMessages[i].SavedAt, ID: savedMessages[i].SavedID}
+		},
+	)
+
+	return &model.SavedPage{Items: savedMessages[:kept], PageInfo: info}, nil
 }
-func (s *SavedDBService) CreateSavedMessage(savedMessage *model.Saved) error {
-	_, err := s.store.Saved.CreateSavedMessage(savedMessage)
+
+// CreateSavedMessage saves message for web user and records it in audit trail in one transaction,
+// id of saved message is set to savedMessage.
+func (s *SavedDBService) CreateSavedMessage(savedMessage *model.Saved, origin model.Origin) error {
+	if err := s.prepareSavedMessage(savedMessage); err != nil {
+		return fmt.Errorf("[Saved] srv.CreateSavedMessage error: %w", err)
+	}
+
+	err := s.store.InTx(func(tx *store.Store) error {
+		ID, err := tx.Saved.CreateSavedMessage(savedMessage)
+		if err != nil {
+			return err
+		}
+
+		savedMessage.ID = ID
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &savedMessage.UserID,
+			Action:  model.AuditSavedCreated,
+			Origin:  origin,
+			After:   savedMessage,
+		})
+	})
 	if err != nil {
 		return fmt.Errorf("[Saved] srv.CreateSavedMessage error: %w", err)
 	}
@@ -32,11 +83,140 @@ func (s *SavedDBService) CreateSavedMessage(savedMessage *model.Saved) error {
 	return nil
 }
 
-func (s *SavedDBService) DeleteSavedMessage(ID int) error {
-	_, err := s.store.Saved.DeleteSavedMessage(ID)
+// UpdateSavedMessage moves saved message to collection, or out of any collection when collection id is nil,
+// and replaces its note and tags.
+func (s *SavedDBService) UpdateSavedMessage(savedMessage *model.Saved) error {
+	if err := s.prepareSavedMessage(savedMessage); err != nil {
+		return fmt.Errorf("[Saved] srv.UpdateSavedMessage error: %w", err)
+	}
+
+	_, err := s.store.Saved.UpdateSavedMessage(savedMessage)
+	if err != nil {
+		return fmt.Errorf("[Saved] srv.UpdateSavedMessage error: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSavedMessage deletes saved message of web user and records deleted message in audit trail in one transaction.
+func (s *SavedDBService) DeleteSavedMessage(userID, ID int, origin model.Origin) error {
+	err := s.store.InTx(func(tx *store.Store) error {
+		deleted, err := tx.Saved.DeleteSavedMessage(userID, ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &userID,
+			Action:  model.AuditSavedDeleted,
+			Origin:  origin,
+			Before:  deleted,
+		})
+	})
 	if err != nil {
 		return fmt.Errorf("[Saved] srv.DeleteSavedMessage error: %w", err)
 	}
 
 	return nil
 }
+
+func (s *SavedDBService) GetCollections(userID int) ([]model.Collection, error) {
+	collections, err := s.store.Saved.GetCollections(userID)
+	if err != nil {
+		return nil, fmt.Errorf("[Saved] srv.GetCollections error: %w", err)
+	}
+
+	return collections, nil
+}
+
+func (s *SavedDBService) CreateCollection(collection *model.Collection) error {
+	name, err := normalizeCollectionName(collection.Name)
+	if err != nil {
+		return fmt.Errorf("[Saved] srv.CreateCollection error: %w", err)
+	}
+
+	collection.Name = name
+
+	_, err = s.store.Saved.CreateCollection(collection)
+	if err != nil {
+		return fmt.Errorf("[Saved] srv.CreateCollection error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SavedDBService) UpdateCollection(collection *model.Collection) error {
+	name, err := normalizeCollectionName(collection.Name)
+	if err != nil {
+		return fmt.Errorf("[Saved] srv.UpdateCollection error: %w", err)
+	}
+
+	collection.Name = name
+
+	_, err = s.store.Saved.UpdateCollection(collection)
+	if err != nil {
+		return fmt.Errorf("[Saved] srv.UpdateCollection error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SavedDBService) DeleteCollection(userID, ID int) error {
+	_, err := s.store.Saved.DeleteCollection(userID, ID)
+	if err != nil {
+		return fmt.Errorf("[Saved] srv.DeleteCollection error: %w", err)
+	}
+
+	return nil
+}
+
+// prepareSavedMessage normalizes tags of saved message and checks that its collection belongs to the same user.
+func (s *SavedDBService) prepareSavedMessage(savedMessage *model.Saved) error {
+	tags, err := normalizeTags(savedMessage.Tags)
+	if err != nil {
+		return err
+	}
+
+	savedMessage.Tags = tags
+	savedMessage.Note = strings.TrimSpace(savedMessage.Note)
+
+	if savedMessage.CollectionID != nil {
+		_, err := s.store.Saved.GetCollection(savedMessage.UserID, *savedMessage.CollectionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func normalizeCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxCollectionNameLength {
+		return "", ErrInvalidCollectionName
+	}
+
+	return name, nil
+}
+
+// normalizeTags lowercases and trims tags, drops empty and repeated ones.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, ErrInvalidTag
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized, nil
+}
@@ -2,41 +2,65 @@ package service_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/service"
 	"github.com/VladPetriv/scanner_backend_api/internal/store"
 	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_GetSavedMessages(t *testing.T) {
-	data := []model.Saved{
-		{ID: 1, UserID: 1, MessageID: 1},
-		{ID: 2, UserID: 1, MessageID: 2},
+	data := []model.SavedMessage{
+		{SavedID: 2, FullMessage: model.FullMessage{ID: 2, Title: "test2"}},
+		{SavedID: 1, FullMessage: model.FullMessage{ID: 1, Title: "test1"}},
 	}
 
+	page := &model.PageRequest{Limit: 10}
+
 	tests := []struct {
 		name           string
 		mock           func(savedRepo *mocks.SavedRepo)
 		input          int
-		want           []model.Saved
+		filter         *model.SavedFilter
+		want           *model.SavedPage
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
 			name: "Ok: [saved messages found]",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("GetSavedMessages", 1).Return(data, nil)
+				savedRepo.On("GetSavedMessages", 1, (*model.SavedFilter)(nil), page).Return(data, nil)
 			},
 			input: 1,
-			want:  data,
+			want:  &model.SavedPage{Items: data},
+		},
+		{
+			name: "Ok: [saved messages with normalized tags found]",
+			mock: func(savedRepo *mocks.SavedRepo) {
+				savedRepo.On("GetSavedMessages", 1, &model.SavedFilter{CollectionID: 3, Tags: []string{"golang", "jobs"}}, page).Return(data, nil)
+			},
+			input:  1,
+			filter: &model.SavedFilter{CollectionID: 3, Tags: []string{" Golang", "golang", "", "JOBS "}},
+			want:   &model.SavedPage{Items: data},
+		},
+		{
+			name:           "Error: [tag is not valid]",
+			mock:           func(savedRepo *mocks.SavedRepo) {},
+			input:          1,
+			filter:         &model.SavedFilter{Tags: []string{strings.Repeat("a", service.MaxTagLength+1)}},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.GetSavedMessages error: tag is not valid",
 		},
 		{
 			name: "Error: [saved messages not found]",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("GetSavedMessages", 1).Return(nil, fmt.Errorf("saved messages not found"))
+				savedRepo.On("GetSavedMessages", 1, (*model.SavedFilter)(nil), page).Return(nil, fmt.Errorf("saved messages not found"))
 			},
 			input:          1,
 			wantErr:        true,
@@ -45,7 +69,7 @@ func Test_GetSavedMessages(t *testing.T) {
 		{
 			name: "Error: [some store error]",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("GetSavedMessages", 1).Return(nil, fmt.Errorf("failed to get saved messages by user id: some error"))
+				savedRepo.On("GetSavedMessages", 1, (*model.SavedFilter)(nil), page).Return(nil, fmt.Errorf("failed to get saved messages by user id: some error"))
 			},
 			input:          1,
 			wantErr:        true,
@@ -60,7 +84,7 @@ func Test_GetSavedMessages(t *testing.T) {
 
 			tt.mock(savedRepo)
 
-			got, err := srv.GetSavedMessages(tt.input)
+			got, err := srv.GetSavedMessages(tt.input, tt.filter, page)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -75,39 +99,216 @@ func Test_GetSavedMessages(t *testing.T) {
 }
 
 func Test_CreateSavedMessage(t *testing.T) {
-	data := &model.Saved{ID: 1, MessageID: 1, UserID: 1}
+	collectionID := 3
+	createQuery := `INSERT INTO saved(user_id, message_id, collection_id, note, tags) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, message_id) DO NOTHING
+		RETURNING id;`
 
 	tests := []struct {
 		name           string
-		mock           func(savedRepo *mocks.SavedRepo)
+		mock           func(mock sqlmock.Sqlmock)
 		input          *model.Saved
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
 			name: "Ok: [saved message created]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(createQuery).WithArgs(1, 1, nil, "", pq.StringArray{}).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				expectAuditEvent(mock, 1, model.AuditSavedCreated, "", nil, `{"id":1,"userId":1,"messageId":1,"collectionId":null,"note":"","tags":[]}`)
+				mock.ExpectCommit()
+			},
+			input: &model.Saved{MessageID: 1, UserID: 1},
+		},
+		{
+			name: "Ok: [saved message created in collection]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM collection WHERE id = $1 AND user_id = $2;").WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(3, 1, "golang"))
+				mock.ExpectBegin()
+				mock.ExpectQuery(createQuery).WithArgs(1, 1, 3, "", pq.StringArray{"golang"}).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				expectAuditEvent(mock, 1, model.AuditSavedCreated, "", nil, `{"id":1,"userId":1,"messageId":1,"collectionId":3,"note":"","tags":["golang"]}`)
+				mock.ExpectCommit()
+			},
+			input: &model.Saved{MessageID: 1, UserID: 1, CollectionID: &collectionID, Tags: pq.StringArray{"Golang"}},
+		},
+		{
+			name: "Error: [collection of user not found]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT * FROM collection WHERE id = $1 AND user_id = $2;").WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}))
+			},
+			input:          &model.Saved{MessageID: 1, UserID: 1, CollectionID: &collectionID},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.CreateSavedMessage error: collection not found",
+		},
+		{
+			name: "Error: [message is already saved]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(createQuery).WithArgs(1, 1, nil, "", pq.StringArray{}).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			input:          &model.Saved{MessageID: 1, UserID: 1},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.CreateSavedMessage error: message is already saved",
+		},
+		{
+			name: "Error: [audit event not recorded]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(createQuery).WithArgs(1, 1, nil, "", pq.StringArray{}).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(auditEventQuery).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectRollback()
+			},
+			input:          &model.Saved{MessageID: 1, UserID: 1},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.CreateSavedMessage error: failed to create audit event: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, mock := newTxStore(t)
+			srv := service.NewSavedService(stores)
+
+			tt.mock(mock)
+
+			err := srv.CreateSavedMessage(tt.input, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_DeleteSavedMessage(t *testing.T) {
+	deleteQuery := "DELETE FROM saved WHERE id = $1 AND user_id = $2 RETURNING id, user_id, message_id, collection_id, note, tags;"
+	savedRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "message_id", "collection_id", "note", "tags"})
+	}
+
+	tests := []struct {
+		name           string
+		mock           func(mock sqlmock.Sqlmock)
+		input          int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [saved message deleted]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(deleteQuery).WithArgs(1, 2).WillReturnRows(savedRows().AddRow(1, 2, 5, nil, "check later", "{}"))
+				expectAuditEvent(mock, 2, model.AuditSavedDeleted, "", `{"id":1,"userId":2,"messageId":5,"collectionId":null,"note":"check later","tags":[]}`, nil)
+				mock.ExpectCommit()
+			},
+			input: 1,
+		},
+		{
+			name: "Error: [saved message not found]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(deleteQuery).WithArgs(1, 2).WillReturnRows(savedRows())
+				mock.ExpectRollback()
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.DeleteSavedMessage error: saved message not found",
+		},
+		{
+			name: "Error: [audit event not recorded]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(deleteQuery).WithArgs(1, 2).WillReturnRows(savedRows().AddRow(1, 2, 5, nil, "check later", "{}"))
+				mock.ExpectQuery(auditEventQuery).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectRollback()
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.DeleteSavedMessage error: failed to create audit event: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, mock := newTxStore(t)
+			srv := service.NewSavedService(stores)
+
+			tt.mock(mock)
+
+			err := srv.DeleteSavedMessage(2, tt.input, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_UpdateSavedMessage(t *testing.T) {
+	collectionID := 3
+
+	tests := []struct {
+		name           string
+		mock           func(savedRepo *mocks.SavedRepo)
+		input          *model.Saved
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [saved message moved to collection]",
+			mock: func(savedRepo *mocks.SavedRepo) {
+				savedRepo.On("GetCollection", 2, 3).Return(&model.Collection{ID: 3, UserID: 2, Name: "golang"}, nil)
+				savedRepo.On("UpdateSavedMessage", &model.Saved{ID: 1, UserID: 2, CollectionID: &collectionID, Note: "check later", Tags: pq.StringArray{"golang", "jobs"}}).Return(1, nil)
+			},
+			input: &model.Saved{ID: 1, UserID: 2, CollectionID: &collectionID, Note: " check later ", Tags: pq.StringArray{"Golang", "jobs", "golang"}},
+		},
+		{
+			name: "Ok: [saved message moved out of collection]",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("CreateSavedMessage", data).Return(1, nil)
+				savedRepo.On("UpdateSavedMessage", &model.Saved{ID: 1, UserID: 2, Tags: pq.StringArray{}}).Return(1, nil)
 			},
-			input: data,
+			input: &model.Saved{ID: 1, UserID: 2},
 		},
 		{
-			name: "Error: [saved message not created]",
+			name: "Error: [collection of user not found]",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("CreateSavedMessage", data).Return(0, fmt.Errorf("saved message not created"))
+				savedRepo.On("GetCollection", 2, 3).Return(nil, pg.ErrCollectionNotFound)
 			},
-			input:          data,
+			input:          &model.Saved{ID: 1, UserID: 2, CollectionID: &collectionID},
 			wantErr:        true,
-			expectedErrMsg: "[Saved] srv.CreateSavedMessage error: saved message not created",
+			expectedErrMsg: "[Saved] srv.UpdateSavedMessage error: collection not found",
 		},
 		{
-			name: "Error: [some store error]",
+			name:           "Error: [tag is not valid]",
+			mock:           func(savedRepo *mocks.SavedRepo) {},
+			input:          &model.Saved{ID: 1, UserID: 2, Tags: pq.StringArray{strings.Repeat("a", service.MaxTagLength+1)}},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.UpdateSavedMessage error: tag is not valid",
+		},
+		{
+			name: "Error: [saved message not found]",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("CreateSavedMessage", data).Return(0, fmt.Errorf("failed to create saved message: some error"))
+				savedRepo.On("UpdateSavedMessage", &model.Saved{ID: 1, UserID: 2, Tags: pq.StringArray{}}).Return(0, pg.ErrSavedMessageNotFound)
 			},
-			input:          data,
+			input:          &model.Saved{ID: 1, UserID: 2},
 			wantErr:        true,
-			expectedErrMsg: "[Saved] srv.CreateSavedMessage error: failed to create saved message: some error",
+			expectedErrMsg: "[Saved] srv.UpdateSavedMessage error: saved message not found",
 		},
 	}
 
@@ -118,7 +319,7 @@ func Test_CreateSavedMessage(t *testing.T) {
 
 			tt.mock(savedRepo)
 
-			err := srv.CreateSavedMessage(tt.input)
+			err := srv.UpdateSavedMessage(tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -131,38 +332,190 @@ func Test_CreateSavedMessage(t *testing.T) {
 	}
 }
 
-func Test_DeleteSavedMessage(t *testing.T) {
+func Test_GetCollections(t *testing.T) {
+	data := []model.Collection{
+		{ID: 2, UserID: 1, Name: "golang"},
+		{ID: 1, UserID: 1, Name: "jobs"},
+	}
+
 	tests := []struct {
 		name           string
 		mock           func(savedRepo *mocks.SavedRepo)
-		input          int
+		want           []model.Collection
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
-			name: "Ok: [saved message deleted]",
+			name: "Ok: [collections found]",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("DeleteSavedMessage", 1).Return(1, nil)
+				savedRepo.On("GetCollections", 1).Return(data, nil)
 			},
-			input: 1,
+			want: data,
 		},
 		{
-			name: "Error: [saved message not deleted]",
+			name: "Error: [collections not found]",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("DeleteSavedMessage", 1).Return(0, fmt.Errorf("saved message not deleted"))
+				savedRepo.On("GetCollections", 1).Return(nil, pg.ErrCollectionsNotFound)
 			},
-			input:          1,
 			wantErr:        true,
-			expectedErrMsg: "[Saved] srv.DeleteSavedMessage error: saved message not deleted",
+			expectedErrMsg: "[Saved] srv.GetCollections error: collections not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			savedRepo := &mocks.SavedRepo{}
+			srv := service.NewSavedService(&store.Store{Saved: savedRepo})
+
+			tt.mock(savedRepo)
+
+			got, err := srv.GetCollections(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			savedRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_CreateCollection(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(savedRepo *mocks.SavedRepo)
+		input          *model.Collection
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [collection created]",
+			mock: func(savedRepo *mocks.SavedRepo) {
+				savedRepo.On("CreateCollection", &model.Collection{UserID: 1, Name: "golang"}).Return(1, nil)
+			},
+			input: &model.Collection{UserID: 1, Name: "  golang "},
+		},
+		{
+			name:           "Error: [collection name is empty]",
+			mock:           func(savedRepo *mocks.SavedRepo) {},
+			input:          &model.Collection{UserID: 1, Name: "   "},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.CreateCollection error: collection name is not valid",
 		},
 		{
-			name: "Error: [some store error]",
+			name:           "Error: [collection name is too long]",
+			mock:           func(savedRepo *mocks.SavedRepo) {},
+			input:          &model.Collection{UserID: 1, Name: strings.Repeat("a", service.MaxCollectionNameLength+1)},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.CreateCollection error: collection name is not valid",
+		},
+		{
+			name: "Error: [collection with this name already exists]",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("DeleteSavedMessage", 1).Return(1, fmt.Errorf("failed to delete saved message by id: some error"))
+				savedRepo.On("CreateCollection", &model.Collection{UserID: 1, Name: "golang"}).Return(0, pg.ErrCollectionExists)
+			},
+			input:          &model.Collection{UserID: 1, Name: "golang"},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.CreateCollection error: collection with this name already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			savedRepo := &mocks.SavedRepo{}
+			srv := service.NewSavedService(&store.Store{Saved: savedRepo})
+
+			tt.mock(savedRepo)
+
+			err := srv.CreateCollection(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			savedRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_UpdateCollection(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(savedRepo *mocks.SavedRepo)
+		input          *model.Collection
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [collection updated]",
+			mock: func(savedRepo *mocks.SavedRepo) {
+				savedRepo.On("UpdateCollection", &model.Collection{ID: 1, UserID: 1, Name: "jobs"}).Return(1, nil)
+			},
+			input: &model.Collection{ID: 1, UserID: 1, Name: "jobs "},
+		},
+		{
+			name:           "Error: [collection name is empty]",
+			mock:           func(savedRepo *mocks.SavedRepo) {},
+			input:          &model.Collection{ID: 1, UserID: 1},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.UpdateCollection error: collection name is not valid",
+		},
+		{
+			name: "Error: [collection not found]",
+			mock: func(savedRepo *mocks.SavedRepo) {
+				savedRepo.On("UpdateCollection", &model.Collection{ID: 1, UserID: 1, Name: "jobs"}).Return(0, pg.ErrCollectionNotFound)
+			},
+			input:          &model.Collection{ID: 1, UserID: 1, Name: "jobs"},
+			wantErr:        true,
+			expectedErrMsg: "[Saved] srv.UpdateCollection error: collection not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			savedRepo := &mocks.SavedRepo{}
+			srv := service.NewSavedService(&store.Store{Saved: savedRepo})
+
+			tt.mock(savedRepo)
+
+			err := srv.UpdateCollection(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			savedRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_DeleteCollection(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(savedRepo *mocks.SavedRepo)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [collection deleted]",
+			mock: func(savedRepo *mocks.SavedRepo) {
+				savedRepo.On("DeleteCollection", 2, 1).Return(1, nil)
+			},
+		},
+		{
+			name: "Error: [collection not found]",
+			mock: func(savedRepo *mocks.SavedRepo) {
+				savedRepo.On("DeleteCollection", 2, 1).Return(0, pg.ErrCollectionNotFound)
 			},
-			input:          1,
 			wantErr:        true,
-			expectedErrMsg: "[Saved] srv.DeleteSavedMessage error: failed to delete saved message by id: some error",
+			expectedErrMsg: "[Saved] srv.DeleteCollection error: collection not found",
 		},
 	}
 
@@ -173,7 +526,7 @@ func Test_DeleteSavedMessage(t *testing.T) {
 
 			tt.mock(savedRepo)
 
-			err := srv.DeleteSavedMessage(tt.input)
+			err := srv.DeleteCollection(2, 1)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
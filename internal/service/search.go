@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/pkg/utils"
+)
+
+var (
+	ErrEmptySearchQuery = errors.New("search query is empty")
+	ErrUnknownLanguage  = errors.New("unknown search language")
+
+	defaultSearchLanguages = map[string]string{"en": "english", "ru": "russian", "uk": "simple"}
+)
+
+type SearchDBService struct {
+	store     *store.Store
+	languages map[string]string
+}
+
+// NewSearchService creates search service with languages in "lang:config" format separated by commas,
+// where config is postgres text search configuration. Default languages are used when it's empty.
+func NewSearchService(store *store.Store, languages string) *SearchDBService {
+	return &SearchDBService{store: store, languages: parseSearchLanguages(languages)}
+}
+
+func parseSearchLanguages(languages string) map[string]string {
+	if languages == "" {
+		return defaultSearchLanguages
+	}
+
+	configs := make(map[string]string)
+
+	for _, pair := range strings.Split(languages, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+
+		configs[parts[0]] = parts[1]
+	}
+
+	return configs
+}
+
+// SearchMessages searches messages and replies in language from lang,
+// query is matched against every configured language when lang is empty.
+func (s *SearchDBService) SearchMessages(query, lang string, page int, filter *model.MessageFilter) ([]model.SearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("[Search] srv.SearchMessages error: %w", ErrEmptySearchQuery)
+	}
+
+	configs, err := s.searchConfigs(lang)
+	if err != nil {
+		return nil, fmt.Errorf("[Search] srv.SearchMessages error: %w", err)
+	}
+
+	results, err := s.store.Message.SearchMessages(query, configs, filter, utils.FormatPage(page))
+	if err != nil {
+		return nil, fmt.Errorf("[Search] srv.SearchMessages error: %w", err)
+	}
+
+	return results, nil
+}
+
+func (s *SearchDBService) searchConfigs(lang string) ([]string, error) {
+	if lang != "" {
+		config, ok := s.languages[lang]
+		if !ok {
+			return nil, ErrUnknownLanguage
+		}
+
+		return []string{config}, nil
+	}
+
+	unique := make(map[string]bool, len(s.languages))
+	configs := make([]string, 0, len(s.languages))
+
+	for _, config := range s.languages {
+		if !unique[config] {
+			unique[config] = true
+			configs = append(configs, config)
+		}
+	}
+
+	if len(configs) == 0 {
+		return nil, ErrUnknownLanguage
+	}
+
+	sort.Strings(configs)
+
+	return configs, nil
+}
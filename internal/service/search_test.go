@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SearchMessages(t *testing.T) {
+	results := []model.SearchResult{
+		{FullMessage: model.FullMessage{ID: 1, Title: "golang news"}, Rank: 0.6, Snippet: "<b>golang</b> news"},
+	}
+
+	tests := []struct {
+		name           string
+		mock           func(messageRepo *mocks.MessageRepo)
+		languages      string
+		query          string
+		lang           string
+		want           []model.SearchResult
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [search results in every default language found]",
+			mock: func(messageRepo *mocks.MessageRepo) {
+				messageRepo.On("SearchMessages", "golang", []string{"english", "russian", "simple"}, (*model.MessageFilter)(nil), 0).Return(results, nil)
+			},
+			query: "golang",
+			want:  results,
+		},
+		{
+			name: "Ok: [search results in configured language found]",
+			mock: func(messageRepo *mocks.MessageRepo) {
+				messageRepo.On("SearchMessages", "golang", []string{"ukrainian"}, (*model.MessageFilter)(nil), 0).Return(results, nil)
+			},
+			languages: "en:english, uk:ukrainian",
+			query:     "golang",
+			lang:      "uk",
+			want:      results,
+		},
+		{
+			name:           "Error: [search query is empty]",
+			mock:           func(messageRepo *mocks.MessageRepo) {},
+			query:          "  ",
+			wantErr:        true,
+			expectedErrMsg: "[Search] srv.SearchMessages error: search query is empty",
+		},
+		{
+			name:           "Error: [unknown search language]",
+			mock:           func(messageRepo *mocks.MessageRepo) {},
+			query:          "golang",
+			lang:           "de",
+			wantErr:        true,
+			expectedErrMsg: "[Search] srv.SearchMessages error: unknown search language",
+		},
+		{
+			name: "Error: [search results not found]",
+			mock: func(messageRepo *mocks.MessageRepo) {
+				messageRepo.On("SearchMessages", "golang", []string{"english"}, (*model.MessageFilter)(nil), 0).Return(nil, pg.ErrSearchResultsNotFound)
+			},
+			query:          "golang",
+			lang:           "en",
+			wantErr:        true,
+			expectedErrMsg: "[Search] srv.SearchMessages error: search results not found",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(messageRepo *mocks.MessageRepo) {
+				messageRepo.On("SearchMessages", "golang", []string{"english"}, (*model.MessageFilter)(nil), 0).Return(nil, fmt.Errorf("failed to search messages: some error"))
+			},
+			query:          "golang",
+			lang:           "en",
+			wantErr:        true,
+			expectedErrMsg: "[Search] srv.SearchMessages error: failed to search messages: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messageRepo := &mocks.MessageRepo{}
+			srv := service.NewSearchService(&store.Store{Message: messageRepo}, tt.languages)
+
+			tt.mock(messageRepo)
+
+			got, err := srv.SearchMessages(tt.query, tt.lang, 1, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			messageRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -6,30 +6,33 @@ import "github.com/VladPetriv/scanner_backend_api/internal/model"
 type ChannelService interface {
 	CreateChannel(channel *model.ChannelDTO) error
 	GetChannelsCount() (int, error)
-	GetChannelsByPage(page int) ([]model.Channel, error)
+	GetChannelsByPage(page *model.PageRequest) (*model.ChannelsPage, error)
 	GetChannelByName(name string) (*model.Channel, error)
 }
 
 //go:generate mockery --dir . --name MessageService --output ./mocks
 type MessageService interface {
 	CreateMessage(message *model.MessageDTO) (int, error)
-	GetMessagesCount() (int, error)
-	GetMessagesCountByChannelID(ID int) (int, error)
+	GetMessagesCount(filter *model.MessageFilter) (int, error)
 	GetFullMessageByID(ID int) (*model.FullMessage, error)
-	GetFullMessagesByPage(offset int) ([]model.FullMessage, error)
-	GetFullMessagesByChannelIDAndPage(ID, offset int) ([]model.FullMessage, error)
+	GetFullMessagesByPage(page *model.PageRequest, filter *model.MessageFilter) (*model.FullMessagesPage, error)
 	GetFullMessagesByUserID(ID int) ([]model.FullMessage, error)
 }
 
+//go:generate mockery --dir . --name SearchService --output ./mocks
+type SearchService interface {
+	SearchMessages(query, lang string, page int, filter *model.MessageFilter) ([]model.SearchResult, error)
+}
+
 //go:generate mockery --dir . --name ReplieService --output ./mocks
 type ReplieService interface {
 	CreateReplie(replie *model.ReplieDTO) error
-	GetFullRepliesByMessageID(ID int) ([]model.FullReplie, error)
+	GetFullRepliesByMessageID(ID int, page *model.PageRequest) (*model.FullRepliesPage, error)
 }
 
 //go:generate mockery --dir . --name UserService --output ./mocks
 type UserService interface {
-	CreateUser(user *model.UserDTO) (int, error)
+	UpsertUser(user *model.UserDTO) (int, error)
 	GetUserByUsername(username string) (*model.User, error)
 	GetUserByID(ID int) (*model.User, error)
 }
@@ -37,20 +40,102 @@ type UserService interface {
 //go:generate mockery --dir . --name WebUserService --output ./mocks
 type WebUserService interface {
 	GetWebUserByEmail(email string) (*model.WebUser, error)
-	CreateWebUser(user *model.WebUser) error
+	CreateWebUser(user *model.WebUser, origin model.Origin) error
+	BootstrapAdmin(email, password string, origin model.Origin) (bool, error)
 	HashPassword(password string) (string, error)
 	ComparePassword(password, HashPassword string) bool
 }
 
+//go:generate mockery --dir . --name SignInService --output ./mocks
+type SignInService interface {
+	SignIn(input *model.SignInInput, origin model.Origin) (*model.Tokens, error)
+	UnlockSignIn(actor *model.Principal, input *model.UnlockSignInInput, origin model.Origin) error
+}
+
+//go:generate mockery --dir . --name OIDCService --output ./mocks
+type OIDCService interface {
+	StartLogin() (string, error)
+	FinishLogin(code, state string, origin model.Origin) (*model.Tokens, error)
+}
+
+//go:generate mockery --dir . --name AccountService --output ./mocks
+type AccountService interface {
+	SignUp(user *model.WebUser, origin model.Origin) error
+	RequestPasswordReset(email string) error
+	ResetPassword(token, password string, origin model.Origin) error
+	VerifyEmail(token string) error
+	ChangePassword(principal *model.Principal, input *model.ChangePasswordInput, origin model.Origin) error
+	RequestEmailChange(principal *model.Principal, input *model.ChangeEmailInput) error
+	ConfirmEmailChange(token string, origin model.Origin) error
+	DeleteAccount(principal *model.Principal, password string, origin model.Origin) error
+	ExportAccount(userID int) (*model.AccountExport, error)
+}
+
+//go:generate mockery --dir . --name MailService --output ./mocks
+type MailService interface {
+	SendPendingMails(limit int) (int, error)
+}
+
 //go:generate mockery --dir . --name SavedService --output ./mocks
 type SavedService interface {
-	GetSavedMessages(ID int) ([]model.Saved, error)
-	CreateSavedMessage(savedMessage *model.Saved) error
-	DeleteSavedMessage(ID int) error
+	GetSavedMessages(ID int, filter *model.SavedFilter, page *model.PageRequest) (*model.SavedPage, error)
+	CreateSavedMessage(savedMessage *model.Saved, origin model.Origin) error
+	UpdateSavedMessage(savedMessage *model.Saved) error
+	DeleteSavedMessage(userID, ID int, origin model.Origin) error
+	GetCollections(userID int) ([]model.Collection, error)
+	CreateCollection(collection *model.Collection) error
+	UpdateCollection(collection *model.Collection) error
+	DeleteCollection(userID, ID int) error
+}
+
+//go:generate mockery --dir . --name ShareService --output ./mocks
+type ShareService interface {
+	CreateShare(userID int, input *model.ShareInput) (*model.Share, error)
+	GetShares(userID int) ([]model.Share, error)
+	GetShareAccess(ID, userID int) (*model.Share, error)
+	GetSharedMessages(share *model.Share) (*model.SharedMessages, error)
+	GetPublicSharedMessages(token string) (*model.SharedMessages, error)
+	DeleteShare(ID int) error
+	RotateShareLink(share *model.Share) error
+	RevokeShareLink(ID int) error
+	AddShareItems(shareID, userID int, savedIDs []int) (int, error)
+	DeleteShareItem(shareID, messageID int) error
+	GetShareMembers(shareID int) ([]model.ShareMember, error)
+	GrantShareMember(ownerID int, member *model.ShareMember) error
+	RevokeShareMember(shareID, userID int) error
+}
+
+//go:generate mockery --dir . --name APIKeyService --output ./mocks
+type APIKeyService interface {
+	CreateAPIKey(principal *model.Principal, input *model.APIKeyInput) (*model.APIKey, error)
+	GetAPIKeys(userID int) ([]model.APIKey, error)
+	RenameAPIKey(userID, ID int, name string) error
+	RevokeAPIKey(userID, ID int, origin model.Origin) error
+	Authenticate(key string) (*model.Principal, error)
+}
+
+//go:generate mockery --dir . --name IngestService --output ./mocks
+type IngestService interface {
+	IngestPayload(topic string, payload []byte) error
+	IngestChannel(channel *model.ChannelDTO) error
+	IngestMessage(message *model.TgMessage) error
+	QuarantineIngestFailure(failure *model.IngestFailure) error
+	GetIngestFailuresByPage(page int) ([]model.IngestFailure, error)
+	ReplayIngestFailure(actor *model.Principal, ID int, origin model.Origin) error
+}
+
+//go:generate mockery --dir . --name AuditService --output ./mocks
+type AuditService interface {
+	GetAuditEvents(filter *model.AuditFilter, page *model.PageRequest) (*model.AuditPage, error)
+	ExportAuditEvents(actor *model.Principal, filter *model.AuditFilter, origin model.Origin, write func(event *model.AuditEvent) error) error
 }
 
 //go:generate mockery --dir . --name JwtService --output ./mocks
 type JwtService interface {
-	GenerateToken(userEmail string) (string, error)
-	ParseToken(accessToken string) (string, error)
+	GenerateTokens(userID int, userEmail string, role model.Role) (*model.Tokens, error)
+	RefreshTokens(refreshToken string, origin model.Origin) (*model.Tokens, error)
+	Logout(principal *model.Principal, origin model.Origin) error
+	LogoutAll(principal *model.Principal, origin model.Origin) error
+	ParseToken(accessToken string) (*model.Principal, error)
+	JWKS() *model.JWKS
 }
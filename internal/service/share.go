@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+)
+
+const (
+	MaxShareTitleLength = 100
+
+	// shareTokenSize is number of random bytes in public link token.
+	shareTokenSize = 24
+)
+
+var (
+	ErrInvalidShareTitle = errors.New("share title is not valid")
+	ErrInvalidShareRole  = errors.New("share role is not valid")
+	ErrEmptySavedIDs     = errors.New("saved ids are empty")
+	ErrShareOwnerMember  = errors.New("share owner can't be share member")
+)
+
+type ShareDBService struct {
+	store *store.Store
+}
+
+func NewShareService(store *store.Store) *ShareDBService {
+	return &ShareDBService{store: store}
+}
+
+// CreateShare creates share of user with public link and messages of chosen saved messages.
+func (s *ShareDBService) CreateShare(userID int, input *model.ShareInput) (*model.Share, error) {
+	title := strings.TrimSpace(input.Title)
+	if utf8.RuneCountInString(title) > MaxShareTitleLength {
+		return nil, fmt.Errorf("[Share] srv.CreateShare error: %w", ErrInvalidShareTitle)
+	}
+
+	token, err := newRandomToken(shareTokenSize)
+	if err != nil {
+		return nil, fmt.Errorf("[Share] srv.CreateShare error: %w", err)
+	}
+
+	ID, err := s.store.Share.CreateShare(&model.Share{UserID: userID, Title: title, Token: &token}, input.SavedIDs)
+	if err != nil {
+		return nil, fmt.Errorf("[Share] srv.CreateShare error: %w", err)
+	}
+
+	share, err := s.store.Share.GetShareAccess(ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[Share] srv.CreateShare error: %w", err)
+	}
+
+	return share, nil
+}
+
+func (s *ShareDBService) GetShares(userID int) ([]model.Share, error) {
+	shares, err := s.store.Share.GetSharesByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("[Share] srv.GetShares error: %w", err)
+	}
+
+	return shares, nil
+}
+
+// GetShareAccess returns share with role of user in it.
+func (s *ShareDBService) GetShareAccess(ID, userID int) (*model.Share, error) {
+	share, err := s.store.Share.GetShareAccess(ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[Share] srv.GetShareAccess error: %w", err)
+	}
+
+	return share, nil
+}
+
+func (s *ShareDBService) GetSharedMessages(share *model.Share) (*model.SharedMessages, error) {
+	messages, err := s.store.Share.GetShareMessages(share.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[Share] srv.GetSharedMessages error: %w", err)
+	}
+
+	return &model.SharedMessages{Title: share.Title, CreatedAt: share.CreatedAt, Items: messages}, nil
+}
+
+// GetPublicSharedMessages returns messages of share by public link token.
+func (s *ShareDBService) GetPublicSharedMessages(token string) (*model.SharedMessages, error) {
+	share, err := s.store.Share.GetShareByToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("[Share] srv.GetPublicSharedMessages error: %w", err)
+	}
+
+	messages, err := s.store.Share.GetShareMessages(share.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[Share] srv.GetPublicSharedMessages error: %w", err)
+	}
+
+	return &model.SharedMessages{Title: share.Title, CreatedAt: share.CreatedAt, Items: messages}, nil
+}
+
+func (s *ShareDBService) DeleteShare(ID int) error {
+	_, err := s.store.Share.DeleteShare(ID)
+	if err != nil {
+		return fmt.Errorf("[Share] srv.DeleteShare error: %w", err)
+	}
+
+	return nil
+}
+
+// RotateShareLink replaces public link of share with a new one, the old link stops working.
+func (s *ShareDBService) RotateShareLink(share *model.Share) error {
+	token, err := newRandomToken(shareTokenSize)
+	if err != nil {
+		return fmt.Errorf("[Share] srv.RotateShareLink error: %w", err)
+	}
+
+	_, err = s.store.Share.UpdateShareToken(share.ID, &token)
+	if err != nil {
+		return fmt.Errorf("[Share] srv.RotateShareLink error: %w", err)
+	}
+
+	share.Token = &token
+
+	return nil
+}
+
+// RevokeShareLink disables public link of share, members keep their access.
+func (s *ShareDBService) RevokeShareLink(ID int) error {
+	_, err := s.store.Share.UpdateShareToken(ID, nil)
+	if err != nil {
+		return fmt.Errorf("[Share] srv.RevokeShareLink error: %w", err)
+	}
+
+	return nil
+}
+
+// AddShareItems adds messages of saved messages of user to share and returns how many messages were added.
+func (s *ShareDBService) AddShareItems(shareID, userID int, savedIDs []int) (int, error) {
+	if len(savedIDs) == 0 {
+		return 0, fmt.Errorf("[Share] srv.AddShareItems error: %w", ErrEmptySavedIDs)
+	}
+
+	added, err := s.store.Share.AddShareItems(shareID, userID, savedIDs)
+	if err != nil {
+		return 0, fmt.Errorf("[Share] srv.AddShareItems error: %w", err)
+	}
+
+	return added, nil
+}
+
+func (s *ShareDBService) DeleteShareItem(shareID, messageID int) error {
+	_, err := s.store.Share.DeleteShareItem(shareID, messageID)
+	if err != nil {
+		return fmt.Errorf("[Share] srv.DeleteShareItem error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ShareDBService) GetShareMembers(shareID int) ([]model.ShareMember, error) {
+	members, err := s.store.Share.GetShareMembers(shareID)
+	if err != nil {
+		return nil, fmt.Errorf("[Share] srv.GetShareMembers error: %w", err)
+	}
+
+	return members, nil
+}
+
+// GrantShareMember gives web user with email of member viewer or editor access to share of owner.
+func (s *ShareDBService) GrantShareMember(ownerID int, member *model.ShareMember) error {
+	if member.Role != model.ShareRoleViewer && member.Role != model.ShareRoleEditor {
+		return fmt.Errorf("[Share] srv.GrantShareMember error: %w", ErrInvalidShareRole)
+	}
+
+	user, err := s.store.WebUser.GetWebUserByEmail(lookupEmail(member.Email))
+	if err != nil {
+		return fmt.Errorf("[Share] srv.GrantShareMember error: %w", err)
+	}
+
+	if user.ID == ownerID {
+		return fmt.Errorf("[Share] srv.GrantShareMember error: %w", ErrShareOwnerMember)
+	}
+
+	member.UserID = user.ID
+
+	err = s.store.Share.UpsertShareMember(member)
+	if err != nil {
+		return fmt.Errorf("[Share] srv.GrantShareMember error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ShareDBService) RevokeShareMember(shareID, userID int) error {
+	_, err := s.store.Share.DeleteShareMember(shareID, userID)
+	if err != nil {
+		return fmt.Errorf("[Share] srv.RevokeShareMember error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package service_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_CreateShare(t *testing.T) {
+	token := "token"
+	data := &model.Share{ID: 1, UserID: 1, Title: "golang", Token: &token, Role: model.ShareRoleOwner}
+
+	tests := []struct {
+		name           string
+		mock           func(shareRepo *mocks.ShareRepo)
+		input          *model.ShareInput
+		want           *model.Share
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share created]",
+			mock: func(shareRepo *mocks.ShareRepo) {
+				shareRepo.On("CreateShare", mock.MatchedBy(func(share *model.Share) bool {
+					return share.UserID == 1 && share.Title == "golang" && share.Token != nil && *share.Token != ""
+				}), []int{1, 2}).Return(1, nil)
+				shareRepo.On("GetShareAccess", 1, 1).Return(data, nil)
+			},
+			input: &model.ShareInput{Title: " golang ", SavedIDs: []int{1, 2}},
+			want:  data,
+		},
+		{
+			name:           "Error: [share title is not valid]",
+			mock:           func(shareRepo *mocks.ShareRepo) {},
+			input:          &model.ShareInput{Title: strings.Repeat("a", service.MaxShareTitleLength+1)},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.CreateShare error: share title is not valid",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(shareRepo *mocks.ShareRepo) {
+				shareRepo.On("CreateShare", mock.AnythingOfType("*model.Share"), []int{1, 2}).Return(0, fmt.Errorf("failed to create share: some error"))
+			},
+			input:          &model.ShareInput{Title: "golang", SavedIDs: []int{1, 2}},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.CreateShare error: failed to create share: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shareRepo := &mocks.ShareRepo{}
+			srv := service.NewShareService(&store.Store{Share: shareRepo})
+
+			tt.mock(shareRepo)
+
+			got, err := srv.CreateShare(1, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			shareRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_GetPublicSharedMessages(t *testing.T) {
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	share := &model.Share{ID: 1, UserID: 3, Title: "golang", CreatedAt: createdAt, Role: model.ShareRoleViewer}
+	messages := []model.FullMessage{{ID: 2, Title: "test2"}, {ID: 1, Title: "test1"}}
+
+	tests := []struct {
+		name           string
+		mock           func(shareRepo *mocks.ShareRepo)
+		want           *model.SharedMessages
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [shared messages found]",
+			mock: func(shareRepo *mocks.ShareRepo) {
+				shareRepo.On("GetShareByToken", "token").Return(share, nil)
+				shareRepo.On("GetShareMessages", 1).Return(messages, nil)
+			},
+			want: &model.SharedMessages{Title: "golang", CreatedAt: createdAt, Items: messages},
+		},
+		{
+			name: "Error: [share not found]",
+			mock: func(shareRepo *mocks.ShareRepo) {
+				shareRepo.On("GetShareByToken", "token").Return(nil, fmt.Errorf("share not found"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.GetPublicSharedMessages error: share not found",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(shareRepo *mocks.ShareRepo) {
+				shareRepo.On("GetShareByToken", "token").Return(share, nil)
+				shareRepo.On("GetShareMessages", 1).Return(nil, fmt.Errorf("failed to get share messages: some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.GetPublicSharedMessages error: failed to get share messages: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shareRepo := &mocks.ShareRepo{}
+			srv := service.NewShareService(&store.Store{Share: shareRepo})
+
+			tt.mock(shareRepo)
+
+			got, err := srv.GetPublicSharedMessages("token")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			shareRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_RotateShareLink(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(shareRepo *mocks.ShareRepo)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share link rotated]",
+			mock: func(shareRepo *mocks.ShareRepo) {
+				shareRepo.On("UpdateShareToken", 1, mock.AnythingOfType("*string")).Return(1, nil)
+			},
+		},
+		{
+			name: "Error: [share not found]",
+			mock: func(shareRepo *mocks.ShareRepo) {
+				shareRepo.On("UpdateShareToken", 1, mock.AnythingOfType("*string")).Return(0, fmt.Errorf("share not found"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.RotateShareLink error: share not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shareRepo := &mocks.ShareRepo{}
+			srv := service.NewShareService(&store.Store{Share: shareRepo})
+
+			tt.mock(shareRepo)
+
+			oldToken := "token"
+			share := &model.Share{ID: 1, Token: &oldToken}
+
+			err := srv.RotateShareLink(share)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+				assert.EqualValues(t, "token", *share.Token)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEqual(t, "token", *share.Token)
+			}
+
+			shareRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_AddShareItems(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(shareRepo *mocks.ShareRepo)
+		input          []int
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share items added]",
+			mock: func(shareRepo *mocks.ShareRepo) {
+				shareRepo.On("AddShareItems", 1, 2, []int{1, 2}).Return(2, nil)
+			},
+			input: []int{1, 2},
+			want:  2,
+		},
+		{
+			name:           "Error: [saved ids are empty]",
+			mock:           func(shareRepo *mocks.ShareRepo) {},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.AddShareItems error: saved ids are empty",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(shareRepo *mocks.ShareRepo) {
+				shareRepo.On("AddShareItems", 1, 2, []int{1, 2}).Return(0, fmt.Errorf("failed to add share items: some error"))
+			},
+			input:          []int{1, 2},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.AddShareItems error: failed to add share items: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shareRepo := &mocks.ShareRepo{}
+			srv := service.NewShareService(&store.Store{Share: shareRepo})
+
+			tt.mock(shareRepo)
+
+			got, err := srv.AddShareItems(1, 2, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			shareRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_GrantShareMember(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(shareRepo *mocks.ShareRepo, webUserRepo *mocks.WebUserRepo)
+		input          *model.ShareMember
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share member granted]",
+			mock: func(shareRepo *mocks.ShareRepo, webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByEmail", "editor@test.com").Return(&model.WebUser{ID: 2, Email: "editor@test.com"}, nil)
+				shareRepo.On("UpsertShareMember", &model.ShareMember{ShareID: 1, UserID: 2, Email: "editor@test.com", Role: model.ShareRoleEditor}).Return(nil)
+			},
+			input: &model.ShareMember{ShareID: 1, Email: "editor@test.com", Role: model.ShareRoleEditor},
+		},
+		{
+			name: "Ok: [share member found by normalized email]",
+			mock: func(shareRepo *mocks.ShareRepo, webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByEmail", "Editor@test.com").Return(&model.WebUser{ID: 2, Email: "Editor@test.com"}, nil)
+				shareRepo.On("UpsertShareMember", &model.ShareMember{ShareID: 1, UserID: 2, Email: " Editor@Test.COM ", Role: model.ShareRoleViewer}).Return(nil)
+			},
+			input: &model.ShareMember{ShareID: 1, Email: " Editor@Test.COM ", Role: model.ShareRoleViewer},
+		},
+		{
+			name:           "Error: [share role is not valid]",
+			mock:           func(shareRepo *mocks.ShareRepo, webUserRepo *mocks.WebUserRepo) {},
+			input:          &model.ShareMember{ShareID: 1, Email: "editor@test.com", Role: model.ShareRoleOwner},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.GrantShareMember error: share role is not valid",
+		},
+		{
+			name: "Error: [web user not found]",
+			mock: func(shareRepo *mocks.ShareRepo, webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByEmail", "editor@test.com").Return(nil, fmt.Errorf("web user not found"))
+			},
+			input:          &model.ShareMember{ShareID: 1, Email: "editor@test.com", Role: model.ShareRoleViewer},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.GrantShareMember error: web user not found",
+		},
+		{
+			name: "Error: [share owner can't be share member]",
+			mock: func(shareRepo *mocks.ShareRepo, webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByEmail", "owner@test.com").Return(&model.WebUser{ID: 1, Email: "owner@test.com"}, nil)
+			},
+			input:          &model.ShareMember{ShareID: 1, Email: "owner@test.com", Role: model.ShareRoleViewer},
+			wantErr:        true,
+			expectedErrMsg: "[Share] srv.GrantShareMember error: share owner can't be share member",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shareRepo := &mocks.ShareRepo{}
+			webUserRepo := &mocks.WebUserRepo{}
+			srv := service.NewShareService(&store.Store{Share: shareRepo, WebUser: webUserRepo})
+
+			tt.mock(shareRepo, webUserRepo)
+
+			err := srv.GrantShareMember(1, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			shareRepo.AssertExpectations(t)
+			webUserRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+const (
+	// accountFreeSignInAttempts is number of failures in a row after which account sign-in is locked.
+	accountFreeSignInAttempts = 5
+	// ipFreeSignInAttempts is number of failures in a row after which sign-in from client ip is locked,
+	// it's higher than for account since several users can share one address.
+	ipFreeSignInAttempts = 20
+
+	// signInLockoutBase is the first lockout, every next failure doubles it up to maxSignInLockout.
+	signInLockoutBase = time.Second
+	maxSignInLockout  = 15 * time.Minute
+
+	// signInFailureWindow is time after the last failure when failures are counted from zero again.
+	signInFailureWindow = time.Hour
+
+	// dummyPasswordHash is compared with password of unknown email, so sign-in takes the same time for any email.
+	dummyPasswordHash = "$2a$14$cSG5SPTz2Sjp5yni.FKoqOvV5PHYBkImAr2XVbmbh0iyR.SaRvT/6"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("email or password is incorrect")
+	ErrSignInLocked       = errors.New("too many failed sign-in attempts, try again later")
+	ErrEmptyUnlockInput   = errors.New("email or ip is required")
+)
+
+type SignInDBService struct {
+	store   *store.Store
+	webUser *WebUserDBService
+	jwt     JwtService
+}
+
+func NewSignInService(store *store.Store, jwt JwtService) *SignInDBService {
+	return &SignInDBService{store: store, webUser: NewWebUserService(store, nil), jwt: jwt}
+}
+
+// SignIn returns tokens of web user when email and password are correct.
+// Failed attempts are counted per email and per client ip, when there are too many of them
+// sign-in is locked for exponentially growing time and the lock is recorded in audit trail.
+// Unknown email and wrong password fail the same way. Every attempt is recorded in audit trail.
+func (s *SignInDBService) SignIn(input *model.SignInInput, origin model.Origin) (*model.Tokens, error) {
+	now := time.Now()
+	subject := signInSubject(input.Email)
+
+	for _, attempt := range signInAttempts(subject, origin.IP) {
+		locked, err := s.isSignInLocked(attempt.Kind, attempt.Subject, now)
+		if err != nil {
+			return nil, fmt.Errorf("[SignIn] srv.SignIn error: %w", err)
+		}
+
+		if locked {
+			err = recordAuditEvent(s.store, auditEntry{
+				Action:  model.AuditSignInFailed,
+				Origin:  origin,
+				Details: map[string]string{"email": subject, "reason": "locked"},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("[SignIn] srv.SignIn error: %w", err)
+			}
+
+			return nil, fmt.Errorf("[SignIn] srv.SignIn error: %w", ErrSignInLocked)
+		}
+	}
+
+	user, err := s.store.WebUser.GetWebUserByEmail(subject)
+	if err != nil && !errors.Is(err, pg.ErrWebUserNotFound) {
+		return nil, fmt.Errorf("[SignIn] srv.SignIn error: %w", err)
+	}
+
+	hashedPassword := dummyPasswordHash
+	if user != nil {
+		hashedPassword = user.Password
+	}
+
+	if !s.webUser.ComparePassword(input.Password, hashedPassword) || user == nil {
+		if err := s.recordSignInFailure(user, subject, origin, now); err != nil {
+			return nil, fmt.Errorf("[SignIn] srv.SignIn error: %w", err)
+		}
+
+		return nil, fmt.Errorf("[SignIn] srv.SignIn error: %w", ErrInvalidCredentials)
+	}
+
+	// failures of client ip are kept, so signing into own account doesn't let guessing others
+	_, err = s.store.SignInAttempt.DeleteSignInAttempt(model.SignInAttemptAccount, subject)
+	if err != nil {
+		return nil, fmt.Errorf("[SignIn] srv.SignIn error: %w", err)
+	}
+
+	tokens, err := s.jwt.GenerateTokens(user.ID, user.Email, user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("[SignIn] srv.SignIn error: %w", err)
+	}
+
+	err = recordAuditEvent(s.store, auditEntry{
+		ActorID: &user.ID,
+		Action:  model.AuditSignInSucceeded,
+		Origin:  origin,
+		Details: map[string]string{"method": "password"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[SignIn] srv.SignIn error: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// UnlockSignIn forgets failed sign-in attempts of email and client ip from input, it's recorded in audit trail as action of actor.
+func (s *SignInDBService) UnlockSignIn(actor *model.Principal, input *model.UnlockSignInInput, origin model.Origin) error {
+	subject := signInSubject(input.Email)
+	clientIP := strings.TrimSpace(input.IP)
+
+	if subject == "" && clientIP == "" {
+		return fmt.Errorf("[SignIn] srv.UnlockSignIn error: %w", ErrEmptyUnlockInput)
+	}
+
+	err := s.store.InTx(func(tx *store.Store) error {
+		var unlocked int
+
+		for _, attempt := range signInAttempts(subject, clientIP) {
+			deleted, err := tx.SignInAttempt.DeleteSignInAttempt(attempt.Kind, attempt.Subject)
+			if err != nil {
+				return err
+			}
+
+			unlocked += deleted
+		}
+
+		if unlocked == 0 {
+			return pg.ErrSignInAttemptNotFound
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &actor.UserID,
+			Action:  model.AuditSignInUnlocked,
+			Origin:  origin,
+			Details: map[string]string{"email": subject, "ip": clientIP},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("[SignIn] srv.UnlockSignIn error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SignInDBService) isSignInLocked(kind model.SignInAttemptKind, subject string, now time.Time) (bool, error) {
+	attempt, err := s.store.SignInAttempt.GetSignInAttempt(kind, subject)
+	if errors.Is(err, pg.ErrSignInAttemptNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return attempt.LockedUntil != nil && attempt.LockedUntil.After(now), nil
+}
+
+// recordSignInFailure records failure in audit trail, counts it for account and client ip
+// and locks them when they have too many failures.
+func (s *SignInDBService) recordSignInFailure(user *model.WebUser, subject string, origin model.Origin, now time.Time) error {
+	var actorID *int
+	if user != nil {
+		actorID = &user.ID
+	}
+
+	err := recordAuditEvent(s.store, auditEntry{
+		ActorID: actorID,
+		Action:  model.AuditSignInFailed,
+		Origin:  origin,
+		Details: map[string]string{"email": subject, "reason": "invalid-credentials"},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, attempt := range signInAttempts(subject, origin.IP) {
+		kind, value := attempt.Kind, attempt.Subject
+
+		failures, err := s.store.SignInAttempt.RecordSignInFailure(kind, value, now, now.Add(-signInFailureWindow))
+		if err != nil {
+			return err
+		}
+
+		freeAttempts := accountFreeSignInAttempts
+		if kind == model.SignInAttemptIP {
+			freeAttempts = ipFreeSignInAttempts
+		}
+
+		if failures < freeAttempts {
+			continue
+		}
+
+		lockedUntil := now.Add(signInLockout(failures - freeAttempts))
+
+		_, err = s.store.SignInAttempt.LockSignIn(kind, value, lockedUntil)
+		if err != nil {
+			return err
+		}
+
+		entry := auditEntry{
+			Action: model.AuditSignInLocked,
+			Origin: origin,
+			Details: map[string]interface{}{
+				"kind":        kind,
+				"subject":     value,
+				"failures":    failures,
+				"lockedUntil": lockedUntil.UTC(),
+			},
+		}
+		if kind == model.SignInAttemptAccount {
+			entry.ActorID = actorID
+		}
+
+		err = recordAuditEvent(s.store, entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// signInAttempts returns counters of failed sign-in attempts for account email and client ip, empty ones are skipped.
+func signInAttempts(subject, ip string) []model.SignInAttempt {
+	attempts := make([]model.SignInAttempt, 0, 2)
+
+	if subject != "" {
+		attempts = append(attempts, model.SignInAttempt{Kind: model.SignInAttemptAccount, Subject: subject})
+	}
+
+	if ip != "" {
+		attempts = append(attempts, model.SignInAttempt{Kind: model.SignInAttemptIP, Subject: ip})
+	}
+
+	return attempts
+}
+
+// signInLockout returns lockout after failures over free attempts, it doubles with every failure.
+func signInLockout(extraFailures int) time.Duration {
+	lockout := signInLockoutBase
+	for i := 0; i < extraFailures && lockout < maxSignInLockout; i++ {
+		lockout *= 2
+	}
+
+	if lockout > maxSignInLockout {
+		return maxSignInLockout
+	}
+
+	return lockout
+}
+
+// signInSubject returns email in form failed attempts of account are counted for. It's the same form
+// emails are stored and looked up in, so attempts of one account are counted together however its email is spelled.
+func signInSubject(email string) string {
+	return lookupEmail(email)
+}
@@ -0,0 +1,240 @@
+package service_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	srvmocks "github.com/VladPetriv/scanner_backend_api/internal/service/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_SignIn(t *testing.T) {
+	user := testPasswordUser(t)
+	tokens := &model.Tokens{AccessToken: "access-token", RefreshToken: "refresh-token", ExpiresIn: 900}
+	lockedUntil := time.Now().Add(time.Minute)
+	anyTime := mock.AnythingOfType("time.Time")
+	failed := func(actorID *int, email string) *model.AuditEvent {
+		return auditEvent(actorID, model.AuditSignInFailed, fmt.Sprintf(`{"email":%q,"reason":"invalid-credentials"}`, email))
+	}
+
+	tests := []struct {
+		name           string
+		mock           func(stores *store.Store, jwtSrv *srvmocks.JwtService)
+		input          *model.SignInInput
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [user signed in and account failures forgotten]",
+			mock: func(stores *store.Store, jwtSrv *srvmocks.JwtService) {
+				attemptRepo := stores.SignInAttempt.(*mocks.SignInAttemptRepo)
+				attemptRepo.On("GetSignInAttempt", model.SignInAttemptAccount, "test@test.com").
+					Return(&model.SignInAttempt{Kind: model.SignInAttemptAccount, Subject: "test@test.com", Failures: 2}, nil)
+				attemptRepo.On("GetSignInAttempt", model.SignInAttemptIP, "192.0.2.1").Return(nil, pg.ErrSignInAttemptNotFound)
+				stores.WebUser.(*mocks.WebUserRepo).On("GetWebUserByEmail", "test@test.com").Return(user, nil)
+				attemptRepo.On("DeleteSignInAttempt", model.SignInAttemptAccount, "test@test.com").Return(1, nil)
+				jwtSrv.On("GenerateTokens", 1, "test@test.com", model.RoleMember).Return(tokens, nil)
+				stores.Audit.(*mocks.AuditRepo).On("CreateAuditEvent", auditEvent(intPtr(1), model.AuditSignInSucceeded, `{"method":"password"}`)).
+					Return(1, nil)
+			},
+			input: &model.SignInInput{Email: " test@TEST.com ", Password: "password"},
+		},
+		{
+			name: "Error: [account is locked]",
+			mock: func(stores *store.Store, jwtSrv *srvmocks.JwtService) {
+				stores.SignInAttempt.(*mocks.SignInAttemptRepo).On("GetSignInAttempt", model.SignInAttemptAccount, "test@test.com").
+					Return(&model.SignInAttempt{Kind: model.SignInAttemptAccount, Subject: "test@test.com", Failures: 6, LockedUntil: &lockedUntil}, nil)
+				stores.Audit.(*mocks.AuditRepo).On("CreateAuditEvent", auditEvent(nil, model.AuditSignInFailed, `{"email":"test@test.com","reason":"locked"}`)).
+					Return(1, nil)
+			},
+			input:          &model.SignInInput{Email: "test@test.com", Password: "password"},
+			wantErr:        true,
+			expectedErrMsg: "[SignIn] srv.SignIn error: too many failed sign-in attempts, try again later",
+		},
+		{
+			name: "Error: [password is incorrect]",
+			mock: func(stores *store.Store, jwtSrv *srvmocks.JwtService) {
+				attemptRepo := stores.SignInAttempt.(*mocks.SignInAttemptRepo)
+				attemptRepo.On("GetSignInAttempt", mock.Anything, mock.Anything).Return(nil, pg.ErrSignInAttemptNotFound)
+				stores.WebUser.(*mocks.WebUserRepo).On("GetWebUserByEmail", "test@test.com").Return(user, nil)
+				stores.Audit.(*mocks.AuditRepo).On("CreateAuditEvent", failed(intPtr(1), "test@test.com")).Return(1, nil)
+				attemptRepo.On("RecordSignInFailure", model.SignInAttemptAccount, "test@test.com", anyTime, anyTime).Return(2, nil)
+				attemptRepo.On("RecordSignInFailure", model.SignInAttemptIP, "192.0.2.1", anyTime, anyTime).Return(7, nil)
+			},
+			input:          &model.SignInInput{Email: "test@test.com", Password: "wrong"},
+			wantErr:        true,
+			expectedErrMsg: "[SignIn] srv.SignIn error: email or password is incorrect",
+		},
+		{
+			name: "Error: [too many failures lock account and lock is audited]",
+			mock: func(stores *store.Store, jwtSrv *srvmocks.JwtService) {
+				attemptRepo := stores.SignInAttempt.(*mocks.SignInAttemptRepo)
+				attemptRepo.On("GetSignInAttempt", mock.Anything, mock.Anything).Return(nil, pg.ErrSignInAttemptNotFound)
+				stores.WebUser.(*mocks.WebUserRepo).On("GetWebUserByEmail", "test@test.com").Return(user, nil)
+				stores.Audit.(*mocks.AuditRepo).On("CreateAuditEvent", failed(intPtr(1), "test@test.com")).Return(1, nil)
+				attemptRepo.On("RecordSignInFailure", model.SignInAttemptAccount, "test@test.com", anyTime, anyTime).Return(7, nil)
+				attemptRepo.On("RecordSignInFailure", model.SignInAttemptIP, "192.0.2.1", anyTime, anyTime).Return(7, nil)
+				attemptRepo.On(
+					"LockSignIn", model.SignInAttemptAccount, "test@test.com",
+					mock.MatchedBy(func(until time.Time) bool {
+						lockout := time.Until(until)
+
+						return lockout > 3*time.Second && lockout <= 4*time.Second
+					}),
+				).Return(7, nil)
+				stores.Audit.(*mocks.AuditRepo).On("CreateAuditEvent", mock.MatchedBy(func(event *model.AuditEvent) bool {
+					details := map[string]interface{}{}
+					if err := json.Unmarshal(event.Details, &details); err != nil {
+						return false
+					}
+
+					return event.Action == model.AuditSignInLocked && event.ActorID != nil && *event.ActorID == 1 &&
+						event.IP == "192.0.2.1" && event.UserAgent == "test-agent" &&
+						details["subject"] == "test@test.com" && details["failures"] == float64(7)
+				})).Return(1, nil)
+			},
+			input:          &model.SignInInput{Email: "test@test.com", Password: "wrong"},
+			wantErr:        true,
+			expectedErrMsg: "[SignIn] srv.SignIn error: email or password is incorrect",
+		},
+		{
+			name: "Error: [email is unknown]",
+			mock: func(stores *store.Store, jwtSrv *srvmocks.JwtService) {
+				attemptRepo := stores.SignInAttempt.(*mocks.SignInAttemptRepo)
+				attemptRepo.On("GetSignInAttempt", mock.Anything, mock.Anything).Return(nil, pg.ErrSignInAttemptNotFound)
+				stores.WebUser.(*mocks.WebUserRepo).On("GetWebUserByEmail", "unknown@test.com").Return(nil, pg.ErrWebUserNotFound)
+				stores.Audit.(*mocks.AuditRepo).On("CreateAuditEvent", failed(nil, "unknown@test.com")).Return(1, nil)
+				attemptRepo.On("RecordSignInFailure", model.SignInAttemptAccount, "unknown@test.com", anyTime, anyTime).Return(1, nil)
+				attemptRepo.On("RecordSignInFailure", model.SignInAttemptIP, "192.0.2.1", anyTime, anyTime).Return(1, nil)
+			},
+			input:          &model.SignInInput{Email: "unknown@test.com", Password: "password"},
+			wantErr:        true,
+			expectedErrMsg: "[SignIn] srv.SignIn error: email or password is incorrect",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(stores *store.Store, jwtSrv *srvmocks.JwtService) {
+				stores.SignInAttempt.(*mocks.SignInAttemptRepo).On("GetSignInAttempt", model.SignInAttemptAccount, "test@test.com").
+					Return(nil, fmt.Errorf("failed to get sign-in attempt: some error"))
+			},
+			input:          &model.SignInInput{Email: "test@test.com", Password: "password"},
+			wantErr:        true,
+			expectedErrMsg: "[SignIn] srv.SignIn error: failed to get sign-in attempt: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores := &store.Store{
+				WebUser:       &mocks.WebUserRepo{},
+				SignInAttempt: &mocks.SignInAttemptRepo{},
+				Audit:         &mocks.AuditRepo{},
+			}
+			jwtSrv := &srvmocks.JwtService{}
+			srv := service.NewSignInService(stores, jwtSrv)
+
+			tt.mock(stores, jwtSrv)
+
+			got, err := srv.SignIn(tt.input, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tokens, got)
+			}
+
+			stores.WebUser.(*mocks.WebUserRepo).AssertExpectations(t)
+			stores.SignInAttempt.(*mocks.SignInAttemptRepo).AssertExpectations(t)
+			stores.Audit.(*mocks.AuditRepo).AssertExpectations(t)
+			jwtSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_UnlockSignIn(t *testing.T) {
+	actor := &model.Principal{UserID: 1, Email: "admin@test.com", Role: model.RoleAdmin}
+	deleteQuery := "DELETE FROM sign_in_attempt WHERE kind = $1 AND subject = $2;"
+
+	tests := []struct {
+		name           string
+		mock           func(mock sqlmock.Sqlmock)
+		input          *model.UnlockSignInInput
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [account and ip unlocked]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(model.SignInAttemptAccount, "test@test.com").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(deleteQuery).
+					WithArgs(model.SignInAttemptIP, "192.0.2.7").WillReturnResult(sqlmock.NewResult(0, 0))
+				expectAuditEvent(mock, 1, model.AuditSignInUnlocked, `{"email":"test@test.com","ip":"192.0.2.7"}`, nil, nil)
+				mock.ExpectCommit()
+			},
+			input: &model.UnlockSignInInput{Email: " test@Test.COM ", IP: "192.0.2.7"},
+		},
+		{
+			name: "Error: [nothing is locked]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(model.SignInAttemptIP, "192.0.2.7").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			input:          &model.UnlockSignInInput{IP: "192.0.2.7"},
+			wantErr:        true,
+			expectedErrMsg: "[SignIn] srv.UnlockSignIn error: sign-in attempt not found",
+		},
+		{
+			name: "Error: [audit event not created]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(deleteQuery).
+					WithArgs(model.SignInAttemptIP, "192.0.2.7").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(auditEventQuery).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectRollback()
+			},
+			input:          &model.UnlockSignInInput{IP: "192.0.2.7"},
+			wantErr:        true,
+			expectedErrMsg: "[SignIn] srv.UnlockSignIn error: failed to create audit event: some error",
+		},
+		{
+			name:           "Error: [email and ip are empty]",
+			mock:           func(mock sqlmock.Sqlmock) {},
+			input:          &model.UnlockSignInInput{Email: " "},
+			wantErr:        true,
+			expectedErrMsg: "[SignIn] srv.UnlockSignIn error: email or ip is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, mock := newTxStore(t)
+			srv := service.NewSignInService(stores, &srvmocks.JwtService{})
+
+			tt.mock(mock)
+
+			err := srv.UnlockSignIn(actor, tt.input, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
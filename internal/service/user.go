@@ -15,20 +15,35 @@ func NewUserService(store *store.Store) *UserDBService {
 	return &UserDBService{store: store}
 }
 
-func (u *UserDBService) CreateUser(user *model.UserDTO) (int, error) {
-	candidate, _ := u.GetUserByUsername(user.Username)
-	if candidate != nil {
-		return candidate.ID, nil
-	}
-
-	id, err := u.store.User.CreateUser(user)
+// UpsertUser returns id of telegram user and refreshes its fullname and image.
+// Users are identified by telegram id, payloads without it fall back to username.
+func (u *UserDBService) UpsertUser(user *model.UserDTO) (int, error) {
+	id, err := upsertUser(u.store, user)
 	if err != nil {
-		return 0, fmt.Errorf("[User] srv.CreateUser error: %w", err)
+		return 0, fmt.Errorf("[User] srv.UpsertUser error: %w", err)
 	}
 
 	return id, nil
 }
 
+func upsertUser(s *store.Store, user *model.UserDTO) (int, error) {
+	if user.TgID == 0 && user.Username == "" {
+		return s.User.UpsertAnonymousUser(user)
+	}
+
+	if user.TgID == 0 {
+		return s.User.UpsertUserByUsername(user)
+	}
+
+	if user.Username != "" {
+		if err := s.User.ClaimUsername(user.TgID, user.Username); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.User.UpsertUserByTgID(user)
+}
+
 func (u *UserDBService) GetUserByUsername(username string) (*model.User, error) {
 	user, err := u.store.User.GetUserByUsername(username)
 	if err != nil {
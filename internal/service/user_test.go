@@ -12,8 +12,11 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_CreateUser(t *testing.T) {
-	userInput := &model.UserDTO{Username: "test", Fullname: "test test", ImageURL: "test.jpg"}
+func Test_UpsertUser(t *testing.T) {
+	userInput := &model.UserDTO{TgID: 100, Username: "test", Fullname: "test test", ImageURL: "test.jpg"}
+	legacyInput := &model.UserDTO{Username: "test", Fullname: "test test", ImageURL: "test.jpg"}
+	noUsernameInput := &model.UserDTO{TgID: 100, Fullname: "test test", ImageURL: "test.jpg"}
+	anonymousInput := &model.UserDTO{Fullname: "test test", ImageURL: "test.jpg"}
 
 	tests := []struct {
 		name           string
@@ -24,41 +27,65 @@ func Test_CreateUser(t *testing.T) {
 		expectedErrMsg string
 	}{
 		{
-			name: "Ok: [new user created]",
+			name: "Ok: [user upserted by tg id]",
 			mock: func(userRepo *mocks.UserRepo) {
-				userRepo.On("GetUserByUsername", userInput.Username).Return(nil, pg.ErrUserNotFound)
-				userRepo.On("CreateUser", userInput).Return(1, nil)
+				userRepo.On("ClaimUsername", int64(100), "test").Return(nil)
+				userRepo.On("UpsertUserByTgID", userInput).Return(1, nil)
 			},
 			input: userInput,
 			want:  1,
 		},
 		{
-			name: "Ok: [new user not created, candidate return an id]",
+			name: "Ok: [user without username upserted by tg id]",
 			mock: func(userRepo *mocks.UserRepo) {
-				userRepo.On("GetUserByUsername", userInput.Username).Return(&model.User{ID: 10}, nil)
+				userRepo.On("UpsertUserByTgID", noUsernameInput).Return(1, nil)
 			},
-			input: userInput,
+			input: noUsernameInput,
+			want:  1,
+		},
+		{
+			name: "Ok: [user without tg id upserted by username]",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("UpsertUserByUsername", legacyInput).Return(10, nil)
+			},
+			input: legacyInput,
 			want:  10,
 		},
+		{
+			name: "Ok: [anonymous user upserted]",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("UpsertAnonymousUser", anonymousInput).Return(20, nil)
+			},
+			input: anonymousInput,
+			want:  20,
+		},
+		{
+			name: "Error: [username not claimed]",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("ClaimUsername", int64(100), "test").Return(fmt.Errorf("failed to release username: some error"))
+			},
+			input:          userInput,
+			wantErr:        true,
+			expectedErrMsg: "[User] srv.UpsertUser error: failed to release username: some error",
+		},
 		{
 			name: "Error: [user not created]",
 			mock: func(userRepo *mocks.UserRepo) {
-				userRepo.On("GetUserByUsername", userInput.Username).Return(nil, pg.ErrUserNotFound)
-				userRepo.On("CreateUser", userInput).Return(0, pg.ErrUserNotCreated)
+				userRepo.On("ClaimUsername", int64(100), "test").Return(nil)
+				userRepo.On("UpsertUserByTgID", userInput).Return(0, pg.ErrUserNotCreated)
 			},
 			input:          userInput,
 			wantErr:        true,
-			expectedErrMsg: "[User] srv.CreateUser error: user not created",
+			expectedErrMsg: "[User] srv.UpsertUser error: user not created",
 		},
 		{
 			name: "Error: [some store error]",
 			mock: func(userRepo *mocks.UserRepo) {
-				userRepo.On("GetUserByUsername", userInput.Username).Return(nil, pg.ErrUserNotFound)
-				userRepo.On("CreateUser", userInput).Return(0, fmt.Errorf("failed to create user: some error"))
+				userRepo.On("UpsertUserByUsername", legacyInput).Return(0, fmt.Errorf("failed to upsert user by username: some error"))
 			},
-			input:          userInput,
+			input:          legacyInput,
 			wantErr:        true,
-			expectedErrMsg: "[User] srv.CreateUser error: failed to create user: some error",
+			expectedErrMsg: "[User] srv.UpsertUser error: failed to upsert user by username: some error",
 		},
 	}
 
@@ -69,7 +96,7 @@ func Test_CreateUser(t *testing.T) {
 
 			tt.mock(userRepo)
 
-			got, err := srv.CreateUser(tt.input)
+			got, err := srv.UpsertUser(tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -1,19 +1,55 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/store"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/internal/validation"
+	"github.com/VladPetriv/scanner_backend_api/pkg/config"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var ErrEmptyAdminPassword = errors.New("admin password is empty")
+
 type WebUserDBService struct {
-	store *store.Store
+	store     *store.Store
+	passwords *validation.PasswordPolicy
 }
 
-func NewWebUserService(store *store.Store) *WebUserDBService {
-	return &WebUserDBService{store: store}
+// NewPasswordPolicy creates policy of new passwords from config, default minimal length is used when it's not set.
+func NewPasswordPolicy(cfg *config.Config) (*validation.PasswordPolicy, error) {
+	var minLength int
+
+	if cfg.PasswordMinLength != "" {
+		var err error
+
+		minLength, err = strconv.Atoi(cfg.PasswordMinLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PASSWORD_MIN_LENGTH: %w", err)
+		}
+	}
+
+	passwords, err := validation.NewPasswordPolicy(minLength, cfg.BreachedPasswords)
+	if err != nil {
+		return nil, err
+	}
+
+	return passwords, nil
+}
+
+// NewWebUserService creates web user service, passwords of new web users have to follow passwords policy.
+// Default policy is used when it's nil.
+func NewWebUserService(store *store.Store, passwords *validation.PasswordPolicy) *WebUserDBService {
+	if passwords == nil {
+		passwords, _ = validation.NewPasswordPolicy(validation.DefaultPasswordMinLength, "")
+	}
+
+	return &WebUserDBService{store: store, passwords: passwords}
 }
 
 func (w *WebUserDBService) GetWebUserByEmail(email string) (*model.WebUser, error) {
@@ -25,10 +61,17 @@ func (w *WebUserDBService) GetWebUserByEmail(email string) (*model.WebUser, erro
 	return user, nil
 }
 
-func (w *WebUserDBService) CreateWebUser(user *model.WebUser) error {
-	user.Password, _ = w.HashPassword(user.Password)
+// CreateWebUser validates user and creates it with member role, role from input is ignored.
+// Every invalid field is reported in validation.Errors. Normalized email, password hash and id of created user are set to user.
+// Sign-up is recorded in audit trail.
+func (w *WebUserDBService) CreateWebUser(user *model.WebUser, origin model.Origin) error {
+	if err := w.prepareWebUser(user); err != nil {
+		return fmt.Errorf("[WebUser] srv.CreateWebUser error: %w", err)
+	}
 
-	_, err := w.store.WebUser.CreateWebUser(user)
+	err := w.store.InTx(func(tx *store.Store) error {
+		return insertWebUser(tx, user, origin)
+	})
 	if err != nil {
 		return fmt.Errorf("[WebUser] srv.CreateWebUser error: %w", err)
 	}
@@ -36,6 +79,115 @@ func (w *WebUserDBService) CreateWebUser(user *model.WebUser) error {
 	return nil
 }
 
+// prepareWebUser validates new web user and sets normalized email, password hash and member role to it.
+func (w *WebUserDBService) prepareWebUser(user *model.WebUser) error {
+	var verrs validation.Errors
+
+	email, err := validation.NormalizeEmail(user.Email)
+	verrs.Add("email", err)
+	verrs.Add("password", w.passwords.Validate(user.Password))
+
+	if err := verrs.Err(); err != nil {
+		return err
+	}
+
+	hashedPassword, err := w.HashPassword(user.Password)
+	if err != nil {
+		return err
+	}
+
+	user.Email = email
+	user.Password = hashedPassword
+	user.Role = model.RoleMember
+
+	return nil
+}
+
+// insertWebUser creates prepared web user with store of transaction and records sign-up in audit trail.
+func insertWebUser(tx *store.Store, user *model.WebUser, origin model.Origin) error {
+	ID, err := tx.WebUser.CreateWebUser(user)
+	if err != nil {
+		return err
+	}
+
+	user.ID = ID
+
+	return recordAuditEvent(tx, auditEntry{
+		ActorID: &user.ID,
+		Action:  model.AuditSignUp,
+		Origin:  origin,
+		After:   map[string]interface{}{"id": user.ID, "email": user.Email, "role": user.Role},
+	})
+}
+
+// BootstrapAdmin gives admin role to web user with email, web user is created with password when it doesn't exist.
+// The grant is recorded in audit trail with origin in the same transaction. It reports whether web user was created.
+func (w *WebUserDBService) BootstrapAdmin(email, password string, origin model.Origin) (bool, error) {
+	email, err := validation.NormalizeEmail(email)
+	if err != nil {
+		return false, fmt.Errorf("[WebUser] srv.BootstrapAdmin error: email %w", err)
+	}
+
+	user, err := w.store.WebUser.GetWebUserByEmail(email)
+	if err != nil && !errors.Is(err, pg.ErrWebUserNotFound) {
+		return false, fmt.Errorf("[WebUser] srv.BootstrapAdmin error: %w", err)
+	}
+
+	if user != nil {
+		err = w.store.InTx(func(tx *store.Store) error {
+			_, err := tx.WebUser.UpdateWebUserRole(user.ID, model.RoleAdmin)
+			if err != nil {
+				return err
+			}
+
+			return recordAuditEvent(tx, auditEntry{
+				ActorID: &user.ID,
+				Action:  model.AuditRoleGranted,
+				Origin:  origin,
+				Before:  map[string]interface{}{"id": user.ID, "email": user.Email, "role": user.Role},
+				After:   map[string]interface{}{"id": user.ID, "email": user.Email, "role": model.RoleAdmin},
+			})
+		})
+		if err != nil {
+			return false, fmt.Errorf("[WebUser] srv.BootstrapAdmin error: %w", err)
+		}
+
+		return false, nil
+	}
+
+	if password == "" {
+		return false, fmt.Errorf("[WebUser] srv.BootstrapAdmin error: %w", ErrEmptyAdminPassword)
+	}
+
+	if err := w.passwords.Validate(password); err != nil {
+		return false, fmt.Errorf("[WebUser] srv.BootstrapAdmin error: password %w", err)
+	}
+
+	hashedPassword, err := w.HashPassword(password)
+	if err != nil {
+		return false, fmt.Errorf("[WebUser] srv.BootstrapAdmin error: %w", err)
+	}
+
+	err = w.store.InTx(func(tx *store.Store) error {
+		ID, err := tx.WebUser.CreateWebUser(&model.WebUser{Email: email, Password: hashedPassword, Role: model.RoleAdmin})
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(tx, auditEntry{
+			ActorID: &ID,
+			Action:  model.AuditRoleGranted,
+			Origin:  origin,
+			After:   map[string]interface{}{"id": ID, "email": email, "role": model.RoleAdmin},
+		})
+	})
+	if err != nil {
+		return false, fmt.Errorf("[WebUser] srv.BootstrapAdmin error: %w", err)
+	}
+
+	return true, nil
+}
+
 func (w *WebUserDBService) HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
 	if err != nil {
@@ -45,6 +197,16 @@ func (w *WebUserDBService) HashPassword(password string) (string, error) {
 	return string(bytes), nil
 }
 
+// lookupEmail returns email in form it's stored in, invalid email is returned only trimmed, so nobody is found by it.
+func lookupEmail(email string) string {
+	normalized, err := validation.NormalizeEmail(email)
+	if err != nil {
+		return strings.TrimSpace(email)
+	}
+
+	return normalized
+}
+
 func (w *WebUserDBService) ComparePassword(password, hashedPassword string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 
@@ -2,59 +2,113 @@ package service_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/service"
 	"github.com/VladPetriv/scanner_backend_api/internal/store"
 	"github.com/VladPetriv/scanner_backend_api/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend_api/pkg/config"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_CreateWebUser(t *testing.T) {
-	data := &model.WebUser{
-		Email:    "test@test.com",
-		Password: "test_pswd",
+	createQuery := "INSERT INTO web_user(email, password, role) VALUES ($1, $2, $3) RETURNING id;"
+	signUp := func(mock sqlmock.Sqlmock, ID int, email string) {
+		expectAuditEvent(mock, ID, model.AuditSignUp, "", nil, fmt.Sprintf(`{"email":%q,"id":%d,"role":"member"}`, email, ID))
 	}
+
 	tests := []struct {
-		name    string
-		mock    func(webUserRepo *mocks.WebUserRepo)
-		input   *model.WebUser
-		wantErr bool
+		name           string
+		mock           func(mock sqlmock.Sqlmock)
+		input          *model.WebUser
+		wantErr        bool
+		expectedErrMsg string
 	}{
 		{
 			name: "Ok: [web user created]",
-			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("CreateWebUser", data).Return(1, nil)
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(createQuery).
+					WithArgs("test@test.com", bcryptHashOf("test_pswd"), model.RoleMember).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				signUp(mock, 1, "test@test.com")
+				mock.ExpectCommit()
+			},
+			input: &model.WebUser{Email: "test@test.com", Password: "test_pswd", Role: model.RoleAdmin},
+		},
+		{
+			name: "Ok: [web user created with normalized email]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(createQuery).
+					WithArgs("Test@example.com", bcryptHashOf("test_pswd"), model.RoleMember).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				signUp(mock, 2, "Test@example.com")
+				mock.ExpectCommit()
 			},
-			input: data,
+			input: &model.WebUser{Email: " Test@Example.COM ", Password: "test_pswd"},
+		},
+		{
+			name:           "Error: [email and password are not valid]",
+			mock:           func(mock sqlmock.Sqlmock) {},
+			input:          &model.WebUser{Email: "test", Password: "test"},
+			wantErr:        true,
+			expectedErrMsg: "[WebUser] srv.CreateWebUser error: input is not valid: email is not valid email address; password must be at least 8 characters long",
+		},
+		{
+			name:           "Error: [password is too long for bcrypt]",
+			mock:           func(mock sqlmock.Sqlmock) {},
+			input:          &model.WebUser{Email: "test@test.com", Password: strings.Repeat("a", 73)},
+			wantErr:        true,
+			expectedErrMsg: "[WebUser] srv.CreateWebUser error: input is not valid: password must be at most 72 bytes long",
 		},
 		{
 			name: "Error: [web user not created]",
-			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("CreateWebUser", data).Return(0, fmt.Errorf("web user not created"))
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(createQuery).
+					WithArgs("test@test.com", bcryptHashOf("test_pswd"), model.RoleMember).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			input:          &model.WebUser{Email: "test@test.com", Password: "test_pswd"},
+			wantErr:        true,
+			expectedErrMsg: "[WebUser] srv.CreateWebUser error: web user not created",
+		},
+		{
+			name: "Error: [audit event not created]",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(createQuery).
+					WithArgs("test@test.com", bcryptHashOf("test_pswd"), model.RoleMember).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(auditEventQuery).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectRollback()
 			},
-			input:   data,
-			wantErr: true,
+			input:          &model.WebUser{Email: "test@test.com", Password: "test_pswd"},
+			wantErr:        true,
+			expectedErrMsg: "[WebUser] srv.CreateWebUser error: failed to create audit event: some error",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			webUserRepo := &mocks.WebUserRepo{}
-			srv := service.NewWebUserService(&store.Store{WebUser: webUserRepo})
+			stores, dbMock := newTxStore(t)
+			srv := service.NewWebUserService(stores, nil)
 
-			tt.mock(webUserRepo)
+			tt.mock(dbMock)
 
-			err := srv.CreateWebUser(tt.input)
+			err := srv.CreateWebUser(tt.input, testOrigin)
 
 			if tt.wantErr {
 				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
 			} else {
 				assert.NoError(t, err)
+				assert.EqualValues(t, model.RoleMember, tt.input.Role)
 			}
 
-			webUserRepo.AssertExpectations(t)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
 		})
 	}
 }
@@ -101,7 +155,7 @@ func Test_GetWebUserByEmail(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			webUserRepo := &mocks.WebUserRepo{}
-			srv := service.NewWebUserService(&store.Store{WebUser: webUserRepo})
+			srv := service.NewWebUserService(&store.Store{WebUser: webUserRepo}, nil)
 
 			tt.mock(webUserRepo)
 
@@ -118,3 +172,135 @@ func Test_GetWebUserByEmail(t *testing.T) {
 		})
 	}
 }
+
+func Test_BootstrapAdmin(t *testing.T) {
+	tests := []struct {
+		name           string
+		mock           func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock)
+		password       string
+		want           bool
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [existing web user became admin]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByEmail", "admin@test.com").Return(&model.WebUser{ID: 1, Email: "admin@test.com", Role: model.RoleMember}, nil)
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("UPDATE web_user SET role = $1 WHERE id = $2 RETURNING id;").WithArgs(model.RoleAdmin, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				expectAuditEvent(
+					dbMock, 1, model.AuditRoleGranted, "",
+					`{"email":"admin@test.com","id":1,"role":"member"}`, `{"email":"admin@test.com","id":1,"role":"admin"}`,
+				)
+				dbMock.ExpectCommit()
+			},
+		},
+		{
+			name: "Ok: [admin created]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByEmail", "admin@test.com").Return(nil, fmt.Errorf("[WebUser] srv.GetWebUserByEmail error: %w", pg.ErrWebUserNotFound))
+				dbMock.ExpectBegin()
+				dbMock.ExpectQuery("INSERT INTO web_user(email, password, role) VALUES ($1, $2, $3) RETURNING id;").
+					WithArgs("admin@test.com", bcryptHashOf("admin_pswd"), model.RoleAdmin).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				expectAuditEvent(dbMock, 1, model.AuditRoleGranted, "", nil, `{"email":"admin@test.com","id":1,"role":"admin"}`)
+				dbMock.ExpectCommit()
+			},
+			password: "admin_pswd",
+			want:     true,
+		},
+		{
+			name: "Error: [admin password is empty]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByEmail", "admin@test.com").Return(nil, pg.ErrWebUserNotFound)
+			},
+			wantErr:        true,
+			expectedErrMsg: "[WebUser] srv.BootstrapAdmin error: admin password is empty",
+		},
+		{
+			name: "Error: [admin password is too short]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByEmail", "admin@test.com").Return(nil, pg.ErrWebUserNotFound)
+			},
+			password:       "admin",
+			wantErr:        true,
+			expectedErrMsg: "[WebUser] srv.BootstrapAdmin error: password must be at least 8 characters long",
+		},
+		{
+			name: "Error: [some store error]",
+			mock: func(webUserRepo *mocks.WebUserRepo, dbMock sqlmock.Sqlmock) {
+				webUserRepo.On("GetWebUserByEmail", "admin@test.com").Return(nil, fmt.Errorf("failed to get web user by email: some error"))
+			},
+			password:       "admin_pswd",
+			wantErr:        true,
+			expectedErrMsg: "[WebUser] srv.BootstrapAdmin error: failed to get web user by email: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores, dbMock := newTxStore(t)
+			webUserRepo := &mocks.WebUserRepo{}
+			stores.WebUser = webUserRepo
+			srv := service.NewWebUserService(stores, nil)
+
+			tt.mock(webUserRepo, dbMock)
+
+			got, err := srv.BootstrapAdmin("admin@test.com", tt.password, testOrigin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			webUserRepo.AssertExpectations(t)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_NewPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            *config.Config
+		password       string
+		wantInvalid    bool
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name:     "Ok: [default minimal length is used when it's not set]",
+			cfg:      &config.Config{},
+			password: "8 chars!",
+		},
+		{
+			name:        "Ok: [minimal length is taken from config]",
+			cfg:         &config.Config{PasswordMinLength: "12"},
+			password:    "11 chars!!!",
+			wantInvalid: true,
+		},
+		{
+			name:           "Error: [minimal length is not a number]",
+			cfg:            &config.Config{PasswordMinLength: "twelve"},
+			wantErr:        true,
+			expectedErrMsg: `failed to parse PASSWORD_MIN_LENGTH: strconv.Atoi: parsing "twelve": invalid syntax`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.NewPasswordPolicy(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantInvalid, got.Validate(tt.password) != nil)
+		})
+	}
+}
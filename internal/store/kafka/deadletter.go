@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+)
+
+// deadLetterSink receives messages which could not be ingested after all attempts.
+type deadLetterSink interface {
+	Send(msg *sarama.ConsumerMessage, cause error, attempts int) error
+	Close() error
+}
+
+// storeSink quarantines failed messages in the ingest_failure table, where they can be listed and replayed.
+type storeSink struct {
+	ingest service.IngestService
+}
+
+func (s *storeSink) Send(msg *sarama.ConsumerMessage, cause error, attempts int) error {
+	return s.ingest.QuarantineIngestFailure(&model.IngestFailure{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Payload:   msg.Value,
+		Error:     cause.Error(),
+		Attempts:  attempts,
+	})
+}
+
+func (s *storeSink) Close() error {
+	return nil
+}
+
+// topicSink forwards failed messages to a dead-letter topic. The original position,
+// the error and the attempts count are kept in the record headers.
+type topicSink struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+func newTopicSink(brokers []string, topic string) (*topicSink, error) {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter producer: %w", err)
+	}
+
+	return &topicSink{producer: producer, topic: topic}, nil
+}
+
+func (s *topicSink) Send(msg *sarama.ConsumerMessage, cause error, attempts int) error {
+	deadLetter := &sarama.ProducerMessage{
+		Topic: s.topic,
+		Value: sarama.ByteEncoder(msg.Value),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte("x-original-topic"), Value: []byte(msg.Topic)},
+			{Key: []byte("x-original-partition"), Value: []byte(strconv.Itoa(int(msg.Partition)))},
+			{Key: []byte("x-original-offset"), Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+			{Key: []byte("x-error"), Value: []byte(cause.Error())},
+			{Key: []byte("x-attempts"), Value: []byte(strconv.Itoa(attempts))},
+		},
+	}
+	if msg.Key != nil {
+		deadLetter.Key = sarama.ByteEncoder(msg.Key)
+	}
+
+	_, _, err := s.producer.SendMessage(deadLetter)
+	if err != nil {
+		return fmt.Errorf("failed to send message to dead-letter topic: %w", err)
+	}
+
+	return nil
+}
+
+func (s *topicSink) Close() error {
+	return s.producer.Close()
+}
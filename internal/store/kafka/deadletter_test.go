@@ -0,0 +1,132 @@
+package kafka
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	srvmocks "github.com/VladPetriv/scanner_backend_api/internal/service/mocks"
+)
+
+func Test_StoreSinkSend(t *testing.T) {
+	msg := &sarama.ConsumerMessage{Topic: service.MessagesTopic, Partition: 2, Offset: 7, Value: []byte("payload")}
+	failure := &model.IngestFailure{
+		Topic: service.MessagesTopic, Partition: 2, Offset: 7, Payload: []byte("payload"), Error: "some error", Attempts: 3,
+	}
+
+	tests := []struct {
+		name           string
+		mock           func(ingestSrv *srvmocks.IngestService)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [message quarantined in store]",
+			mock: func(ingestSrv *srvmocks.IngestService) {
+				ingestSrv.On("QuarantineIngestFailure", failure).Return(nil)
+			},
+		},
+		{
+			name: "Error: [message not quarantined]",
+			mock: func(ingestSrv *srvmocks.IngestService) {
+				ingestSrv.On("QuarantineIngestFailure", failure).
+					Return(fmt.Errorf("[Ingest] srv.QuarantineIngestFailure error: some store error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "[Ingest] srv.QuarantineIngestFailure error: some store error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingestSrv := &srvmocks.IngestService{}
+			tt.mock(ingestSrv)
+
+			err := (&storeSink{ingest: ingestSrv}).Send(msg, fmt.Errorf("some error"), 3)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			ingestSrv.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_TopicSinkSend(t *testing.T) {
+	msg := &sarama.ConsumerMessage{Topic: service.MessagesTopic, Partition: 2, Offset: 7, Key: []byte("key"), Value: []byte("payload")}
+
+	checkDeadLetter := func(deadLetter *sarama.ProducerMessage) error {
+		headers := make(map[string]string)
+		for _, header := range deadLetter.Headers {
+			headers[string(header.Key)] = string(header.Value)
+		}
+
+		expected := map[string]string{
+			"x-original-topic":     service.MessagesTopic,
+			"x-original-partition": "2",
+			"x-original-offset":    "7",
+			"x-error":              "some error",
+			"x-attempts":           "3",
+		}
+		if deadLetter.Topic != "dead-letter" || fmt.Sprint(headers) != fmt.Sprint(expected) {
+			return fmt.Errorf("unexpected dead-letter message to %s with headers %v", deadLetter.Topic, headers)
+		}
+
+		key, _ := deadLetter.Key.Encode()
+		value, _ := deadLetter.Value.Encode()
+		if string(key) != "key" || string(value) != "payload" {
+			return fmt.Errorf("unexpected dead-letter message %q: %q", key, value)
+		}
+
+		return nil
+	}
+
+	tests := []struct {
+		name           string
+		mock           func(producer *mocks.SyncProducer)
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [message sent to dead-letter topic with its position]",
+			mock: func(producer *mocks.SyncProducer) {
+				producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(checkDeadLetter)
+			},
+		},
+		{
+			name: "Error: [dead-letter topic is not available]",
+			mock: func(producer *mocks.SyncProducer) {
+				producer.ExpectSendMessageAndFail(sarama.ErrLeaderNotAvailable)
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to send message to dead-letter topic: " + sarama.ErrLeaderNotAvailable.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := mocks.NewSyncProducer(t, nil)
+			tt.mock(producer)
+
+			sink := &topicSink{producer: producer, topic: "dead-letter"}
+
+			err := sink.Send(msg, fmt.Errorf("some error"), 3)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, sink.Close())
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+const maxAttempts = 3
+
+var retryBackoff = time.Second
+
+type groupHandler struct {
+	ingest     service.IngestService
+	deadLetter deadLetterSink
+	log        *logger.Logger
+}
+
+func (h *groupHandler) Setup(sess sarama.ConsumerGroupSession) error {
+	h.log.Info("kafka session started", zap.Any("claims", sess.Claims()), zap.Int32("generation", sess.GenerationID()))
+
+	return nil
+}
+
+func (h *groupHandler) Cleanup(sess sarama.ConsumerGroupSession) error {
+	h.log.Info("kafka session finished", zap.Int32("generation", sess.GenerationID()))
+
+	return nil
+}
+
+// ConsumeClaim marks a message only after it was ingested or dead-lettered. Otherwise the offset
+// is left uncommitted and the session is ended, so the message is delivered again once the group rejoins.
+func (h *groupHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			if err := h.handle(sess.Context(), msg); err != nil {
+				h.log.Error(
+					"failed to handle kafka message",
+					zap.String("topic", msg.Topic),
+					zap.Int32("partition", msg.Partition),
+					zap.Int64("offset", msg.Offset),
+					zap.Error(err),
+				)
+
+				select {
+				case <-time.After(rejoinBackoff):
+				case <-sess.Context().Done():
+				}
+
+				return fmt.Errorf("failed to handle message %s/%d/%d: %w", msg.Topic, msg.Partition, msg.Offset, err)
+			}
+
+			sess.MarkMessage(msg, "")
+
+		case <-sess.Context().Done():
+			return nil
+		}
+	}
+}
+
+// handle ingests the message, retrying transient failures, and dead-letters it when
+// attempts are exhausted or the payload can never be ingested.
+func (h *groupHandler) handle(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	var (
+		err      error
+		attempts int
+	)
+
+	for attempts < maxAttempts {
+		attempts++
+
+		err = h.ingest.IngestPayload(msg.Topic, msg.Value)
+		if err == nil {
+			return nil
+		}
+
+		if errors.Is(err, service.ErrInvalidPayload) || errors.Is(err, service.ErrUnknownTopic) {
+			break
+		}
+
+		if attempts < maxAttempts {
+			select {
+			case <-time.After(retryBackoff * time.Duration(attempts)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	if dlErr := h.deadLetter.Send(msg, err, attempts); dlErr != nil {
+		return fmt.Errorf("failed to dead-letter message after error %q: %w", err.Error(), dlErr)
+	}
+
+	h.log.Warn(
+		"kafka message dead-lettered",
+		zap.String("topic", msg.Topic),
+		zap.Int32("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.Int("attempts", attempts),
+		zap.Error(err),
+	)
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/service"
+	srvmocks "github.com/VladPetriv/scanner_backend_api/internal/service/mocks"
+	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
+)
+
+// fakeSink is dead-letter sink which records messages sent to it.
+type fakeSink struct {
+	mock.Mock
+}
+
+func (f *fakeSink) Send(msg *sarama.ConsumerMessage, cause error, attempts int) error {
+	return f.Called(msg, cause, attempts).Error(0)
+}
+
+func (f *fakeSink) Close() error {
+	return f.Called().Error(0)
+}
+
+// fakeSession is consumer group session which records marked messages.
+type fakeSession struct {
+	ctx    context.Context
+	marked []*sarama.ConsumerMessage
+}
+
+func (s *fakeSession) Claims() map[string][]int32                                        { return nil }
+func (s *fakeSession) MemberID() string                                                  { return "member" }
+func (s *fakeSession) GenerationID() int32                                               { return 1 }
+func (s *fakeSession) MarkOffset(topic string, partition int32, offset int64, m string)  {}
+func (s *fakeSession) Commit()                                                           {}
+func (s *fakeSession) ResetOffset(topic string, partition int32, offset int64, m string) {}
+func (s *fakeSession) Context() context.Context                                          { return s.ctx }
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.marked = append(s.marked, msg)
+}
+
+// fakeClaim is consumer group claim which delivers messages from its channel.
+type fakeClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return service.MessagesTopic }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// withoutBackoff makes retries and rejoins immediate for the duration of the test.
+func withoutBackoff(t *testing.T) {
+	retry, rejoin := retryBackoff, rejoinBackoff
+	retryBackoff, rejoinBackoff = time.Millisecond, time.Millisecond
+
+	t.Cleanup(func() { retryBackoff, rejoinBackoff = retry, rejoin })
+}
+
+func testMessage(offset int64) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{Topic: service.MessagesTopic, Partition: 0, Offset: offset, Value: []byte(`{"MessageURL":"test.url"}`)}
+}
+
+func Test_GroupHandlerHandle(t *testing.T) {
+	withoutBackoff(t)
+
+	msg := testMessage(1)
+	storeErr := fmt.Errorf("[Ingest] srv.IngestMessage error: failed to create message: some error")
+	payloadErr := fmt.Errorf("[Ingest] srv.IngestMessage error: %w", service.ErrInvalidPayload)
+
+	tests := []struct {
+		name           string
+		mock           func(ingestSrv *srvmocks.IngestService, sink *fakeSink)
+		wantAttempts   int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [message ingested at first attempt]",
+			mock: func(ingestSrv *srvmocks.IngestService, sink *fakeSink) {
+				ingestSrv.On("IngestPayload", msg.Topic, msg.Value).Return(nil).Once()
+			},
+			wantAttempts: 1,
+		},
+		{
+			name: "Ok: [message ingested after transient failure]",
+			mock: func(ingestSrv *srvmocks.IngestService, sink *fakeSink) {
+				ingestSrv.On("IngestPayload", msg.Topic, msg.Value).Return(storeErr).Once()
+				ingestSrv.On("IngestPayload", msg.Topic, msg.Value).Return(nil).Once()
+			},
+			wantAttempts: 2,
+		},
+		{
+			name: "Ok: [message dead-lettered when attempts are exhausted]",
+			mock: func(ingestSrv *srvmocks.IngestService, sink *fakeSink) {
+				ingestSrv.On("IngestPayload", msg.Topic, msg.Value).Return(storeErr).Times(maxAttempts)
+				sink.On("Send", msg, storeErr, maxAttempts).Return(nil).Once()
+			},
+			wantAttempts: maxAttempts,
+		},
+		{
+			name: "Ok: [invalid payload dead-lettered without retries]",
+			mock: func(ingestSrv *srvmocks.IngestService, sink *fakeSink) {
+				ingestSrv.On("IngestPayload", msg.Topic, msg.Value).Return(payloadErr).Once()
+				sink.On("Send", msg, payloadErr, 1).Return(nil).Once()
+			},
+			wantAttempts: 1,
+		},
+		{
+			name: "Error: [message can't be dead-lettered]",
+			mock: func(ingestSrv *srvmocks.IngestService, sink *fakeSink) {
+				ingestSrv.On("IngestPayload", msg.Topic, msg.Value).Return(payloadErr).Once()
+				sink.On("Send", msg, payloadErr, 1).Return(fmt.Errorf("failed to send message to dead-letter topic: some error")).Once()
+			},
+			wantAttempts: 1,
+			wantErr:      true,
+			expectedErrMsg: `failed to dead-letter message after error "[Ingest] srv.IngestMessage error: invalid payload": ` +
+				"failed to send message to dead-letter topic: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingestSrv := &srvmocks.IngestService{}
+			sink := &fakeSink{}
+			h := &groupHandler{ingest: ingestSrv, deadLetter: sink, log: logger.Get("debug")}
+
+			tt.mock(ingestSrv, sink)
+
+			err := h.handle(context.Background(), msg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			ingestSrv.AssertNumberOfCalls(t, "IngestPayload", tt.wantAttempts)
+			ingestSrv.AssertExpectations(t)
+			sink.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_GroupHandlerHandleStopsRetriesWhenSessionEnds(t *testing.T) {
+	retry := retryBackoff
+	retryBackoff = time.Hour
+	t.Cleanup(func() { retryBackoff = retry })
+
+	msg := testMessage(1)
+	ingestSrv := &srvmocks.IngestService{}
+	ingestSrv.On("IngestPayload", msg.Topic, msg.Value).Return(fmt.Errorf("some error")).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	h := &groupHandler{ingest: ingestSrv, deadLetter: &fakeSink{}, log: logger.Get("debug")}
+
+	err := h.handle(ctx, msg)
+	assert.ErrorIs(t, err, context.Canceled)
+	ingestSrv.AssertExpectations(t)
+}
+
+func Test_GroupHandlerConsumeClaim(t *testing.T) {
+	withoutBackoff(t)
+
+	first, second, third := testMessage(1), testMessage(2), testMessage(3)
+
+	tests := []struct {
+		name           string
+		mock           func(ingestSrv *srvmocks.IngestService, sink *fakeSink)
+		messages       []*sarama.ConsumerMessage
+		wantMarked     []*sarama.ConsumerMessage
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [ingested and dead-lettered messages are marked]",
+			mock: func(ingestSrv *srvmocks.IngestService, sink *fakeSink) {
+				ingestSrv.On("IngestPayload", service.MessagesTopic, first.Value).Return(nil).Once()
+				ingestSrv.On("IngestPayload", service.MessagesTopic, second.Value).Return(service.ErrInvalidPayload).Once()
+				sink.On("Send", second, service.ErrInvalidPayload, 1).Return(nil).Once()
+			},
+			messages:   []*sarama.ConsumerMessage{first, second},
+			wantMarked: []*sarama.ConsumerMessage{first, second},
+		},
+		{
+			name: "Error: [message which can't be dead-lettered is not marked and session ends]",
+			mock: func(ingestSrv *srvmocks.IngestService, sink *fakeSink) {
+				ingestSrv.On("IngestPayload", service.MessagesTopic, first.Value).Return(nil).Once()
+				ingestSrv.On("IngestPayload", service.MessagesTopic, second.Value).Return(service.ErrInvalidPayload).Once()
+				sink.On("Send", second, service.ErrInvalidPayload, 1).Return(fmt.Errorf("some error")).Once()
+			},
+			messages:       []*sarama.ConsumerMessage{first, second, third},
+			wantMarked:     []*sarama.ConsumerMessage{first},
+			wantErr:        true,
+			expectedErrMsg: `failed to handle message messages.get/0/2: failed to dead-letter message after error "invalid payload": some error`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingestSrv := &srvmocks.IngestService{}
+			sink := &fakeSink{}
+			h := &groupHandler{ingest: ingestSrv, deadLetter: sink, log: logger.Get("debug")}
+
+			tt.mock(ingestSrv, sink)
+
+			claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, len(tt.messages))}
+			for _, msg := range tt.messages {
+				claim.messages <- msg
+			}
+			close(claim.messages)
+
+			sess := &fakeSession{ctx: context.Background()}
+
+			err := h.ConsumeClaim(sess, claim)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantMarked, sess.marked)
+			ingestSrv.AssertExpectations(t)
+			sink.AssertExpectations(t)
+		})
+	}
+}
@@ -1,148 +1,118 @@
 package kafka
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Shopify/sarama"
-	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"go.uber.org/zap"
+
 	"github.com/VladPetriv/scanner_backend_api/internal/service"
-	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
 	"github.com/VladPetriv/scanner_backend_api/pkg/config"
 	"github.com/VladPetriv/scanner_backend_api/pkg/logger"
-	"go.uber.org/zap"
 )
 
-func createWorker(addr string) (sarama.Consumer, error) {
-	config := sarama.NewConfig()
-	config.Consumer.Return.Errors = true
+const defaultGroupID = "scanner_backend_api"
 
-	conn, err := sarama.NewConsumer([]string{addr}, config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create connection: %w", err)
-	}
+var (
+	ErrNoKafkaAddr = errors.New("no kafka address provided")
 
-	return conn, nil
+	rejoinBackoff = time.Second * 5
+)
+
+// Runner consumes scanner topics as a member of a consumer group.
+// Partitions are balanced across every API instance that uses the same group id
+// and offsets are committed only for messages that were persisted.
+type Runner struct {
+	group   sarama.ConsumerGroup
+	handler *groupHandler
+	topics  []string
+	log     *logger.Logger
 }
 
-func GetChannelFromQueue(srvManager *service.Manager, cfg *config.Config, log *logger.Logger) {
-	worker, err := createWorker(cfg.KafkaAddr)
-	if err != nil {
-		log.Error("failed to create kafka worker", zap.Error(err))
+func NewRunner(srvManager *service.Manager, cfg *config.Config, log *logger.Logger) (*Runner, error) {
+	if cfg.KafkaAddr == "" {
+		return nil, ErrNoKafkaAddr
 	}
 
-	consumer, err := worker.ConsumePartition("channels.get", 0, sarama.OffsetOldest)
-	if err != nil {
-		log.Error("failed to create consumer", zap.Error(err))
+	groupID := cfg.KafkaGroupID
+	if groupID == "" {
+		groupID = defaultGroupID
 	}
 
-	go func() {
-		for {
-			select {
-			case err := <-consumer.Errors():
-				log.Error("failed to get channels from kafka queue", zap.Error(err))
-
-				return
-
-			case data := <-consumer.Messages():
-				channel := model.ChannelDTO{}
-
-				err := json.Unmarshal(data.Value, &channel)
-				if err != nil {
-					log.Error("unmarshal error", zap.Error(err))
-				}
-
-				candidate, err := srvManager.Channel.GetChannelByName(channel.Name)
-				if err != nil && !errors.Is(err, pg.ErrChannelNotFound) {
-					log.Error("get channel by name error", zap.Error(err))
-				}
-
-				if candidate == nil {
-					err := srvManager.Channel.CreateChannel(&channel)
-					if err != nil {
-						log.Error("create channel error", zap.Error(err))
-					}
-				} else {
-					log.Info(fmt.Sprintf("channel with name %s is exist", channel.Name))
-				}
-			}
+	brokers := strings.Split(cfg.KafkaAddr, ",")
+
+	var deadLetter deadLetterSink = &storeSink{ingest: srvManager.Ingest}
+	if cfg.KafkaDeadLetterTopic != "" {
+		sink, err := newTopicSink(brokers, cfg.KafkaDeadLetterTopic)
+		if err != nil {
+			return nil, err
 		}
-	}()
-}
 
-func GetDataFromQueue(srvManager *service.Manager, cfg *config.Config, log *logger.Logger) {
-	worker, err := createWorker(cfg.KafkaAddr)
-	if err != nil {
-		log.Error("failed to create kafka worker", zap.Error(err))
+		deadLetter = sink
 	}
 
-	consumer, err := worker.ConsumePartition("messages.get", 0, sarama.OffsetOldest)
+	config := sarama.NewConfig()
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategySticky
+
+	group, err := sarama.NewConsumerGroup(brokers, groupID, config)
 	if err != nil {
-		log.Error("failed to create consumer", zap.Error(err))
+		deadLetter.Close()
+
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
 	}
 
+	return &Runner{
+		group: group,
+		handler: &groupHandler{
+			ingest:     srvManager.Ingest,
+			deadLetter: deadLetter,
+			log:        log,
+		},
+		topics: []string{service.ChannelsTopic, service.MessagesTopic},
+		log:    log,
+	}, nil
+}
+
+// Run joins the consumer group and blocks until ctx is cancelled or the group is closed.
+// Every rebalance ends the current session, so Consume is called in a loop to rejoin.
+func (r *Runner) Run(ctx context.Context) {
 	go func() {
-		for {
+		for err := range r.group.Errors() {
+			r.log.Error("kafka consumer group error", zap.Error(err))
+		}
+	}()
+
+	for {
+		err := r.group.Consume(ctx, r.topics, r.handler)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return
+		}
+
+		if err != nil {
+			r.log.Error("failed to consume from kafka", zap.Error(err))
+
 			select {
-			case err := <-consumer.Errors():
-				log.Error("failed to get data from kafka queue", zap.Error(err))
-
-				return
-			case data := <-consumer.Messages():
-				telegramMessage := model.TgMessage{}
-
-				err := json.Unmarshal(data.Value, &telegramMessage)
-				if err != nil {
-					log.Error("unmarshal error", zap.Error(err))
-				}
-
-				channel, err := srvManager.Channel.GetChannelByName(telegramMessage.PeerID.Username)
-				if err != nil {
-					log.Error("get channel by name error", zap.Error(err))
-				}
-
-				userID, err := srvManager.User.CreateUser(&model.UserDTO{
-					Username: telegramMessage.FromID.Username,
-					Fullname: telegramMessage.FromID.Fullname,
-					ImageURL: telegramMessage.FromID.ImageURL,
-				})
-				if err != nil {
-					log.Error("create user error", zap.Error(err))
-				}
-
-				messageID, err := srvManager.Message.CreateMessage(&model.MessageDTO{
-					ChannelID:  channel.ID,
-					UserID:     userID,
-					Title:      telegramMessage.Message,
-					MessageURL: telegramMessage.MessageURL,
-					ImageURL:   telegramMessage.ImageURL,
-				})
-				if err != nil {
-					log.Error("create message error", zap.Error(err))
-				}
-
-				for _, replie := range telegramMessage.Replies.Messages {
-					userID, err := srvManager.User.CreateUser(&model.UserDTO{
-						Username: replie.FromID.Username,
-						Fullname: replie.FromID.Fullname,
-						ImageURL: replie.FromID.ImageURL,
-					})
-					if err != nil {
-						log.Error("create user for replie error", zap.Error(err))
-					}
-
-					err = srvManager.Replie.CreateReplie(&model.ReplieDTO{
-						MessageID: messageID,
-						UserID:    userID,
-						Title:     replie.Message,
-						ImageURL:  replie.ImageURL,
-					})
-					if err != nil {
-						log.Error("create replie error", zap.Error(err))
-					}
-				}
+			case <-time.After(rejoinBackoff):
+			case <-ctx.Done():
 			}
 		}
-	}()
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (r *Runner) Close() error {
+	if err := r.group.Close(); err != nil {
+		return fmt.Errorf("failed to close consumer group: %w", err)
+	}
+
+	return r.handler.deadLetter.Close()
 }
@@ -0,0 +1,137 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyRepo is an autogenerated mock type for the APIKeyRepo type
+type APIKeyRepo struct {
+	mock.Mock
+}
+
+// CreateAPIKey provides a mock function with given fields: key
+func (_m *APIKeyRepo) CreateAPIKey(key *model.APIKey) (int, error) {
+	ret := _m.Called(key)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.APIKey) int); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.APIKey) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeys provides a mock function with given fields: userID
+func (_m *APIKeyRepo) GetAPIKeys(userID int) ([]model.APIKey, error) {
+	ret := _m.Called(userID)
+
+	var r0 []model.APIKey
+	if rf, ok := ret.Get(0).(func(int) []model.APIKey); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.APIKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: userID, ID
+func (_m *APIKeyRepo) RevokeAPIKey(userID int, ID int) (int, error) {
+	ret := _m.Called(userID, ID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(userID, ID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAPIKeyName provides a mock function with given fields: userID, ID, name
+func (_m *APIKeyRepo) UpdateAPIKeyName(userID int, ID int, name string) (int, error) {
+	ret := _m.Called(userID, ID, name)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, int, string) int); ok {
+		r0 = rf(userID, ID, name)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, string) error); ok {
+		r1 = rf(userID, ID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseAPIKey provides a mock function with given fields: keyHash
+func (_m *APIKeyRepo) UseAPIKey(keyHash string) (*model.Principal, error) {
+	ret := _m.Called(keyHash)
+
+	var r0 *model.Principal
+	if rf, ok := ret.Get(0).(func(string) *model.Principal); ok {
+		r0 = rf(keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Principal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAPIKeyRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyRepo creates a new instance of APIKeyRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyRepo(t mockConstructorTestingTNewAPIKeyRepo) *APIKeyRepo {
+	mock := &APIKeyRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
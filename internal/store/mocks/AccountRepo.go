@@ -0,0 +1,114 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccountRepo is an autogenerated mock type for the AccountRepo type
+type AccountRepo struct {
+	mock.Mock
+}
+
+// ChangeEmail provides a mock function with given fields: tokenHash
+func (_m *AccountRepo) ChangeEmail(tokenHash string) (*model.EmailChange, error) {
+	ret := _m.Called(tokenHash)
+
+	var r0 *model.EmailChange
+	if rf, ok := ret.Get(0).(func(string) *model.EmailChange); ok {
+		r0 = rf(tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.EmailChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAccountToken provides a mock function with given fields: token, mail
+func (_m *AccountRepo) CreateAccountToken(token *model.AccountToken, mail *model.Mail) (int, error) {
+	ret := _m.Called(token, mail)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.AccountToken, *model.Mail) int); ok {
+		r0 = rf(token, mail)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.AccountToken, *model.Mail) error); ok {
+		r1 = rf(token, mail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPassword provides a mock function with given fields: tokenHash, password
+func (_m *AccountRepo) ResetPassword(tokenHash string, password string) (int, error) {
+	ret := _m.Called(tokenHash, password)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, string) int); ok {
+		r0 = rf(tokenHash, password)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(tokenHash, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyEmail provides a mock function with given fields: tokenHash
+func (_m *AccountRepo) VerifyEmail(tokenHash string) (int, error) {
+	ret := _m.Called(tokenHash)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(tokenHash)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAccountRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAccountRepo creates a new instance of AccountRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAccountRepo(t mockConstructorTestingTNewAccountRepo) *AccountRepo {
+	mock := &AccountRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
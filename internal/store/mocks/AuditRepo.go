@@ -0,0 +1,72 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRepo is an autogenerated mock type for the AuditRepo type
+type AuditRepo struct {
+	mock.Mock
+}
+
+// CreateAuditEvent provides a mock function with given fields: event
+func (_m *AuditRepo) CreateAuditEvent(event *model.AuditEvent) (int, error) {
+	ret := _m.Called(event)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.AuditEvent) int); ok {
+		r0 = rf(event)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.AuditEvent) error); ok {
+		r1 = rf(event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAuditEvents provides a mock function with given fields: filter, page
+func (_m *AuditRepo) GetAuditEvents(filter *model.AuditFilter, page *model.PageRequest) ([]model.AuditEvent, error) {
+	ret := _m.Called(filter, page)
+
+	var r0 []model.AuditEvent
+	if rf, ok := ret.Get(0).(func(*model.AuditFilter, *model.PageRequest) []model.AuditEvent); ok {
+		r0 = rf(filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.AuditEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.AuditFilter, *model.PageRequest) error); ok {
+		r1 = rf(filter, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAuditRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuditRepo creates a new instance of AuditRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuditRepo(t mockConstructorTestingTNewAuditRepo) *AuditRepo {
+	mock := &AuditRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -49,13 +49,13 @@ func (_m *ChannelRepo) GetChannelByName(name string) (*model.Channel, error) {
 	return r0, r1
 }
 
-// GetChannelsByPage provides a mock function with given fields: offset
-func (_m *ChannelRepo) GetChannelsByPage(offset int) ([]model.Channel, error) {
-	ret := _m.Called(offset)
+// GetChannelsByPage provides a mock function with given fields: page
+func (_m *ChannelRepo) GetChannelsByPage(page *model.PageRequest) ([]model.Channel, error) {
+	ret := _m.Called(page)
 
 	var r0 []model.Channel
-	if rf, ok := ret.Get(0).(func(int) []model.Channel); ok {
-		r0 = rf(offset)
+	if rf, ok := ret.Get(0).(func(*model.PageRequest) []model.Channel); ok {
+		r0 = rf(page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Channel)
@@ -63,8 +63,8 @@ func (_m *ChannelRepo) GetChannelsByPage(offset int) ([]model.Channel, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(offset)
+	if rf, ok := ret.Get(1).(func(*model.PageRequest) error); ok {
+		r1 = rf(page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,130 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IngestFailureRepo is an autogenerated mock type for the IngestFailureRepo type
+type IngestFailureRepo struct {
+	mock.Mock
+}
+
+// CreateIngestFailure provides a mock function with given fields: failure
+func (_m *IngestFailureRepo) CreateIngestFailure(failure *model.IngestFailure) (int, error) {
+	ret := _m.Called(failure)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.IngestFailure) int); ok {
+		r0 = rf(failure)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.IngestFailure) error); ok {
+		r1 = rf(failure)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteIngestFailure provides a mock function with given fields: ID
+func (_m *IngestFailureRepo) DeleteIngestFailure(ID int) (int, error) {
+	ret := _m.Called(ID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int) int); ok {
+		r0 = rf(ID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIngestFailureByID provides a mock function with given fields: ID
+func (_m *IngestFailureRepo) GetIngestFailureByID(ID int) (*model.IngestFailure, error) {
+	ret := _m.Called(ID)
+
+	var r0 *model.IngestFailure
+	if rf, ok := ret.Get(0).(func(int) *model.IngestFailure); ok {
+		r0 = rf(ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.IngestFailure)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIngestFailuresByPage provides a mock function with given fields: offset
+func (_m *IngestFailureRepo) GetIngestFailuresByPage(offset int) ([]model.IngestFailure, error) {
+	ret := _m.Called(offset)
+
+	var r0 []model.IngestFailure
+	if rf, ok := ret.Get(0).(func(int) []model.IngestFailure); ok {
+		r0 = rf(offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.IngestFailure)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateIngestFailure provides a mock function with given fields: ID, errMsg
+func (_m *IngestFailureRepo) UpdateIngestFailure(ID int, errMsg string) error {
+	ret := _m.Called(ID, errMsg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(ID, errMsg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewIngestFailureRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIngestFailureRepo creates a new instance of IngestFailureRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIngestFailureRepo(t mockConstructorTestingTNewIngestFailureRepo) *IngestFailureRepo {
+	mock := &IngestFailureRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -56,13 +56,13 @@ func (_m *MessageRepo) GetFullMessageByID(ID int) (*model.FullMessage, error) {
 	return r0, r1
 }
 
-// GetFullMessagesByChannelIDAndPage provides a mock function with given fields: ID, offset
-func (_m *MessageRepo) GetFullMessagesByChannelIDAndPage(ID int, offset int) ([]model.FullMessage, error) {
-	ret := _m.Called(ID, offset)
+// GetFullMessagesByPage provides a mock function with given fields: filter, page
+func (_m *MessageRepo) GetFullMessagesByPage(filter *model.MessageFilter, page *model.PageRequest) ([]model.FullMessage, error) {
+	ret := _m.Called(filter, page)
 
 	var r0 []model.FullMessage
-	if rf, ok := ret.Get(0).(func(int, int) []model.FullMessage); ok {
-		r0 = rf(ID, offset)
+	if rf, ok := ret.Get(0).(func(*model.MessageFilter, *model.PageRequest) []model.FullMessage); ok {
+		r0 = rf(filter, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.FullMessage)
@@ -70,31 +70,8 @@ func (_m *MessageRepo) GetFullMessagesByChannelIDAndPage(ID int, offset int) ([]
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(ID, offset)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// GetFullMessagesByPage provides a mock function with given fields: offset
-func (_m *MessageRepo) GetFullMessagesByPage(offset int) ([]model.FullMessage, error) {
-	ret := _m.Called(offset)
-
-	var r0 []model.FullMessage
-	if rf, ok := ret.Get(0).(func(int) []model.FullMessage); ok {
-		r0 = rf(offset)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.FullMessage)
-		}
-	}
-
-	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(offset)
+	if rf, ok := ret.Get(1).(func(*model.MessageFilter, *model.PageRequest) error); ok {
+		r1 = rf(filter, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -125,20 +102,20 @@ func (_m *MessageRepo) GetFullMessagesByUserID(ID int) ([]model.FullMessage, err
 	return r0, r1
 }
 
-// GetMessagesCount provides a mock function with given fields:
-func (_m *MessageRepo) GetMessagesCount() (int, error) {
-	ret := _m.Called()
+// GetMessagesCount provides a mock function with given fields: filter
+func (_m *MessageRepo) GetMessagesCount(filter *model.MessageFilter) (int, error) {
+	ret := _m.Called(filter)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func() int); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(*model.MessageFilter) int); ok {
+		r0 = rf(filter)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(*model.MessageFilter) error); ok {
+		r1 = rf(filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -146,20 +123,22 @@ func (_m *MessageRepo) GetMessagesCount() (int, error) {
 	return r0, r1
 }
 
-// GetMessagesCountByChannelID provides a mock function with given fields: ID
-func (_m *MessageRepo) GetMessagesCountByChannelID(ID int) (int, error) {
-	ret := _m.Called(ID)
+// SearchMessages provides a mock function with given fields: query, configs, filter, offset
+func (_m *MessageRepo) SearchMessages(query string, configs []string, filter *model.MessageFilter, offset int) ([]model.SearchResult, error) {
+	ret := _m.Called(query, configs, filter, offset)
 
-	var r0 int
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(ID)
+	var r0 []model.SearchResult
+	if rf, ok := ret.Get(0).(func(string, []string, *model.MessageFilter, int) []model.SearchResult); ok {
+		r0 = rf(query, configs, filter, offset)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.SearchResult)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(ID)
+	if rf, ok := ret.Get(1).(func(string, []string, *model.MessageFilter, int) error); ok {
+		r1 = rf(query, configs, filter, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,153 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCRepo is an autogenerated mock type for the OIDCRepo type
+type OIDCRepo struct {
+	mock.Mock
+}
+
+// CreateOIDCLogin provides a mock function with given fields: login
+func (_m *OIDCRepo) CreateOIDCLogin(login *model.OIDCLogin) error {
+	ret := _m.Called(login)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.OIDCLogin) error); ok {
+		r0 = rf(login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateWebUserIdentity provides a mock function with given fields: identity
+func (_m *OIDCRepo) CreateWebUserIdentity(identity *model.WebUserIdentity) (int, error) {
+	ret := _m.Called(identity)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.WebUserIdentity) int); ok {
+		r0 = rf(identity)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.WebUserIdentity) error); ok {
+		r1 = rf(identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebUserWithIdentity provides a mock function with given fields: user, identity
+func (_m *OIDCRepo) CreateWebUserWithIdentity(user *model.WebUser, identity *model.WebUserIdentity) (int, error) {
+	ret := _m.Called(user, identity)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.WebUser, *model.WebUserIdentity) int); ok {
+		r0 = rf(user, identity)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.WebUser, *model.WebUserIdentity) error); ok {
+		r1 = rf(user, identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebUserByIdentity provides a mock function with given fields: issuer, subject
+func (_m *OIDCRepo) GetWebUserByIdentity(issuer string, subject string) (*model.WebUser, error) {
+	ret := _m.Called(issuer, subject)
+
+	var r0 *model.WebUser
+	if rf, ok := ret.Get(0).(func(string, string) *model.WebUser); ok {
+		r0 = rf(issuer, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebUser)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(issuer, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebUserIdentities provides a mock function with given fields: userID
+func (_m *OIDCRepo) GetWebUserIdentities(userID int) ([]model.WebUserIdentity, error) {
+	ret := _m.Called(userID)
+
+	var r0 []model.WebUserIdentity
+	if rf, ok := ret.Get(0).(func(int) []model.WebUserIdentity); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.WebUserIdentity)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseOIDCLogin provides a mock function with given fields: stateHash
+func (_m *OIDCRepo) UseOIDCLogin(stateHash string) (*model.OIDCLogin, error) {
+	ret := _m.Called(stateHash)
+
+	var r0 *model.OIDCLogin
+	if rf, ok := ret.Get(0).(func(string) *model.OIDCLogin); ok {
+		r0 = rf(stateHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OIDCLogin)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(stateHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewOIDCRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOIDCRepo creates a new instance of OIDCRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOIDCRepo(t mockConstructorTestingTNewOIDCRepo) *OIDCRepo {
+	mock := &OIDCRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRepo is an autogenerated mock type for the OutboxRepo type
+type OutboxRepo struct {
+	mock.Mock
+}
+
+// ClaimMails provides a mock function with given fields: limit, maxAttempts, leaseUntil
+func (_m *OutboxRepo) ClaimMails(limit int, maxAttempts int, leaseUntil time.Time) ([]model.Mail, error) {
+	ret := _m.Called(limit, maxAttempts, leaseUntil)
+
+	var r0 []model.Mail
+	if rf, ok := ret.Get(0).(func(int, int, time.Time) []model.Mail); ok {
+		r0 = rf(limit, maxAttempts, leaseUntil)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Mail)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, time.Time) error); ok {
+		r1 = rf(limit, maxAttempts, leaseUntil)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkMailFailed provides a mock function with given fields: ID, lastError, retryAt
+func (_m *OutboxRepo) MarkMailFailed(ID int, lastError string, retryAt time.Time) (int, error) {
+	ret := _m.Called(ID, lastError, retryAt)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, string, time.Time) int); ok {
+		r0 = rf(ID, lastError, retryAt)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string, time.Time) error); ok {
+		r1 = rf(ID, lastError, retryAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkMailSent provides a mock function with given fields: ID
+func (_m *OutboxRepo) MarkMailSent(ID int) (int, error) {
+	ret := _m.Called(ID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int) int); ok {
+		r0 = rf(ID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewOutboxRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOutboxRepo creates a new instance of OutboxRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOutboxRepo(t mockConstructorTestingTNewOutboxRepo) *OutboxRepo {
+	mock := &OutboxRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -26,13 +26,13 @@ func (_m *ReplieRepo) CreateReplie(replie *model.ReplieDTO) error {
 	return r0
 }
 
-// GetFullRepliesByMessageID provides a mock function with given fields: ID
-func (_m *ReplieRepo) GetFullRepliesByMessageID(ID int) ([]model.FullReplie, error) {
-	ret := _m.Called(ID)
+// GetFullRepliesByMessageID provides a mock function with given fields: ID, page
+func (_m *ReplieRepo) GetFullRepliesByMessageID(ID int, page *model.PageRequest) ([]model.FullReplie, error) {
+	ret := _m.Called(ID, page)
 
 	var r0 []model.FullReplie
-	if rf, ok := ret.Get(0).(func(int) []model.FullReplie); ok {
-		r0 = rf(ID)
+	if rf, ok := ret.Get(0).(func(int, *model.PageRequest) []model.FullReplie); ok {
+		r0 = rf(ID, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.FullReplie)
@@ -40,8 +40,8 @@ func (_m *ReplieRepo) GetFullRepliesByMessageID(ID int) ([]model.FullReplie, err
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(ID)
+	if rf, ok := ret.Get(1).(func(int, *model.PageRequest) error); ok {
+		r1 = rf(ID, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -12,6 +12,27 @@ type SavedRepo struct {
 	mock.Mock
 }
 
+// CreateCollection provides a mock function with given fields: collection
+func (_m *SavedRepo) CreateCollection(collection *model.Collection) (int, error) {
+	ret := _m.Called(collection)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.Collection) int); ok {
+		r0 = rf(collection)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Collection) error); ok {
+		r1 = rf(collection)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateSavedMessage provides a mock function with given fields: savedMessage
 func (_m *SavedRepo) CreateSavedMessage(savedMessage *model.Saved) (int, error) {
 	ret := _m.Called(savedMessage)
@@ -33,20 +54,20 @@ func (_m *SavedRepo) CreateSavedMessage(savedMessage *model.Saved) (int, error)
 	return r0, r1
 }
 
-// DeleteSavedMessage provides a mock function with given fields: ID
-func (_m *SavedRepo) DeleteSavedMessage(ID int) (int, error) {
-	ret := _m.Called(ID)
+// DeleteCollection provides a mock function with given fields: userID, ID
+func (_m *SavedRepo) DeleteCollection(userID int, ID int) (int, error) {
+	ret := _m.Called(userID, ID)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(ID)
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(userID, ID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(ID)
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, ID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -54,22 +75,133 @@ func (_m *SavedRepo) DeleteSavedMessage(ID int) (int, error) {
 	return r0, r1
 }
 
-// GetSavedMessages provides a mock function with given fields: ID
-func (_m *SavedRepo) GetSavedMessages(ID int) ([]model.Saved, error) {
-	ret := _m.Called(ID)
+// DeleteSavedMessage provides a mock function with given fields: userID, ID
+func (_m *SavedRepo) DeleteSavedMessage(userID int, ID int) (*model.Saved, error) {
+	ret := _m.Called(userID, ID)
 
-	var r0 []model.Saved
-	if rf, ok := ret.Get(0).(func(int) []model.Saved); ok {
-		r0 = rf(ID)
+	var r0 *model.Saved
+	if rf, ok := ret.Get(0).(func(int, int) *model.Saved); ok {
+		r0 = rf(userID, ID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Saved)
+			r0 = ret.Get(0).(*model.Saved)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCollection provides a mock function with given fields: userID, ID
+func (_m *SavedRepo) GetCollection(userID int, ID int) (*model.Collection, error) {
+	ret := _m.Called(userID, ID)
+
+	var r0 *model.Collection
+	if rf, ok := ret.Get(0).(func(int, int) *model.Collection); ok {
+		r0 = rf(userID, ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Collection)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCollections provides a mock function with given fields: userID
+func (_m *SavedRepo) GetCollections(userID int) ([]model.Collection, error) {
+	ret := _m.Called(userID)
+
+	var r0 []model.Collection
+	if rf, ok := ret.Get(0).(func(int) []model.Collection); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Collection)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(ID)
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSavedMessages provides a mock function with given fields: ID, filter, page
+func (_m *SavedRepo) GetSavedMessages(ID int, filter *model.SavedFilter, page *model.PageRequest) ([]model.SavedMessage, error) {
+	ret := _m.Called(ID, filter, page)
+
+	var r0 []model.SavedMessage
+	if rf, ok := ret.Get(0).(func(int, *model.SavedFilter, *model.PageRequest) []model.SavedMessage); ok {
+		r0 = rf(ID, filter, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.SavedMessage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, *model.SavedFilter, *model.PageRequest) error); ok {
+		r1 = rf(ID, filter, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCollection provides a mock function with given fields: collection
+func (_m *SavedRepo) UpdateCollection(collection *model.Collection) (int, error) {
+	ret := _m.Called(collection)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.Collection) int); ok {
+		r0 = rf(collection)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Collection) error); ok {
+		r1 = rf(collection)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSavedMessage provides a mock function with given fields: savedMessage
+func (_m *SavedRepo) UpdateSavedMessage(savedMessage *model.Saved) (int, error) {
+	ret := _m.Called(savedMessage)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.Saved) int); ok {
+		r0 = rf(savedMessage)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Saved) error); ok {
+		r1 = rf(savedMessage)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,206 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SessionRepo is an autogenerated mock type for the SessionRepo type
+type SessionRepo struct {
+	mock.Mock
+}
+
+// CreateSession provides a mock function with given fields: userID, tokenHash, expiresAt
+func (_m *SessionRepo) CreateSession(userID int, tokenHash string, expiresAt time.Time) (int, error) {
+	ret := _m.Called(userID, tokenHash, expiresAt)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, string, time.Time) int); ok {
+		r0 = rf(userID, tokenHash, expiresAt)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string, time.Time) error); ok {
+		r1 = rf(userID, tokenHash, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefreshToken provides a mock function with given fields: tokenHash
+func (_m *SessionRepo) GetRefreshToken(tokenHash string) (*model.RefreshToken, error) {
+	ret := _m.Called(tokenHash)
+
+	var r0 *model.RefreshToken
+	if rf, ok := ret.Get(0).(func(string) *model.RefreshToken); ok {
+		r0 = rf(tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.RefreshToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSession provides a mock function with given fields: ID
+func (_m *SessionRepo) GetSession(ID int) (*model.Session, error) {
+	ret := _m.Called(ID)
+
+	var r0 *model.Session
+	if rf, ok := ret.Get(0).(func(int) *model.Session); ok {
+		r0 = rf(ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionsByUserID provides a mock function with given fields: userID
+func (_m *SessionRepo) GetSessionsByUserID(userID int) ([]model.Session, error) {
+	ret := _m.Called(userID)
+
+	var r0 []model.Session
+	if rf, ok := ret.Get(0).(func(int) []model.Session); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeOtherUserSessions provides a mock function with given fields: userID, sessionID
+func (_m *SessionRepo) RevokeOtherUserSessions(userID int, sessionID int) (int, error) {
+	ret := _m.Called(userID, sessionID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(userID, sessionID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(userID, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: ID
+func (_m *SessionRepo) RevokeSession(ID int) (int, error) {
+	ret := _m.Called(ID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int) int); ok {
+		r0 = rf(ID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeUserSessions provides a mock function with given fields: userID
+func (_m *SessionRepo) RevokeUserSessions(userID int) (int, error) {
+	ret := _m.Called(userID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int) int); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateRefreshToken provides a mock function with given fields: tokenHash, newTokenHash, expiresAt
+func (_m *SessionRepo) RotateRefreshToken(tokenHash string, newTokenHash string, expiresAt time.Time) (*model.Principal, error) {
+	ret := _m.Called(tokenHash, newTokenHash, expiresAt)
+
+	var r0 *model.Principal
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) *model.Principal); ok {
+		r0 = rf(tokenHash, newTokenHash, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Principal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) error); ok {
+		r1 = rf(tokenHash, newTokenHash, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewSessionRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSessionRepo creates a new instance of SessionRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSessionRepo(t mockConstructorTestingTNewSessionRepo) *SessionRepo {
+	mock := &SessionRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
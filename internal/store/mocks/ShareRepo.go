@@ -0,0 +1,283 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ShareRepo is an autogenerated mock type for the ShareRepo type
+type ShareRepo struct {
+	mock.Mock
+}
+
+// AddShareItems provides a mock function with given fields: shareID, userID, savedIDs
+func (_m *ShareRepo) AddShareItems(shareID int, userID int, savedIDs []int) (int, error) {
+	ret := _m.Called(shareID, userID, savedIDs)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, int, []int) int); ok {
+		r0 = rf(shareID, userID, savedIDs)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int, []int) error); ok {
+		r1 = rf(shareID, userID, savedIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateShare provides a mock function with given fields: share, savedIDs
+func (_m *ShareRepo) CreateShare(share *model.Share, savedIDs []int) (int, error) {
+	ret := _m.Called(share, savedIDs)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.Share, []int) int); ok {
+		r0 = rf(share, savedIDs)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Share, []int) error); ok {
+		r1 = rf(share, savedIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteShare provides a mock function with given fields: ID
+func (_m *ShareRepo) DeleteShare(ID int) (int, error) {
+	ret := _m.Called(ID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int) int); ok {
+		r0 = rf(ID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteShareItem provides a mock function with given fields: shareID, messageID
+func (_m *ShareRepo) DeleteShareItem(shareID int, messageID int) (int, error) {
+	ret := _m.Called(shareID, messageID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(shareID, messageID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(shareID, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteShareMember provides a mock function with given fields: shareID, userID
+func (_m *ShareRepo) DeleteShareMember(shareID int, userID int) (int, error) {
+	ret := _m.Called(shareID, userID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(shareID, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(shareID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShareAccess provides a mock function with given fields: ID, userID
+func (_m *ShareRepo) GetShareAccess(ID int, userID int) (*model.Share, error) {
+	ret := _m.Called(ID, userID)
+
+	var r0 *model.Share
+	if rf, ok := ret.Get(0).(func(int, int) *model.Share); ok {
+		r0 = rf(ID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Share)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(ID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShareByToken provides a mock function with given fields: token
+func (_m *ShareRepo) GetShareByToken(token string) (*model.Share, error) {
+	ret := _m.Called(token)
+
+	var r0 *model.Share
+	if rf, ok := ret.Get(0).(func(string) *model.Share); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Share)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShareMembers provides a mock function with given fields: shareID
+func (_m *ShareRepo) GetShareMembers(shareID int) ([]model.ShareMember, error) {
+	ret := _m.Called(shareID)
+
+	var r0 []model.ShareMember
+	if rf, ok := ret.Get(0).(func(int) []model.ShareMember); ok {
+		r0 = rf(shareID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ShareMember)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(shareID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShareMessages provides a mock function with given fields: shareID
+func (_m *ShareRepo) GetShareMessages(shareID int) ([]model.FullMessage, error) {
+	ret := _m.Called(shareID)
+
+	var r0 []model.FullMessage
+	if rf, ok := ret.Get(0).(func(int) []model.FullMessage); ok {
+		r0 = rf(shareID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.FullMessage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(shareID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSharesByUserID provides a mock function with given fields: userID
+func (_m *ShareRepo) GetSharesByUserID(userID int) ([]model.Share, error) {
+	ret := _m.Called(userID)
+
+	var r0 []model.Share
+	if rf, ok := ret.Get(0).(func(int) []model.Share); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Share)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateShareToken provides a mock function with given fields: ID, token
+func (_m *ShareRepo) UpdateShareToken(ID int, token *string) (int, error) {
+	ret := _m.Called(ID, token)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, *string) int); ok {
+		r0 = rf(ID, token)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, *string) error); ok {
+		r1 = rf(ID, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertShareMember provides a mock function with given fields: member
+func (_m *ShareRepo) UpsertShareMember(member *model.ShareMember) error {
+	ret := _m.Called(member)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.ShareMember) error); ok {
+		r0 = rf(member)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewShareRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewShareRepo creates a new instance of ShareRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewShareRepo(t mockConstructorTestingTNewShareRepo) *ShareRepo {
+	mock := &ShareRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
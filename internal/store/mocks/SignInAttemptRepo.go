@@ -0,0 +1,116 @@
+// Code generated by mockery v2.14.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/VladPetriv/scanner_backend_api/internal/model"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SignInAttemptRepo is an autogenerated mock type for the SignInAttemptRepo type
+type SignInAttemptRepo struct {
+	mock.Mock
+}
+
+// DeleteSignInAttempt provides a mock function with given fields: kind, subject
+func (_m *SignInAttemptRepo) DeleteSignInAttempt(kind model.SignInAttemptKind, subject string) (int, error) {
+	ret := _m.Called(kind, subject)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(model.SignInAttemptKind, string) int); ok {
+		r0 = rf(kind, subject)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.SignInAttemptKind, string) error); ok {
+		r1 = rf(kind, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSignInAttempt provides a mock function with given fields: kind, subject
+func (_m *SignInAttemptRepo) GetSignInAttempt(kind model.SignInAttemptKind, subject string) (*model.SignInAttempt, error) {
+	ret := _m.Called(kind, subject)
+
+	var r0 *model.SignInAttempt
+	if rf, ok := ret.Get(0).(func(model.SignInAttemptKind, string) *model.SignInAttempt); ok {
+		r0 = rf(kind, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SignInAttempt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.SignInAttemptKind, string) error); ok {
+		r1 = rf(kind, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockSignIn provides a mock function with given fields: kind, subject, lockedUntil
+func (_m *SignInAttemptRepo) LockSignIn(kind model.SignInAttemptKind, subject string, lockedUntil time.Time) (int, error) {
+	ret := _m.Called(kind, subject, lockedUntil)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(model.SignInAttemptKind, string, time.Time) int); ok {
+		r0 = rf(kind, subject, lockedUntil)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.SignInAttemptKind, string, time.Time) error); ok {
+		r1 = rf(kind, subject, lockedUntil)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordSignInFailure provides a mock function with given fields: kind, subject, failedAt, resetBefore
+func (_m *SignInAttemptRepo) RecordSignInFailure(kind model.SignInAttemptKind, subject string, failedAt time.Time, resetBefore time.Time) (int, error) {
+	ret := _m.Called(kind, subject, failedAt, resetBefore)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(model.SignInAttemptKind, string, time.Time, time.Time) int); ok {
+		r0 = rf(kind, subject, failedAt, resetBefore)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.SignInAttemptKind, string, time.Time, time.Time) error); ok {
+		r1 = rf(kind, subject, failedAt, resetBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewSignInAttemptRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSignInAttemptRepo creates a new instance of SignInAttemptRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSignInAttemptRepo(t mockConstructorTestingTNewSignInAttemptRepo) *SignInAttemptRepo {
+	mock := &SignInAttemptRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
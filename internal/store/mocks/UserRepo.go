@@ -12,25 +12,18 @@ type UserRepo struct {
 	mock.Mock
 }
 
-// CreateUser provides a mock function with given fields: user
-func (_m *UserRepo) CreateUser(user *model.UserDTO) (int, error) {
-	ret := _m.Called(user)
+// ClaimUsername provides a mock function with given fields: tgID, username
+func (_m *UserRepo) ClaimUsername(tgID int64, username string) error {
+	ret := _m.Called(tgID, username)
 
-	var r0 int
-	if rf, ok := ret.Get(0).(func(*model.UserDTO) int); ok {
-		r0 = rf(user)
-	} else {
-		r0 = ret.Get(0).(int)
-	}
-
-	var r1 error
-	if rf, ok := ret.Get(1).(func(*model.UserDTO) error); ok {
-		r1 = rf(user)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string) error); ok {
+		r0 = rf(tgID, username)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
 // GetUserByID provides a mock function with given fields: ID
@@ -79,6 +72,69 @@ func (_m *UserRepo) GetUserByUsername(username string) (*model.User, error) {
 	return r0, r1
 }
 
+// UpsertAnonymousUser provides a mock function with given fields: user
+func (_m *UserRepo) UpsertAnonymousUser(user *model.UserDTO) (int, error) {
+	ret := _m.Called(user)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.UserDTO) int); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.UserDTO) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertUserByTgID provides a mock function with given fields: user
+func (_m *UserRepo) UpsertUserByTgID(user *model.UserDTO) (int, error) {
+	ret := _m.Called(user)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.UserDTO) int); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.UserDTO) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertUserByUsername provides a mock function with given fields: user
+func (_m *UserRepo) UpsertUserByUsername(user *model.UserDTO) (int, error) {
+	ret := _m.Called(user)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*model.UserDTO) int); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.UserDTO) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewUserRepo interface {
 	mock.TestingT
 	Cleanup(func())
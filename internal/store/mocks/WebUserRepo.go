@@ -33,6 +33,27 @@ func (_m *WebUserRepo) CreateWebUser(user *model.WebUser) (int, error) {
 	return r0, r1
 }
 
+// DeleteWebUser provides a mock function with given fields: ID
+func (_m *WebUserRepo) DeleteWebUser(ID int) (int, error) {
+	ret := _m.Called(ID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int) int); ok {
+		r0 = rf(ID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetWebUserByEmail provides a mock function with given fields: email
 func (_m *WebUserRepo) GetWebUserByEmail(email string) (*model.WebUser, error) {
 	ret := _m.Called(email)
@@ -56,6 +77,71 @@ func (_m *WebUserRepo) GetWebUserByEmail(email string) (*model.WebUser, error) {
 	return r0, r1
 }
 
+// GetWebUserByID provides a mock function with given fields: ID
+func (_m *WebUserRepo) GetWebUserByID(ID int) (*model.WebUser, error) {
+	ret := _m.Called(ID)
+
+	var r0 *model.WebUser
+	if rf, ok := ret.Get(0).(func(int) *model.WebUser); ok {
+		r0 = rf(ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebUser)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebUserPassword provides a mock function with given fields: ID, password
+func (_m *WebUserRepo) UpdateWebUserPassword(ID int, password string) (int, error) {
+	ret := _m.Called(ID, password)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, string) int); ok {
+		r0 = rf(ID, password)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(ID, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebUserRole provides a mock function with given fields: ID, role
+func (_m *WebUserRepo) UpdateWebUserRole(ID int, role model.Role) (int, error) {
+	ret := _m.Called(ID, role)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, model.Role) int); ok {
+		r0 = rf(ID, role)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, model.Role) error); ok {
+		r1 = rf(ID, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewWebUserRepo interface {
 	mock.TestingT
 	Cleanup(func())
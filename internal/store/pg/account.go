@@ -0,0 +1,142 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+var (
+	ErrAccountTokenNotCreated = errors.New("account token not created")
+	ErrAccountTokenNotFound   = errors.New("account token not found")
+)
+
+type AccountRepo struct {
+	db Queryer
+}
+
+func NewAccountRepo(db Queryer) *AccountRepo {
+	return &AccountRepo{db: db}
+}
+
+// CreateAccountToken creates account token and puts mail with it into outbox in one statement,
+// so the token is never created without mail. Unused tokens of user with the same purpose stop working.
+func (a *AccountRepo) CreateAccountToken(token *model.AccountToken, mail *model.Mail) (int, error) {
+	var id int
+
+	row := a.db.QueryRow(
+		`WITH old_tokens AS (
+			UPDATE account_token SET used_at = now()
+			WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+		), new_token AS (
+			INSERT INTO account_token(user_id, purpose, token_hash, expires_at, new_email) VALUES ($1, $2, $3, $4, $5) RETURNING id
+		), new_mail AS (
+			INSERT INTO mail_outbox(recipient, subject, body)
+			SELECT $6, $7, $8 FROM new_token
+		)
+		SELECT id FROM new_token;`,
+		token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.NewEmail, mail.Recipient, mail.Subject, mail.Body,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrAccountTokenNotCreated
+		}
+
+		return 0, fmt.Errorf("failed to create account token: %w", err)
+	}
+
+	return id, nil
+}
+
+// ResetPassword uses unused and unexpired password reset token and sets new password of its owner.
+// Email of the owner becomes verified, since the token was received by it. It returns id of the owner.
+func (a *AccountRepo) ResetPassword(tokenHash, password string) (int, error) {
+	var id int
+
+	row := a.db.QueryRow(
+		`WITH used AS (
+			UPDATE account_token SET used_at = now()
+			WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id
+		)
+		UPDATE web_user u SET password = $3, email_verified_at = COALESCE(u.email_verified_at, now())
+		FROM used WHERE u.id = used.user_id
+		RETURNING u.id;`,
+		tokenHash, model.AccountTokenResetPassword, password,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrAccountTokenNotFound
+		}
+
+		return 0, fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	return id, nil
+}
+
+// VerifyEmail uses unused and unexpired email verification token and marks email of its owner as verified.
+// It returns id of the owner.
+func (a *AccountRepo) VerifyEmail(tokenHash string) (int, error) {
+	var id int
+
+	row := a.db.QueryRow(
+		`WITH used AS (
+			UPDATE account_token SET used_at = now()
+			WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id
+		)
+		UPDATE web_user u SET email_verified_at = COALESCE(u.email_verified_at, now())
+		FROM used WHERE u.id = used.user_id
+		RETURNING u.id;`,
+		tokenHash, model.AccountTokenVerifyEmail,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrAccountTokenNotFound
+		}
+
+		return 0, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return id, nil
+}
+
+// ChangeEmail uses unused and unexpired email change token and sets new email from it to its owner,
+// the new email is verified since the token was sent to it. It returns id of the owner with its old and new email,
+// the old one is read from the row joined before update.
+func (a *AccountRepo) ChangeEmail(tokenHash string) (*model.EmailChange, error) {
+	var change model.EmailChange
+
+	err := a.db.Get(
+		&change,
+		`WITH used AS (
+			UPDATE account_token SET used_at = now()
+			WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id, new_email
+		)
+		UPDATE web_user u SET email = used.new_email, email_verified_at = now()
+		FROM used JOIN web_user old ON old.id = used.user_id
+		WHERE u.id = used.user_id
+		RETURNING u.id, old.email AS old_email, u.email AS new_email;`,
+		tokenHash, model.AccountTokenChangeEmail,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrAccountTokenNotFound
+	}
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return nil, ErrWebUserExists
+		}
+
+		return nil, fmt.Errorf("failed to change email: %w", err)
+	}
+
+	return &change, nil
+}
@@ -0,0 +1,310 @@
+package pg_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateAccountToken(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAccountRepo(&pg.DB{DB: sqlxDB})
+
+	expiresAt := time.Date(2022, time.August, 1, 12, 0, 0, 0, time.UTC)
+	token := &model.AccountToken{UserID: 1, Purpose: model.AccountTokenResetPassword, TokenHash: "hash", ExpiresAt: expiresAt}
+	mail := &model.Mail{Recipient: "test@test.com", Subject: "Reset your password", Body: "link"}
+	query := `WITH old_tokens AS (
+			UPDATE account_token SET used_at = now()
+			WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+		), new_token AS (
+			INSERT INTO account_token(user_id, purpose, token_hash, expires_at, new_email) VALUES ($1, $2, $3, $4, $5) RETURNING id
+		), new_mail AS (
+			INSERT INTO mail_outbox(recipient, subject, body)
+			SELECT $6, $7, $8 FROM new_token
+		)
+		SELECT id FROM new_token;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [account token created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(7)
+
+				mock.ExpectQuery(query).
+					WithArgs(1, model.AccountTokenResetPassword, "hash", expiresAt, nil, "test@test.com", "Reset your password", "link").
+					WillReturnRows(rows)
+			},
+			want: 7,
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).
+					WithArgs(1, model.AccountTokenResetPassword, "hash", expiresAt, nil, "test@test.com", "Reset your password", "link").
+					WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to create account token: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateAccountToken(token, mail)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_ResetPassword(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAccountRepo(&pg.DB{DB: sqlxDB})
+
+	query := `WITH used AS (
+			UPDATE account_token SET used_at = now()
+			WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id
+		)
+		UPDATE web_user u SET password = $3, email_verified_at = COALESCE(u.email_verified_at, now())
+		FROM used WHERE u.id = used.user_id
+		RETURNING u.id;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [password reset]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery(query).WithArgs("hash", model.AccountTokenResetPassword, "password").WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [token is used, expired or unknown]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).WithArgs("hash", model.AccountTokenResetPassword, "password").WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "account token not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("hash", model.AccountTokenResetPassword, "password").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to reset password: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.ResetPassword("hash", "password")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_VerifyEmail(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAccountRepo(&pg.DB{DB: sqlxDB})
+
+	query := `WITH used AS (
+			UPDATE account_token SET used_at = now()
+			WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id
+		)
+		UPDATE web_user u SET email_verified_at = COALESCE(u.email_verified_at, now())
+		FROM used WHERE u.id = used.user_id
+		RETURNING u.id;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [email verified]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery(query).WithArgs("hash", model.AccountTokenVerifyEmail).WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [token is used, expired or unknown]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).WithArgs("hash", model.AccountTokenVerifyEmail).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "account token not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.VerifyEmail("hash")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_ChangeEmail(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAccountRepo(&pg.DB{DB: sqlxDB})
+
+	query := `WITH used AS (
+			UPDATE account_token SET used_at = now()
+			WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+			RETURNING user_id, new_email
+		)
+		UPDATE web_user u SET email = used.new_email, email_verified_at = now()
+		FROM used JOIN web_user old ON old.id = used.user_id
+		WHERE u.id = used.user_id
+		RETURNING u.id, old.email AS old_email, u.email AS new_email;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.EmailChange
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [email changed]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "old_email", "new_email"}).
+					AddRow(1, "old@test.com", "new@test.com")
+
+				mock.ExpectQuery(query).WithArgs("hash", model.AccountTokenChangeEmail).WillReturnRows(rows)
+			},
+			want: &model.EmailChange{UserID: 1, OldEmail: "old@test.com", NewEmail: "new@test.com"},
+		},
+		{
+			name: "Error: [token is used, expired or unknown]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "old_email", "new_email"})
+
+				mock.ExpectQuery(query).WithArgs("hash", model.AccountTokenChangeEmail).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "account token not found",
+		},
+		{
+			name: "Error: [new email was taken after request]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("hash", model.AccountTokenChangeEmail).WillReturnError(&pq.Error{Code: "23505"})
+			},
+			wantErr:        true,
+			expectedErrMsg: "web user with this email already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.ChangeEmail("hash")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+var (
+	ErrAPIKeysNotFound  = errors.New("api keys not found")
+	ErrAPIKeyNotFound   = errors.New("api key not found")
+	ErrAPIKeyNotCreated = errors.New("api key not created")
+)
+
+type APIKeyRepo struct {
+	db Queryer
+}
+
+func NewAPIKeyRepo(db Queryer) *APIKeyRepo {
+	return &APIKeyRepo{db: db}
+}
+
+func (a *APIKeyRepo) CreateAPIKey(key *model.APIKey) (int, error) {
+	var id int
+
+	row := a.db.QueryRow(
+		"INSERT INTO api_key(user_id, name, prefix, key_hash, scope) VALUES ($1, $2, $3, $4, $5) RETURNING id;",
+		key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scope,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrAPIKeyNotCreated
+		}
+
+		return 0, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetAPIKeys returns not revoked api keys of user, latest created first.
+func (a *APIKeyRepo) GetAPIKeys(userID int) ([]model.APIKey, error) {
+	keys := make([]model.APIKey, 0, 10)
+
+	err := a.db.Select(
+		&keys,
+		`SELECT id, user_id, name, prefix, key_hash, scope, created_at, last_used_at FROM api_key
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC, id DESC;`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrAPIKeysNotFound
+	}
+
+	return keys, nil
+}
+
+func (a *APIKeyRepo) UpdateAPIKeyName(userID, ID int, name string) (int, error) {
+	var id int
+
+	row := a.db.QueryRow(
+		"UPDATE api_key SET name = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL RETURNING id;",
+		name, ID, userID,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrAPIKeyNotFound
+		}
+
+		return 0, fmt.Errorf("failed to update api key name: %w", err)
+	}
+
+	return id, nil
+}
+
+func (a *APIKeyRepo) RevokeAPIKey(userID, ID int) (int, error) {
+	var id int
+
+	row := a.db.QueryRow(
+		"UPDATE api_key SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL RETURNING id;",
+		ID, userID,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrAPIKeyNotFound
+		}
+
+		return 0, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return id, nil
+}
+
+// UseAPIKey records use of not revoked api key and returns its owner with key scope.
+func (a *APIKeyRepo) UseAPIKey(keyHash string) (*model.Principal, error) {
+	var principal model.Principal
+
+	err := a.db.Get(
+		&principal,
+		`UPDATE api_key k SET last_used_at = now()
+		FROM web_user u
+		WHERE k.key_hash = $1 AND k.revoked_at IS NULL AND u.id = k.user_id
+		RETURNING k.user_id, u.email, u.role, k.id AS api_key_id, k.scope;`,
+		keyHash,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to use api key: %w", err)
+	}
+
+	return &principal, nil
+}
@@ -0,0 +1,290 @@
+package pg_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPIKeyRepo(&pg.DB{DB: sqlxDB})
+
+	key := &model.APIKey{UserID: 1, Name: "notebook", Prefix: "sbk_3kT9aQ1x", KeyHash: "hash", Scope: model.APIKeyScopeRead}
+	query := "INSERT INTO api_key(user_id, name, prefix, key_hash, scope) VALUES ($1, $2, $3, $4, $5) RETURNING id;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [api key created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(3)
+
+				mock.ExpectQuery(query).WithArgs(1, "notebook", "sbk_3kT9aQ1x", "hash", model.APIKeyScopeRead).WillReturnRows(rows)
+			},
+			want: 3,
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1, "notebook", "sbk_3kT9aQ1x", "hash", model.APIKeyScopeRead).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to create api key: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateAPIKey(key)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetAPIKeys(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPIKeyRepo(&pg.DB{DB: sqlxDB})
+
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	query := `SELECT id, user_id, name, prefix, key_hash, scope, created_at, last_used_at FROM api_key
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC, id DESC;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           []model.APIKey
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [api keys found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "key_hash", "scope", "created_at", "last_used_at"}).
+					AddRow(2, 1, "ci", "sbk_aaaaaaaa", "hash2", "saved-write", createdAt, createdAt).
+					AddRow(1, 1, "notebook", "sbk_bbbbbbbb", "hash1", "read", createdAt, nil)
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: []model.APIKey{
+				{ID: 2, UserID: 1, Name: "ci", Prefix: "sbk_aaaaaaaa", KeyHash: "hash2", Scope: model.APIKeyScopeSavedWrite, CreatedAt: createdAt, LastUsedAt: &createdAt},
+				{ID: 1, UserID: 1, Name: "notebook", Prefix: "sbk_bbbbbbbb", KeyHash: "hash1", Scope: model.APIKeyScopeRead, CreatedAt: createdAt},
+			},
+		},
+		{
+			name: "Error: [api keys not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "key_hash", "scope", "created_at", "last_used_at"})
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "api keys not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get api keys: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetAPIKeys(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_RevokeAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPIKeyRepo(&pg.DB{DB: sqlxDB})
+
+	query := "UPDATE api_key SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL RETURNING id;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [api key revoked]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(3)
+
+				mock.ExpectQuery(query).WithArgs(3, 1).WillReturnRows(rows)
+			},
+			want: 3,
+		},
+		{
+			name: "Error: [api key not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).WithArgs(3, 1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "api key not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(3, 1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to revoke api key: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.RevokeAPIKey(1, 3)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_UseAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPIKeyRepo(&pg.DB{DB: sqlxDB})
+
+	query := `UPDATE api_key k SET last_used_at = now()
+		FROM web_user u
+		WHERE k.key_hash = $1 AND k.revoked_at IS NULL AND u.id = k.user_id
+		RETURNING k.user_id, u.email, u.role, k.id AS api_key_id, k.scope;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.Principal
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [api key used]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"user_id", "email", "role", "api_key_id", "scope"}).
+					AddRow(1, "test@test.com", "member", 3, "read")
+
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+			},
+			want: &model.Principal{UserID: 1, Email: "test@test.com", Role: model.RoleMember, APIKeyID: 3, Scope: model.APIKeyScopeRead},
+		},
+		{
+			name: "Error: [api key not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"user_id", "email", "role", "api_key_id", "scope"})
+
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "api key not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to use api key: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UseAPIKey("hash")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
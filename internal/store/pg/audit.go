@@ -0,0 +1,106 @@
+package pg
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+var (
+	ErrAuditEventNotCreated = errors.New("audit event not created")
+	ErrAuditEventsNotFound  = errors.New("audit events not found")
+)
+
+type AuditRepo struct {
+	db Queryer
+}
+
+func NewAuditRepo(db Queryer) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+func (a *AuditRepo) CreateAuditEvent(event *model.AuditEvent) (int, error) {
+	var id int
+
+	details := string(event.Details)
+	if details == "" {
+		details = "{}"
+	}
+
+	row := a.db.QueryRow(
+		`INSERT INTO audit_event(actor_id, action, ip, user_agent, details, before_payload, after_payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`,
+		event.ActorID, event.Action, event.IP, event.UserAgent, details, jsonPayload(event.Before), jsonPayload(event.After),
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrAuditEventNotCreated
+		}
+
+		return 0, fmt.Errorf("failed to create audit event: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetAuditEvents returns page of audit events matching filter, latest first.
+// Rows of backward page are returned in reverse order.
+func (a *AuditRepo) GetAuditEvents(filter *model.AuditFilter, page *model.PageRequest) ([]model.AuditEvent, error) {
+	events := make([]model.AuditEvent, 0, page.Limit+1)
+
+	where := &whereBuilder{}
+
+	if filter != nil {
+		if filter.ActorID != 0 {
+			where.add("actor_id = %s", filter.ActorID)
+		}
+
+		if filter.Action != "" {
+			where.add("action = %s", filter.Action)
+		}
+
+		if filter.IP != "" {
+			where.add("ip = %s", filter.IP)
+		}
+
+		if !filter.From.IsZero() {
+			where.add("created_at >= %s", filter.From)
+		}
+
+		if !filter.To.IsZero() {
+			where.add("created_at < %s", filter.To)
+		}
+	}
+
+	order := keysetOrder(where, page, true, "", "id")
+	query := fmt.Sprintf(
+		`SELECT id, actor_id, action, ip, user_agent, details, before_payload, after_payload, created_at
+		FROM audit_event
+		%s
+		%s;`,
+		where, order,
+	)
+
+	err := a.db.Select(&events, query, where.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit events: %w", err)
+	}
+
+	if len(events) == 0 {
+		return nil, ErrAuditEventsNotFound
+	}
+
+	return events, nil
+}
+
+// jsonPayload returns json payload as query argument, missing payload is stored as NULL.
+func jsonPayload(payload *json.RawMessage) interface{} {
+	if payload == nil || len(*payload) == 0 {
+		return nil
+	}
+
+	return string(*payload)
+}
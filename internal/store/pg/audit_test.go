@@ -0,0 +1,234 @@
+package pg_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateAuditEvent(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAuditRepo(&pg.DB{DB: sqlxDB})
+
+	actorID := 1
+	payload := json.RawMessage(`{"id":1}`)
+	query := `INSERT INTO audit_event(actor_id, action, ip, user_agent, details, before_payload, after_payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          *model.AuditEvent
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [audit event created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(7)
+
+				mock.ExpectQuery(query).
+					WithArgs(&actorID, model.AuditSignInUnlocked, "192.0.2.1", "curl/7.81.0", `{"email":"test@test.com"}`, nil, nil).
+					WillReturnRows(rows)
+			},
+			input: &model.AuditEvent{
+				ActorID:   &actorID,
+				Action:    model.AuditSignInUnlocked,
+				IP:        "192.0.2.1",
+				UserAgent: "curl/7.81.0",
+				Details:   json.RawMessage(`{"email":"test@test.com"}`),
+			},
+			want: 7,
+		},
+		{
+			name: "Ok: [audit event with before and after payload created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(9)
+
+				mock.ExpectQuery(query).
+					WithArgs(&actorID, model.AuditSavedDeleted, "192.0.2.1", "", "{}", `{"id":1}`, nil).
+					WillReturnRows(rows)
+			},
+			input: &model.AuditEvent{
+				ActorID: &actorID,
+				Action:  model.AuditSavedDeleted,
+				IP:      "192.0.2.1",
+				Before:  &payload,
+			},
+			want: 9,
+		},
+		{
+			name: "Ok: [audit event without actor and details created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(8)
+
+				mock.ExpectQuery(query).WithArgs(nil, model.AuditSignInLocked, "192.0.2.1", "", "{}", nil, nil).WillReturnRows(rows)
+			},
+			input: &model.AuditEvent{Action: model.AuditSignInLocked, IP: "192.0.2.1"},
+			want:  8,
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(nil, model.AuditSignInLocked, "192.0.2.1", "", "{}", nil, nil).WillReturnError(fmt.Errorf("some error"))
+			},
+			input:          &model.AuditEvent{Action: model.AuditSignInLocked, IP: "192.0.2.1"},
+			wantErr:        true,
+			expectedErrMsg: "failed to create audit event: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateAuditEvent(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetAuditEvents(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAuditRepo(&pg.DB{DB: sqlxDB})
+
+	actorID := 1
+	payload := json.RawMessage(`{"id":1}`)
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "actor_id", "action", "ip", "user_agent", "details", "before_payload", "after_payload", "created_at"}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		filter         *model.AuditFilter
+		page           *model.PageRequest
+		want           []model.AuditEvent
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [first page without filter]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, 1, "saved.deleted", "192.0.2.1", "curl/7.81.0", []byte("{}"), []byte(`{"id":1}`), nil, createdAt).
+					AddRow(1, nil, "sign-in.failed", "192.0.2.1", "", []byte(`{"email":"test@test.com"}`), nil, nil, createdAt)
+
+				mock.ExpectQuery(`SELECT id, actor_id, action, ip, user_agent, details, before_payload, after_payload, created_at
+		FROM audit_event
+
+		ORDER BY id DESC LIMIT $1;`).WithArgs(3).WillReturnRows(rows)
+			},
+			page: &model.PageRequest{Limit: 2},
+			want: []model.AuditEvent{
+				{
+					ID: 2, ActorID: &actorID, Action: model.AuditSavedDeleted, IP: "192.0.2.1", UserAgent: "curl/7.81.0",
+					Details: json.RawMessage("{}"), Before: &payload, CreatedAt: createdAt,
+				},
+				{
+					ID: 1, Action: model.AuditSignInFailed, IP: "192.0.2.1",
+					Details: json.RawMessage(`{"email":"test@test.com"}`), CreatedAt: createdAt,
+				},
+			},
+		},
+		{
+			name: "Ok: [next page with filter]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(4, 1, "saved.created", "192.0.2.1", "", []byte("{}"), nil, []byte(`{"id":1}`), createdAt)
+
+				mock.ExpectQuery(`SELECT id, actor_id, action, ip, user_agent, details, before_payload, after_payload, created_at
+		FROM audit_event
+		WHERE actor_id = $1 AND action = $2 AND ip = $3 AND created_at >= $4 AND created_at < $5 AND id < $6
+		ORDER BY id DESC LIMIT $7;`).
+					WithArgs(1, model.AuditSavedCreated, "192.0.2.1", createdAt, createdAt.Add(time.Hour), 5, 11).
+					WillReturnRows(rows)
+			},
+			filter: &model.AuditFilter{
+				ActorID: 1, Action: model.AuditSavedCreated, IP: "192.0.2.1", From: createdAt, To: createdAt.Add(time.Hour),
+			},
+			page: &model.PageRequest{Cursor: &model.Cursor{ID: 5}, Limit: 10},
+			want: []model.AuditEvent{
+				{
+					ID: 4, ActorID: &actorID, Action: model.AuditSavedCreated, IP: "192.0.2.1",
+					Details: json.RawMessage("{}"), After: &payload, CreatedAt: createdAt,
+				},
+			},
+		},
+		{
+			name: "Error: [audit events not found]",
+			mock: func() {
+				mock.ExpectQuery(`SELECT id, actor_id, action, ip, user_agent, details, before_payload, after_payload, created_at
+		FROM audit_event
+
+		ORDER BY id DESC LIMIT $1;`).WithArgs(11).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			page:           &model.PageRequest{Limit: 10},
+			wantErr:        true,
+			expectedErrMsg: "audit events not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(`SELECT id, actor_id, action, ip, user_agent, details, before_payload, after_payload, created_at
+		FROM audit_event
+
+		ORDER BY id DESC LIMIT $1;`).WithArgs(11).WillReturnError(fmt.Errorf("some error"))
+			},
+			page:           &model.PageRequest{Limit: 10},
+			wantErr:        true,
+			expectedErrMsg: "failed to get audit events: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetAuditEvents(tt.filter, tt.page)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
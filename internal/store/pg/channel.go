@@ -15,10 +15,10 @@ var (
 )
 
 type ChannelRepo struct {
-	db *DB
+	db Queryer
 }
 
-func NewChannelRepo(db *DB) *ChannelRepo {
+func NewChannelRepo(db Queryer) *ChannelRepo {
 	return &ChannelRepo{db: db}
 }
 
@@ -49,10 +49,14 @@ func (c *ChannelRepo) GetChannelsCount() (int, error) {
 	return count, nil
 }
 
-func (c *ChannelRepo) GetChannelsByPage(offset int) ([]model.Channel, error) {
-	channels := make([]model.Channel, 0, 10)
+// GetChannelsByPage returns page of channels in order they were created, backward page is returned reversed.
+func (c *ChannelRepo) GetChannelsByPage(page *model.PageRequest) ([]model.Channel, error) {
+	channels := make([]model.Channel, 0, page.Limit+1)
 
-	err := c.db.Select(&channels, "SELECT * FROM channel OFFSET $1 LIMIT 10;", offset)
+	where := &whereBuilder{}
+	order := keysetOrder(where, page, false, "", "id")
+
+	err := c.db.Select(&channels, fmt.Sprintf("SELECT * FROM channel %s %s;", where, order), where.args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get channels by page: %w", err)
 	}
@@ -160,55 +160,68 @@ func Test_GetChannelsByPage(t *testing.T) {
 	tests := []struct {
 		name           string
 		mock           func()
-		input          int
+		input          *model.PageRequest
 		want           []model.Channel
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
-			name: "Ok: [channels found with offset 0]",
+			name: "Ok: [first page of channels found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"}).
 					AddRow(1).AddRow(2).AddRow(3).AddRow(4).AddRow(5).
 					AddRow(6).AddRow(7).AddRow(8).AddRow(9).AddRow(10)
 
-				mock.ExpectQuery("SELECT * FROM channel OFFSET $1 LIMIT 10;").
-					WithArgs(0).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT * FROM channel ORDER BY id ASC LIMIT $1;").
+					WithArgs(11).WillReturnRows(rows)
 			},
-			input: 0,
+			input: &model.PageRequest{Limit: 10},
 			want:  channels[:10],
 		},
 		{
-			name: "Ok: [channels found with offset 10]",
+			name: "Ok: [channels after cursor found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"}).
 					AddRow(11).AddRow(12).AddRow(13).AddRow(14).AddRow(15).
 					AddRow(16).AddRow(17).AddRow(18).AddRow(19).AddRow(20)
 
-				mock.ExpectQuery("SELECT * FROM channel OFFSET $1 LIMIT 10;").
-					WithArgs(10).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT * FROM channel WHERE id > $1 ORDER BY id ASC LIMIT $2;").
+					WithArgs(10, 11).WillReturnRows(rows)
 			},
-			input: 10,
+			input: &model.PageRequest{Cursor: &model.Cursor{ID: 10}, Limit: 10},
 			want:  channels[10:],
 		},
+		{
+			name: "Ok: [channels before cursor found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(10).AddRow(9).AddRow(8).AddRow(7).AddRow(6)
+
+				mock.ExpectQuery("SELECT * FROM channel WHERE id < $1 ORDER BY id DESC LIMIT $2;").
+					WithArgs(11, 6).WillReturnRows(rows)
+			},
+			input: &model.PageRequest{Cursor: &model.Cursor{ID: 11, Backward: true}, Limit: 5},
+			want:  []model.Channel{{ID: 10}, {ID: 9}, {ID: 8}, {ID: 7}, {ID: 6}},
+		},
 		{
 			name: "Error: [channels not found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"})
 
-				mock.ExpectQuery("SELECT * FROM channel OFFSET $1 LIMIT 10;").
-					WithArgs(0).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT * FROM channel ORDER BY id ASC LIMIT $1;").
+					WithArgs(11).WillReturnRows(rows)
 			},
-			input:          0,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "channels not found",
 		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("SELECT * FROM channel OFFSET $1 LIMIT 10;").
-					WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery("SELECT * FROM channel ORDER BY id ASC LIMIT $1;").
+					WithArgs(11).WillReturnError(fmt.Errorf("some error"))
 			},
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "failed to get channels by page: some error",
 		},
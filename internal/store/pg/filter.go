@@ -0,0 +1,91 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+// whereBuilder collects query conditions with their positional arguments.
+type whereBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add appends condition where every %s is replaced by placeholder of arg.
+func (w *whereBuilder) add(condition string, arg interface{}) {
+	w.args = append(w.args, arg)
+
+	placeholder := fmt.Sprintf("$%d", len(w.args))
+
+	w.conditions = append(w.conditions, strings.ReplaceAll(condition, "%s", placeholder))
+}
+
+// addRaw appends condition which doesn't need any argument.
+func (w *whereBuilder) addRaw(condition string) {
+	w.conditions = append(w.conditions, condition)
+}
+
+// next returns placeholder of the argument which will be appended after conditions.
+func (w *whereBuilder) next(arg interface{}) string {
+	w.args = append(w.args, arg)
+
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+func (w *whereBuilder) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+// messageFilterWhere builds conditions for message m joined with channel c and tg_user u.
+func messageFilterWhere(filter *model.MessageFilter) *whereBuilder {
+	where := &whereBuilder{}
+	if filter == nil {
+		return where
+	}
+
+	if filter.ChannelID != 0 {
+		where.add("m.channel_id = %s", filter.ChannelID)
+	}
+
+	if len(filter.ChannelNames) != 0 {
+		where.add("c.name = ANY(%s)", pq.Array(filter.ChannelNames))
+	}
+
+	if filter.TgUserID != 0 {
+		where.add("u.tg_id = %s", filter.TgUserID)
+	}
+
+	if !filter.From.IsZero() {
+		where.add("m.posted_at >= %s", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		where.add("m.posted_at < %s", filter.To)
+	}
+
+	if filter.MinReplies > 0 {
+		where.add("(SELECT COUNT(*) FROM replie WHERE message_id = m.id) >= %s", filter.MinReplies)
+	}
+
+	if filter.HasImage != nil {
+		if *filter.HasImage {
+			where.addRaw("COALESCE(m.imageurl, '') <> ''")
+		} else {
+			where.addRaw("COALESCE(m.imageurl, '') = ''")
+		}
+	}
+
+	if filter.NoReplies {
+		where.addRaw("NOT EXISTS (SELECT 1 FROM replie WHERE message_id = m.id)")
+	}
+
+	return where
+}
@@ -0,0 +1,101 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+var (
+	ErrIngestFailuresNotFound  = errors.New("ingest failures not found")
+	ErrIngestFailureNotFound   = errors.New("ingest failure not found")
+	ErrIngestFailureNotCreated = errors.New("ingest failure not created")
+)
+
+type IngestFailureRepo struct {
+	db Queryer
+}
+
+func NewIngestFailureRepo(db Queryer) *IngestFailureRepo {
+	return &IngestFailureRepo{db: db}
+}
+
+func (i *IngestFailureRepo) CreateIngestFailure(failure *model.IngestFailure) (int, error) {
+	var id int
+
+	row := i.db.QueryRow(
+		`INSERT INTO ingest_failure(topic, kafka_partition, kafka_offset, payload, error, attempts) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (topic, kafka_partition, kafka_offset) DO UPDATE
+		SET error = EXCLUDED.error, attempts = ingest_failure.attempts + EXCLUDED.attempts, updated_at = now()
+		RETURNING id;`,
+		failure.Topic, failure.Partition, failure.Offset, failure.Payload, failure.Error, failure.Attempts,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrIngestFailureNotCreated
+		}
+
+		return 0, fmt.Errorf("failed to create ingest failure: %w", err)
+	}
+
+	return id, nil
+}
+
+func (i *IngestFailureRepo) GetIngestFailuresByPage(offset int) ([]model.IngestFailure, error) {
+	failures := make([]model.IngestFailure, 0, 10)
+
+	err := i.db.Select(&failures, "SELECT * FROM ingest_failure ORDER BY id DESC OFFSET $1 LIMIT 10;", offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingest failures by page: %w", err)
+	}
+
+	if len(failures) == 0 {
+		return nil, ErrIngestFailuresNotFound
+	}
+
+	return failures, nil
+}
+
+func (i *IngestFailureRepo) GetIngestFailureByID(ID int) (*model.IngestFailure, error) {
+	var failure model.IngestFailure
+
+	err := i.db.Get(&failure, "SELECT * FROM ingest_failure WHERE id = $1;", ID)
+	if err == sql.ErrNoRows {
+		return nil, ErrIngestFailureNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingest failure by id: %w", err)
+	}
+
+	return &failure, nil
+}
+
+func (i *IngestFailureRepo) UpdateIngestFailure(ID int, errMsg string) error {
+	_, err := i.db.Exec(
+		"UPDATE ingest_failure SET error = $1, attempts = attempts + 1, updated_at = now() WHERE id = $2;",
+		errMsg, ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update ingest failure: %w", err)
+	}
+
+	return nil
+}
+
+func (i *IngestFailureRepo) DeleteIngestFailure(ID int) (int, error) {
+	var id int
+
+	row := i.db.QueryRow("DELETE FROM ingest_failure WHERE id = $1 RETURNING id;", ID)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrIngestFailureNotFound
+		}
+
+		return 0, fmt.Errorf("failed to delete ingest failure by id: %w", err)
+	}
+
+	return id, nil
+}
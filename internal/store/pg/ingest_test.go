@@ -0,0 +1,321 @@
+package pg_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+)
+
+func Test_CreateIngestFailure(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewIngestFailureRepo(&pg.DB{DB: sqlxDB})
+
+	query := `INSERT INTO ingest_failure(topic, kafka_partition, kafka_offset, payload, error, attempts) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (topic, kafka_partition, kafka_offset) DO UPDATE
+		SET error = EXCLUDED.error, attempts = ingest_failure.attempts + EXCLUDED.attempts, updated_at = now()
+		RETURNING id;`
+
+	input := &model.IngestFailure{Topic: "messages.get", Partition: 1, Offset: 2, Payload: []byte("{"), Error: "invalid payload", Attempts: 1}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          *model.IngestFailure
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [ingest failure created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+
+				mock.ExpectQuery(query).
+					WithArgs("messages.get", 1, 2, []byte("{"), "invalid payload", 1).WillReturnRows(rows)
+			},
+			input: input,
+			want:  1,
+		},
+		{
+			name: "Error: [ingest failure not created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).
+					WithArgs("messages.get", 1, 2, []byte("{"), "invalid payload", 1).WillReturnRows(rows)
+			},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "ingest failure not created",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).
+					WithArgs("messages.get", 1, 2, []byte("{"), "invalid payload", 1).WillReturnError(fmt.Errorf("some error"))
+			},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "failed to create ingest failure: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateIngestFailure(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetIngestFailuresByPage(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewIngestFailureRepo(&pg.DB{DB: sqlxDB})
+
+	failedAt := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "topic", "kafka_partition", "kafka_offset", "payload", "error", "attempts", "created_at", "updated_at"}
+
+	data := []model.IngestFailure{
+		{ID: 2, Topic: "messages.get", Partition: 0, Offset: 5, Payload: []byte("{}"), Error: "channel not found", Attempts: 3, CreatedAt: failedAt, UpdatedAt: failedAt},
+		{ID: 1, Topic: "channels.get", Partition: 0, Offset: 1, Payload: []byte("{"), Error: "invalid payload", Attempts: 1, CreatedAt: failedAt, UpdatedAt: failedAt},
+	}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          int
+		want           []model.IngestFailure
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [ingest failures found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, "messages.get", 0, 5, []byte("{}"), "channel not found", 3, failedAt, failedAt).
+					AddRow(1, "channels.get", 0, 1, []byte("{"), "invalid payload", 1, failedAt, failedAt)
+
+				mock.ExpectQuery("SELECT * FROM ingest_failure ORDER BY id DESC OFFSET $1 LIMIT 10;").
+					WithArgs(0).WillReturnRows(rows)
+			},
+			input: 0,
+			want:  data,
+		},
+		{
+			name: "Error: [ingest failures not found]",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM ingest_failure ORDER BY id DESC OFFSET $1 LIMIT 10;").
+					WithArgs(0).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			input:          0,
+			wantErr:        true,
+			expectedErrMsg: "ingest failures not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM ingest_failure ORDER BY id DESC OFFSET $1 LIMIT 10;").
+					WithArgs(0).WillReturnError(fmt.Errorf("some error"))
+			},
+			input:          0,
+			wantErr:        true,
+			expectedErrMsg: "failed to get ingest failures by page: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetIngestFailuresByPage(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetIngestFailureByID(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewIngestFailureRepo(&pg.DB{DB: sqlxDB})
+
+	failedAt := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "topic", "kafka_partition", "kafka_offset", "payload", "error", "attempts", "created_at", "updated_at"}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          int
+		want           *model.IngestFailure
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [ingest failure found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "channels.get", 0, 1, []byte("{"), "invalid payload", 1, failedAt, failedAt)
+
+				mock.ExpectQuery("SELECT * FROM ingest_failure WHERE id = $1;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			input: 1,
+			want: &model.IngestFailure{
+				ID: 1, Topic: "channels.get", Partition: 0, Offset: 1, Payload: []byte("{"), Error: "invalid payload", Attempts: 1, CreatedAt: failedAt, UpdatedAt: failedAt,
+			},
+		},
+		{
+			name: "Error: [ingest failure not found]",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM ingest_failure WHERE id = $1;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "ingest failure not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM ingest_failure WHERE id = $1;").
+					WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "failed to get ingest failure by id: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetIngestFailureByID(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_DeleteIngestFailure(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewIngestFailureRepo(&pg.DB{DB: sqlxDB})
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          int
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [ingest failure deleted]",
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM ingest_failure WHERE id = $1 RETURNING id;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			input: 1,
+			want:  1,
+		},
+		{
+			name: "Error: [ingest failure not found]",
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM ingest_failure WHERE id = $1 RETURNING id;").
+					WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "ingest failure not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM ingest_failure WHERE id = $1 RETURNING id;").
+					WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "failed to delete ingest failure by id: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.DeleteIngestFailure(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 )
@@ -13,22 +14,49 @@ var (
 	ErrFullMessagesNotFound  = errors.New("full messages not found")
 	ErrFullMessageNotFound   = errors.New("full message not found")
 	ErrMessageNotCreated     = errors.New("message not created")
+	ErrSearchResultsNotFound = errors.New("search results not found")
+)
+
+const (
+	fullMessageColumns = `m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+		m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
+		c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
+		u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
+		(SELECT COUNT(*) FROM replie WHERE message_id = m.id)`
+
+	fullMessageQuery = `SELECT 
+		` + fullMessageColumns + `
+		FROM message m
+		LEFT JOIN channel c ON c.id = m.channel_id 
+		LEFT JOIN tg_user u ON m.user_id = u.id`
 )
 
 type MessageRepo struct {
-	db *DB
+	db Queryer
 }
 
-func NewMessageRepo(db *DB) *MessageRepo {
+func NewMessageRepo(db Queryer) *MessageRepo {
 	return &MessageRepo{db: db}
 }
 
+// CreateMessage inserts message or updates title, image and edit time of the message with the same url.
+// Message is updated only by payload edited later than the stored one, so redelivered older payload can't undo an edit.
+// Id of the stored message is returned in all cases.
 func (m *MessageRepo) CreateMessage(message *model.MessageDTO) (int, error) {
 	var id int
 
 	row := m.db.QueryRow(
-		"INSERT INTO message(channel_id, user_id, title, message_url, imageurl) VALUES ($1, $2, $3, $4, $5) RETURNING id;",
-		message.ChannelID, message.UserID, message.Title, message.MessageURL, message.ImageURL,
+		`WITH upserted AS (
+			INSERT INTO message(channel_id, user_id, title, message_url, imageurl, posted_at, edited_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (message_url) DO UPDATE
+			SET title = EXCLUDED.title, imageurl = EXCLUDED.imageurl, edited_at = EXCLUDED.edited_at
+			WHERE EXCLUDED.edited_at IS NOT NULL AND (message.edited_at IS NULL OR EXCLUDED.edited_at > message.edited_at)
+			RETURNING id
+		)
+		SELECT id FROM upserted
+		UNION ALL
+		SELECT id FROM message WHERE message_url = $4 AND NOT EXISTS (SELECT 1 FROM upserted);`,
+		message.ChannelID, message.UserID, message.Title, message.MessageURL, message.ImageURL, message.PostedAt, message.EditedAt,
 	)
 	if err := row.Scan(&id); err != nil {
 		if err == sql.ErrNoRows {
@@ -41,83 +69,49 @@ func (m *MessageRepo) CreateMessage(message *model.MessageDTO) (int, error) {
 	return id, nil
 }
 
-func (m *MessageRepo) GetMessagesCount() (int, error) {
+// GetMessagesCount returns number of messages which match filter.
+func (m *MessageRepo) GetMessagesCount(filter *model.MessageFilter) (int, error) {
 	var count int
 
-	err := m.db.Get(&count, "SELECT COUNT(*) FROM message;")
-	if err == sql.ErrNoRows {
-		return 0, ErrMessagesCountNotFound
-	}
-
-	if err != nil {
-		return 0, fmt.Errorf("failed to get messages count: %w", err)
-	}
-
-	return count, nil
-}
-
-func (m *MessageRepo) GetMessagesCountByChannelID(ID int) (int, error) {
-	var count int
+	where := messageFilterWhere(filter)
+	query := fmt.Sprintf(
+		`SELECT COUNT(*)
+		FROM message m
+		LEFT JOIN channel c ON c.id = m.channel_id
+		LEFT JOIN tg_user u ON m.user_id = u.id
+		%s;`,
+		where,
+	)
 
-	err := m.db.Get(&count, "SELECT COUNT(*) FROM message WHERE channel_id = $1;", ID)
+	err := m.db.Get(&count, query, where.args...)
 	if err == sql.ErrNoRows {
 		return 0, ErrMessagesCountNotFound
 	}
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to get messages count by channel id: %w", err)
+		return 0, fmt.Errorf("failed to get messages count: %w", err)
 	}
 
 	return count, nil
 }
 
-func (m *MessageRepo) GetFullMessagesByPage(offset int) ([]model.FullMessage, error) {
-	messages := make([]model.FullMessage, 0, 10)
-
-	err := m.db.Select(
-		&messages,
-		`SELECT 
-		m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
-		c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
-		u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
-		(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
-		FROM message m
-		LEFT JOIN channel c ON c.id = m.channel_id 
-		LEFT JOIN tg_user u ON m.user_id = u.id 
-		ORDER BY m.id DESC NULLS LAST OFFSET $1 LIMIT 10;`,
-		offset,
+// GetFullMessagesByPage returns page of messages which match filter, newest messages first.
+// Rows of backward page are returned oldest first.
+func (m *MessageRepo) GetFullMessagesByPage(filter *model.MessageFilter, page *model.PageRequest) ([]model.FullMessage, error) {
+	messages := make([]model.FullMessage, 0, page.Limit+1)
+
+	where := messageFilterWhere(filter)
+	order := keysetOrder(where, page, true, "m.posted_at", "m.id")
+	query := fmt.Sprintf(
+		`%s
+		%s
+		%s;`,
+		fullMessageQuery, where, order,
 	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get full messages by page: %w", err)
-	}
-
-	if len(messages) == 0 {
-		return nil, ErrFullMessagesNotFound
-	}
-
-	return messages, nil
-}
 
-func (m *MessageRepo) GetFullMessagesByChannelIDAndPage(ID int, offset int) ([]model.FullMessage, error) {
-	messages := make([]model.FullMessage, 0, 10)
-
-	err := m.db.Select(
-		&messages,
-		`SELECT 
-		m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
-		c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
-		u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
-		(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
-		FROM message m
-		LEFT JOIN channel c ON c.id = m.channel_id 
-		LEFT JOIN tg_user u ON m.user_id = u.id 
-		WHERE m.channel_id = $1
-		ORDER BY m.id DESC NULLS LAST OFFSET $2 LIMIT 10;`,
-		ID,
-		offset,
-	)
+	err := m.db.Select(&messages, query, where.args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get full messages by channel ID and page: %w", err)
+		return nil, fmt.Errorf("failed to get full messages by page: %w", err)
 	}
 
 	if len(messages) == 0 {
@@ -132,15 +126,9 @@ func (m *MessageRepo) GetFullMessagesByUserID(ID int) ([]model.FullMessage, erro
 
 	err := m.db.Select(
 		&messages,
-		`SELECT 
-		m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
-		c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
-		u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
-		(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
-		FROM message m
-		LEFT JOIN channel c ON c.id = m.channel_id 
-		LEFT JOIN tg_user u ON m.user_id = u.id 
-		WHERE m.user_id = $1;`,
+		fullMessageQuery+`
+		WHERE m.user_id = $1
+		ORDER BY m.posted_at DESC, m.id DESC;`,
 		ID,
 	)
 	if err != nil {
@@ -159,14 +147,7 @@ func (m *MessageRepo) GetFullMessageByID(ID int) (*model.FullMessage, error) {
 
 	err := m.db.Get(
 		&message,
-		`SELECT 
-		m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
-		c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
-		u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
-		(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
-		FROM message m
-		LEFT JOIN channel c ON c.id = m.channel_id 
-		LEFT JOIN tg_user u ON m.user_id = u.id 
+		fullMessageQuery+`
 		WHERE m.id = $1;`,
 		ID,
 	)
@@ -179,3 +160,67 @@ func (m *MessageRepo) GetFullMessageByID(ID int) (*model.FullMessage, error) {
 
 	return &message, nil
 }
+
+// SearchMessages returns page of messages whose title or replies match query, best matches first.
+// Query is parsed with every text search config from configs, first of them highlights the snippet.
+func (m *MessageRepo) SearchMessages(query string, configs []string, filter *model.MessageFilter, offset int) ([]model.SearchResult, error) {
+	results := make([]model.SearchResult, 0, 10)
+
+	where := messageFilterWhere(filter)
+	text := where.next(query)
+
+	var headlineConfig string
+
+	tsqueries := make([]string, 0, len(configs))
+	for i, config := range configs {
+		placeholder := where.next(config)
+		if i == 0 {
+			headlineConfig = placeholder
+		}
+
+		tsqueries = append(tsqueries, fmt.Sprintf("websearch_to_tsquery(%s::regconfig, %s)", placeholder, text))
+	}
+
+	offsetPlaceholder := where.next(offset)
+
+	sqlQuery := fmt.Sprintf(
+		`WITH q AS (SELECT %s AS query),
+		matches AS (
+			SELECT m.id AS message_id, ts_rank(m.tsv, q.query) AS rank, NULL::INT AS replie_id, COALESCE(m.title, '') AS matched
+			FROM message m, q
+			WHERE m.tsv @@ q.query
+			UNION ALL
+			SELECT r.message_id, ts_rank(r.tsv, q.query), r.id, r.title
+			FROM replie r, q
+			WHERE r.tsv @@ q.query
+		),
+		best AS (
+			SELECT DISTINCT ON (message_id) message_id, rank, replie_id, matched
+			FROM matches
+			ORDER BY message_id, rank DESC, replie_id NULLS FIRST
+		)
+		SELECT 
+		%s,
+		b.rank as rank, b.replie_id as matchedReplieId,
+		ts_headline(%s::regconfig, b.matched, q.query, 'StartSel=<b>, StopSel=</b>, MaxFragments=2') as snippet
+		FROM best b
+		CROSS JOIN q
+		JOIN message m ON m.id = b.message_id
+		LEFT JOIN channel c ON c.id = m.channel_id 
+		LEFT JOIN tg_user u ON m.user_id = u.id
+		%s
+		ORDER BY b.rank DESC, m.posted_at DESC, m.id DESC OFFSET %s LIMIT 10;`,
+		strings.Join(tsqueries, " || "), fullMessageColumns, headlineConfig, where, offsetPlaceholder,
+	)
+
+	err := m.db.Select(&results, sqlQuery, where.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	if len(results) == 0 {
+		return nil, ErrSearchResultsNotFound
+	}
+
+	return results, nil
+}
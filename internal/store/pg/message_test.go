@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
@@ -26,6 +27,21 @@ func Test_CreateMessage(t *testing.T) {
 
 	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
 
+	query := `WITH upserted AS (
+			INSERT INTO message(channel_id, user_id, title, message_url, imageurl, posted_at, edited_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (message_url) DO UPDATE
+			SET title = EXCLUDED.title, imageurl = EXCLUDED.imageurl, edited_at = EXCLUDED.edited_at
+			WHERE EXCLUDED.edited_at IS NOT NULL AND (message.edited_at IS NULL OR EXCLUDED.edited_at > message.edited_at)
+			RETURNING id
+		)
+		SELECT id FROM upserted
+		UNION ALL
+		SELECT id FROM message WHERE message_url = $4 AND NOT EXISTS (SELECT 1 FROM upserted);`
+
+	postedAt := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	editedAt := postedAt.Add(time.Hour)
+	input := &model.MessageDTO{ChannelID: 1, UserID: 1, Title: "test", MessageURL: "test.url", ImageURL: "test.jpg", PostedAt: postedAt}
+
 	tests := []struct {
 		name           string
 		mock           func()
@@ -40,9 +56,32 @@ func Test_CreateMessage(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id"}).
 					AddRow(1)
 
-				mock.ExpectQuery("INSERT INTO message(channel_id, user_id, title, message_url, imageurl) VALUES ($1, $2, $3, $4, $5) RETURNING id;").WithArgs(1, 1, "test", "test.url", "test.jpg").WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(1, 1, "test", "test.url", "test.jpg", postedAt, nil).WillReturnRows(rows)
+			},
+			input: input,
+			want:  1,
+		},
+		{
+			name: "Ok: [edited message stored]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery(query).WithArgs(1, 1, "edited", "test.url", "test.jpg", postedAt, editedAt).WillReturnRows(rows)
+			},
+			input: &model.MessageDTO{ChannelID: 1, UserID: 1, Title: "edited", MessageURL: "test.url", ImageURL: "test.jpg", PostedAt: postedAt, EditedAt: &editedAt},
+			want:  1,
+		},
+		{
+			// update is skipped for payload which is older than stored edit, id of stored message is still returned
+			name: "Ok: [older payload after edit returns stored message]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery(query).WithArgs(1, 1, "test", "test.url", "test.jpg", postedAt, nil).WillReturnRows(rows)
 			},
-			input: &model.MessageDTO{ChannelID: 1, UserID: 1, Title: "test", MessageURL: "test.url", ImageURL: "test.jpg"},
+			input: input,
 			want:  1,
 		},
 		{
@@ -50,18 +89,18 @@ func Test_CreateMessage(t *testing.T) {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"})
 
-				mock.ExpectQuery("INSERT INTO message(channel_id, user_id, title, message_url, imageurl) VALUES ($1, $2, $3, $4, $5) RETURNING id;").WithArgs(1, 1, "test", "test.url", "test.jpg").WillReturnRows(rows)
+				mock.ExpectQuery(query).WithArgs(1, 1, "test", "test.url", "test.jpg", postedAt, nil).WillReturnRows(rows)
 			},
-			input:          &model.MessageDTO{ChannelID: 1, UserID: 1, Title: "test", MessageURL: "test.url", ImageURL: "test.jpg"},
+			input:          input,
 			wantErr:        true,
 			expectedErrMsg: "message not created",
 		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("INSERT INTO message(channel_id, user_id, title, message_url, imageurl) VALUES ($1, $2, $3, $4, $5) RETURNING id;").WithArgs(1, 1, "test", "test.url", "test.jpg").WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery(query).WithArgs(1, 1, "test", "test.url", "test.jpg", postedAt, nil).WillReturnError(fmt.Errorf("some error"))
 			},
-			input:          &model.MessageDTO{ChannelID: 1, UserID: 1, Title: "test", MessageURL: "test.url", ImageURL: "test.jpg"},
+			input:          input,
 			wantErr:        true,
 			expectedErrMsg: "failed to create message: some error",
 		},
@@ -97,9 +136,17 @@ func Test_GetMessagesCount(t *testing.T) {
 
 	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
 
+	query := `SELECT COUNT(*)
+		FROM message m
+		LEFT JOIN channel c ON c.id = m.channel_id
+		LEFT JOIN tg_user u ON m.user_id = u.id`
+
+	hasImage := true
+
 	tests := []struct {
 		name           string
 		mock           func()
+		input          *model.MessageFilter
 		want           int
 		wantErr        bool
 		expectedErrMsg string
@@ -110,104 +157,54 @@ func Test_GetMessagesCount(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"count"}).
 					AddRow(10)
 
-				mock.ExpectQuery("SELECT COUNT(*) FROM message;").
+				mock.ExpectQuery(query + "\n;").
 					WillReturnRows(rows)
 			},
 			want: 10,
 		},
 		{
-			name: "Error: [messages count not found]",
+			name: "Ok: [messages count by channel id found]",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"count"})
+				rows := sqlmock.NewRows([]string{"count"}).
+					AddRow(5)
 
-				mock.ExpectQuery("SELECT COUNT(*) FROM message;").
-					WillReturnRows(rows)
-			},
-			wantErr:        true,
-			expectedErrMsg: "messages count not found",
-		},
-		{
-			name: "Error: [some sql error]",
-			mock: func() {
-				mock.ExpectQuery("SELECT COUNT(*) FROM message;").
-					WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery(query + " WHERE m.channel_id = $1;").
+					WithArgs(1).WillReturnRows(rows)
 			},
-			wantErr:        true,
-			expectedErrMsg: "failed to get messages count: some error",
+			input: &model.MessageFilter{ChannelID: 1},
+			want:  5,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mock()
-
-			got, err := r.GetMessagesCount()
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
-			} else {
-				assert.NoError(t, err)
-				assert.EqualValues(t, tt.want, got)
-			}
-
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
-	}
-}
-
-func Test_GetMessagesCountByChannelID(t *testing.T) {
-	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-
-	defer db.Close()
-
-	sqlxDB := sqlx.NewDb(db, "postgres")
-
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
-
-	tests := []struct {
-		name           string
-		mock           func()
-		input          int
-		want           int
-		wantErr        bool
-		expectedErrMsg string
-	}{
 		{
-			name: "Ok: [messages count found]",
+			name: "Ok: [filtered messages count found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"count"}).
-					AddRow(10)
+					AddRow(2)
 
-				mock.ExpectQuery("SELECT COUNT(*) FROM message WHERE channel_id = $1;").
-					WithArgs(1).WillReturnRows(rows)
+				mock.ExpectQuery(query+" WHERE c.name = ANY($1) AND (SELECT COUNT(*) FROM replie WHERE message_id = m.id) >= $2 AND COALESCE(m.imageurl, '') <> '';").
+					WithArgs("{\"go_go\",\"golang\"}", 3).WillReturnRows(rows)
 			},
-			input: 1,
-			want:  10,
+			input: &model.MessageFilter{ChannelNames: []string{"go_go", "golang"}, MinReplies: 3, HasImage: &hasImage},
+			want:  2,
 		},
 		{
 			name: "Error: [messages count not found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"count"})
 
-				mock.ExpectQuery("SELECT COUNT(*) FROM message WHERE channel_id = $1;").
-					WithArgs(1).WillReturnRows(rows)
+				mock.ExpectQuery(query + "\n;").
+					WillReturnRows(rows)
 			},
-			input:          1,
 			wantErr:        true,
 			expectedErrMsg: "messages count not found",
 		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("SELECT COUNT(*) FROM message WHERE channel_id = $1;").
-					WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery(query + "\n;").
+					WillReturnError(fmt.Errorf("some error"))
 			},
-			input:          1,
 			wantErr:        true,
-			expectedErrMsg: "failed to get messages count by channel id: some error",
+			expectedErrMsg: "failed to get messages count: some error",
 		},
 	}
 
@@ -215,7 +212,7 @@ func Test_GetMessagesCountByChannelID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetMessagesCountByChannelID(tt.input)
+			got, err := r.GetMessagesCount(tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -272,6 +269,7 @@ func Test_GetFullMessageByID(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
@@ -293,6 +291,7 @@ func Test_GetFullMessageByID(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
@@ -313,6 +312,7 @@ func Test_GetFullMessageByID(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
@@ -370,16 +370,19 @@ func Test_GetFullMessagesByPage(t *testing.T) {
 
 	json.Unmarshal(bytes, &data)
 
+	postedAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name           string
 		mock           func()
-		input          int
+		input          *model.PageRequest
+		filter         *model.MessageFilter
 		want           []model.FullMessage
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
-			name: "Ok: [full messages with offset 0 found]",
+			name: "Ok: [first page of full messages found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"messageid", "messagetitle", "messageurl", "messageimageurl", "channelname", "channeltitle", "channelimageurl", "userid", "userfullname", "userimageurl", "count"}).
 					AddRow(1, "test1", "test1.tg", "test1.jpg", "test1c", "test1c testc", "test1c.jpg", 1, "test1u testu", "test1u.jpg", 0).
@@ -396,21 +399,22 @@ func Test_GetFullMessagesByPage(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
-					LEFT JOIN tg_user u ON m.user_id = u.id 
-					ORDER BY m.id DESC NULLS LAST OFFSET $1 LIMIT 10;`,
+					LEFT JOIN tg_user u ON m.user_id = u.id  
+					ORDER BY m.posted_at DESC, m.id DESC LIMIT $1;`,
 				).
-					WithArgs(0).WillReturnRows(rows)
+					WithArgs(11).WillReturnRows(rows)
 			},
-			input: 0,
+			input: &model.PageRequest{Limit: 10},
 			want:  data[:10],
 		},
 		{
-			name: "Ok: [full messages with offset 10 found]",
+			name: "Ok: [full messages after cursor found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"messageid", "messagetitle", "messageurl", "messageimageurl", "channelname", "channeltitle", "channelimageurl", "userid", "userfullname", "userimageurl", "count"}).
 					AddRow(11, "test11", "test11.tg", "test11.jpg", "test11c", "test11c testc", "test11c.jpg", 11, "test11u testu", "test11u.jpg", 0).
@@ -427,195 +431,94 @@ func Test_GetFullMessagesByPage(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
-					LEFT JOIN tg_user u ON m.user_id = u.id 
-					ORDER BY m.id DESC NULLS LAST OFFSET $1 LIMIT 10;`,
-				).WithArgs(10).WillReturnRows(rows)
+					LEFT JOIN tg_user u ON m.user_id = u.id
+					WHERE (m.posted_at, m.id) < ($1, $2)
+					ORDER BY m.posted_at DESC, m.id DESC LIMIT $3;`,
+				).WithArgs(postedAt, 10, 11).WillReturnRows(rows)
 			},
-			input: 10,
+			input: &model.PageRequest{Cursor: &model.Cursor{PostedAt: postedAt, ID: 10}, Limit: 10},
 			want:  data[10:],
 		},
 		{
-			name: "Error: [full messages not found]",
-			mock: func() {
-				rows := sqlmock.NewRows([]string{"messageid", "messagetitle", "messageurl", "messageimageurl", "channelname", "channeltitle", "channelimageurl", "userid", "userfullname", "userimageurl", "count"})
-
-				mock.ExpectQuery(
-					`SELECT 
-					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
-					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
-					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
-					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
-					FROM message m
-					LEFT JOIN channel c ON c.id = m.channel_id 
-					LEFT JOIN tg_user u ON m.user_id = u.id 
-					ORDER BY m.id DESC NULLS LAST OFFSET $1 LIMIT 10;`,
-				).WithArgs(0).WillReturnRows(rows)
-			},
-			input:          0,
-			wantErr:        true,
-			expectedErrMsg: "full messages not found",
-		},
-		{
-			name: "Error: [some sql error]",
-			mock: func() {
-				mock.ExpectQuery(
-					`SELECT 
-					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
-					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
-					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
-					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
-					FROM message m
-					LEFT JOIN channel c ON c.id = m.channel_id 
-					LEFT JOIN tg_user u ON m.user_id = u.id 
-					ORDER BY m.id DESC NULLS LAST OFFSET $1 LIMIT 10;`,
-				).WithArgs(0).WillReturnError(fmt.Errorf("some error"))
-			},
-			input:          0,
-			wantErr:        true,
-			expectedErrMsg: "failed to get full messages by page: some error",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mock()
-
-			got, err := r.GetFullMessagesByPage(tt.input)
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
-			} else {
-				assert.NoError(t, err)
-				assert.EqualValues(t, tt.want, got)
-			}
-
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
-	}
-}
-
-func Test_GetFullMessagesByChannelIDAndPage(t *testing.T) {
-	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-
-	defer db.Close()
-
-	sqlxDB := sqlx.NewDb(db, "postgres")
-
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
-
-	var data []model.FullMessage
-
-	file, err := os.Open("./fake_data/fullmessagesbychannel.json")
-	if err != nil {
-		panic(err)
-	}
-
-	bytes, _ := io.ReadAll(file)
-
-	json.Unmarshal(bytes, &data)
-
-	tests := []struct {
-		name           string
-		mock           func()
-		ID             int
-		offset         int
-		want           []model.FullMessage
-		wantErr        bool
-		expectedErrMsg string
-	}{
-		{
-			name: "Ok: [full messages by channel ID with offset 0 found]",
+			name: "Ok: [full messages posted before time found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"messageid", "messagetitle", "messageurl", "messageimageurl", "channelname", "channeltitle", "channelimageurl", "userid", "userfullname", "userimageurl", "count"}).
-					AddRow(1, "test1", "test1.tg", "test1.jpg", "testc", "testc testc", "testc.jpg", 1, "test1u testu", "test1u.jpg", 0).
-					AddRow(2, "test2", "test2.tg", "test2.jpg", "testc", "testc testc", "testc.jpg", 2, "test2u testu", "test2u.jpg", 0).
-					AddRow(3, "test3", "test3.tg", "test3.jpg", "testc", "testc testc", "testc.jpg", 3, "test3u testu", "test3u.jpg", 0).
-					AddRow(4, "test4", "test4.tg", "test4.jpg", "testc", "testc testc", "testc.jpg", 4, "test4u testu", "test4u.jpg", 0).
-					AddRow(5, "test5", "test5.tg", "test5.jpg", "testc", "testc testc", "testc.jpg", 5, "test5u testu", "test5u.jpg", 0).
-					AddRow(6, "test6", "test6.tg", "test6.jpg", "testc", "testc testc", "testc.jpg", 6, "test6u testu", "test6u.jpg", 0).
-					AddRow(7, "test7", "test7.tg", "test7.jpg", "testc", "testc testc", "testc.jpg", 7, "test7u testu", "test7u.jpg", 0).
-					AddRow(8, "test8", "test8.tg", "test8.jpg", "testc", "testc testc", "testc.jpg", 8, "test8u testu", "test8u.jpg", 0).
-					AddRow(9, "test9", "test9.tg", "test9.jpg", "testc", "testc testc", "testc.jpg", 9, "test9u testu", "test9u.jpg", 0).
-					AddRow(10, "test10", "test10.tg", "test10.jpg", "testc", "testc testc", "testc.jpg", 10, "test10u testu", "test10u.jpg", 0)
+					AddRow(11, "test11", "test11.tg", "test11.jpg", "test11c", "test11c testc", "test11c.jpg", 11, "test11u testu", "test11u.jpg", 0)
 
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
 					LEFT JOIN tg_user u ON m.user_id = u.id 
-					WHERE m.channel_id = $1
-					ORDER BY m.id DESC NULLS LAST OFFSET $2 LIMIT 10;`,
-				).
-					WithArgs(1, 0).WillReturnRows(rows)
+					WHERE m.posted_at < $1
+					ORDER BY m.posted_at DESC, m.id DESC LIMIT $2;`,
+				).WithArgs(time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), 11).WillReturnRows(rows)
 			},
-			ID:     1,
-			offset: 0,
-			want:   data[:10],
+			input:  &model.PageRequest{Limit: 10},
+			filter: &model.MessageFilter{To: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)},
+			want:   data[10:11],
 		},
 		{
-			name: "Ok: [full messages by channel ID with offset 10 found]",
+			name: "Ok: [filtered full messages before cursor found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"messageid", "messagetitle", "messageurl", "messageimageurl", "channelname", "channeltitle", "channelimageurl", "userid", "userfullname", "userimageurl", "count"}).
-					AddRow(11, "test11", "test11.tg", "test11.jpg", "testc", "testc testc", "testc.jpg", 11, "test11u testu", "test11u.jpg", 0).
-					AddRow(12, "test12", "test12.tg", "test12.jpg", "testc", "testc testc", "testc.jpg", 12, "test12u testu", "test12u.jpg", 0).
-					AddRow(13, "test13", "test13.tg", "test13.jpg", "testc", "testc testc", "testc.jpg", 13, "test13u testu", "test13u.jpg", 0).
-					AddRow(14, "test14", "test14.tg", "test14.jpg", "testc", "testc testc", "testc.jpg", 14, "test14u testu", "test14u.jpg", 0).
-					AddRow(15, "test15", "test15.tg", "test15.jpg", "testc", "testc testc", "testc.jpg", 15, "test15u testu", "test15u.jpg", 0).
-					AddRow(16, "test16", "test16.tg", "test16.jpg", "testc", "testc testc", "testc.jpg", 16, "test16u testu", "test16u.jpg", 0).
-					AddRow(17, "test17", "test17.tg", "test17.jpg", "testc", "testc testc", "testc.jpg", 17, "test17u testu", "test17u.jpg", 0).
-					AddRow(18, "test18", "test18.tg", "test18.jpg", "testc", "testc testc", "testc.jpg", 18, "test18u testu", "test18u.jpg", 0).
-					AddRow(19, "test19", "test19.tg", "test19.jpg", "testc", "testc testc", "testc.jpg", 19, "test19u testu", "test19u.jpg", 0).
-					AddRow(20, "test20", "test20.tg", "test20.jpg", "testc", "testc testc", "testc.jpg", 20, "test20u testu", "test20u.jpg", 0)
+					AddRow(11, "test11", "test11.tg", "test11.jpg", "test11c", "test11c testc", "test11c.jpg", 11, "test11u testu", "test11u.jpg", 0)
 
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
-					LEFT JOIN tg_user u ON m.user_id = u.id 
-					WHERE m.channel_id = $1
-					ORDER BY m.id DESC NULLS LAST OFFSET $2 LIMIT 10;`,
-				).WithArgs(1, 10).WillReturnRows(rows)
+					LEFT JOIN tg_user u ON m.user_id = u.id
+					WHERE m.channel_id = $1 AND u.tg_id = $2 AND m.posted_at >= $3 AND m.posted_at < $4
+					AND NOT EXISTS (SELECT 1 FROM replie WHERE message_id = m.id) AND (m.posted_at, m.id) > ($5, $6)
+					ORDER BY m.posted_at ASC, m.id ASC LIMIT $7;`,
+				).WithArgs(1, 100, time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, time.July, 2, 0, 0, 0, 0, time.UTC), postedAt, 12, 11).WillReturnRows(rows)
+			},
+			input: &model.PageRequest{Cursor: &model.Cursor{PostedAt: postedAt, ID: 12, Backward: true}, Limit: 10},
+			filter: &model.MessageFilter{
+				ChannelID: 1,
+				TgUserID:  100,
+				From:      time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC),
+				To:        time.Date(2022, time.July, 2, 0, 0, 0, 0, time.UTC),
+				NoReplies: true,
 			},
-			ID:     1,
-			offset: 10,
-			want:   data[10:],
+			want: data[10:11],
 		},
 		{
-			name: "Error: [full messages by channel ID not found]",
+			name: "Error: [full messages not found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"messageid", "messagetitle", "messageurl", "messageimageurl", "channelname", "channeltitle", "channelimageurl", "userid", "userfullname", "userimageurl", "count"})
 
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
-					LEFT JOIN tg_user u ON m.user_id = u.id 
-					WHERE m.channel_id = $1
-					ORDER BY m.id DESC NULLS LAST OFFSET $2 LIMIT 10;`,
-				).WithArgs(1, 0).WillReturnRows(rows)
+					LEFT JOIN tg_user u ON m.user_id = u.id  
+					ORDER BY m.posted_at DESC, m.id DESC LIMIT $1;`,
+				).WithArgs(11).WillReturnRows(rows)
 			},
-			ID:             1,
-			offset:         0,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "full messages not found",
 		},
@@ -625,20 +528,19 @@ func Test_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
-					LEFT JOIN tg_user u ON m.user_id = u.id 
-					WHERE m.channel_id = $1
-					ORDER BY m.id DESC NULLS LAST OFFSET $2 LIMIT 10;`,
-				).WithArgs(1, 0).WillReturnError(fmt.Errorf("some error"))
+					LEFT JOIN tg_user u ON m.user_id = u.id  
+					ORDER BY m.posted_at DESC, m.id DESC LIMIT $1;`,
+				).WithArgs(11).WillReturnError(fmt.Errorf("some error"))
 			},
-			ID:             1,
-			offset:         0,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
-			expectedErrMsg: "failed to get full messages by channel ID and page: some error",
+			expectedErrMsg: "failed to get full messages by page: some error",
 		},
 	}
 
@@ -646,7 +548,7 @@ func Test_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetFullMessagesByChannelIDAndPage(tt.ID, tt.offset)
+			got, err := r.GetFullMessagesByPage(tt.filter, tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -710,13 +612,15 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
 					LEFT JOIN tg_user u ON m.user_id = u.id 
-					WHERE m.user_id = $1;`,
+					WHERE m.user_id = $1
+					ORDER BY m.posted_at DESC, m.id DESC;`,
 				).
 					WithArgs(1).WillReturnRows(rows)
 			},
@@ -741,13 +645,15 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
 					LEFT JOIN tg_user u ON m.user_id = u.id 
-					WHERE m.user_id = $1;`,
+					WHERE m.user_id = $1
+					ORDER BY m.posted_at DESC, m.id DESC;`,
 				).WithArgs(2).WillReturnRows(rows)
 			},
 			ID:   2,
@@ -761,13 +667,15 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
 					LEFT JOIN tg_user u ON m.user_id = u.id 
-					WHERE m.user_id = $1;`,
+					WHERE m.user_id = $1
+					ORDER BY m.posted_at DESC, m.id DESC;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
 			ID:             1,
@@ -780,13 +688,15 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 				mock.ExpectQuery(
 					`SELECT 
 					m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+					m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
 					c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
 					u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
 					(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
 					FROM message m
 					LEFT JOIN channel c ON c.id = m.channel_id 
 					LEFT JOIN tg_user u ON m.user_id = u.id 
-					WHERE m.user_id = $1;`,
+					WHERE m.user_id = $1
+					ORDER BY m.posted_at DESC, m.id DESC;`,
 				).WithArgs(1).WillReturnError(fmt.Errorf("some error"))
 			},
 			ID:             1,
@@ -812,3 +722,173 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 		})
 	}
 }
+
+func Test_SearchMessages(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
+
+	replieID := 3
+	columns := []string{"messageid", "messagetitle", "messageurl", "messageimageurl", "channelname", "channeltitle", "channelimageurl", "userid", "userfullname", "userimageurl", "count", "rank", "matchedreplieid", "snippet"}
+
+	query := `WITH q AS (SELECT websearch_to_tsquery($2::regconfig, $1) || websearch_to_tsquery($3::regconfig, $1) AS query),
+		matches AS (
+			SELECT m.id AS message_id, ts_rank(m.tsv, q.query) AS rank, NULL::INT AS replie_id, COALESCE(m.title, '') AS matched
+			FROM message m, q
+			WHERE m.tsv @@ q.query
+			UNION ALL
+			SELECT r.message_id, ts_rank(r.tsv, q.query), r.id, r.title
+			FROM replie r, q
+			WHERE r.tsv @@ q.query
+		),
+		best AS (
+			SELECT DISTINCT ON (message_id) message_id, rank, replie_id, matched
+			FROM matches
+			ORDER BY message_id, rank DESC, replie_id NULLS FIRST
+		)
+		SELECT 
+		m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+		m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
+		c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
+		u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
+		(SELECT COUNT(*) FROM replie WHERE message_id = m.id),
+		b.rank as rank, b.replie_id as matchedReplieId,
+		ts_headline($2::regconfig, b.matched, q.query, 'StartSel=<b>, StopSel=</b>, MaxFragments=2') as snippet
+		FROM best b
+		CROSS JOIN q
+		JOIN message m ON m.id = b.message_id
+		LEFT JOIN channel c ON c.id = m.channel_id 
+		LEFT JOIN tg_user u ON m.user_id = u.id
+		
+		ORDER BY b.rank DESC, m.posted_at DESC, m.id DESC OFFSET $4 LIMIT 10;`
+
+	filteredQuery := `WITH q AS (SELECT websearch_to_tsquery($3::regconfig, $2) AS query),
+		matches AS (
+			SELECT m.id AS message_id, ts_rank(m.tsv, q.query) AS rank, NULL::INT AS replie_id, COALESCE(m.title, '') AS matched
+			FROM message m, q
+			WHERE m.tsv @@ q.query
+			UNION ALL
+			SELECT r.message_id, ts_rank(r.tsv, q.query), r.id, r.title
+			FROM replie r, q
+			WHERE r.tsv @@ q.query
+		),
+		best AS (
+			SELECT DISTINCT ON (message_id) message_id, rank, replie_id, matched
+			FROM matches
+			ORDER BY message_id, rank DESC, replie_id NULLS FIRST
+		)
+		SELECT 
+		m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+		m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
+		c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
+		u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
+		(SELECT COUNT(*) FROM replie WHERE message_id = m.id),
+		b.rank as rank, b.replie_id as matchedReplieId,
+		ts_headline($3::regconfig, b.matched, q.query, 'StartSel=<b>, StopSel=</b>, MaxFragments=2') as snippet
+		FROM best b
+		CROSS JOIN q
+		JOIN message m ON m.id = b.message_id
+		LEFT JOIN channel c ON c.id = m.channel_id 
+		LEFT JOIN tg_user u ON m.user_id = u.id
+		WHERE c.name = ANY($1)
+		ORDER BY b.rank DESC, m.posted_at DESC, m.id DESC OFFSET $4 LIMIT 10;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		configs        []string
+		filter         *model.MessageFilter
+		want           []model.SearchResult
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [search results found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "golang news", "test1.tg", "test1.jpg", "test1c", "test1c testc", "test1c.jpg", 1, "test1u testu", "test1u.jpg", 2, 0.6, nil, "<b>golang</b> news").
+					AddRow(2, "test2", "test2.tg", "test2.jpg", "test2c", "test2c testc", "test2c.jpg", 2, "test2u testu", "test2u.jpg", 1, 0.3, replieID, "about <b>golang</b>")
+
+				mock.ExpectQuery(query).
+					WithArgs("golang", "english", "russian", 0).WillReturnRows(rows)
+			},
+			configs: []string{"english", "russian"},
+			want: []model.SearchResult{
+				{
+					FullMessage: model.FullMessage{ID: 1, Title: "golang news", MessageURL: "test1.tg", MessageImageURL: "test1.jpg", ChannelName: "test1c", ChannelTitle: "test1c testc", ChannelImageURL: "test1c.jpg", UserID: 1, UserFullname: "test1u testu", UserImageURL: "test1u.jpg", RepliesCount: 2},
+					Rank:        0.6,
+					Snippet:     "<b>golang</b> news",
+				},
+				{
+					FullMessage:     model.FullMessage{ID: 2, Title: "test2", MessageURL: "test2.tg", MessageImageURL: "test2.jpg", ChannelName: "test2c", ChannelTitle: "test2c testc", ChannelImageURL: "test2c.jpg", UserID: 2, UserFullname: "test2u testu", UserImageURL: "test2u.jpg", RepliesCount: 1},
+					Rank:            0.3,
+					Snippet:         "about <b>golang</b>",
+					MatchedReplieID: &replieID,
+				},
+			},
+		},
+		{
+			name: "Ok: [search results in channels found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "golang news", "test1.tg", "test1.jpg", "test1c", "test1c testc", "test1c.jpg", 1, "test1u testu", "test1u.jpg", 2, 0.6, nil, "<b>golang</b> news")
+
+				mock.ExpectQuery(filteredQuery).
+					WithArgs(`{"test1c"}`, "golang", "english", 0).WillReturnRows(rows)
+			},
+			configs: []string{"english"},
+			filter:  &model.MessageFilter{ChannelNames: []string{"test1c"}},
+			want: []model.SearchResult{
+				{
+					FullMessage: model.FullMessage{ID: 1, Title: "golang news", MessageURL: "test1.tg", MessageImageURL: "test1.jpg", ChannelName: "test1c", ChannelTitle: "test1c testc", ChannelImageURL: "test1c.jpg", UserID: 1, UserFullname: "test1u testu", UserImageURL: "test1u.jpg", RepliesCount: 2},
+					Rank:        0.6,
+					Snippet:     "<b>golang</b> news",
+				},
+			},
+		},
+		{
+			name: "Error: [search results not found]",
+			mock: func() {
+				mock.ExpectQuery(query).
+					WithArgs("golang", "english", "russian", 0).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			configs:        []string{"english", "russian"},
+			wantErr:        true,
+			expectedErrMsg: "search results not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).
+					WithArgs("golang", "english", "russian", 0).WillReturnError(fmt.Errorf("some error"))
+			},
+			configs:        []string{"english", "russian"},
+			wantErr:        true,
+			expectedErrMsg: "failed to search messages: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.SearchMessages("golang", tt.configs, tt.filter, 0)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
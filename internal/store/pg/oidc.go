@@ -0,0 +1,164 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+const webUserIdentityConstraint = "uq_web_user_identity"
+
+var (
+	ErrOIDCLoginNotFound         = errors.New("oidc login not found")
+	ErrWebUserIdentityNotCreated = errors.New("web user identity not created")
+	ErrWebUserIdentityExists     = errors.New("identity is already linked to web user")
+	ErrWebUserIdentitiesNotFound = errors.New("web user identities not found")
+)
+
+type OIDCRepo struct {
+	db Queryer
+}
+
+func NewOIDCRepo(db Queryer) *OIDCRepo {
+	return &OIDCRepo{db: db}
+}
+
+// CreateOIDCLogin stores state of started sso login, expired logins which were never finished are cleaned up on the way.
+func (o *OIDCRepo) CreateOIDCLogin(login *model.OIDCLogin) error {
+	_, err := o.db.Exec(
+		`WITH expired AS (
+			DELETE FROM oidc_login WHERE expires_at < now()
+		)
+		INSERT INTO oidc_login(state_hash, nonce, code_verifier, expires_at) VALUES ($1, $2, $3, $4);`,
+		login.StateHash, login.Nonce, login.CodeVerifier, login.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create oidc login: %w", err)
+	}
+
+	return nil
+}
+
+// UseOIDCLogin deletes unexpired sso login by hash of its state and returns it, so every login can be finished only once.
+func (o *OIDCRepo) UseOIDCLogin(stateHash string) (*model.OIDCLogin, error) {
+	var login model.OIDCLogin
+
+	err := o.db.Get(
+		&login,
+		"DELETE FROM oidc_login WHERE state_hash = $1 AND expires_at > now() RETURNING state_hash, nonce, code_verifier, expires_at;",
+		stateHash,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrOIDCLoginNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to use oidc login: %w", err)
+	}
+
+	return &login, nil
+}
+
+// GetWebUserByIdentity returns web user linked to subject of identity provider with issuer.
+func (o *OIDCRepo) GetWebUserByIdentity(issuer, subject string) (*model.WebUser, error) {
+	var user model.WebUser
+
+	err := o.db.Get(
+		&user,
+		`SELECT u.* FROM web_user u
+		JOIN web_user_identity i ON i.user_id = u.id
+		WHERE i.issuer = $1 AND i.subject = $2;`,
+		issuer, subject,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrWebUserNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get web user by identity: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetWebUserIdentities returns identities linked to web user, the first linked first.
+func (o *OIDCRepo) GetWebUserIdentities(userID int) ([]model.WebUserIdentity, error) {
+	identities := make([]model.WebUserIdentity, 0, 1)
+
+	err := o.db.Select(
+		&identities,
+		"SELECT id, user_id, issuer, subject, created_at FROM web_user_identity WHERE user_id = $1 ORDER BY id;",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get web user identities: %w", err)
+	}
+
+	if len(identities) == 0 {
+		return nil, ErrWebUserIdentitiesNotFound
+	}
+
+	return identities, nil
+}
+
+func (o *OIDCRepo) CreateWebUserIdentity(identity *model.WebUserIdentity) (int, error) {
+	var id int
+
+	row := o.db.QueryRow(
+		"INSERT INTO web_user_identity(user_id, issuer, subject) VALUES ($1, $2, $3) RETURNING id;",
+		identity.UserID, identity.Issuer, identity.Subject,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrWebUserIdentityNotCreated
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, ErrWebUserIdentityExists
+		}
+
+		return 0, fmt.Errorf("failed to create web user identity: %w", err)
+	}
+
+	return id, nil
+}
+
+// CreateWebUserWithIdentity creates web user together with link to identity provider in one statement
+// and returns id of the user.
+func (o *OIDCRepo) CreateWebUserWithIdentity(user *model.WebUser, identity *model.WebUserIdentity) (int, error) {
+	var id int
+
+	row := o.db.QueryRow(
+		`WITH new_user AS (
+			INSERT INTO web_user(email, password, role, email_verified_at) VALUES ($1, $2, $3, $4) RETURNING id
+		), new_identity AS (
+			INSERT INTO web_user_identity(user_id, issuer, subject)
+			SELECT id, $5, $6 FROM new_user
+		)
+		SELECT id FROM new_user;`,
+		user.Email, user.Password, user.Role, user.EmailVerifiedAt, identity.Issuer, identity.Subject,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrWebUserNotCreated
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			if pqErr.Constraint == webUserIdentityConstraint {
+				return 0, ErrWebUserIdentityExists
+			}
+
+			return 0, ErrWebUserExists
+		}
+
+		return 0, fmt.Errorf("failed to create web user with identity: %w", err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,435 @@
+package pg_test
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateOIDCLogin(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewOIDCRepo(&pg.DB{DB: sqlxDB})
+
+	expiresAt := time.Date(2022, time.July, 1, 12, 10, 0, 0, time.UTC)
+	login := &model.OIDCLogin{StateHash: "state-hash", Nonce: "nonce", CodeVerifier: "code-verifier", ExpiresAt: expiresAt}
+	query := `WITH expired AS (
+			DELETE FROM oidc_login WHERE expires_at < now()
+		)
+		INSERT INTO oidc_login(state_hash, nonce, code_verifier, expires_at) VALUES ($1, $2, $3, $4);`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [oidc login created]",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("state-hash", "nonce", "code-verifier", expiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("state-hash", "nonce", "code-verifier", expiresAt).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to create oidc login: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateOIDCLogin(login)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_UseOIDCLogin(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewOIDCRepo(&pg.DB{DB: sqlxDB})
+
+	expiresAt := time.Date(2022, time.July, 1, 12, 10, 0, 0, time.UTC)
+	query := "DELETE FROM oidc_login WHERE state_hash = $1 AND expires_at > now() RETURNING state_hash, nonce, code_verifier, expires_at;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.OIDCLogin
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [oidc login used]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"state_hash", "nonce", "code_verifier", "expires_at"}).
+					AddRow("state-hash", "nonce", "code-verifier", expiresAt)
+
+				mock.ExpectQuery(query).WithArgs("state-hash").WillReturnRows(rows)
+			},
+			want: &model.OIDCLogin{StateHash: "state-hash", Nonce: "nonce", CodeVerifier: "code-verifier", ExpiresAt: expiresAt},
+		},
+		{
+			name: "Error: [oidc login not found, expired or used]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"state_hash", "nonce", "code_verifier", "expires_at"})
+
+				mock.ExpectQuery(query).WithArgs("state-hash").WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "oidc login not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("state-hash").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to use oidc login: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UseOIDCLogin("state-hash")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetWebUserByIdentity(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewOIDCRepo(&pg.DB{DB: sqlxDB})
+
+	query := `SELECT u.* FROM web_user u
+		JOIN web_user_identity i ON i.user_id = u.id
+		WHERE i.issuer = $1 AND i.subject = $2;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.WebUser
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [web user found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "email", "password", "role"}).
+					AddRow(1, "test@test.com", "", model.RoleMember)
+
+				mock.ExpectQuery(query).WithArgs("https://sso.example.com", "1001").WillReturnRows(rows)
+			},
+			want: &model.WebUser{ID: 1, Email: "test@test.com", Role: model.RoleMember},
+		},
+		{
+			name: "Error: [web user not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "email", "password", "role"})
+
+				mock.ExpectQuery(query).WithArgs("https://sso.example.com", "1001").WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "web user not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("https://sso.example.com", "1001").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get web user by identity: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetWebUserByIdentity("https://sso.example.com", "1001")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetWebUserIdentities(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewOIDCRepo(&pg.DB{DB: sqlxDB})
+
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	query := "SELECT id, user_id, issuer, subject, created_at FROM web_user_identity WHERE user_id = $1 ORDER BY id;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           []model.WebUserIdentity
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [identities found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "issuer", "subject", "created_at"}).
+					AddRow(3, 1, "https://sso.example.com", "1001", createdAt)
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: []model.WebUserIdentity{{ID: 3, UserID: 1, Issuer: "https://sso.example.com", Subject: "1001", CreatedAt: createdAt}},
+		},
+		{
+			name: "Error: [identities not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "issuer", "subject", "created_at"})
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "web user identities not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get web user identities: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetWebUserIdentities(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_CreateWebUserIdentity(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewOIDCRepo(&pg.DB{DB: sqlxDB})
+
+	identity := &model.WebUserIdentity{UserID: 1, Issuer: "https://sso.example.com", Subject: "1001"}
+	query := "INSERT INTO web_user_identity(user_id, issuer, subject) VALUES ($1, $2, $3) RETURNING id;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [identity linked]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(3)
+
+				mock.ExpectQuery(query).WithArgs(1, "https://sso.example.com", "1001").WillReturnRows(rows)
+			},
+			want: 3,
+		},
+		{
+			name: "Error: [identity is already linked]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1, "https://sso.example.com", "1001").WillReturnError(&pq.Error{Code: "23505"})
+			},
+			wantErr:        true,
+			expectedErrMsg: "identity is already linked to web user",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1, "https://sso.example.com", "1001").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to create web user identity: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateWebUserIdentity(identity)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_CreateWebUserWithIdentity(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewOIDCRepo(&pg.DB{DB: sqlxDB})
+
+	verifiedAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	user := &model.WebUser{Email: "test@test.com", Role: model.RoleMember, EmailVerifiedAt: &verifiedAt}
+	identity := &model.WebUserIdentity{Issuer: "https://sso.example.com", Subject: "1001"}
+	query := `WITH new_user AS (
+			INSERT INTO web_user(email, password, role, email_verified_at) VALUES ($1, $2, $3, $4) RETURNING id
+		), new_identity AS (
+			INSERT INTO web_user_identity(user_id, issuer, subject)
+			SELECT id, $5, $6 FROM new_user
+		)
+		SELECT id FROM new_user;`
+	args := []driver.Value{"test@test.com", "", model.RoleMember, &verifiedAt, "https://sso.example.com", "1001"}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [web user created with identity]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(5)
+
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnRows(rows)
+			},
+			want: 5,
+		},
+		{
+			name: "Error: [web user with email exists]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnError(&pq.Error{Code: "23505", Constraint: "web_user_email_key"})
+			},
+			wantErr:        true,
+			expectedErrMsg: "web user with this email already exists",
+		},
+		{
+			name: "Error: [identity is already linked]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnError(&pq.Error{Code: "23505", Constraint: "uq_web_user_identity"})
+			},
+			wantErr:        true,
+			expectedErrMsg: "identity is already linked to web user",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to create web user with identity: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateWebUserWithIdentity(user, identity)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
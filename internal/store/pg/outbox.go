@@ -0,0 +1,80 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+var ErrMailNotFound = errors.New("mail not found")
+
+type OutboxRepo struct {
+	db Queryer
+}
+
+func NewOutboxRepo(db Queryer) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+// ClaimMails takes up to limit unsent mails which are due and have less than maxAttempts attempts.
+// Claimed mails are postponed until leaseUntil, so other instances don't send them at the same time
+// and mails of crashed instance are sent again after it.
+func (o *OutboxRepo) ClaimMails(limit, maxAttempts int, leaseUntil time.Time) ([]model.Mail, error) {
+	mails := make([]model.Mail, 0, limit)
+
+	err := o.db.Select(
+		&mails,
+		`UPDATE mail_outbox SET attempts = attempts + 1, send_after = $3
+		WHERE id IN (
+			SELECT id FROM mail_outbox
+			WHERE sent_at IS NULL AND send_after <= now() AND attempts < $2
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, recipient, subject, body, attempts, last_error, send_after, sent_at, created_at;`,
+		limit, maxAttempts, leaseUntil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim mails: %w", err)
+	}
+
+	return mails, nil
+}
+
+func (o *OutboxRepo) MarkMailSent(ID int) (int, error) {
+	var id int
+
+	row := o.db.QueryRow("UPDATE mail_outbox SET sent_at = now(), last_error = NULL WHERE id = $1 RETURNING id;", ID)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrMailNotFound
+		}
+
+		return 0, fmt.Errorf("failed to mark mail as sent: %w", err)
+	}
+
+	return id, nil
+}
+
+// MarkMailFailed stores error of the last attempt and postpones mail until retryAt.
+func (o *OutboxRepo) MarkMailFailed(ID int, lastError string, retryAt time.Time) (int, error) {
+	var id int
+
+	row := o.db.QueryRow(
+		"UPDATE mail_outbox SET last_error = $1, send_after = $2 WHERE id = $3 RETURNING id;",
+		lastError, retryAt, ID,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrMailNotFound
+		}
+
+		return 0, fmt.Errorf("failed to mark mail as failed: %w", err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,156 @@
+package pg_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ClaimMails(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewOutboxRepo(&pg.DB{DB: sqlxDB})
+
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	leaseUntil := createdAt.Add(5 * time.Minute)
+	query := `UPDATE mail_outbox SET attempts = attempts + 1, send_after = $3
+		WHERE id IN (
+			SELECT id FROM mail_outbox
+			WHERE sent_at IS NULL AND send_after <= now() AND attempts < $2
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, recipient, subject, body, attempts, last_error, send_after, sent_at, created_at;`
+	columns := []string{"id", "recipient", "subject", "body", "attempts", "last_error", "send_after", "sent_at", "created_at"}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           []model.Mail
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [mails claimed]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "test@test.com", "subject", "body", 1, nil, leaseUntil, nil, createdAt)
+
+				mock.ExpectQuery(query).WithArgs(20, 10, leaseUntil).WillReturnRows(rows)
+			},
+			want: []model.Mail{
+				{ID: 1, Recipient: "test@test.com", Subject: "subject", Body: "body", Attempts: 1, SendAfter: leaseUntil, CreatedAt: createdAt},
+			},
+		},
+		{
+			name: "Ok: [no pending mails]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns)
+
+				mock.ExpectQuery(query).WithArgs(20, 10, leaseUntil).WillReturnRows(rows)
+			},
+			want: []model.Mail{},
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(20, 10, leaseUntil).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to claim mails: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.ClaimMails(20, 10, leaseUntil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_MarkMailFailed(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewOutboxRepo(&pg.DB{DB: sqlxDB})
+
+	retryAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	query := "UPDATE mail_outbox SET last_error = $1, send_after = $2 WHERE id = $3 RETURNING id;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [mail marked as failed]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery(query).WithArgs("connection refused", retryAt, 1).WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [mail not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).WithArgs("connection refused", retryAt, 1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "mail not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.MarkMailFailed(1, "connection refused", retryAt)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+// keysetOrder narrows where to rows which follow page cursor in listing ordered by timeColumn and idColumn,
+// timeColumn is optional. It returns ORDER BY and LIMIT clause, backward pages are walked in reverse order
+// and one extra row is fetched so caller can tell whether more rows follow.
+func keysetOrder(where *whereBuilder, page *model.PageRequest, desc bool, timeColumn, idColumn string) string {
+	walkDesc := desc != page.IsBackward()
+
+	operator, direction := ">", "ASC"
+	if walkDesc {
+		operator, direction = "<", "DESC"
+	}
+
+	if page.Cursor != nil {
+		if timeColumn != "" {
+			where.addRaw(fmt.Sprintf(
+				"(%s, %s) %s (%s, %s)",
+				timeColumn, idColumn, operator, where.next(page.Cursor.PostedAt), where.next(page.Cursor.ID),
+			))
+		} else {
+			where.addRaw(fmt.Sprintf("%s %s %s", idColumn, operator, where.next(page.Cursor.ID)))
+		}
+	}
+
+	order := fmt.Sprintf("%s %s", idColumn, direction)
+	if timeColumn != "" {
+		order = fmt.Sprintf("%s %s, %s", timeColumn, direction, order)
+	}
+
+	return fmt.Sprintf("ORDER BY %s LIMIT %s", order, where.next(page.Limit+1))
+}
@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 
@@ -17,6 +18,14 @@ type DB struct {
 	*sqlx.DB
 }
 
+// Queryer is implemented by both DB and sqlx.Tx, so every repository can work inside a transaction.
+type Queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+}
+
 func Dial(cfg *config.Config) (*DB, error) {
 	if cfg.DBURL == "" {
 		return nil, ErrNoDBURL
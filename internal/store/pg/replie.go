@@ -10,17 +10,22 @@ import (
 var ErrFullRepliesNotFound = errors.New("full replies not found")
 
 type ReplieRepo struct {
-	db *DB
+	db Queryer
 }
 
-func NewReplieRepo(db *DB) *ReplieRepo {
+func NewReplieRepo(db Queryer) *ReplieRepo {
 	return &ReplieRepo{db: db}
 }
 
+// CreateReplie stores replie or updates content of the one with the same reply key.
+// Re-delivered replie overwrites stored one only when it was edited later, so stale redelivery can't undo an edit.
 func (r *ReplieRepo) CreateReplie(replie *model.ReplieDTO) error {
 	_, err := r.db.Exec(
-		"INSERT INTO replie(message_id, user_id, title, imageurl) VALUES ($1, $2, $3, $4);",
-		replie.MessageID, replie.UserID, replie.Title, replie.ImageURL,
+		`INSERT INTO replie(message_id, reply_key, user_id, title, imageurl, posted_at, edited_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (message_id, reply_key) DO UPDATE
+		SET title = EXCLUDED.title, imageurl = EXCLUDED.imageurl, edited_at = EXCLUDED.edited_at
+		WHERE EXCLUDED.edited_at IS NOT NULL AND (replie.edited_at IS NULL OR EXCLUDED.edited_at > replie.edited_at);`,
+		replie.MessageID, replie.ReplyKey, replie.UserID, replie.Title, replie.ImageURL, replie.PostedAt, replie.EditedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create replie: %w", err)
@@ -29,19 +34,28 @@ func (r *ReplieRepo) CreateReplie(replie *model.ReplieDTO) error {
 	return nil
 }
 
-func (r *ReplieRepo) GetFullRepliesByMessageID(ID int) ([]model.FullReplie, error) {
-	replies := make([]model.FullReplie, 0, 10)
+// GetFullRepliesByMessageID returns page of message replies, newest replies first.
+// Rows of backward page are returned oldest first.
+func (r *ReplieRepo) GetFullRepliesByMessageID(ID int, page *model.PageRequest) ([]model.FullReplie, error) {
+	replies := make([]model.FullReplie, 0, page.Limit+1)
+
+	where := &whereBuilder{}
+	where.add("r.message_id = %s", ID)
+	order := keysetOrder(where, page, true, "r.posted_at", "r.id")
 
 	err := r.db.Select(
 		&replies,
-		`SELECT
-		r.id, r.title, r.message_id, r.imageurl,
-		u.id as userId, u.fullname, u.imageurl AS userimageurl
-		FROM replie r 
-		LEFT JOIN tg_user u ON u.id = r.user_id
-		WHERE r.message_id = $1
-		ORDER BY r.id DESC NULLS LAST;`,
-		ID,
+		fmt.Sprintf(
+			`SELECT
+			r.id, r.title, r.message_id, r.imageurl, r.posted_at, r.edited_at, r.ingested_at,
+			u.id as userId, u.fullname, u.imageurl AS userimageurl
+			FROM replie r 
+			LEFT JOIN tg_user u ON u.id = r.user_id
+			%s
+			%s;`,
+			where, order,
+		),
+		where.args...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get full replies by message ID: %w", err)
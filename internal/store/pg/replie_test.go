@@ -3,6 +3,7 @@ package pg_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
@@ -23,6 +24,13 @@ func Test_CreateReplie(t *testing.T) {
 
 	r := pg.NewReplieRepo(&pg.DB{DB: sqlxDB})
 
+	query := `INSERT INTO replie(message_id, reply_key, user_id, title, imageurl, posted_at, edited_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (message_id, reply_key) DO UPDATE
+		SET title = EXCLUDED.title, imageurl = EXCLUDED.imageurl, edited_at = EXCLUDED.edited_at
+		WHERE EXCLUDED.edited_at IS NOT NULL AND (replie.edited_at IS NULL OR EXCLUDED.edited_at > replie.edited_at);`
+
+	postedAt := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name           string
 		mock           func()
@@ -33,18 +41,18 @@ func Test_CreateReplie(t *testing.T) {
 		{
 			name: "Ok: [replie created]",
 			mock: func() {
-				mock.ExpectExec("INSERT INTO replie(message_id, user_id, title, imageurl) VALUES ($1, $2, $3, $4);").
-					WithArgs(1, 1, "test", "test.jpg").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(query).
+					WithArgs(1, "42", 1, "test", "test.jpg", postedAt, nil).WillReturnResult(sqlmock.NewResult(0, 1))
 			},
-			input: &model.ReplieDTO{MessageID: 1, UserID: 1, Title: "test", ImageURL: "test.jpg"},
+			input: &model.ReplieDTO{MessageID: 1, ReplyKey: "42", UserID: 1, Title: "test", ImageURL: "test.jpg", PostedAt: postedAt},
 		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectExec("INSERT INTO replie(message_id, user_id, title, imageurl) VALUES ($1, $2, $3, $4);").
-					WithArgs(1, 1, "test", "test.jpg").WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectExec(query).
+					WithArgs(1, "42", 1, "test", "test.jpg", postedAt, nil).WillReturnError(fmt.Errorf("some error"))
 			},
-			input:          &model.ReplieDTO{MessageID: 1, UserID: 1, Title: "test", ImageURL: "test.jpg"},
+			input:          &model.ReplieDTO{MessageID: 1, ReplyKey: "42", UserID: 1, Title: "test", ImageURL: "test.jpg", PostedAt: postedAt},
 			wantErr:        true,
 			expectedErrMsg: "failed to create replie: some error",
 		},
@@ -82,7 +90,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 	tests := []struct {
 		name           string
 		mock           func()
-		input          int
+		input          *model.PageRequest
 		want           []model.FullReplie
 		wantErr        bool
 		expectedErrMsg string
@@ -96,15 +104,15 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 
 				mock.ExpectQuery(
 					`SELECT
-					r.id, r.title, r.message_id, r.imageurl, 
+					r.id, r.title, r.message_id, r.imageurl, r.posted_at, r.edited_at, r.ingested_at,
 					u.id as userId, u.fullname, u.imageurl AS userimageurl 	
 					FROM replie r 
 					LEFT JOIN tg_user u ON u.id = r.user_id
 					WHERE r.message_id = $1
-					ORDER BY r.id DESC NULLS LAST;`,
-				).WithArgs(1).WillReturnRows(rows)
+					ORDER BY r.posted_at DESC, r.id DESC LIMIT $2;`,
+				).WithArgs(1, 11).WillReturnRows(rows)
 			},
-			input: 1,
+			input: &model.PageRequest{Limit: 10},
 			want: []model.FullReplie{
 				{ID: 1, Title: "test1", MessageID: 1, ImageURL: "test1r.jpg", UserID: 1, UserFullname: "test1 test1", UserImageURL: "test1.jpg"},
 				{ID: 2, Title: "test2", MessageID: 1, ImageURL: "test2r.jpg", UserID: 2, UserFullname: "test2 test2", UserImageURL: "test2.jpg"},
@@ -117,32 +125,53 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 
 				mock.ExpectQuery(
 					`SELECT
-					r.id, r.title, r.message_id, r.imageurl,
+					r.id, r.title, r.message_id, r.imageurl, r.posted_at, r.edited_at, r.ingested_at,
 					u.id as userId, u.fullname, u.imageurl AS userimageurl	
 					FROM replie r 
 					LEFT JOIN tg_user u ON u.id = r.user_id
 					WHERE r.message_id = $1
-					ORDER BY r.id DESC NULLS LAST;`,
-				).WithArgs(1).WillReturnRows(rows)
+					ORDER BY r.posted_at DESC, r.id DESC LIMIT $2;`,
+				).WithArgs(1, 11).WillReturnRows(rows)
 			},
-			input:          1,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "full replies not found",
 		},
+		{
+			name: "Ok: [full replies before cursor found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "message_id", "imageurl", "userid", "fullname", "userimageurl"}).
+					AddRow(2, "test2", 1, "test2r.jpg", 2, "test2 test2", "test2.jpg")
+
+				mock.ExpectQuery(
+					`SELECT
+					r.id, r.title, r.message_id, r.imageurl, r.posted_at, r.edited_at, r.ingested_at,
+					u.id as userId, u.fullname, u.imageurl AS userimageurl
+					FROM replie r
+					LEFT JOIN tg_user u ON u.id = r.user_id
+					WHERE r.message_id = $1 AND (r.posted_at, r.id) > ($2, $3)
+					ORDER BY r.posted_at ASC, r.id ASC LIMIT $4;`,
+				).WithArgs(1, time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), 1, 11).WillReturnRows(rows)
+			},
+			input: &model.PageRequest{Cursor: &model.Cursor{PostedAt: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), ID: 1, Backward: true}, Limit: 10},
+			want: []model.FullReplie{
+				{ID: 2, Title: "test2", MessageID: 1, ImageURL: "test2r.jpg", UserID: 2, UserFullname: "test2 test2", UserImageURL: "test2.jpg"},
+			},
+		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
 				mock.ExpectQuery(
 					`SELECT
-					r.id, r.title, r.message_id, r.imageurl,
+					r.id, r.title, r.message_id, r.imageurl, r.posted_at, r.edited_at, r.ingested_at,
 					u.id as userId, u.fullname, u.imageurl AS userimageurl 	
 					FROM replie r 
 					LEFT JOIN tg_user u ON u.id = r.user_id
 					WHERE r.message_id = $1
-					ORDER BY r.id DESC NULLS LAST;`,
-				).WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+					ORDER BY r.posted_at DESC, r.id DESC LIMIT $2;`,
+				).WithArgs(1, 11).WillReturnError(fmt.Errorf("some error"))
 			},
-			input:          1,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "failed to get full replies by message ID: some error",
 		},
@@ -152,7 +181,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetFullRepliesByMessageID(tt.input)
+			got, err := r.GetFullRepliesByMessageID(1, tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -5,27 +5,64 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 )
 
 var (
-	ErrSavedMessagesNotFound  = errors.New("saved messages not found")
-	ErrSavedMessageNotCreated = errors.New("saved message not created")
-	ErrSavedMessageNotDeleted = errors.New("saved message not deleted")
+	ErrSavedMessagesNotFound = errors.New("saved messages not found")
+	ErrSavedMessageExists    = errors.New("message is already saved")
+	ErrSavedMessageNotFound  = errors.New("saved message not found")
+	ErrCollectionsNotFound   = errors.New("collections not found")
+	ErrCollectionNotFound    = errors.New("collection not found")
+	ErrCollectionExists      = errors.New("collection with this name already exists")
 )
 
+// uniqueViolation is postgres error code of unique constraint violation.
+const uniqueViolation = "23505"
+
 type SavedRepo struct {
-	db *DB
+	db Queryer
 }
 
-func NewSavedRepo(db *DB) *SavedRepo {
+func NewSavedRepo(db Queryer) *SavedRepo {
 	return &SavedRepo{db: db}
 }
 
-func (s *SavedRepo) GetSavedMessages(ID int) ([]model.Saved, error) {
-	savedMessages := make([]model.Saved, 0, 10)
+// GetSavedMessages returns page of messages saved by user with all info about them, latest saved first.
+// Rows of backward page are returned in reverse order.
+func (s *SavedRepo) GetSavedMessages(ID int, filter *model.SavedFilter, page *model.PageRequest) ([]model.SavedMessage, error) {
+	savedMessages := make([]model.SavedMessage, 0, page.Limit+1)
+
+	where := &whereBuilder{}
+	where.add("s.user_id = %s", ID)
+
+	if filter != nil {
+		if filter.CollectionID != 0 {
+			where.add("s.collection_id = %s", filter.CollectionID)
+		}
+
+		if len(filter.Tags) != 0 {
+			where.add("s.tags @> %s", pq.Array(filter.Tags))
+		}
+	}
+
+	order := keysetOrder(where, page, true, "s.saved_at", "s.id")
+	query := fmt.Sprintf(
+		`SELECT
+		s.id as savedId, s.saved_at as savedAt, s.collection_id as savedCollectionId, s.note as savedNote, s.tags as savedTags,
+		%s
+		FROM saved s
+		JOIN message m ON m.id = s.message_id
+		LEFT JOIN channel c ON c.id = m.channel_id
+		LEFT JOIN tg_user u ON m.user_id = u.id
+		%s
+		%s;`,
+		fullMessageColumns, where, order,
+	)
 
-	err := s.db.Select(&savedMessages, "SELECT * FROM saved WHERE user_id = $1;", ID)
+	err := s.db.Select(&savedMessages, query, where.args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get saved messages by user id: %w", err)
 	}
@@ -37,16 +74,19 @@ func (s *SavedRepo) GetSavedMessages(ID int) ([]model.Saved, error) {
 	return savedMessages, nil
 }
 
+// CreateSavedMessage saves message for user, saving the same message twice by one user returns ErrSavedMessageExists.
 func (s *SavedRepo) CreateSavedMessage(savedMessage *model.Saved) (int, error) {
 	var id int
 
 	row := s.db.QueryRow(
-		"INSERT INTO saved(user_id, message_id) VALUES ($1, $2) RETURNING id;",
-		savedMessage.UserID, savedMessage.MessageID,
+		`INSERT INTO saved(user_id, message_id, collection_id, note, tags) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, message_id) DO NOTHING
+		RETURNING id;`,
+		savedMessage.UserID, savedMessage.MessageID, savedMessage.CollectionID, savedMessage.Note, savedMessage.Tags,
 	)
 	if err := row.Scan(&id); err != nil {
 		if err == sql.ErrNoRows {
-			return 0, ErrSavedMessageNotCreated
+			return 0, ErrSavedMessageExists
 		}
 
 		return 0, fmt.Errorf("failed to create saved message: %w", err)
@@ -55,16 +95,134 @@ func (s *SavedRepo) CreateSavedMessage(savedMessage *model.Saved) (int, error) {
 	return id, nil
 }
 
-func (s *SavedRepo) DeleteSavedMessage(ID int) (int, error) {
+// UpdateSavedMessage replaces collection, note and tags of saved message which belongs to user.
+func (s *SavedRepo) UpdateSavedMessage(savedMessage *model.Saved) (int, error) {
+	var id int
+
+	row := s.db.QueryRow(
+		"UPDATE saved SET collection_id = $1, note = $2, tags = $3 WHERE id = $4 AND user_id = $5 RETURNING id;",
+		savedMessage.CollectionID, savedMessage.Note, savedMessage.Tags, savedMessage.ID, savedMessage.UserID,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrSavedMessageNotFound
+		}
+
+		return 0, fmt.Errorf("failed to update saved message: %w", err)
+	}
+
+	return id, nil
+}
+
+// DeleteSavedMessage deletes saved message only when it belongs to user and returns deleted row,
+// saved messages of other users are reported as not found.
+func (s *SavedRepo) DeleteSavedMessage(userID, ID int) (*model.Saved, error) {
+	var savedMessage model.Saved
+
+	err := s.db.Get(
+		&savedMessage,
+		"DELETE FROM saved WHERE id = $1 AND user_id = $2 RETURNING id, user_id, message_id, collection_id, note, tags;",
+		ID, userID,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrSavedMessageNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete saved message by id: %w", err)
+	}
+
+	return &savedMessage, nil
+}
+
+// GetCollections returns all collections of user ordered by name.
+func (s *SavedRepo) GetCollections(userID int) ([]model.Collection, error) {
+	collections := make([]model.Collection, 0, 10)
+
+	err := s.db.Select(&collections, "SELECT * FROM collection WHERE user_id = $1 ORDER BY name;", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collections by user id: %w", err)
+	}
+
+	if len(collections) == 0 {
+		return nil, ErrCollectionsNotFound
+	}
+
+	return collections, nil
+}
+
+// GetCollection returns collection only when it belongs to user.
+func (s *SavedRepo) GetCollection(userID, ID int) (*model.Collection, error) {
+	var collection model.Collection
+
+	err := s.db.Get(&collection, "SELECT * FROM collection WHERE id = $1 AND user_id = $2;", ID, userID)
+	if err == sql.ErrNoRows {
+		return nil, ErrCollectionNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection by id: %w", err)
+	}
+
+	return &collection, nil
+}
+
+// CreateCollection creates collection, names of collections are unique per user.
+func (s *SavedRepo) CreateCollection(collection *model.Collection) (int, error) {
+	var id int
+
+	row := s.db.QueryRow(
+		`INSERT INTO collection(user_id, name) VALUES ($1, $2)
+		ON CONFLICT (user_id, name) DO NOTHING
+		RETURNING id;`,
+		collection.UserID, collection.Name,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrCollectionExists
+		}
+
+		return 0, fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdateCollection renames collection which belongs to user.
+func (s *SavedRepo) UpdateCollection(collection *model.Collection) (int, error) {
+	var id int
+
+	row := s.db.QueryRow(
+		"UPDATE collection SET name = $1 WHERE id = $2 AND user_id = $3 RETURNING id;",
+		collection.Name, collection.ID, collection.UserID,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrCollectionNotFound
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, ErrCollectionExists
+		}
+
+		return 0, fmt.Errorf("failed to update collection: %w", err)
+	}
+
+	return id, nil
+}
+
+// DeleteCollection deletes collection which belongs to user, its saved messages stay saved without collection.
+func (s *SavedRepo) DeleteCollection(userID, ID int) (int, error) {
 	var id int
 
-	row := s.db.QueryRow("DELETE FROM saved WHERE id = $1 RETURNING id;", ID)
+	row := s.db.QueryRow("DELETE FROM collection WHERE id = $1 AND user_id = $2 RETURNING id;", ID, userID)
 	if err := row.Scan(&id); err != nil {
 		if err == sql.ErrNoRows {
-			return 0, ErrSavedMessageNotDeleted
+			return 0, ErrCollectionNotFound
 		}
 
-		return 0, fmt.Errorf("failed to delete saved message by id: %w", err)
+		return 0, fmt.Errorf("failed to delete collection by id: %w", err)
 	}
 
 	return id, nil
@@ -3,11 +3,13 @@ package pg_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,51 +25,100 @@ func Test_GetSavedMessages(t *testing.T) {
 
 	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
 
-	data := []model.Saved{
-		{ID: 1, UserID: 1, MessageID: 1},
-		{ID: 2, UserID: 1, MessageID: 2},
+	savedAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	data := []model.SavedMessage{
+		{
+			SavedID: 2, SavedAt: savedAt,
+			FullMessage: model.FullMessage{ID: 2, Title: "test2", MessageURL: "test2.tg", ChannelName: "test2c", UserID: 2, UserFullname: "test2u testu"},
+		},
+		{
+			SavedID: 1, SavedAt: savedAt.Add(-time.Hour),
+			FullMessage: model.FullMessage{ID: 1, Title: "test1", MessageURL: "test1.tg", ChannelName: "test1c", UserID: 1, UserFullname: "test1u testu"},
+		},
 	}
 
+	columns := []string{"savedid", "savedat", "messageid", "messagetitle", "messageurl", "channelname", "userid", "userfullname", "count"}
+
+	query := `SELECT
+		s.id as savedId, s.saved_at as savedAt, s.collection_id as savedCollectionId, s.note as savedNote, s.tags as savedTags,
+		m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+		m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
+		c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
+		u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
+		(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
+		FROM saved s
+		JOIN message m ON m.id = s.message_id
+		LEFT JOIN channel c ON c.id = m.channel_id
+		LEFT JOIN tg_user u ON m.user_id = u.id
+		%s;`
+
 	tests := []struct {
 		name           string
 		mock           func()
-		input          int
-		want           []model.Saved
+		input          *model.PageRequest
+		filter         *model.SavedFilter
+		want           []model.SavedMessage
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
 			name: "Ok: [saved messages found]",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "user_id", "message_id"}).
-					AddRow(1, 1, 1).
-					AddRow(2, 1, 2)
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, savedAt, 2, "test2", "test2.tg", "test2c", 2, "test2u testu", 0).
+					AddRow(1, savedAt.Add(-time.Hour), 1, "test1", "test1.tg", "test1c", 1, "test1u testu", 0)
 
-				mock.ExpectQuery("SELECT * FROM saved WHERE user_id = $1;").
-					WithArgs(1).WillReturnRows(rows)
+				mock.ExpectQuery(fmt.Sprintf(query, "WHERE s.user_id = $1 ORDER BY s.saved_at DESC, s.id DESC LIMIT $2")).
+					WithArgs(1, 11).WillReturnRows(rows)
 			},
-			input: 1,
+			input: &model.PageRequest{Limit: 10},
 			want:  data,
 		},
+		{
+			name: "Ok: [saved messages after cursor found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, savedAt.Add(-time.Hour), 1, "test1", "test1.tg", "test1c", 1, "test1u testu", 0)
+
+				mock.ExpectQuery(fmt.Sprintf(query, "WHERE s.user_id = $1 AND (s.saved_at, s.id) < ($2, $3) ORDER BY s.saved_at DESC, s.id DESC LIMIT $4")).
+					WithArgs(1, savedAt, 2, 11).WillReturnRows(rows)
+			},
+			input: &model.PageRequest{Cursor: &model.Cursor{PostedAt: savedAt, ID: 2}, Limit: 10},
+			want:  data[1:],
+		},
+		{
+			name: "Ok: [saved messages of collection with tags found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, savedAt.Add(-time.Hour), 1, "test1", "test1.tg", "test1c", 1, "test1u testu", 0)
+
+				mock.ExpectQuery(fmt.Sprintf(query, "WHERE s.user_id = $1 AND s.collection_id = $2 AND s.tags @> $3 ORDER BY s.saved_at DESC, s.id DESC LIMIT $4")).
+					WithArgs(1, 3, pq.Array([]string{"golang", "jobs"}), 11).WillReturnRows(rows)
+			},
+			input:  &model.PageRequest{Limit: 10},
+			filter: &model.SavedFilter{CollectionID: 3, Tags: []string{"golang", "jobs"}},
+			want:   data[1:],
+		},
 		{
 			name: "Error: [saved messages not found]",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "user_id", "message_id"})
+				rows := sqlmock.NewRows(columns)
 
-				mock.ExpectQuery("SELECT * FROM saved WHERE user_id = $1;").
-					WithArgs(1).WillReturnRows(rows)
+				mock.ExpectQuery(fmt.Sprintf(query, "WHERE s.user_id = $1 ORDER BY s.saved_at DESC, s.id DESC LIMIT $2")).
+					WithArgs(1, 11).WillReturnRows(rows)
 			},
-			input:          1,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "saved messages not found",
 		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("SELECT * FROM saved WHERE user_id = $1;").
-					WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery(fmt.Sprintf(query, "WHERE s.user_id = $1 ORDER BY s.saved_at DESC, s.id DESC LIMIT $2")).
+					WithArgs(1, 11).WillReturnError(fmt.Errorf("some error"))
 			},
-			input:          1,
+			input:          &model.PageRequest{Limit: 10},
 			wantErr:        true,
 			expectedErrMsg: "failed to get saved messages by user id: some error",
 		},
@@ -77,7 +128,7 @@ func Test_GetSavedMessages(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetSavedMessages(tt.input)
+			got, err := r.GetSavedMessages(1, tt.filter, tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -103,6 +154,9 @@ func Test_CreateSavedMessage(t *testing.T) {
 
 	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
 
+	collectionID := 3
+	input := &model.Saved{UserID: 1, MessageID: 1, CollectionID: &collectionID, Note: "check later", Tags: pq.StringArray{"golang"}}
+
 	tests := []struct {
 		name           string
 		mock           func()
@@ -117,31 +171,31 @@ func Test_CreateSavedMessage(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id"}).
 					AddRow(1)
 
-				mock.ExpectQuery("INSERT INTO saved(user_id, message_id) VALUES ($1, $2) RETURNING id;").
-					WithArgs(1, 1).WillReturnRows(rows)
+				mock.ExpectQuery("INSERT INTO saved(user_id, message_id, collection_id, note, tags) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, message_id) DO NOTHING RETURNING id;").
+					WithArgs(1, 1, 3, "check later", pq.StringArray{"golang"}).WillReturnRows(rows)
 			},
-			input: &model.Saved{UserID: 1, MessageID: 1},
+			input: input,
 			want:  1,
 		},
 		{
-			name: "Error: [saved message not created]",
+			name: "Error: [message is already saved]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"})
 
-				mock.ExpectQuery("INSERT INTO saved(user_id, message_id) VALUES ($1, $2) RETURNING id;").
-					WithArgs(1, 1).WillReturnRows(rows)
+				mock.ExpectQuery("INSERT INTO saved(user_id, message_id, collection_id, note, tags) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, message_id) DO NOTHING RETURNING id;").
+					WithArgs(1, 1, 3, "check later", pq.StringArray{"golang"}).WillReturnRows(rows)
 			},
-			input:          &model.Saved{UserID: 1, MessageID: 1},
+			input:          input,
 			wantErr:        true,
-			expectedErrMsg: "saved message not created",
+			expectedErrMsg: "message is already saved",
 		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("INSERT INTO saved(user_id, message_id) VALUES ($1, $2) RETURNING id;").
-					WithArgs(1, 1).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery("INSERT INTO saved(user_id, message_id, collection_id, note, tags) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, message_id) DO NOTHING RETURNING id;").
+					WithArgs(1, 1, 3, "check later", pq.StringArray{"golang"}).WillReturnError(fmt.Errorf("some error"))
 			},
-			input:          &model.Saved{UserID: 1, MessageID: 1},
+			input:          input,
 			wantErr:        true,
 			expectedErrMsg: "failed to create saved message: some error",
 		},
@@ -181,43 +235,501 @@ func Test_DeleteSavedMessage(t *testing.T) {
 		name           string
 		mock           func()
 		input          int
-		want           int
+		want           *model.Saved
 		wantErr        bool
 		expectedErrMsg string
 	}{
 		{
 			name: "Ok: [saved message deleted]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "message_id", "collection_id", "note", "tags"}).
+					AddRow(1, 2, 3, nil, "check later", "{golang,jobs}")
+
+				mock.ExpectQuery("DELETE FROM saved WHERE id = $1 AND user_id = $2 RETURNING id, user_id, message_id, collection_id, note, tags;").
+					WithArgs(1, 2).WillReturnRows(rows)
+			},
+			input: 1,
+			want:  &model.Saved{ID: 1, UserID: 2, MessageID: 3, Note: "check later", Tags: pq.StringArray{"golang", "jobs"}},
+		},
+		{
+			name: "Error: [saved message of user not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "message_id", "collection_id", "note", "tags"})
+
+				mock.ExpectQuery("DELETE FROM saved WHERE id = $1 AND user_id = $2 RETURNING id, user_id, message_id, collection_id, note, tags;").
+					WithArgs(1, 2).WillReturnRows(rows)
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "saved message not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM saved WHERE id = $1 AND user_id = $2 RETURNING id, user_id, message_id, collection_id, note, tags;").
+					WithArgs(1, 2).WillReturnError(fmt.Errorf("some error"))
+			},
+			input:          1,
+			wantErr:        true,
+			expectedErrMsg: "failed to delete saved message by id: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.DeleteSavedMessage(2, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_UpdateSavedMessage(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+
+	collectionID := 3
+	input := &model.Saved{ID: 1, UserID: 2, CollectionID: &collectionID, Note: "check later", Tags: pq.StringArray{"golang"}}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          *model.Saved
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [saved message updated]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"}).
 					AddRow(1)
 
-				mock.ExpectQuery("DELETE FROM saved WHERE id = $1 RETURNING id;").
-					WithArgs(1).WillReturnRows(rows)
+				mock.ExpectQuery("UPDATE saved SET collection_id = $1, note = $2, tags = $3 WHERE id = $4 AND user_id = $5 RETURNING id;").
+					WithArgs(3, "check later", pq.StringArray{"golang"}, 1, 2).WillReturnRows(rows)
 			},
-			input: 1,
+			input: input,
+			want:  1,
+		},
+		{
+			name: "Ok: [saved message moved out of collection]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery("UPDATE saved SET collection_id = $1, note = $2, tags = $3 WHERE id = $4 AND user_id = $5 RETURNING id;").
+					WithArgs(nil, "", pq.StringArray{}, 1, 2).WillReturnRows(rows)
+			},
+			input: &model.Saved{ID: 1, UserID: 2, Tags: pq.StringArray{}},
 			want:  1,
 		},
 		{
-			name: "Error: [saved message not deleted]",
+			name: "Error: [saved message of user not found]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"})
 
-				mock.ExpectQuery("DELETE FROM saved WHERE id = $1 RETURNING id;").
+				mock.ExpectQuery("UPDATE saved SET collection_id = $1, note = $2, tags = $3 WHERE id = $4 AND user_id = $5 RETURNING id;").
+					WithArgs(3, "check later", pq.StringArray{"golang"}, 1, 2).WillReturnRows(rows)
+			},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "saved message not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("UPDATE saved SET collection_id = $1, note = $2, tags = $3 WHERE id = $4 AND user_id = $5 RETURNING id;").
+					WithArgs(3, "check later", pq.StringArray{"golang"}, 1, 2).WillReturnError(fmt.Errorf("some error"))
+			},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "failed to update saved message: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateSavedMessage(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetCollections(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	data := []model.Collection{
+		{ID: 2, UserID: 1, Name: "golang", CreatedAt: createdAt},
+		{ID: 1, UserID: 1, Name: "jobs", CreatedAt: createdAt},
+	}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           []model.Collection
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [collections found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "created_at"}).
+					AddRow(2, 1, "golang", createdAt).
+					AddRow(1, 1, "jobs", createdAt)
+
+				mock.ExpectQuery("SELECT * FROM collection WHERE user_id = $1 ORDER BY name;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			want: data,
+		},
+		{
+			name: "Error: [collections not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "created_at"})
+
+				mock.ExpectQuery("SELECT * FROM collection WHERE user_id = $1 ORDER BY name;").
 					WithArgs(1).WillReturnRows(rows)
 			},
-			input:          1,
 			wantErr:        true,
-			expectedErrMsg: "saved message not deleted",
+			expectedErrMsg: "collections not found",
 		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("DELETE FROM saved WHERE id = $1 RETURNING id;").
+				mock.ExpectQuery("SELECT * FROM collection WHERE user_id = $1 ORDER BY name;").
 					WithArgs(1).WillReturnError(fmt.Errorf("some error"))
 			},
-			input:          1,
 			wantErr:        true,
-			expectedErrMsg: "failed to delete saved message by id: some error",
+			expectedErrMsg: "failed to get collections by user id: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetCollections(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetCollection(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.Collection
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [collection found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "created_at"}).
+					AddRow(1, 2, "golang", createdAt)
+
+				mock.ExpectQuery("SELECT * FROM collection WHERE id = $1 AND user_id = $2;").
+					WithArgs(1, 2).WillReturnRows(rows)
+			},
+			want: &model.Collection{ID: 1, UserID: 2, Name: "golang", CreatedAt: createdAt},
+		},
+		{
+			name: "Error: [collection of user not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "created_at"})
+
+				mock.ExpectQuery("SELECT * FROM collection WHERE id = $1 AND user_id = $2;").
+					WithArgs(1, 2).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "collection not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM collection WHERE id = $1 AND user_id = $2;").
+					WithArgs(1, 2).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get collection by id: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetCollection(2, 1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_CreateCollection(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [collection created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery("INSERT INTO collection(user_id, name) VALUES ($1, $2) ON CONFLICT (user_id, name) DO NOTHING RETURNING id;").
+					WithArgs(2, "golang").WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [collection with this name already exists]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery("INSERT INTO collection(user_id, name) VALUES ($1, $2) ON CONFLICT (user_id, name) DO NOTHING RETURNING id;").
+					WithArgs(2, "golang").WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "collection with this name already exists",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO collection(user_id, name) VALUES ($1, $2) ON CONFLICT (user_id, name) DO NOTHING RETURNING id;").
+					WithArgs(2, "golang").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to create collection: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateCollection(&model.Collection{UserID: 2, Name: "golang"})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_UpdateCollection(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [collection updated]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery("UPDATE collection SET name = $1 WHERE id = $2 AND user_id = $3 RETURNING id;").
+					WithArgs("golang", 1, 2).WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [collection of user not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery("UPDATE collection SET name = $1 WHERE id = $2 AND user_id = $3 RETURNING id;").
+					WithArgs("golang", 1, 2).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "collection not found",
+		},
+		{
+			name: "Error: [collection with this name already exists]",
+			mock: func() {
+				mock.ExpectQuery("UPDATE collection SET name = $1 WHERE id = $2 AND user_id = $3 RETURNING id;").
+					WithArgs("golang", 1, 2).WillReturnError(&pq.Error{Code: "23505"})
+			},
+			wantErr:        true,
+			expectedErrMsg: "collection with this name already exists",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("UPDATE collection SET name = $1 WHERE id = $2 AND user_id = $3 RETURNING id;").
+					WithArgs("golang", 1, 2).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to update collection: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateCollection(&model.Collection{ID: 1, UserID: 2, Name: "golang"})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_DeleteCollection(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [collection deleted]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery("DELETE FROM collection WHERE id = $1 AND user_id = $2 RETURNING id;").
+					WithArgs(1, 2).WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [collection of user not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery("DELETE FROM collection WHERE id = $1 AND user_id = $2 RETURNING id;").
+					WithArgs(1, 2).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "collection not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM collection WHERE id = $1 AND user_id = $2 RETURNING id;").
+					WithArgs(1, 2).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to delete collection by id: some error",
 		},
 	}
 
@@ -225,7 +737,7 @@ func Test_DeleteSavedMessage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.DeleteSavedMessage(tt.input)
+			got, err := r.DeleteCollection(2, 1)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
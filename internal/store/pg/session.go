@@ -0,0 +1,186 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+var (
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrSessionNotCreated    = errors.New("session not created")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+)
+
+type SessionRepo struct {
+	db Queryer
+}
+
+func NewSessionRepo(db Queryer) *SessionRepo {
+	return &SessionRepo{db: db}
+}
+
+// CreateSession creates session of user together with its first refresh token.
+func (s *SessionRepo) CreateSession(userID int, tokenHash string, expiresAt time.Time) (int, error) {
+	var id int
+
+	row := s.db.QueryRow(
+		`WITH new_session AS (
+			INSERT INTO auth_session(user_id) VALUES ($1) RETURNING id
+		), new_token AS (
+			INSERT INTO refresh_token(session_id, token_hash, expires_at)
+			SELECT id, $2, $3 FROM new_session
+		)
+		SELECT id FROM new_session;`,
+		userID, tokenHash, expiresAt,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrSessionNotCreated
+		}
+
+		return 0, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *SessionRepo) GetSession(ID int) (*model.Session, error) {
+	var session model.Session
+
+	err := s.db.Get(&session, "SELECT id, user_id, created_at, revoked_at FROM auth_session WHERE id = $1;", ID)
+	if err == sql.ErrNoRows {
+		return nil, ErrSessionNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// GetSessionsByUserID returns every session of user, latest first.
+func (s *SessionRepo) GetSessionsByUserID(userID int) ([]model.Session, error) {
+	sessions := make([]model.Session, 0, 10)
+
+	err := s.db.Select(
+		&sessions,
+		"SELECT id, user_id, created_at, revoked_at FROM auth_session WHERE user_id = $1 ORDER BY created_at DESC, id DESC;",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions by user id: %w", err)
+	}
+
+	if len(sessions) == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	return sessions, nil
+}
+
+// RotateRefreshToken marks unused and unexpired refresh token of active session as used
+// and issues new token in the same session. It returns owner of the session.
+func (s *SessionRepo) RotateRefreshToken(tokenHash, newTokenHash string, expiresAt time.Time) (*model.Principal, error) {
+	var principal model.Principal
+
+	err := s.db.Get(
+		&principal,
+		`WITH used AS (
+			UPDATE refresh_token rt SET used_at = now()
+			FROM auth_session s
+			WHERE rt.token_hash = $1 AND rt.used_at IS NULL AND rt.expires_at > now()
+			AND s.id = rt.session_id AND s.revoked_at IS NULL
+			RETURNING rt.session_id, s.user_id
+		), new_token AS (
+			INSERT INTO refresh_token(session_id, token_hash, expires_at)
+			SELECT session_id, $2, $3 FROM used
+		)
+		SELECT used.user_id, u.email, u.role, used.session_id FROM used JOIN web_user u ON u.id = used.user_id;`,
+		tokenHash, newTokenHash, expiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return &principal, nil
+}
+
+func (s *SessionRepo) GetRefreshToken(tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+
+	err := s.db.Get(
+		&token,
+		`SELECT t.id, t.session_id, s.user_id, t.expires_at, t.used_at FROM refresh_token t
+		JOIN auth_session s ON s.id = t.session_id
+		WHERE t.token_hash = $1;`,
+		tokenHash,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// RevokeSession revokes session, revoking already revoked session keeps its first revocation time.
+func (s *SessionRepo) RevokeSession(ID int) (int, error) {
+	var id int
+
+	row := s.db.QueryRow("UPDATE auth_session SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1 RETURNING id;", ID)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrSessionNotFound
+		}
+
+		return 0, fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return id, nil
+}
+
+// RevokeUserSessions revokes every active session of user and returns how many sessions were revoked.
+func (s *SessionRepo) RevokeUserSessions(userID int) (int, error) {
+	result, err := s.db.Exec("UPDATE auth_session SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL;", userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return int(revoked), nil
+}
+
+// RevokeOtherUserSessions revokes every active session of user except session with sessionID
+// and returns how many sessions were revoked.
+func (s *SessionRepo) RevokeOtherUserSessions(userID, sessionID int) (int, error) {
+	result, err := s.db.Exec(
+		"UPDATE auth_session SET revoked_at = now() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;",
+		userID, sessionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke other user sessions: %w", err)
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke other user sessions: %w", err)
+	}
+
+	return int(revoked), nil
+}
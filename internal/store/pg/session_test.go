@@ -0,0 +1,420 @@
+package pg_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateSession(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(&pg.DB{DB: sqlxDB})
+
+	expiresAt := time.Date(2022, time.August, 1, 12, 0, 0, 0, time.UTC)
+	query := `WITH new_session AS (
+			INSERT INTO auth_session(user_id) VALUES ($1) RETURNING id
+		), new_token AS (
+			INSERT INTO refresh_token(session_id, token_hash, expires_at)
+			SELECT id, $2, $3 FROM new_session
+		)
+		SELECT id FROM new_session;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [session created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(5)
+
+				mock.ExpectQuery(query).WithArgs(1, "hash", expiresAt).WillReturnRows(rows)
+			},
+			want: 5,
+		},
+		{
+			name: "Error: [session not created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).WithArgs(1, "hash", expiresAt).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "session not created",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1, "hash", expiresAt).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to create session: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateSession(1, "hash", expiresAt)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetSession(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(&pg.DB{DB: sqlxDB})
+
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	query := "SELECT id, user_id, created_at, revoked_at FROM auth_session WHERE id = $1;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.Session
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [session found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "created_at", "revoked_at"}).
+					AddRow(5, 1, createdAt, nil)
+
+				mock.ExpectQuery(query).WithArgs(5).WillReturnRows(rows)
+			},
+			want: &model.Session{ID: 5, UserID: 1, CreatedAt: createdAt},
+		},
+		{
+			name: "Error: [session not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "created_at", "revoked_at"})
+
+				mock.ExpectQuery(query).WithArgs(5).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "session not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(5).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get session: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetSession(5)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_RotateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(&pg.DB{DB: sqlxDB})
+
+	expiresAt := time.Date(2022, time.August, 1, 12, 0, 0, 0, time.UTC)
+	query := `WITH used AS (
+			UPDATE refresh_token rt SET used_at = now()
+			FROM auth_session s
+			WHERE rt.token_hash = $1 AND rt.used_at IS NULL AND rt.expires_at > now()
+			AND s.id = rt.session_id AND s.revoked_at IS NULL
+			RETURNING rt.session_id, s.user_id
+		), new_token AS (
+			INSERT INTO refresh_token(session_id, token_hash, expires_at)
+			SELECT session_id, $2, $3 FROM used
+		)
+		SELECT used.user_id, u.email, u.role, used.session_id FROM used JOIN web_user u ON u.id = used.user_id;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.Principal
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [refresh token rotated]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"user_id", "email", "role", "session_id"}).
+					AddRow(1, "test@test.com", "member", 5)
+
+				mock.ExpectQuery(query).WithArgs("hash", "new-hash", expiresAt).WillReturnRows(rows)
+			},
+			want: &model.Principal{UserID: 1, Email: "test@test.com", Role: model.RoleMember, SessionID: 5},
+		},
+		{
+			name: "Error: [used, expired or revoked refresh token not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"user_id", "email", "role", "session_id"})
+
+				mock.ExpectQuery(query).WithArgs("hash", "new-hash", expiresAt).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "refresh token not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("hash", "new-hash", expiresAt).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to rotate refresh token: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.RotateRefreshToken("hash", "new-hash", expiresAt)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_RevokeSession(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(&pg.DB{DB: sqlxDB})
+
+	query := "UPDATE auth_session SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1 RETURNING id;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [session revoked]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(5)
+
+				mock.ExpectQuery(query).WithArgs(5).WillReturnRows(rows)
+			},
+			want: 5,
+		},
+		{
+			name: "Error: [session not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).WithArgs(5).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "session not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(5).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to revoke session: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.RevokeSession(5)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_RevokeUserSessions(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(&pg.DB{DB: sqlxDB})
+
+	query := "UPDATE auth_session SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [sessions revoked]",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			want: 3,
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to revoke user sessions: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.RevokeUserSessions(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_RevokeOtherUserSessions(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(&pg.DB{DB: sqlxDB})
+
+	query := "UPDATE auth_session SET revoked_at = now() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [other sessions revoked]",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, 5).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			want: 2,
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, 5).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to revoke other user sessions: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.RevokeOtherUserSessions(1, 5)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+var (
+	ErrSharesNotFound       = errors.New("shares not found")
+	ErrShareNotFound        = errors.New("share not found")
+	ErrShareNotCreated      = errors.New("share not created")
+	ErrShareItemNotFound    = errors.New("shared message not found")
+	ErrShareMembersNotFound = errors.New("share members not found")
+	ErrShareMemberNotFound  = errors.New("share member not found")
+)
+
+// shareOfUserQuery selects shares which user owns or was granted with role of user in them,
+// public link token is shown only to owner.
+const shareOfUserQuery = `SELECT
+	s.id, s.user_id, s.title, CASE WHEN s.user_id = $1 THEN s.token END AS token, s.created_at,
+	CASE WHEN s.user_id = $1 THEN 'owner' ELSE sm.role END AS role
+	FROM share s
+	LEFT JOIN share_member sm ON sm.share_id = s.id AND sm.user_id = $1
+	WHERE (s.user_id = $1 OR sm.user_id IS NOT NULL)`
+
+type ShareRepo struct {
+	db Queryer
+}
+
+func NewShareRepo(db Queryer) *ShareRepo {
+	return &ShareRepo{db: db}
+}
+
+// CreateShare creates share with messages of saved messages of its owner, saved ids of other users are skipped.
+func (s *ShareRepo) CreateShare(share *model.Share, savedIDs []int) (int, error) {
+	var id int
+
+	row := s.db.QueryRow(
+		`WITH new_share AS (
+			INSERT INTO share(user_id, title, token) VALUES ($1, $2, $3) RETURNING id
+		), new_items AS (
+			INSERT INTO share_item(share_id, message_id)
+			SELECT new_share.id, saved.message_id FROM new_share, saved
+			WHERE saved.user_id = $1 AND saved.id = ANY($4)
+		)
+		SELECT id FROM new_share;`,
+		share.UserID, share.Title, share.Token, pq.Array(savedIDs),
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrShareNotCreated
+		}
+
+		return 0, fmt.Errorf("failed to create share: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetSharesByUserID returns shares which user owns or was granted, latest created first.
+func (s *ShareRepo) GetSharesByUserID(userID int) ([]model.Share, error) {
+	shares := make([]model.Share, 0, 10)
+
+	err := s.db.Select(&shares, shareOfUserQuery+" ORDER BY s.created_at DESC, s.id DESC;", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shares by user id: %w", err)
+	}
+
+	if len(shares) == 0 {
+		return nil, ErrSharesNotFound
+	}
+
+	return shares, nil
+}
+
+// GetShareAccess returns share with role of user in it, shares without access of user are reported as not found.
+func (s *ShareRepo) GetShareAccess(ID, userID int) (*model.Share, error) {
+	var share model.Share
+
+	err := s.db.Get(&share, shareOfUserQuery+" AND s.id = $2;", userID, ID)
+	if err == sql.ErrNoRows {
+		return nil, ErrShareNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share access: %w", err)
+	}
+
+	return &share, nil
+}
+
+// GetShareByToken returns share by its public link token, anybody with the link is a viewer.
+func (s *ShareRepo) GetShareByToken(token string) (*model.Share, error) {
+	var share model.Share
+
+	err := s.db.Get(&share, "SELECT id, user_id, title, token, created_at, 'viewer' AS role FROM share WHERE token = $1;", token)
+	if err == sql.ErrNoRows {
+		return nil, ErrShareNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share by token: %w", err)
+	}
+
+	return &share, nil
+}
+
+// UpdateShareToken replaces public link token of share, nil token revokes the link.
+func (s *ShareRepo) UpdateShareToken(ID int, token *string) (int, error) {
+	var id int
+
+	row := s.db.QueryRow("UPDATE share SET token = $1 WHERE id = $2 RETURNING id;", token, ID)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrShareNotFound
+		}
+
+		return 0, fmt.Errorf("failed to update share token: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *ShareRepo) DeleteShare(ID int) (int, error) {
+	var id int
+
+	row := s.db.QueryRow("DELETE FROM share WHERE id = $1 RETURNING id;", ID)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrShareNotFound
+		}
+
+		return 0, fmt.Errorf("failed to delete share by id: %w", err)
+	}
+
+	return id, nil
+}
+
+// AddShareItems adds messages of saved messages of user to share and returns how many messages were added,
+// saved ids of other users and messages which are already shared are skipped.
+func (s *ShareRepo) AddShareItems(shareID, userID int, savedIDs []int) (int, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO share_item(share_id, message_id)
+		SELECT $1, message_id FROM saved WHERE user_id = $2 AND id = ANY($3)
+		ON CONFLICT (share_id, message_id) DO NOTHING;`,
+		shareID, userID, pq.Array(savedIDs),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add share items: %w", err)
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get added share items count: %w", err)
+	}
+
+	return int(added), nil
+}
+
+func (s *ShareRepo) DeleteShareItem(shareID, messageID int) (int, error) {
+	var id int
+
+	row := s.db.QueryRow(
+		"DELETE FROM share_item WHERE share_id = $1 AND message_id = $2 RETURNING message_id;",
+		shareID, messageID,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrShareItemNotFound
+		}
+
+		return 0, fmt.Errorf("failed to delete share item: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetShareMessages returns messages of share latest added first, share without messages is not an error.
+func (s *ShareRepo) GetShareMessages(shareID int) ([]model.FullMessage, error) {
+	messages := make([]model.FullMessage, 0, 10)
+
+	err := s.db.Select(
+		&messages,
+		fullMessageQuery+`
+		JOIN share_item si ON si.message_id = m.id
+		WHERE si.share_id = $1
+		ORDER BY si.added_at DESC, m.id DESC;`,
+		shareID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// UpsertShareMember grants web user access to share or changes role of existing member.
+func (s *ShareRepo) UpsertShareMember(member *model.ShareMember) error {
+	_, err := s.db.Exec(
+		`INSERT INTO share_member(share_id, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT (share_id, user_id) DO UPDATE SET role = EXCLUDED.role;`,
+		member.ShareID, member.UserID, member.Role,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert share member: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ShareRepo) GetShareMembers(shareID int) ([]model.ShareMember, error) {
+	members := make([]model.ShareMember, 0, 10)
+
+	err := s.db.Select(
+		&members,
+		`SELECT sm.share_id, sm.user_id, u.email, sm.role
+		FROM share_member sm
+		JOIN web_user u ON u.id = sm.user_id
+		WHERE sm.share_id = $1
+		ORDER BY u.email;`,
+		shareID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share members: %w", err)
+	}
+
+	if len(members) == 0 {
+		return nil, ErrShareMembersNotFound
+	}
+
+	return members, nil
+}
+
+func (s *ShareRepo) DeleteShareMember(shareID, userID int) (int, error) {
+	var id int
+
+	row := s.db.QueryRow(
+		"DELETE FROM share_member WHERE share_id = $1 AND user_id = $2 RETURNING user_id;",
+		shareID, userID,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrShareMemberNotFound
+		}
+
+		return 0, fmt.Errorf("failed to delete share member: %w", err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,759 @@
+package pg_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const shareOfUserQuery = `SELECT
+	s.id, s.user_id, s.title, CASE WHEN s.user_id = $1 THEN s.token END AS token, s.created_at,
+	CASE WHEN s.user_id = $1 THEN 'owner' ELSE sm.role END AS role
+	FROM share s
+	LEFT JOIN share_member sm ON sm.share_id = s.id AND sm.user_id = $1
+	WHERE (s.user_id = $1 OR sm.user_id IS NOT NULL)`
+
+func Test_CreateShare(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	token := "token"
+	query := `WITH new_share AS (
+			INSERT INTO share(user_id, title, token) VALUES ($1, $2, $3) RETURNING id
+		), new_items AS (
+			INSERT INTO share_item(share_id, message_id)
+			SELECT new_share.id, saved.message_id FROM new_share, saved
+			WHERE saved.user_id = $1 AND saved.id = ANY($4)
+		)
+		SELECT id FROM new_share;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery(query).
+					WithArgs(1, "golang", &token, pq.Array([]int{1, 2})).WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [share not created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).
+					WithArgs(1, "golang", &token, pq.Array([]int{1, 2})).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "share not created",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).
+					WithArgs(1, "golang", &token, pq.Array([]int{1, 2})).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to create share: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateShare(&model.Share{UserID: 1, Title: "golang", Token: &token}, []int{1, 2})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetSharesByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	token := "token"
+
+	data := []model.Share{
+		{ID: 2, UserID: 1, Title: "golang", Token: &token, CreatedAt: createdAt, Role: model.ShareRoleOwner},
+		{ID: 1, UserID: 3, Title: "jobs", CreatedAt: createdAt, Role: model.ShareRoleEditor},
+	}
+
+	columns := []string{"id", "user_id", "title", "token", "created_at", "role"}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           []model.Share
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [owned and granted shares found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, 1, "golang", "token", createdAt, "owner").
+					AddRow(1, 3, "jobs", nil, createdAt, "editor")
+
+				mock.ExpectQuery(shareOfUserQuery + " ORDER BY s.created_at DESC, s.id DESC;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			want: data,
+		},
+		{
+			name: "Error: [shares not found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns)
+
+				mock.ExpectQuery(shareOfUserQuery + " ORDER BY s.created_at DESC, s.id DESC;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "shares not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(shareOfUserQuery + " ORDER BY s.created_at DESC, s.id DESC;").
+					WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get shares by user id: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetSharesByUserID(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetShareAccess(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "title", "token", "created_at", "role"}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.Share
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share of viewer found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, 3, "jobs", nil, createdAt, "viewer")
+
+				mock.ExpectQuery(shareOfUserQuery+" AND s.id = $2;").
+					WithArgs(2, 1).WillReturnRows(rows)
+			},
+			want: &model.Share{ID: 1, UserID: 3, Title: "jobs", CreatedAt: createdAt, Role: model.ShareRoleViewer},
+		},
+		{
+			name: "Error: [share without access of user not found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns)
+
+				mock.ExpectQuery(shareOfUserQuery+" AND s.id = $2;").
+					WithArgs(2, 1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "share not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(shareOfUserQuery+" AND s.id = $2;").
+					WithArgs(2, 1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get share access: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetShareAccess(1, 2)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetShareByToken(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	createdAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	token := "token"
+	columns := []string{"id", "user_id", "title", "token", "created_at", "role"}
+	query := "SELECT id, user_id, title, token, created_at, 'viewer' AS role FROM share WHERE token = $1;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.Share
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, 3, "jobs", "token", createdAt, "viewer")
+
+				mock.ExpectQuery(query).WithArgs("token").WillReturnRows(rows)
+			},
+			want: &model.Share{ID: 1, UserID: 3, Title: "jobs", Token: &token, CreatedAt: createdAt, Role: model.ShareRoleViewer},
+		},
+		{
+			name: "Error: [share with revoked link not found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns)
+
+				mock.ExpectQuery(query).WithArgs("token").WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "share not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("token").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get share by token: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetShareByToken("token")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_UpdateShareToken(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	token := "token"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          *string
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share link rotated]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery("UPDATE share SET token = $1 WHERE id = $2 RETURNING id;").
+					WithArgs(&token, 1).WillReturnRows(rows)
+			},
+			input: &token,
+			want:  1,
+		},
+		{
+			name: "Ok: [share link revoked]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery("UPDATE share SET token = $1 WHERE id = $2 RETURNING id;").
+					WithArgs(nil, 1).WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [share not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery("UPDATE share SET token = $1 WHERE id = $2 RETURNING id;").
+					WithArgs(nil, 1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "share not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery("UPDATE share SET token = $1 WHERE id = $2 RETURNING id;").
+					WithArgs(nil, 1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to update share token: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateShareToken(1, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_AddShareItems(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	query := `INSERT INTO share_item(share_id, message_id)
+		SELECT $1, message_id FROM saved WHERE user_id = $2 AND id = ANY($3)
+		ON CONFLICT (share_id, message_id) DO NOTHING;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share items added]",
+			mock: func() {
+				mock.ExpectExec(query).
+					WithArgs(1, 2, pq.Array([]int{1, 2, 3})).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			want: 2,
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectExec(query).
+					WithArgs(1, 2, pq.Array([]int{1, 2, 3})).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to add share items: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.AddShareItems(1, 2, []int{1, 2, 3})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_DeleteShareItem(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	query := "DELETE FROM share_item WHERE share_id = $1 AND message_id = $2 RETURNING message_id;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share item deleted]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"message_id"}).
+					AddRow(2)
+
+				mock.ExpectQuery(query).WithArgs(1, 2).WillReturnRows(rows)
+			},
+			want: 2,
+		},
+		{
+			name: "Error: [shared message not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"message_id"})
+
+				mock.ExpectQuery(query).WithArgs(1, 2).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "shared message not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1, 2).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to delete share item: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.DeleteShareItem(1, 2)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetShareMessages(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	query := `SELECT 
+		m.id as messageId, m.title as messageTitle, m.message_url as messageUrl, m.imageurl as messageImageUrl, 
+		m.posted_at as messagePostedAt, m.edited_at as messageEditedAt, m.ingested_at as messageIngestedAt,
+		c.name as channelName, c.title as channelTitle, c.imageurl as channelImageUrl, 
+		u.id as userId, u.fullname as userFullname, u.imageurl as userImageUrl,
+		(SELECT COUNT(*) FROM replie WHERE message_id = m.id)
+		FROM message m
+		LEFT JOIN channel c ON c.id = m.channel_id 
+		LEFT JOIN tg_user u ON m.user_id = u.id
+		JOIN share_item si ON si.message_id = m.id
+		WHERE si.share_id = $1
+		ORDER BY si.added_at DESC, m.id DESC;`
+
+	columns := []string{"messageid", "messagetitle", "messageurl", "channelname", "userid", "userfullname", "count"}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           []model.FullMessage
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [shared messages found]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, "test2", "test2.tg", "test2c", 2, "test2u testu", 0).
+					AddRow(1, "test1", "test1.tg", "test1c", 1, "test1u testu", 3)
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: []model.FullMessage{
+				{ID: 2, Title: "test2", MessageURL: "test2.tg", ChannelName: "test2c", UserID: 2, UserFullname: "test2u testu"},
+				{ID: 1, Title: "test1", MessageURL: "test1.tg", ChannelName: "test1c", UserID: 1, UserFullname: "test1u testu", RepliesCount: 3},
+			},
+		},
+		{
+			name: "Ok: [share without messages]",
+			mock: func() {
+				rows := sqlmock.NewRows(columns)
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: []model.FullMessage{},
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get share messages: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetShareMessages(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_UpsertShareMember(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	query := `INSERT INTO share_member(share_id, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT (share_id, user_id) DO UPDATE SET role = EXCLUDED.role;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share member upserted]",
+			mock: func() {
+				mock.ExpectExec(query).
+					WithArgs(1, 2, model.ShareRoleEditor).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectExec(query).
+					WithArgs(1, 2, model.ShareRoleEditor).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to upsert share member: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.UpsertShareMember(&model.ShareMember{ShareID: 1, UserID: 2, Role: model.ShareRoleEditor})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_GetShareMembers(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewShareRepo(&pg.DB{DB: sqlxDB})
+
+	query := `SELECT sm.share_id, sm.user_id, u.email, sm.role
+		FROM share_member sm
+		JOIN web_user u ON u.id = sm.user_id
+		WHERE sm.share_id = $1
+		ORDER BY u.email;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           []model.ShareMember
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [share members found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"share_id", "user_id", "email", "role"}).
+					AddRow(1, 2, "editor@test.com", "editor").
+					AddRow(1, 3, "viewer@test.com", "viewer")
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: []model.ShareMember{
+				{ShareID: 1, UserID: 2, Email: "editor@test.com", Role: model.ShareRoleEditor},
+				{ShareID: 1, UserID: 3, Email: "viewer@test.com", Role: model.ShareRoleViewer},
+			},
+		},
+		{
+			name: "Error: [share members not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"share_id", "user_id", "email", "role"})
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "share members not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get share members: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetShareMembers(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
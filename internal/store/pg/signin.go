@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
+
+var ErrSignInAttemptNotFound = errors.New("sign-in attempt not found")
+
+type SignInAttemptRepo struct {
+	db Queryer
+}
+
+func NewSignInAttemptRepo(db Queryer) *SignInAttemptRepo {
+	return &SignInAttemptRepo{db: db}
+}
+
+func (s *SignInAttemptRepo) GetSignInAttempt(kind model.SignInAttemptKind, subject string) (*model.SignInAttempt, error) {
+	var attempt model.SignInAttempt
+
+	err := s.db.Get(
+		&attempt,
+		"SELECT kind, subject, failures, last_failed_at, locked_until FROM sign_in_attempt WHERE kind = $1 AND subject = $2;",
+		kind, subject,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrSignInAttemptNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sign-in attempt: %w", err)
+	}
+
+	return &attempt, nil
+}
+
+// RecordSignInFailure counts failed sign-in attempt at failedAt and returns number of failures in a row,
+// counting starts again when the previous failure happened before resetBefore.
+func (s *SignInAttemptRepo) RecordSignInFailure(kind model.SignInAttemptKind, subject string, failedAt, resetBefore time.Time) (int, error) {
+	var failures int
+
+	row := s.db.QueryRow(
+		`INSERT INTO sign_in_attempt(kind, subject, failures, last_failed_at) VALUES ($1, $2, 1, $3)
+		ON CONFLICT (kind, subject) DO UPDATE SET
+			failures = CASE WHEN sign_in_attempt.last_failed_at < $4 THEN 1 ELSE sign_in_attempt.failures + 1 END,
+			last_failed_at = $3
+		RETURNING failures;`,
+		kind, subject, failedAt, resetBefore,
+	)
+	if err := row.Scan(&failures); err != nil {
+		return 0, fmt.Errorf("failed to record sign-in failure: %w", err)
+	}
+
+	return failures, nil
+}
+
+func (s *SignInAttemptRepo) LockSignIn(kind model.SignInAttemptKind, subject string, lockedUntil time.Time) (int, error) {
+	var failures int
+
+	row := s.db.QueryRow(
+		"UPDATE sign_in_attempt SET locked_until = $1 WHERE kind = $2 AND subject = $3 RETURNING failures;",
+		lockedUntil, kind, subject,
+	)
+	if err := row.Scan(&failures); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrSignInAttemptNotFound
+		}
+
+		return 0, fmt.Errorf("failed to lock sign-in: %w", err)
+	}
+
+	return failures, nil
+}
+
+// DeleteSignInAttempt forgets failed sign-in attempts together with lock and returns how many counters were deleted.
+func (s *SignInAttemptRepo) DeleteSignInAttempt(kind model.SignInAttemptKind, subject string) (int, error) {
+	result, err := s.db.Exec("DELETE FROM sign_in_attempt WHERE kind = $1 AND subject = $2;", kind, subject)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sign-in attempt: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sign-in attempt: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,288 @@
+package pg_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetSignInAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSignInAttemptRepo(&pg.DB{DB: sqlxDB})
+
+	failedAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	lockedUntil := failedAt.Add(time.Minute)
+	query := "SELECT kind, subject, failures, last_failed_at, locked_until FROM sign_in_attempt WHERE kind = $1 AND subject = $2;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           *model.SignInAttempt
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [sign-in attempt found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"kind", "subject", "failures", "last_failed_at", "locked_until"}).
+					AddRow("account", "test@test.com", 6, failedAt, lockedUntil)
+
+				mock.ExpectQuery(query).WithArgs(model.SignInAttemptAccount, "test@test.com").WillReturnRows(rows)
+			},
+			want: &model.SignInAttempt{
+				Kind:         model.SignInAttemptAccount,
+				Subject:      "test@test.com",
+				Failures:     6,
+				LastFailedAt: failedAt,
+				LockedUntil:  &lockedUntil,
+			},
+		},
+		{
+			name: "Error: [sign-in attempt not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"kind", "subject", "failures", "last_failed_at", "locked_until"})
+
+				mock.ExpectQuery(query).WithArgs(model.SignInAttemptAccount, "test@test.com").WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "sign-in attempt not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(model.SignInAttemptAccount, "test@test.com").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to get sign-in attempt: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetSignInAttempt(model.SignInAttemptAccount, "test@test.com")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_RecordSignInFailure(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSignInAttemptRepo(&pg.DB{DB: sqlxDB})
+
+	failedAt := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	resetBefore := failedAt.Add(-time.Hour)
+	query := `INSERT INTO sign_in_attempt(kind, subject, failures, last_failed_at) VALUES ($1, $2, 1, $3)
+		ON CONFLICT (kind, subject) DO UPDATE SET
+			failures = CASE WHEN sign_in_attempt.last_failed_at < $4 THEN 1 ELSE sign_in_attempt.failures + 1 END,
+			last_failed_at = $3
+		RETURNING failures;`
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [sign-in failure recorded]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"failures"}).
+					AddRow(3)
+
+				mock.ExpectQuery(query).WithArgs(model.SignInAttemptIP, "192.0.2.1", failedAt, resetBefore).WillReturnRows(rows)
+			},
+			want: 3,
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(model.SignInAttemptIP, "192.0.2.1", failedAt, resetBefore).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to record sign-in failure: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.RecordSignInFailure(model.SignInAttemptIP, "192.0.2.1", failedAt, resetBefore)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_LockSignIn(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSignInAttemptRepo(&pg.DB{DB: sqlxDB})
+
+	lockedUntil := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+	query := "UPDATE sign_in_attempt SET locked_until = $1 WHERE kind = $2 AND subject = $3 RETURNING failures;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [sign-in locked]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"failures"}).
+					AddRow(5)
+
+				mock.ExpectQuery(query).WithArgs(lockedUntil, model.SignInAttemptAccount, "test@test.com").WillReturnRows(rows)
+			},
+			want: 5,
+		},
+		{
+			name: "Error: [sign-in attempt not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"failures"})
+
+				mock.ExpectQuery(query).WithArgs(lockedUntil, model.SignInAttemptAccount, "test@test.com").WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "sign-in attempt not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(lockedUntil, model.SignInAttemptAccount, "test@test.com").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to lock sign-in: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.LockSignIn(model.SignInAttemptAccount, "test@test.com", lockedUntil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_DeleteSignInAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSignInAttemptRepo(&pg.DB{DB: sqlxDB})
+
+	query := "DELETE FROM sign_in_attempt WHERE kind = $1 AND subject = $2;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [sign-in attempt deleted]",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(model.SignInAttemptAccount, "test@test.com").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: 1,
+		},
+		{
+			name: "Ok: [nothing to delete]",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(model.SignInAttemptAccount, "test@test.com").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			want: 0,
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(model.SignInAttemptAccount, "test@test.com").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to delete sign-in attempt: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.DeleteSignInAttempt(model.SignInAttemptAccount, "test@test.com")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -13,19 +13,46 @@ var (
 	ErrUserNotCreated = errors.New("user not created")
 )
 
+const userQuery = "SELECT id, COALESCE(tg_id, 0) AS tg_id, COALESCE(username, '') AS username, fullname, imageurl FROM tg_user"
+
 type UserRepo struct {
-	db *DB
+	db Queryer
 }
 
-func NewUserRepo(db *DB) *UserRepo {
+func NewUserRepo(db Queryer) *UserRepo {
 	return &UserRepo{db: db}
 }
 
-func (u *UserRepo) CreateUser(user *model.UserDTO) (int, error) {
+// UpsertUserByTgID creates telegram user keyed by its telegram id or refreshes profile of existing one.
+func (u *UserRepo) UpsertUserByTgID(user *model.UserDTO) (int, error) {
+	var id int
+
+	row := u.db.QueryRow(
+		`INSERT INTO tg_user(tg_id, username, fullname, imageurl) VALUES ($1, NULLIF($2, ''), $3, $4)
+		ON CONFLICT (tg_id) DO UPDATE SET username = EXCLUDED.username, fullname = EXCLUDED.fullname, imageurl = EXCLUDED.imageurl
+		RETURNING id;`,
+		user.TgID, user.Username, user.Fullname, user.ImageURL,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrUserNotCreated
+		}
+
+		return 0, fmt.Errorf("failed to upsert user by tg id: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpsertUserByUsername creates telegram user keyed by its username or refreshes profile of existing one.
+// It's used only for payloads which don't carry telegram id, senders without username are stored by UpsertAnonymousUser.
+func (u *UserRepo) UpsertUserByUsername(user *model.UserDTO) (int, error) {
 	var id int
 
 	row := u.db.QueryRow(
-		"INSERT INTO tg_user(username, fullname, imageurl) VALUES ($1, $2, $3) RETURNING id;",
+		`INSERT INTO tg_user(username, fullname, imageurl) VALUES ($1, $2, $3)
+		ON CONFLICT (username) DO UPDATE SET fullname = EXCLUDED.fullname, imageurl = EXCLUDED.imageurl
+		RETURNING id;`,
 		user.Username, user.Fullname, user.ImageURL,
 	)
 	if err := row.Scan(&id); err != nil {
@@ -33,16 +60,62 @@ func (u *UserRepo) CreateUser(user *model.UserDTO) (int, error) {
 			return 0, ErrUserNotCreated
 		}
 
-		return 0, fmt.Errorf("failed to create user: %w", err)
+		return 0, fmt.Errorf("failed to upsert user by username: %w", err)
 	}
 
 	return id, nil
 }
 
+// UpsertAnonymousUser returns telegram user which has neither telegram id nor username.
+// Such senders are told apart only by their profile, so redelivered payloads reuse the same row.
+func (u *UserRepo) UpsertAnonymousUser(user *model.UserDTO) (int, error) {
+	var id int
+
+	row := u.db.QueryRow(
+		`INSERT INTO tg_user(fullname, imageurl) VALUES ($1, $2)
+		ON CONFLICT (fullname, imageurl) WHERE tg_id IS NULL AND username IS NULL DO UPDATE SET fullname = EXCLUDED.fullname
+		RETURNING id;`,
+		user.Fullname, user.ImageURL,
+	)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrUserNotCreated
+		}
+
+		return 0, fmt.Errorf("failed to upsert anonymous user: %w", err)
+	}
+
+	return id, nil
+}
+
+// ClaimUsername hands username over to user with telegram id.
+// Row which was created by username before telegram ids were known gets this id attached,
+// any other user who held the username before loses it since usernames can change hands.
+func (u *UserRepo) ClaimUsername(tgID int64, username string) error {
+	_, err := u.db.Exec(
+		`UPDATE tg_user SET tg_id = $1
+		WHERE username = $2 AND tg_id IS NULL AND NOT EXISTS (SELECT 1 FROM tg_user WHERE tg_id = $1);`,
+		tgID, username,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to attach tg id to user: %w", err)
+	}
+
+	_, err = u.db.Exec(
+		"UPDATE tg_user SET username = NULL WHERE username = $1 AND tg_id IS DISTINCT FROM $2;",
+		username, tgID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release username: %w", err)
+	}
+
+	return nil
+}
+
 func (u *UserRepo) GetUserByUsername(username string) (*model.User, error) {
 	var user model.User
 
-	err := u.db.Get(&user, "SELECT * FROM tg_user WHERE username = $1;", username)
+	err := u.db.Get(&user, userQuery+" WHERE username = $1;", username)
 	if err == sql.ErrNoRows {
 		return nil, ErrUserNotFound
 	}
@@ -57,7 +130,7 @@ func (u *UserRepo) GetUserByUsername(username string) (*model.User, error) {
 func (u *UserRepo) GetUserByID(ID int) (*model.User, error) {
 	var user model.User
 
-	err := u.db.Get(&user, "SELECT * FROM tg_user WHERE id = $1;", ID)
+	err := u.db.Get(&user, userQuery+" WHERE id = $1;", ID)
 	if err == sql.ErrNoRows {
 		return nil, ErrUserNotFound
 	}
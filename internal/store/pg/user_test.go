@@ -11,7 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_CreateUser(t *testing.T) {
+func Test_UpsertUserByTgID(t *testing.T) {
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -23,6 +23,12 @@ func Test_CreateUser(t *testing.T) {
 
 	r := pg.NewUserRepo(&pg.DB{DB: sqlxDB})
 
+	query := `INSERT INTO tg_user(tg_id, username, fullname, imageurl) VALUES ($1, NULLIF($2, ''), $3, $4)
+		ON CONFLICT (tg_id) DO UPDATE SET username = EXCLUDED.username, fullname = EXCLUDED.fullname, imageurl = EXCLUDED.imageurl
+		RETURNING id;`
+
+	input := &model.UserDTO{TgID: 100, Username: "test", Fullname: "test test", ImageURL: "test.jpg"}
+
 	tests := []struct {
 		name           string
 		mock           func()
@@ -32,15 +38,107 @@ func Test_CreateUser(t *testing.T) {
 		expectedErrMsg string
 	}{
 		{
-			name: "Ok: [user created]",
+			name: "Ok: [user upserted]",
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"}).
 					AddRow(1)
 
-				mock.ExpectQuery("INSERT INTO tg_user(username, fullname, imageurl) VALUES ($1, $2, $3) RETURNING id;").
+				mock.ExpectQuery(query).
+					WithArgs(100, "test", "test test", "test.jpg").WillReturnRows(rows)
+			},
+			input: input,
+			want:  1,
+		},
+		{
+			name: "Ok: [user without username upserted]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(2)
+
+				mock.ExpectQuery(query).
+					WithArgs(200, "", "test test", "test.jpg").WillReturnRows(rows)
+			},
+			input: &model.UserDTO{TgID: 200, Fullname: "test test", ImageURL: "test.jpg"},
+			want:  2,
+		},
+		{
+			name: "Error: [user not created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).
+					WithArgs(100, "test", "test test", "test.jpg").WillReturnRows(rows)
+			},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "user not created",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).
+					WithArgs(100, "test", "test test", "test.jpg").WillReturnError(fmt.Errorf("some error"))
+			},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "failed to upsert user by tg id: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpsertUserByTgID(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_UpsertUserByUsername(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewUserRepo(&pg.DB{DB: sqlxDB})
+
+	query := `INSERT INTO tg_user(username, fullname, imageurl) VALUES ($1, $2, $3)
+		ON CONFLICT (username) DO UPDATE SET fullname = EXCLUDED.fullname, imageurl = EXCLUDED.imageurl
+		RETURNING id;`
+
+	input := &model.UserDTO{Username: "test", Fullname: "test test", ImageURL: "test.jpg"}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          *model.UserDTO
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [user upserted]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery(query).
 					WithArgs("test", "test test", "test.jpg").WillReturnRows(rows)
 			},
-			input: &model.UserDTO{Username: "test", Fullname: "test test", ImageURL: "test.jpg"},
+			input: input,
 			want:  1,
 		},
 		{
@@ -48,22 +146,102 @@ func Test_CreateUser(t *testing.T) {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"})
 
-				mock.ExpectQuery("INSERT INTO tg_user(username, fullname, imageurl) VALUES ($1, $2, $3) RETURNING id;").
+				mock.ExpectQuery(query).
 					WithArgs("test", "test test", "test.jpg").WillReturnRows(rows)
 			},
-			input:          &model.UserDTO{Username: "test", Fullname: "test test", ImageURL: "test.jpg"},
+			input:          input,
 			wantErr:        true,
 			expectedErrMsg: "user not created",
 		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("INSERT INTO tg_user(username, fullname, imageurl) VALUES ($1, $2, $3) RETURNING id;").
+				mock.ExpectQuery(query).
 					WithArgs("test", "test test", "test.jpg").WillReturnError(fmt.Errorf("some error"))
 			},
-			input:          &model.UserDTO{Username: "test", Fullname: "test test", ImageURL: "test.jpg"},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "failed to upsert user by username: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpsertUserByUsername(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_UpsertAnonymousUser(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewUserRepo(&pg.DB{DB: sqlxDB})
+
+	query := `INSERT INTO tg_user(fullname, imageurl) VALUES ($1, $2)
+		ON CONFLICT (fullname, imageurl) WHERE tg_id IS NULL AND username IS NULL DO UPDATE SET fullname = EXCLUDED.fullname
+		RETURNING id;`
+
+	input := &model.UserDTO{Fullname: "anonymous", ImageURL: "anonymous.jpg"}
+
+	tests := []struct {
+		name           string
+		mock           func()
+		input          *model.UserDTO
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [anonymous user upserted]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(2)
+
+				mock.ExpectQuery(query).
+					WithArgs("anonymous", "anonymous.jpg").WillReturnRows(rows)
+			},
+			input: input,
+			want:  2,
+		},
+		{
+			name: "Error: [user not created]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).
+					WithArgs("anonymous", "anonymous.jpg").WillReturnRows(rows)
+			},
+			input:          input,
 			wantErr:        true,
-			expectedErrMsg: "failed to create user: some error",
+			expectedErrMsg: "user not created",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).
+					WithArgs("anonymous", "anonymous.jpg").WillReturnError(fmt.Errorf("some error"))
+			},
+			input:          input,
+			wantErr:        true,
+			expectedErrMsg: "failed to upsert anonymous user: some error",
 		},
 	}
 
@@ -71,7 +249,7 @@ func Test_CreateUser(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.CreateUser(tt.input)
+			got, err := r.UpsertAnonymousUser(tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
@@ -85,6 +263,76 @@ func Test_CreateUser(t *testing.T) {
 	}
 }
 
+func Test_ClaimUsername(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewUserRepo(&pg.DB{DB: sqlxDB})
+
+	attachQuery := `UPDATE tg_user SET tg_id = $1
+		WHERE username = $2 AND tg_id IS NULL AND NOT EXISTS (SELECT 1 FROM tg_user WHERE tg_id = $1);`
+	releaseQuery := "UPDATE tg_user SET username = NULL WHERE username = $1 AND tg_id IS DISTINCT FROM $2;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [username claimed]",
+			mock: func() {
+				mock.ExpectExec(attachQuery).
+					WithArgs(100, "test").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(releaseQuery).
+					WithArgs("test", 100).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "Error: [some sql error on attach]",
+			mock: func() {
+				mock.ExpectExec(attachQuery).
+					WithArgs(100, "test").WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to attach tg id to user: some error",
+		},
+		{
+			name: "Error: [some sql error on release]",
+			mock: func() {
+				mock.ExpectExec(attachQuery).
+					WithArgs(100, "test").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(releaseQuery).
+					WithArgs("test", 100).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to release username: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.ClaimUsername(100, "test")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func Test_GetUserByID(t *testing.T) {
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 	if err != nil {
@@ -108,21 +356,21 @@ func Test_GetUserByID(t *testing.T) {
 		{
 			name: "Ok: [user by id found]",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "username", "fullname", "imageurl"}).
-					AddRow(1, "test", "test test", "test.jpg")
+				rows := sqlmock.NewRows([]string{"id", "tg_id", "username", "fullname", "imageurl"}).
+					AddRow(1, 100, "test", "test test", "test.jpg")
 
-				mock.ExpectQuery("SELECT * FROM tg_user WHERE id = $1;").
+				mock.ExpectQuery("SELECT id, COALESCE(tg_id, 0) AS tg_id, COALESCE(username, '') AS username, fullname, imageurl FROM tg_user WHERE id = $1;").
 					WithArgs(1).WillReturnRows(rows)
 			},
 			input: 1,
-			want:  &model.User{ID: 1, Username: "test", Fullname: "test test", ImageURL: "test.jpg"},
+			want:  &model.User{ID: 1, TgID: 100, Username: "test", Fullname: "test test", ImageURL: "test.jpg"},
 		},
 		{
 			name: "Error: [user by id not found]",
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "username", "fullname", "imageurl"})
+				rows := sqlmock.NewRows([]string{"id", "tg_id", "username", "fullname", "imageurl"})
 
-				mock.ExpectQuery("SELECT * FROM tg_user WHERE id = $1;").
+				mock.ExpectQuery("SELECT id, COALESCE(tg_id, 0) AS tg_id, COALESCE(username, '') AS username, fullname, imageurl FROM tg_user WHERE id = $1;").
 					WithArgs(1).WillReturnRows(rows)
 			},
 			input:          1,
@@ -132,7 +380,7 @@ func Test_GetUserByID(t *testing.T) {
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("SELECT * FROM tg_user WHERE id = $1;").
+				mock.ExpectQuery("SELECT id, COALESCE(tg_id, 0) AS tg_id, COALESCE(username, '') AS username, fullname, imageurl FROM tg_user WHERE id = $1;").
 					WithArgs(1).WillReturnError(fmt.Errorf("some error"))
 			},
 			input:          1,
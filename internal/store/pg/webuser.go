@@ -6,18 +6,20 @@ import (
 	"fmt"
 
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
+	"github.com/lib/pq"
 )
 
 var (
 	ErrWebUserNotFound   = errors.New("web user not found")
 	ErrWebUserNotCreated = errors.New("web user not created")
+	ErrWebUserExists     = errors.New("web user with this email already exists")
 )
 
 type WebUserRepo struct {
-	db *DB
+	db Queryer
 }
 
-func NewWebUserRepo(db *DB) *WebUserRepo {
+func NewWebUserRepo(db Queryer) *WebUserRepo {
 	return &WebUserRepo{db: db}
 }
 
@@ -36,18 +38,84 @@ func (w *WebUserRepo) GetWebUserByEmail(email string) (*model.WebUser, error) {
 	return &user, nil
 }
 
+func (w *WebUserRepo) GetWebUserByID(ID int) (*model.WebUser, error) {
+	var user model.WebUser
+
+	err := w.db.Get(&user, "SELECT * FROM web_user WHERE id = $1;", ID)
+	if err == sql.ErrNoRows {
+		return nil, ErrWebUserNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get web user by id: %w", err)
+	}
+
+	return &user, nil
+}
+
 func (w *WebUserRepo) CreateWebUser(user *model.WebUser) (int, error) {
 	var id int
 
-	row := w.db.QueryRow("INSERT INTO web_user(email, password) VALUES ($1, $2) RETURNING id;", user.Email, user.Password)
+	row := w.db.QueryRow("INSERT INTO web_user(email, password, role) VALUES ($1, $2, $3) RETURNING id;", user.Email, user.Password, user.Role)
 
 	if err := row.Scan(&id); err != nil {
 		if err == sql.ErrNoRows {
 			return 0, ErrWebUserNotCreated
 		}
 
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, ErrWebUserExists
+		}
+
 		return 0, fmt.Errorf("failed to create web user:%w", err)
 	}
 
 	return id, nil
 }
+
+func (w *WebUserRepo) UpdateWebUserRole(ID int, role model.Role) (int, error) {
+	var id int
+
+	row := w.db.QueryRow("UPDATE web_user SET role = $1 WHERE id = $2 RETURNING id;", role, ID)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrWebUserNotFound
+		}
+
+		return 0, fmt.Errorf("failed to update web user role: %w", err)
+	}
+
+	return id, nil
+}
+
+func (w *WebUserRepo) UpdateWebUserPassword(ID int, password string) (int, error) {
+	var id int
+
+	row := w.db.QueryRow("UPDATE web_user SET password = $1 WHERE id = $2 RETURNING id;", password, ID)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrWebUserNotFound
+		}
+
+		return 0, fmt.Errorf("failed to update web user password: %w", err)
+	}
+
+	return id, nil
+}
+
+// DeleteWebUser deletes web user, its saved messages, collections, shares, sessions and keys are deleted with it.
+func (w *WebUserRepo) DeleteWebUser(ID int) (int, error) {
+	var id int
+
+	row := w.db.QueryRow("DELETE FROM web_user WHERE id = $1 RETURNING id;", ID)
+	if err := row.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrWebUserNotFound
+		}
+
+		return 0, fmt.Errorf("failed to delete web user: %w", err)
+	}
+
+	return id, nil
+}
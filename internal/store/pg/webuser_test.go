@@ -8,6 +8,7 @@ import (
 	"github.com/VladPetriv/scanner_backend_api/internal/model"
 	"github.com/VladPetriv/scanner_backend_api/internal/store/pg"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,10 +37,10 @@ func Test_CreateWebUser(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id"}).
 					AddRow(1)
 
-				mock.ExpectQuery("INSERT INTO web_user(email, password) VALUES ($1, $2) RETURNING id;").
-					WithArgs("test@test.com", "test_pswd").WillReturnRows(rows)
+				mock.ExpectQuery("INSERT INTO web_user(email, password, role) VALUES ($1, $2, $3) RETURNING id;").
+					WithArgs("test@test.com", "test_pswd", model.RoleMember).WillReturnRows(rows)
 			},
-			input: &model.WebUser{Email: "test@test.com", Password: "test_pswd"},
+			input: &model.WebUser{Email: "test@test.com", Password: "test_pswd", Role: model.RoleMember},
 			want:  1,
 		},
 		{
@@ -47,20 +48,29 @@ func Test_CreateWebUser(t *testing.T) {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id"})
 
-				mock.ExpectQuery("INSERT INTO web_user(email, password) VALUES ($1, $2) RETURNING id;").
-					WithArgs("test@test.com", "test_pswd").WillReturnRows(rows)
+				mock.ExpectQuery("INSERT INTO web_user(email, password, role) VALUES ($1, $2, $3) RETURNING id;").
+					WithArgs("test@test.com", "test_pswd", model.RoleMember).WillReturnRows(rows)
 			},
-			input: &model.WebUser{Email: "test@test.com", Password: "test_pswd"},
+			input: &model.WebUser{Email: "test@test.com", Password: "test_pswd", Role: model.RoleMember},
 
 			wantErr: true,
 		},
+		{
+			name: "Error: [web user with email exists]",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO web_user(email, password, role) VALUES ($1, $2, $3) RETURNING id;").
+					WithArgs("test@test.com", "test_pswd", model.RoleMember).WillReturnError(&pq.Error{Code: "23505"})
+			},
+			input:   &model.WebUser{Email: "test@test.com", Password: "test_pswd", Role: model.RoleMember},
+			wantErr: true,
+		},
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("INSERT INTO web_user(email, password) VALUES ($1, $2) RETURNING id;").
-					WithArgs("test@test.com", "test_pswd").WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery("INSERT INTO web_user(email, password, role) VALUES ($1, $2, $3) RETURNING id;").
+					WithArgs("test@test.com", "test_pswd", model.RoleMember).WillReturnError(fmt.Errorf("some error"))
 			},
-			input:   &model.WebUser{Email: "test@test.com", Password: "test_pswd"},
+			input:   &model.WebUser{Email: "test@test.com", Password: "test_pswd", Role: model.RoleMember},
 			wantErr: true,
 		},
 	}
@@ -155,3 +165,141 @@ func Test_GetWebUserByEmail(t *testing.T) {
 		})
 	}
 }
+
+func Test_UpdateWebUserRole(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(&pg.DB{DB: sqlxDB})
+
+	query := "UPDATE web_user SET role = $1 WHERE id = $2 RETURNING id;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [web user role updated]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery(query).WithArgs(model.RoleAdmin, 1).WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [web user not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).WithArgs(model.RoleAdmin, 1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "web user not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(model.RoleAdmin, 1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to update web user role: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateWebUserRole(1, model.RoleAdmin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_DeleteWebUser(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(&pg.DB{DB: sqlxDB})
+
+	query := "DELETE FROM web_user WHERE id = $1 RETURNING id;"
+
+	tests := []struct {
+		name           string
+		mock           func()
+		want           int
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name: "Ok: [web user deleted]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"}).
+					AddRow(1)
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: 1,
+		},
+		{
+			name: "Error: [web user not found]",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id"})
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			wantErr:        true,
+			expectedErrMsg: "web user not found",
+		},
+		{
+			name: "Error: [some sql error]",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(fmt.Errorf("some error"))
+			},
+			wantErr:        true,
+			expectedErrMsg: "failed to delete web user: some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.DeleteWebUser(1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,35 +1,41 @@
 package store
 
-import "github.com/VladPetriv/scanner_backend_api/internal/model"
+import (
+	"time"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/model"
+)
 
 //go:generate mockery --dir . --name ChannelRepo --output ./mocks
 type ChannelRepo interface {
 	CreateChannel(channel *model.ChannelDTO) error
 	GetChannelsCount() (int, error)
-	GetChannelsByPage(offset int) ([]model.Channel, error)
+	GetChannelsByPage(page *model.PageRequest) ([]model.Channel, error)
 	GetChannelByName(name string) (*model.Channel, error)
 }
 
 //go:generate mockery --dir . --name MessageRepo --output ./mocks
 type MessageRepo interface {
 	CreateMessage(message *model.MessageDTO) (int, error)
-	GetMessagesCount() (int, error)
-	GetMessagesCountByChannelID(ID int) (int, error)
+	GetMessagesCount(filter *model.MessageFilter) (int, error)
 	GetFullMessageByID(ID int) (*model.FullMessage, error)
-	GetFullMessagesByPage(offset int) ([]model.FullMessage, error)
-	GetFullMessagesByChannelIDAndPage(ID, offset int) ([]model.FullMessage, error)
+	GetFullMessagesByPage(filter *model.MessageFilter, page *model.PageRequest) ([]model.FullMessage, error)
+	SearchMessages(query string, configs []string, filter *model.MessageFilter, offset int) ([]model.SearchResult, error)
 	GetFullMessagesByUserID(ID int) ([]model.FullMessage, error)
 }
 
 //go:generate mockery --dir . --name ReplieRepo --output ./mocks
 type ReplieRepo interface {
 	CreateReplie(replie *model.ReplieDTO) error
-	GetFullRepliesByMessageID(ID int) ([]model.FullReplie, error)
+	GetFullRepliesByMessageID(ID int, page *model.PageRequest) ([]model.FullReplie, error)
 }
 
 //go:generate mockery --dir . --name UserRepo --output ./mocks
 type UserRepo interface {
-	CreateUser(user *model.UserDTO) (int, error)
+	UpsertUserByTgID(user *model.UserDTO) (int, error)
+	UpsertUserByUsername(user *model.UserDTO) (int, error)
+	UpsertAnonymousUser(user *model.UserDTO) (int, error)
+	ClaimUsername(tgID int64, username string) error
 	GetUserByUsername(username string) (*model.User, error)
 	GetUserByID(ID int) (*model.User, error)
 }
@@ -37,12 +43,107 @@ type UserRepo interface {
 //go:generate mockery --dir . --name WebUserRepo --output ./mocks
 type WebUserRepo interface {
 	GetWebUserByEmail(email string) (*model.WebUser, error)
+	GetWebUserByID(ID int) (*model.WebUser, error)
 	CreateWebUser(user *model.WebUser) (int, error)
+	UpdateWebUserRole(ID int, role model.Role) (int, error)
+	UpdateWebUserPassword(ID int, password string) (int, error)
+	DeleteWebUser(ID int) (int, error)
 }
 
 //go:generate mockery --dir . --name SavedRepo --output ./mocks
 type SavedRepo interface {
-	GetSavedMessages(ID int) ([]model.Saved, error)
+	GetSavedMessages(ID int, filter *model.SavedFilter, page *model.PageRequest) ([]model.SavedMessage, error)
 	CreateSavedMessage(savedMessage *model.Saved) (int, error)
-	DeleteSavedMessage(ID int) (int, error)
+	UpdateSavedMessage(savedMessage *model.Saved) (int, error)
+	DeleteSavedMessage(userID, ID int) (*model.Saved, error)
+	GetCollections(userID int) ([]model.Collection, error)
+	GetCollection(userID, ID int) (*model.Collection, error)
+	CreateCollection(collection *model.Collection) (int, error)
+	UpdateCollection(collection *model.Collection) (int, error)
+	DeleteCollection(userID, ID int) (int, error)
+}
+
+//go:generate mockery --dir . --name ShareRepo --output ./mocks
+type ShareRepo interface {
+	CreateShare(share *model.Share, savedIDs []int) (int, error)
+	GetSharesByUserID(userID int) ([]model.Share, error)
+	GetShareAccess(ID, userID int) (*model.Share, error)
+	GetShareByToken(token string) (*model.Share, error)
+	UpdateShareToken(ID int, token *string) (int, error)
+	DeleteShare(ID int) (int, error)
+	AddShareItems(shareID, userID int, savedIDs []int) (int, error)
+	DeleteShareItem(shareID, messageID int) (int, error)
+	GetShareMessages(shareID int) ([]model.FullMessage, error)
+	UpsertShareMember(member *model.ShareMember) error
+	GetShareMembers(shareID int) ([]model.ShareMember, error)
+	DeleteShareMember(shareID, userID int) (int, error)
+}
+
+//go:generate mockery --dir . --name SessionRepo --output ./mocks
+type SessionRepo interface {
+	CreateSession(userID int, tokenHash string, expiresAt time.Time) (int, error)
+	GetSession(ID int) (*model.Session, error)
+	GetSessionsByUserID(userID int) ([]model.Session, error)
+	RotateRefreshToken(tokenHash, newTokenHash string, expiresAt time.Time) (*model.Principal, error)
+	GetRefreshToken(tokenHash string) (*model.RefreshToken, error)
+	RevokeSession(ID int) (int, error)
+	RevokeUserSessions(userID int) (int, error)
+	RevokeOtherUserSessions(userID, sessionID int) (int, error)
+}
+
+//go:generate mockery --dir . --name APIKeyRepo --output ./mocks
+type APIKeyRepo interface {
+	CreateAPIKey(key *model.APIKey) (int, error)
+	GetAPIKeys(userID int) ([]model.APIKey, error)
+	UpdateAPIKeyName(userID, ID int, name string) (int, error)
+	RevokeAPIKey(userID, ID int) (int, error)
+	UseAPIKey(keyHash string) (*model.Principal, error)
+}
+
+//go:generate mockery --dir . --name AccountRepo --output ./mocks
+type AccountRepo interface {
+	CreateAccountToken(token *model.AccountToken, mail *model.Mail) (int, error)
+	ResetPassword(tokenHash, password string) (int, error)
+	VerifyEmail(tokenHash string) (int, error)
+	ChangeEmail(tokenHash string) (*model.EmailChange, error)
+}
+
+//go:generate mockery --dir . --name SignInAttemptRepo --output ./mocks
+type SignInAttemptRepo interface {
+	GetSignInAttempt(kind model.SignInAttemptKind, subject string) (*model.SignInAttempt, error)
+	RecordSignInFailure(kind model.SignInAttemptKind, subject string, failedAt, resetBefore time.Time) (int, error)
+	LockSignIn(kind model.SignInAttemptKind, subject string, lockedUntil time.Time) (int, error)
+	DeleteSignInAttempt(kind model.SignInAttemptKind, subject string) (int, error)
+}
+
+//go:generate mockery --dir . --name AuditRepo --output ./mocks
+type AuditRepo interface {
+	CreateAuditEvent(event *model.AuditEvent) (int, error)
+	GetAuditEvents(filter *model.AuditFilter, page *model.PageRequest) ([]model.AuditEvent, error)
+}
+
+//go:generate mockery --dir . --name OIDCRepo --output ./mocks
+type OIDCRepo interface {
+	CreateOIDCLogin(login *model.OIDCLogin) error
+	UseOIDCLogin(stateHash string) (*model.OIDCLogin, error)
+	GetWebUserByIdentity(issuer, subject string) (*model.WebUser, error)
+	GetWebUserIdentities(userID int) ([]model.WebUserIdentity, error)
+	CreateWebUserIdentity(identity *model.WebUserIdentity) (int, error)
+	CreateWebUserWithIdentity(user *model.WebUser, identity *model.WebUserIdentity) (int, error)
+}
+
+//go:generate mockery --dir . --name OutboxRepo --output ./mocks
+type OutboxRepo interface {
+	ClaimMails(limit, maxAttempts int, leaseUntil time.Time) ([]model.Mail, error)
+	MarkMailSent(ID int) (int, error)
+	MarkMailFailed(ID int, lastError string, retryAt time.Time) (int, error)
+}
+
+//go:generate mockery --dir . --name IngestFailureRepo --output ./mocks
+type IngestFailureRepo interface {
+	CreateIngestFailure(failure *model.IngestFailure) (int, error)
+	GetIngestFailuresByPage(offset int) ([]model.IngestFailure, error)
+	GetIngestFailureByID(ID int) (*model.IngestFailure, error)
+	UpdateIngestFailure(ID int, errMsg string) error
+	DeleteIngestFailure(ID int) (int, error)
 }
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go.uber.org/zap"
@@ -12,16 +14,27 @@ import (
 
 const KeepAlivePollPeriod = 5
 
+var ErrNoDB = errors.New("no db connection")
+
 type Store struct {
 	db  *pg.DB
 	log *logger.Logger
 
-	Channel ChannelRepo
-	User    UserRepo
-	Message MessageRepo
-	Replie  ReplieRepo
-	WebUser WebUserRepo
-	Saved   SavedRepo
+	Channel       ChannelRepo
+	User          UserRepo
+	Message       MessageRepo
+	Replie        ReplieRepo
+	WebUser       WebUserRepo
+	Saved         SavedRepo
+	Share         ShareRepo
+	Session       SessionRepo
+	APIKey        APIKeyRepo
+	Account       AccountRepo
+	SignInAttempt SignInAttemptRepo
+	Audit         AuditRepo
+	OIDC          OIDCRepo
+	Outbox        OutboxRepo
+	IngestFailure IngestFailureRepo
 }
 
 func New(cfg *config.Config, log *logger.Logger) (*Store, error) {
@@ -44,46 +57,92 @@ func New(cfg *config.Config, log *logger.Logger) (*Store, error) {
 
 	if db != nil {
 		store.db = db
+		store.initRepos(store.db)
 
-		store.Channel = pg.NewChannelRepo(store.db)
-		store.Message = pg.NewMessageRepo(store.db)
-		store.Replie = pg.NewReplieRepo(store.db)
-		store.User = pg.NewUserRepo(store.db)
-		store.WebUser = pg.NewWebUserRepo(store.db)
-		store.Saved = pg.NewSavedRepo(store.db)
-
-		go store.keepAliveDB(cfg)
+		go store.keepAliveDB()
 	}
 
 	return &store, nil
 }
 
-func (s *Store) keepAliveDB(cfg *config.Config) {
-	var err error
+// NewFromDB creates store on top of already opened connection without running migrations.
+func NewFromDB(db *pg.DB, log *logger.Logger) *Store {
+	store := Store{db: db, log: log}
+	store.initRepos(db)
+
+	return &store
+}
+
+func (s *Store) initRepos(db pg.Queryer) {
+	s.Channel = pg.NewChannelRepo(db)
+	s.Message = pg.NewMessageRepo(db)
+	s.Replie = pg.NewReplieRepo(db)
+	s.User = pg.NewUserRepo(db)
+	s.WebUser = pg.NewWebUserRepo(db)
+	s.Saved = pg.NewSavedRepo(db)
+	s.Share = pg.NewShareRepo(db)
+	s.Session = pg.NewSessionRepo(db)
+	s.APIKey = pg.NewAPIKeyRepo(db)
+	s.Account = pg.NewAccountRepo(db)
+	s.SignInAttempt = pg.NewSignInAttemptRepo(db)
+	s.Audit = pg.NewAuditRepo(db)
+	s.OIDC = pg.NewOIDCRepo(db)
+	s.Outbox = pg.NewOutboxRepo(db)
+	s.IngestFailure = pg.NewIngestFailureRepo(db)
+}
+
+// InTx calls fn with a store whose repositories share one transaction.
+// The transaction is committed when fn returns nil and rolled back otherwise.
+func (s *Store) InTx(fn func(tx *Store) error) error {
+	if s.db == nil {
+		return ErrNoDB
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	txStore := Store{log: s.log}
+	txStore.initRepos(tx)
+
+	if err := fn(&txStore); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			s.log.Error("failed to rollback transaction", zap.Error(rbErr))
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// keepAliveDB only reports state of db connection, connections of the pool are restored by database/sql itself,
+// so repositories keep working with the same db after it's back.
+func (s *Store) keepAliveDB() {
+	lostConnection := false
 
 	for {
 		time.Sleep(time.Second * KeepAlivePollPeriod)
 
-		lostConnection := false
-		if s.db == nil {
-			lostConnection = true
-		} else if _, err = s.db.Exec("SELECT 1;"); err != nil {
+		if err := s.db.Ping(); err != nil {
+			if !lostConnection {
+				s.log.Error("[store.KeepAliveDB] Lost db connection", zap.Error(err))
+			}
+
 			lostConnection = true
-		}
 
-		if !lostConnection {
 			continue
 		}
 
-		s.log.Debug("[store.KeepAliveDB] Lost db connection. Restoring...")
-
-		s.db, err = pg.Dial(cfg)
-		if err != nil {
-			s.log.Error("failed to connect", zap.Error(err))
-
-			continue
+		if lostConnection {
+			s.log.Info("[store.KeepAliveDB] DB reconnected")
 		}
 
-		s.log.Debug("[store.KeepAliveDB] DB reconnected")
+		lostConnection = false
 	}
 }
@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// MaxEmailLength is size of email column of web_user table.
+const MaxEmailLength = 255
+
+var (
+	ErrInvalidEmail = errors.New("is not valid email address")
+	ErrEmailTooLong = fmt.Errorf("must be at most %d characters long", MaxEmailLength)
+)
+
+// NormalizeEmail returns email without surrounding spaces and with domain in lower case,
+// local part is kept as is since mail servers may treat its case as significant.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", ErrRequired
+	}
+
+	if len(email) > MaxEmailLength {
+		return "", ErrEmailTooLong
+	}
+
+	// display names and comments are valid in mail headers, but not in email of user
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || address.Name != "" {
+		return "", ErrInvalidEmail
+	}
+
+	at := strings.LastIndex(email, "@")
+
+	return email[:at] + "@" + strings.ToLower(email[at+1:]), nil
+}
@@ -0,0 +1,67 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		want           string
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name:  "Ok: [email is valid]",
+			input: "test@test.com",
+			want:  "test@test.com",
+		},
+		{
+			name:  "Ok: [spaces are trimmed and domain is lower cased]",
+			input: "  Test.User@Example.COM ",
+			want:  "Test.User@example.com",
+		},
+		{
+			name:           "Error: [email is empty]",
+			input:          " ",
+			wantErr:        true,
+			expectedErrMsg: "is required",
+		},
+		{
+			name:           "Error: [email has no domain]",
+			input:          "test",
+			wantErr:        true,
+			expectedErrMsg: "is not valid email address",
+		},
+		{
+			name:           "Error: [email has display name]",
+			input:          "Test <test@test.com>",
+			wantErr:        true,
+			expectedErrMsg: "is not valid email address",
+		},
+		{
+			name:           "Error: [email is too long]",
+			input:          strings.Repeat("a", 250) + "@test.com",
+			wantErr:        true,
+			expectedErrMsg: "must be at most 255 characters long",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validation.NormalizeEmail(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+		})
+	}
+}
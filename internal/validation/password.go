@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DefaultPasswordMinLength = 8
+	// MaxPasswordLength is number of bytes bcrypt uses, the rest of longer password would be silently ignored.
+	MaxPasswordLength = 72
+)
+
+var (
+	ErrPasswordTooLong  = fmt.Errorf("must be at most %d bytes long", MaxPasswordLength)
+	ErrPasswordBreached = errors.New("is known from data breaches, choose another one")
+)
+
+// PasswordPolicy is set of rules new passwords have to follow.
+type PasswordPolicy struct {
+	minLength int
+	// breached are upper case hex encoded sha-1 hashes of passwords known from data breaches.
+	breached map[string]struct{}
+}
+
+// NewPasswordPolicy creates policy which requires passwords of at least minLength characters,
+// default length is used when minLength isn't positive. Passwords listed in breachedFile are rejected,
+// the file has one password or its hex encoded sha-1 hash per line, optionally followed by ":count"
+// like in Pwned Passwords downloads. No password is rejected as breached when breachedFile is empty.
+func NewPasswordPolicy(minLength int, breachedFile string) (*PasswordPolicy, error) {
+	if minLength <= 0 {
+		minLength = DefaultPasswordMinLength
+	}
+
+	policy := &PasswordPolicy{minLength: minLength, breached: make(map[string]struct{})}
+
+	if breachedFile == "" {
+		return policy, nil
+	}
+
+	if err := policy.loadBreached(breachedFile); err != nil {
+		return nil, fmt.Errorf("failed to load breached passwords: %w", err)
+	}
+
+	return policy, nil
+}
+
+// Validate returns error describing why password doesn't follow policy.
+func (p *PasswordPolicy) Validate(password string) error {
+	if password == "" {
+		return ErrRequired
+	}
+
+	if utf8.RuneCountInString(password) < p.minLength {
+		return fmt.Errorf("must be at least %d characters long", p.minLength)
+	}
+
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+
+	if _, ok := p.breached[hashPassword(password)]; ok {
+		return ErrPasswordBreached
+	}
+
+	return nil
+}
+
+func (p *PasswordPolicy) loadBreached(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if hash, ok := parsePasswordHash(line); ok {
+			p.breached[hash] = struct{}{}
+
+			continue
+		}
+
+		p.breached[hashPassword(line)] = struct{}{}
+	}
+
+	return scanner.Err()
+}
+
+// parsePasswordHash returns hash from line when it's hex encoded sha-1 hash with optional ":count" suffix.
+func parsePasswordHash(line string) (string, bool) {
+	if i := strings.IndexByte(line, ':'); i == sha1.Size*2 {
+		line = line[:i]
+	}
+
+	if len(line) != sha1.Size*2 {
+		return "", false
+	}
+
+	if _, err := hex.DecodeString(line); err != nil {
+		return "", false
+	}
+
+	return strings.ToUpper(line), true
+}
+
+func hashPassword(password string) string {
+	hash := sha1.Sum([]byte(password))
+
+	return strings.ToUpper(hex.EncodeToString(hash[:]))
+}
@@ -0,0 +1,93 @@
+package validation_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/VladPetriv/scanner_backend_api/internal/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PasswordPolicyValidate(t *testing.T) {
+	// "qwertyuiop" is listed as plain text and "password123" as sha-1 hash with count
+	breachedFile := filepath.Join(t.TempDir(), "breached.txt")
+	breached := "qwertyuiop\n\ncbfdac6008f9cab4083784cbd1874f76618d2a97:123456\n"
+
+	if err := os.WriteFile(breachedFile, []byte(breached), 0o600); err != nil {
+		t.Fatalf("failed to write breached passwords: %s", err)
+	}
+
+	policy, err := validation.NewPasswordPolicy(10, breachedFile)
+	if err != nil {
+		t.Fatalf("failed to create password policy: %s", err)
+	}
+
+	tests := []struct {
+		name           string
+		input          string
+		wantErr        bool
+		expectedErrMsg string
+	}{
+		{
+			name:  "Ok: [password follows policy]",
+			input: "correct horse battery",
+		},
+		{
+			name:  "Ok: [length is counted in characters]",
+			input: "пароль-пароль",
+		},
+		{
+			name:           "Error: [password is empty]",
+			input:          "",
+			wantErr:        true,
+			expectedErrMsg: "is required",
+		},
+		{
+			name:           "Error: [password is too short]",
+			input:          "short",
+			wantErr:        true,
+			expectedErrMsg: "must be at least 10 characters long",
+		},
+		{
+			name:           "Error: [password is too long]",
+			input:          strings.Repeat("a", 73),
+			wantErr:        true,
+			expectedErrMsg: "must be at most 72 bytes long",
+		},
+		{
+			name:           "Error: [password is listed as breached]",
+			input:          "qwertyuiop",
+			wantErr:        true,
+			expectedErrMsg: "is known from data breaches, choose another one",
+		},
+		{
+			name:           "Error: [hash of password is listed as breached]",
+			input:          "password123",
+			wantErr:        true,
+			expectedErrMsg: "is known from data breaches, choose another one",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedErrMsg, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_NewPasswordPolicy(t *testing.T) {
+	policy, err := validation.NewPasswordPolicy(0, "")
+	assert.NoError(t, err)
+	assert.EqualError(t, policy.Validate("seven77"), "must be at least 8 characters long")
+
+	_, err = validation.NewPasswordPolicy(0, filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+// Package validation checks and normalizes user input before it's stored.
+package validation
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/VladPetriv/scanner_backend_api/pkg/lib"
+)
+
+var ErrRequired = errors.New("is required")
+
+// Errors are problems with input fields, they are reported together so client can fix all of them at once.
+type Errors []lib.FieldError
+
+func (e Errors) Error() string {
+	problems := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		problems = append(problems, fieldErr.Field+" "+fieldErr.Message)
+	}
+
+	return "input is not valid: " + strings.Join(problems, "; ")
+}
+
+// Add adds problem with field when err is not nil.
+func (e *Errors) Add(field string, err error) {
+	if err != nil {
+		*e = append(*e, lib.FieldError{Field: field, Message: err.Error()})
+	}
+}
+
+// Err returns errors when there are any and nil otherwise.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -8,12 +8,34 @@ import (
 )
 
 type Config struct {
-	DBURL            string
-	DBMigrationsPath string
-	LogLevel         string
-	Port             string
-	JwtSecretKey     string
-	KafkaAddr        string
+	DBURL                string
+	DBMigrationsPath     string
+	LogLevel             string
+	Port                 string
+	JwtSecretKey         string
+	JwtKeys              string
+	JwtIssuer            string
+	JwtAudience          string
+	AccessTokenTTL       string
+	RefreshTokenTTL      string
+	KafkaAddr            string
+	KafkaGroupID         string
+	KafkaDeadLetterTopic string
+	SearchLanguages      string
+	AppURL               string
+	MailFrom             string
+	MailFile             string
+	MailStdout           string
+	SMTPAddr             string
+	SMTPUsername         string
+	SMTPPassword         string
+	PasswordMinLength    string
+	BreachedPasswords    string
+	OIDCIssuer           string
+	OIDCClientID         string
+	OIDCClientSecret     string
+	OIDCRedirectURL      string
+	OIDCScopes           string
 }
 
 func Get() (*Config, error) {
@@ -22,11 +44,33 @@ func Get() (*Config, error) {
 	}
 
 	return &Config{
-		DBURL:            os.Getenv("DATABASE_URL"),
-		DBMigrationsPath: os.Getenv("DB_MIGRATION_PATH"),
-		LogLevel:         os.Getenv("LOG_LEVEL"),
-		Port:             os.Getenv("PORT"),
-		JwtSecretKey:     os.Getenv("JWT_SECRET_KEY"),
-		KafkaAddr:        os.Getenv("KAFKA_ADDR"),
+		DBURL:                os.Getenv("DATABASE_URL"),
+		DBMigrationsPath:     os.Getenv("DB_MIGRATION_PATH"),
+		LogLevel:             os.Getenv("LOG_LEVEL"),
+		Port:                 os.Getenv("PORT"),
+		JwtSecretKey:         os.Getenv("JWT_SECRET_KEY"),
+		JwtKeys:              os.Getenv("JWT_KEYS"),
+		JwtIssuer:            os.Getenv("JWT_ISSUER"),
+		JwtAudience:          os.Getenv("JWT_AUDIENCE"),
+		AccessTokenTTL:       os.Getenv("ACCESS_TOKEN_TTL"),
+		RefreshTokenTTL:      os.Getenv("REFRESH_TOKEN_TTL"),
+		KafkaAddr:            os.Getenv("KAFKA_ADDR"),
+		KafkaGroupID:         os.Getenv("KAFKA_GROUP_ID"),
+		KafkaDeadLetterTopic: os.Getenv("KAFKA_DEAD_LETTER_TOPIC"),
+		SearchLanguages:      os.Getenv("SEARCH_LANGUAGES"),
+		AppURL:               os.Getenv("APP_URL"),
+		MailFrom:             os.Getenv("MAIL_FROM"),
+		MailFile:             os.Getenv("MAIL_FILE"),
+		MailStdout:           os.Getenv("MAIL_STDOUT"),
+		SMTPAddr:             os.Getenv("SMTP_ADDR"),
+		SMTPUsername:         os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:         os.Getenv("SMTP_PASSWORD"),
+		PasswordMinLength:    os.Getenv("PASSWORD_MIN_LENGTH"),
+		BreachedPasswords:    os.Getenv("BREACHED_PASSWORDS_FILE"),
+		OIDCIssuer:           os.Getenv("OIDC_ISSUER"),
+		OIDCClientID:         os.Getenv("OIDC_CLIENT_ID"),
+		OIDCClientSecret:     os.Getenv("OIDC_CLIENT_SECRET"),
+		OIDCRedirectURL:      os.Getenv("OIDC_REDIRECT_URL"),
+		OIDCScopes:           os.Getenv("OIDC_SCOPES"),
 	}, nil
 }
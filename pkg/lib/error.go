@@ -1,8 +1,20 @@
 package lib
 
-// @Description Http Error structure
-type HttpError struct {
-	Code    int    `json:"code"`    // Http status code
-	Name    string `json:"name"`    // Http status name
-	Message string `json:"message"` // Error message
+// ProblemContentType is media type of error responses defined by RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// @Description Error in RFC 7807 problem details format, problem type is always about:blank so it's omitted
+type Problem struct {
+	Title         string       `json:"title"`                   // Http status name example: Not Found
+	Status        int          `json:"status"`                  // Http status code example: 404
+	Detail        string       `json:"detail"`                  // What went wrong, details of server errors are hidden example: full message not found
+	Code          string       `json:"code"`                    // Stable machine-readable error code example: MESSAGE_NOT_FOUND
+	CorrelationID string       `json:"correlationId,omitempty"` // Id of request under which error is logged, it's also sent in X-Request-ID header
+	Fields        []FieldError `json:"fields,omitempty"`        // Problems with input fields, they are set only for invalid input
+}
+
+// @Description Problem with input field
+type FieldError struct {
+	Field   string `json:"field"`   // Name of invalid field example: password
+	Message string `json:"message"` // What is wrong with the field example: must be at least 8 characters long
 }